@@ -0,0 +1,110 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/hybridDB"
+	"github.com/FactomProject/factomd/state"
+)
+
+const level string = "level"
+const bolt string = "bolt"
+
+func usage() {
+	fmt.Println("Usage:")
+	fmt.Println("FinalArchive verify level/bolt DBFileLocation ArchiveFile")
+	fmt.Println("FinalArchive build level/bolt DBFileLocation Height ArchiveFile")
+	fmt.Println("verify recomputes the Merkle root of a final archive from a database, and checks its signature")
+	fmt.Println("build writes an unsigned archive of the balances, chain heads and authorities at a height")
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	args := flag.Args()
+
+	if len(args) < 1 {
+		usage()
+		os.Exit(1)
+	}
+	switch {
+	case args[0] == "verify" && len(args) == 4:
+		os.Exit(verify(open(args[1], args[2]), args[3]))
+	case args[0] == "build" && len(args) == 5:
+		height, err := strconv.ParseUint(args[3], 10, 32)
+		if err != nil {
+			fmt.Printf("\nBad height %q\n", args[3])
+			os.Exit(1)
+		}
+		os.Exit(build(open(args[1], args[2]), uint32(height), args[4]))
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func open(levelBolt string, path string) *databaseOverlay.Overlay {
+	var dbase *hybridDB.HybridDB
+	var err error
+	switch levelBolt {
+	case bolt:
+		dbase = hybridDB.NewBoltMapHybridDB(nil, path)
+	case level:
+		dbase, err = hybridDB.NewLevelMapHybridDB(path, false)
+		if err != nil {
+			panic(err)
+		}
+	default:
+		fmt.Println("\nDatabase type should be `level` or `bolt`")
+		os.Exit(1)
+	}
+	return databaseOverlay.NewOverlay(dbase)
+}
+
+func verify(dbo *databaseOverlay.Overlay, name string) int {
+	a, err := state.ReadFinalArchive(name)
+	if err != nil {
+		fmt.Printf("Cannot read the archive: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Archive of height %d, network %q, Merkle root %s\n", a.DBHeight, a.Network, a.MerkleRoot)
+
+	failed := 0
+	if err := state.VerifyFinalArchive(dbo, a); err != nil {
+		fmt.Printf("FAILED: %v\n", err)
+		failed = 1
+	} else {
+		fmt.Printf("OK: the database gives the same Merkle root\n")
+	}
+
+	if err := a.CheckSignature(); err != nil {
+		fmt.Printf("FAILED: signature: %v\n", err)
+		failed = 1
+	} else if auth := a.SignerAuthority(); auth != nil {
+		fmt.Printf("OK: signed by %s, a %s authority at the final height\n", a.Signature.IdentityChainID, auth.Status)
+	} else {
+		fmt.Printf("OK: signed by identity %s with key %s, which is not an authority key at the final height\n", a.Signature.IdentityChainID, a.Signature.PublicKey)
+	}
+	return failed
+}
+
+func build(dbo *databaseOverlay.Overlay, height uint32, name string) int {
+	a, err := state.BuildFinalArchive(dbo, height)
+	if err == nil {
+		err = state.WriteFinalArchive(name, a)
+	}
+	if err != nil {
+		fmt.Printf("FAILED: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Archive of height %d written to %s, Merkle root %s\n", height, name, a.MerkleRoot)
+	return 0
+}
@@ -19,6 +19,7 @@ const (
 	//
 	AUTHRORITY_SET_MAX_DELTA = iota
 	MAX_FACTOM_HEIGHT        = iota
+	RCD_2_MULTISIG           = iota
 	ACTIVATION_TYPE_COUNT    = iota - 1 // Always Last
 )
 
@@ -65,6 +66,15 @@ func init() {
 				"CUSTOM:fct_community_test": 400000, // stop at minute 2. That last block will not be included
 			}, //                                       in the History provided to the Accumulate ERA.
 		},
+		{"RCD2Multisig", RCD_2_MULTISIG,
+			"Accept m of n multisig (RCD type 2) inputs on factoid transactions",
+			math.MaxInt32, // Don't activate by default
+			map[string]int{
+				"MAIN":                      math.MaxInt32, // Not yet scheduled
+				"LOCAL":                     25,
+				"CUSTOM:fct_community_test": math.MaxInt32, // Not yet scheduled
+			},
+		},
 	}
 
 	if ACTIVATION_TYPE_COUNT != len(activations) {
@@ -126,3 +136,16 @@ func IsActive(id ActivationType, height int) bool {
 
 	return false
 }
+
+// ActivationHeight returns the height at which the input activation turns 'on' for the network
+// factomd was started on, and false if it never does
+func ActivationHeight(id ActivationType) (int, bool) {
+	a, ok := activationMap[id]
+	if !ok {
+		return 0, false
+	}
+	if h, ok := a.activationHeight[networkname()]; ok {
+		return h, h < math.MaxInt32
+	}
+	return a.defaultHeight, a.defaultHeight < math.MaxInt32
+}
@@ -82,3 +82,29 @@ func TestDefaults(t *testing.T) {
 	assert.True(t, IsActive(AUTHRORITY_SET_MAX_DELTA, 222874))
 	assert.True(t, IsActive(AUTHRORITY_SET_MAX_DELTA, math.MaxInt32-1))
 }
+
+func TestActivationHeight(t *testing.T) {
+	netName = "MAIN"
+	h, ok := ActivationHeight(MAX_FACTOM_HEIGHT)
+	assert.True(t, ok)
+	assert.Equal(t, 374000, h)
+	_, ok = ActivationHeight(TESTNET_COINBASE_PERIOD)
+	assert.False(t, ok)
+	_, ok = ActivationHeight(RCD_2_MULTISIG)
+	assert.False(t, ok)
+
+	netName = "LOCAL"
+	h, ok = ActivationHeight(RCD_2_MULTISIG)
+	assert.True(t, ok)
+	assert.Equal(t, 25, h)
+
+	netName = "SOMETHING_ELSE"
+	h, ok = ActivationHeight(AUTHRORITY_SET_MAX_DELTA)
+	assert.True(t, ok)
+	assert.Equal(t, 0, h)
+	_, ok = ActivationHeight(MAX_FACTOM_HEIGHT)
+	assert.False(t, ok)
+	_, ok = ActivationHeight(RCD_2_MULTISIG)
+	assert.False(t, ok)
+	assert.False(t, IsActive(RCD_2_MULTISIG, 1000000))
+}
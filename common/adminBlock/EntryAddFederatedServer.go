@@ -39,8 +39,9 @@ func (e *AddFederatedServer) String() string {
 
 func (c *AddFederatedServer) UpdateState(state interfaces.IState) error {
 	c.Init()
-	if c.DBHeight == 1 {
-		//use the bootstrap identity for the process list following the genesis block
+	if c.DBHeight == 1 && (c.IdentityChainID.IsZero() || c.IdentityChainID.IsSameAs(primitives.Sha([]byte("FNode0")))) {
+		//use the bootstrap identity for the process list following the genesis block, in place of the
+		//placeholder the TEST and LOCAL genesis blocks have.  Custom genesis blocks name their servers.
 		id := state.GetNetworkBootStrapIdentity()
 		state.AddFedServer(c.DBHeight, id)
 	} else {
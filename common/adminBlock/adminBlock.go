@@ -442,6 +442,9 @@ func (b *AdminBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
 	// admin block. The limit is the body size divided by the smallest possible
 	// message size (2 bytes for a minute message {0x00, 0x0[0-9]})
 	msgLimit := b.Header.GetBodySize() / 2
+	if bufLimit := uint32(buf.Len() / 2); bufLimit < msgLimit {
+		msgLimit = bufLimit // The header can claim a bigger body than was sent
+	}
 	msgCount := b.Header.GetMessageCount()
 	if msgCount > msgLimit {
 		return nil, fmt.Errorf(
@@ -0,0 +1,17 @@
+package adminBlock_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func FuzzAdminBlock(f *testing.F) {
+	var seeds []interfaces.BinaryMarshallable
+	for _, set := range testHelper.CreateFullTestBlockSet()[:3] {
+		seeds = append(seeds, set.ABlock)
+	}
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return NewAdminBlock(nil) }, seeds...)
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package clock is the source of time for factomd.  It is the wall clock unless a simulation
+// sets a virtual clock, which every node in the simulation then shares.
+package clock
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// Real is the wall clock
+type Real struct{}
+
+var _ interfaces.IClock = Real{}
+
+func (Real) Now() time.Time                         { return time.Now() }
+func (Real) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (Real) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (Real) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// current holds the clock in use, in a holder as an atomic.Value takes one concrete type.  Reading
+// it costs a load, so the wall clock of a node isn't slowed down by what simulations need.
+var current atomic.Value
+
+type holder struct {
+	clock interfaces.IClock
+}
+
+func init() {
+	current.Store(holder{Real{}})
+}
+
+// Set changes the clock everything uses.  Set it before starting the nodes.
+func Set(c interfaces.IClock) {
+	current.Store(holder{c})
+}
+
+// Get returns the clock in use
+func Get() interfaces.IClock {
+	return current.Load().(holder).clock
+}
+
+func Now() time.Time                         { return Get().Now() }
+func Since(t time.Time) time.Duration        { return Get().Since(t) }
+func Sleep(d time.Duration)                  { Get().Sleep(d) }
+func After(d time.Duration) <-chan time.Time { return Get().After(d) }
+
+// Go starts f in a goroutine.  With a virtual clock it is one of the simulated goroutines the
+// clock runs in a fixed order (see Sim).
+func Go(f func()) {
+	if sim, ok := Get().(*Sim); ok {
+		sim.Go(f)
+		return
+	}
+	go f()
+}
+
+// NewRand returns a source of random numbers for one part of a simulation, like the link
+// between two nodes.  With a virtual clock the numbers follow from its seed and the name, so
+// a replay draws the same numbers; otherwise they are seeded from the time.
+func NewRand(name string) *rand.Rand {
+	if sim, ok := Get().(*Sim); ok {
+		return sim.NewRand(name)
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano() ^ nameSeed(name)))
+}
+
+func nameSeed(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,172 @@
+package clock_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/FactomProject/factomd/common/clock"
+)
+
+var start = time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC)
+
+func TestSimSleepersWakeInOrder(t *testing.T) {
+	c := NewSim(1, start, 1)
+
+	// Two sleepers due at the same time wake in the order they went to sleep
+	late := c.After(30 * time.Millisecond)
+	first := c.After(10 * time.Millisecond)
+	second := c.After(10 * time.Millisecond)
+	now := c.After(0)
+
+	if got := <-now; !got.Equal(start) {
+		t.Errorf("expected an After of 0 to fire at once, at %v, got %v", start, got)
+	}
+
+	c.Advance(20 * time.Millisecond)
+	select {
+	case <-late:
+		t.Fatal("woke the late sleeper too soon")
+	default:
+	}
+	if got := <-first; !got.Equal(start.Add(10 * time.Millisecond)) {
+		t.Errorf("expected the first sleeper to wake at its deadline, got %v", got)
+	}
+	if got := <-second; !got.Equal(start.Add(10 * time.Millisecond)) {
+		t.Errorf("expected the second sleeper to wake at its deadline, got %v", got)
+	}
+	if c.Since(start) != 20*time.Millisecond {
+		t.Errorf("expected the clock to have moved 20ms, it moved %v", c.Since(start))
+	}
+
+	c.Advance(10 * time.Millisecond)
+	if got := <-late; !got.Equal(start.Add(30 * time.Millisecond)) {
+		t.Errorf("expected the late sleeper to wake at its deadline, got %v", got)
+	}
+}
+
+func TestSimRun(t *testing.T) {
+	c := NewSim(1, start, 100)
+	go c.Run()
+	defer c.Stop()
+
+	// A second of virtual time is about 10ms of real time
+	began := time.Now()
+	c.Sleep(time.Second)
+	if real := time.Since(began); real > 500*time.Millisecond {
+		t.Errorf("expected the clock to run 100 times faster than real time, a second took %v", real)
+	}
+	if c.Since(start) < time.Second {
+		t.Errorf("woke before the second was up, at %v", c.Now())
+	}
+}
+
+// simTrace runs a few simulated goroutines that sleep for random times, start another and do
+// some real work in between, and returns what each did when
+func simTrace(seed int64) []string {
+	c := NewSim(seed, start, 1000)
+	var trace []string
+	var wg sync.WaitGroup
+	var worker func(name string, n int) func()
+	worker = func(name string, n int) func() {
+		return func() {
+			defer wg.Done()
+			r := c.NewRand(name)
+			for i := 0; i < n; i++ {
+				c.Sleep(time.Duration(r.Intn(4)) * time.Millisecond)
+				trace = append(trace, fmt.Sprintf("%v %s %d %d", c.Since(start), name, i, r.Intn(100)))
+				if name == "node 0" && i == 5 {
+					wg.Add(1)
+					c.Go(worker("node 0 child", 5))
+				}
+				// Real time the scheduler mustn't depend on
+				time.Sleep(time.Duration(rand.Intn(200)) * time.Microsecond)
+			}
+		}
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		c.Go(worker(fmt.Sprintf("node %d", i), 20))
+	}
+	go c.Run()
+	defer c.Stop()
+	wg.Wait()
+	return trace
+}
+
+func TestSimReplay(t *testing.T) {
+	first := simTrace(42)
+	if len(first) != 4*20+5 {
+		t.Fatalf("expected 85 events, got %d", len(first))
+	}
+	if again := simTrace(42); !reflect.DeepEqual(first, again) {
+		t.Errorf("expected the same seed to replay the same run, got\n%v\nthen\n%v", first, again)
+	}
+	if other := simTrace(43); reflect.DeepEqual(first, other) {
+		t.Error("expected another seed to make another run")
+	}
+}
+
+func TestSimRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewSim(0, start, 2.5)
+	if c.Seed() == 0 {
+		t.Error("expected a seed to be picked")
+	}
+	file := filepath.Join(dir, "simclock.json")
+	if err := c.WriteRecord(file); err != nil {
+		t.Fatal(err)
+	}
+	r, err := ReadRecord(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *r != *c.Record() {
+		t.Errorf("expected to read %+v, got %+v", c.Record(), r)
+	}
+
+	replay := NewSimFromRecord(r)
+	if !replay.Now().Equal(start) {
+		t.Errorf("expected the replay to start at %v, got %v", start, replay.Now())
+	}
+	a, b := c.NewRand("link 0-1"), replay.NewRand("link 0-1")
+	for i := 0; i < 10; i++ {
+		if a.Int63() != b.Int63() {
+			t.Fatal("expected the replay to pick the same random numbers")
+		}
+	}
+	if c.NewRand("link 0-1").Int63() == c.NewRand("link 1-0").Int63() {
+		t.Error("expected different names to get different random numbers")
+	}
+
+	if err := ioutil.WriteFile(file, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadRecord(file); err == nil {
+		t.Error("expected an empty record to be refused")
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	c := NewSim(1, start, 1)
+	Set(c)
+	defer Set(Real{})
+
+	if !Now().Equal(start) {
+		t.Errorf("expected the virtual time %v, got %v", start, Now())
+	}
+	if NewRand("x").Int63() != c.NewRand("x").Int63() {
+		t.Error("expected NewRand to use the virtual clock's seed")
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package clock
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/util/atomic"
+)
+
+// Sim is a virtual clock.  Its time only moves when the scheduler (Run) moves it to the deadline
+// of the next sleeper, or Advance moves it on.  Sleepers wake in the order of their deadlines, ties going to
+// whoever went to sleep first.  The seed picks the random numbers of the simulation (see NewRand).
+//
+// The goroutines started with Go are simulated: the clock runs them one at a time, and only moves
+// when all of them are asleep on it.  Each wakes, runs until it sleeps again, and then the next
+// wakes, so however the Go runtime schedules them and however fast the machine is, they run in the
+// same order at the same virtual times.  A run of them started from the Record of another is the
+// same run.  A simulated goroutine waiting on anything but the clock holds it until it is done
+// waiting.  Other goroutines can use the clock too, but they don't hold it back and it doesn't
+// wait for them.
+type Sim struct {
+	mtx       sync.Mutex
+	asleep    *sync.Cond // Signalled when a simulated goroutine goes to sleep or ends, or a sleeper comes
+	seed      int64
+	start     time.Time
+	speed     float64
+	now       time.Time
+	seq       uint64
+	sleepers  sleepers
+	simulated map[string]bool // The simulated goroutines, by Goid
+	running   int             // How many simulated goroutines are awake
+	stop      chan struct{}
+}
+
+var _ interfaces.IClock = (*Sim)(nil)
+
+// Record is the seed and start of a run on a virtual clock
+type Record struct {
+	Seed  int64     `json:"seed"`
+	Start time.Time `json:"start"`
+	Speed float64   `json:"speed"` // Virtual seconds per real second
+}
+
+// NewSim makes a virtual clock starting at start and running speed times faster than real
+// time.  A seed of 0 picks one.
+func NewSim(seed int64, start time.Time, speed float64) *Sim {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	if speed <= 0 {
+		speed = 1
+	}
+	c := new(Sim)
+	c.seed = seed
+	c.start = start.UTC().Truncate(time.Millisecond)
+	c.speed = speed
+	c.now = c.start
+	c.asleep = sync.NewCond(&c.mtx)
+	c.simulated = make(map[string]bool)
+	return c
+}
+
+// NewSimFromRecord makes a virtual clock with the seed, start and speed of a recorded run
+func NewSimFromRecord(r *Record) *Sim {
+	return NewSim(r.Seed, r.Start, r.Speed)
+}
+
+func (c *Sim) Record() *Record {
+	return &Record{Seed: c.seed, Start: c.start, Speed: c.speed}
+}
+
+// WriteRecord saves the record of the run, to start another like it with ReadRecord and
+// NewSimFromRecord
+func (c *Sim) WriteRecord(filename string) error {
+	data, err := json.MarshalIndent(c.Record(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+func ReadRecord(filename string) (*Record, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	r := new(Record)
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if r.Seed == 0 || r.Start.IsZero() {
+		return nil, fmt.Errorf("%s: not a virtual clock record", filename)
+	}
+	return r, nil
+}
+
+func (c *Sim) Seed() int64 {
+	return c.seed
+}
+
+func (c *Sim) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+func (c *Sim) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Sleep waits for d of virtual time.  A simulated goroutine always gives way, even for a d of 0,
+// to the others due to wake before it.
+func (c *Sim) Sleep(d time.Duration) {
+	c.mtx.Lock()
+	if !c.simulated[atomic.Goid()] {
+		c.mtx.Unlock()
+		<-c.After(d)
+		return
+	}
+	ch := c.push(d, true)
+	c.running--
+	c.asleep.Broadcast()
+	c.mtx.Unlock()
+	<-ch
+}
+
+// After sends the time on the channel returned once d of virtual time has passed.  A simulated
+// goroutine waiting on it is still awake as far as the clock is concerned; it should Sleep.
+func (c *Sim) After(d time.Duration) <-chan time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if d <= 0 {
+		ch := make(chan time.Time, 1)
+		ch <- c.now
+		return ch
+	}
+	return c.push(d, false)
+}
+
+// Go starts f as a simulated goroutine.  It starts once the goroutines due to wake at the current
+// time have, in the order Go was called.
+func (c *Sim) Go(f func()) {
+	c.mtx.Lock()
+	ch := c.push(0, true)
+	c.mtx.Unlock()
+
+	go func() {
+		id := atomic.Goid()
+		c.mtx.Lock()
+		c.simulated[id] = true
+		c.mtx.Unlock()
+		defer func() {
+			c.mtx.Lock()
+			delete(c.simulated, id)
+			c.running--
+			c.asleep.Broadcast()
+			c.mtx.Unlock()
+		}()
+		<-ch
+		f()
+	}()
+}
+
+// push adds a sleeper due after d.  Must be called with the lock held.
+func (c *Sim) push(d time.Duration, simulated bool) chan time.Time {
+	if d < 0 {
+		d = 0
+	}
+	ch := make(chan time.Time, 1)
+	c.seq++
+	heap.Push(&c.sleepers, &sleeper{at: c.now.Add(d), seq: c.seq, ch: ch, simulated: simulated})
+	c.asleep.Broadcast()
+	return ch
+}
+
+// wake wakes the next sleeper, moving the clock to its deadline.  A simulated goroutine counts
+// as running from then on.  Must be called with the lock held, and only when none is running.
+func (c *Sim) wake() {
+	s := heap.Pop(&c.sleepers).(*sleeper)
+	if s.at.After(c.now) {
+		c.now = s.at
+	}
+	if s.simulated {
+		c.running++
+	}
+	s.ch <- c.now
+}
+
+// NewRand returns a source of random numbers that follows from the seed and the name
+func (c *Sim) NewRand(name string) *rand.Rand {
+	return rand.New(rand.NewSource(c.seed ^ nameSeed(name)))
+}
+
+// Advance moves the clock forward, waking the sleepers due on the way in order.  Each simulated
+// goroutine woken runs until it sleeps again before the next wakes.
+func (c *Sim) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	end := c.now.Add(d)
+	for {
+		for c.running > 0 {
+			c.asleep.Wait()
+		}
+		if len(c.sleepers) == 0 || c.sleepers[0].at.After(end) {
+			break
+		}
+		c.wake()
+	}
+	if end.After(c.now) {
+		c.now = end
+	}
+}
+
+// Run is the scheduler.  Whenever the simulated goroutines are all asleep it wakes the next
+// sleeper, until Stop is called.  The clock runs at most speed times faster than real time, so it
+// waits for the real time to catch up before moving on; that changes when things happen, never
+// in what order or at what virtual time.
+func (c *Sim) Run() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	c.stop = stop
+
+	began, from := time.Now(), c.now
+	for {
+		for c.stop == stop && (c.running > 0 || len(c.sleepers) == 0) {
+			c.asleep.Wait()
+		}
+		if c.stop != stop {
+			return
+		}
+
+		due := began.Add(time.Duration(float64(c.sleepers[0].at.Sub(from)) / c.speed))
+		if wait := time.Until(due); wait > 0 {
+			c.mtx.Unlock()
+			select {
+			case <-stop:
+			case <-time.After(wait):
+			}
+			c.mtx.Lock()
+			continue // Someone may have woken or gone to sleep meanwhile
+		}
+		c.wake()
+	}
+}
+
+func (c *Sim) Stop() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+		c.asleep.Broadcast()
+	}
+}
+
+type sleeper struct {
+	at        time.Time
+	seq       uint64
+	ch        chan time.Time
+	simulated bool // A simulated goroutine, which runs once woken
+}
+
+// sleepers is a heap of the sleepers, the next to wake first
+type sleepers []*sleeper
+
+func (s sleepers) Len() int { return len(s) }
+func (s sleepers) Less(i, j int) bool {
+	return s[i].at.Before(s[j].at) || (s[i].at.Equal(s[j].at) && s[i].seq < s[j].seq)
+}
+func (s sleepers) Swap(i, j int)       { s[i], s[j] = s[j], s[i] }
+func (s *sleepers) Push(x interface{}) { *s = append(*s, x.(*sleeper)) }
+func (s *sleepers) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	*s = old[:n-1]
+	return x
+}
@@ -51,3 +51,22 @@ func AckStatusString(status int) string {
 	}
 	return "na"
 }
+
+// Where a message waiting to get into a block is, in the mempool api
+const (
+	MempoolStatusAcked             = "acked"
+	MempoolStatusHeldForDependency = "held-dependency"
+	MempoolStatusHeldForHeight     = "held-height"
+	MempoolStatusHolding           = "holding"
+)
+
+// Why a message in the mempool is held
+const (
+	MempoolReasonMissingCommit       = "missing-commit"
+	MempoolReasonUnderpaidCommit     = "underpaid-commit"
+	MempoolReasonMissingChain        = "missing-chain"
+	MempoolReasonInsufficientEC      = "insufficient-ec"
+	MempoolReasonInsufficientFactoid = "insufficient-fct"
+	MempoolReasonFutureHeight        = "future-height"
+	MempoolReasonDependency          = "dependency"
+)
@@ -310,6 +310,16 @@ func SetCustomCoinBaseConstants() {
 	COINBASE_ACTIVATION = 0
 }
 
+// IsCoinbasePayoutHeight returns true if the coinbase transaction of a block pays the outputs of
+// the descriptor declared COINBASE_DECLARATION blocks earlier
+func IsCoinbasePayoutHeight(dbheight uint32) bool {
+	return dbheight > COINBASE_ACTIVATION && // Coinbase code must be above activation
+		dbheight != 0 && // Does not affect gensis
+		(dbheight%COINBASE_PAYOUT_FREQUENCY == 0 || dbheight%COINBASE_PAYOUT_FREQUENCY == 1) && // Frequency of payouts
+		// Cannot payout before a declaration (cannot grab below height 0)
+		dbheight > COINBASE_DECLARATION+COINBASE_PAYOUT_FREQUENCY
+}
+
 const (
 	// Limits for keeping inputs from flooding our execution
 	INMSGQUEUE_HIGH = 10000
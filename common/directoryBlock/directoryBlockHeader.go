@@ -285,7 +285,7 @@ func (b *DBlockHeader) UnmarshalBinaryData(data []byte) ([]byte, error) {
 	}
 
 	if b.BlockCount > 100000 {
-		panic("Receive: Blockcount too great in directory block" + fmt.Sprintf(":::: %d", b.BlockCount))
+		return nil, fmt.Errorf("Blockcount too great in directory block: %d", b.BlockCount)
 	}
 
 	return buf.DeepCopyBytes(), nil
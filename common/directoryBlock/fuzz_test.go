@@ -0,0 +1,26 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package directoryBlock_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func FuzzDirectoryBlock(f *testing.F) {
+	var seeds []interfaces.BinaryMarshallable
+	for _, set := range testHelper.CreateFullTestBlockSet()[:3] {
+		seeds = append(seeds, set.DBlock)
+	}
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return NewDirectoryBlock(nil) }, seeds...)
+}
+
+func FuzzDBlockHeader(f *testing.F) {
+	set := testHelper.CreateTestBlockSet(nil)
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return NewDBlockHeader() }, set.DBlock.GetHeader())
+}
@@ -0,0 +1,29 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package entryBlock_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func FuzzEBlock(f *testing.F) {
+	var seeds []interfaces.BinaryMarshallable
+	for _, set := range testHelper.CreateFullTestBlockSet()[:3] {
+		seeds = append(seeds, set.EBlock, set.AnchorEBlock)
+	}
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return NewEBlock() }, seeds...)
+}
+
+func FuzzEntry(f *testing.F) {
+	var seeds []interfaces.BinaryMarshallable
+	for _, e := range testHelper.CreateTestBlockSet(nil).Entries {
+		seeds = append(seeds, e)
+	}
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return NewEntry() }, seeds...)
+}
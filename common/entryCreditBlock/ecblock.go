@@ -339,8 +339,23 @@ func (e *ECBlock) unmarshalBodyBinaryData(data []byte) ([]byte, error) {
 	//buf := primitives.NewBuffer(data)
 	newData := data
 
-	allentries := make([]interfaces.IECBlockEntry, e.GetHeader().GetObjectCount())
-	for i := uint64(0); i < e.GetHeader().GetObjectCount(); i++ {
+	// entryLimit is the maximum number of entries that could fit in the body,
+	// as the smallest (a minute number) takes 2 bytes
+	entryLimit := uint64(len(data) / 2)
+	entryCount := e.GetHeader().GetObjectCount()
+	if entryCount > entryLimit {
+		return nil, fmt.Errorf(
+			"Error: ECBlock.UnmarshalBinary: object count %d is larger "+
+				"than body size %d. (uint underflow?)",
+			entryCount, entryLimit,
+		)
+	}
+
+	allentries := make([]interfaces.IECBlockEntry, entryCount)
+	for i := uint64(0); i < entryCount; i++ {
+		if len(newData) < 1 {
+			return nil, fmt.Errorf("Error: ECBlock.UnmarshalBinary: body ends before entry %d", i)
+		}
 		id := newData[0]
 		newData = newData[1:]
 
@@ -354,6 +369,9 @@ func (e *ECBlock) unmarshalBodyBinaryData(data []byte) ([]byte, error) {
 			allentries[i] = s
 		case constants.ECIDMinuteNumber:
 			m := NewMinuteNumber(0)
+			if len(newData) < 1 {
+				return nil, fmt.Errorf("Error: ECBlock.UnmarshalBinary: body ends in minute number %d", i)
+			}
 			_, err = m.UnmarshalBinaryData(newData[:1])
 			if err != nil {
 				return nil, err
@@ -362,6 +380,9 @@ func (e *ECBlock) unmarshalBodyBinaryData(data []byte) ([]byte, error) {
 			newData = newData[1:]
 		case constants.ECIDChainCommit:
 			c := NewCommitChain()
+			if len(newData) < 200 {
+				return nil, fmt.Errorf("Error: ECBlock.UnmarshalBinary: body ends in chain commit %d", i)
+			}
 			_, err = c.UnmarshalBinaryData(newData[:200])
 			if err != nil {
 				return nil, err
@@ -370,6 +391,9 @@ func (e *ECBlock) unmarshalBodyBinaryData(data []byte) ([]byte, error) {
 			newData = newData[200:]
 		case constants.ECIDEntryCommit:
 			c := NewCommitEntry()
+			if len(newData) < 136 {
+				return nil, fmt.Errorf("Error: ECBlock.UnmarshalBinary: body ends in entry commit %d", i)
+			}
 			_, err = c.UnmarshalBinaryData(newData[:136])
 			if err != nil {
 				return nil, err
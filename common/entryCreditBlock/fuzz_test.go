@@ -0,0 +1,31 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package entryCreditBlock_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func FuzzECBlock(f *testing.F) {
+	var seeds []interfaces.BinaryMarshallable
+	for _, set := range testHelper.CreateFullTestBlockSet()[:3] {
+		seeds = append(seeds, set.ECBlock)
+	}
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return NewECBlock() }, seeds...)
+}
+
+func FuzzCommitChain(f *testing.F) {
+	set := testHelper.CreateTestBlockSet(nil)
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return NewCommitChain() }, testHelper.NewCommitChain(set.EBlock))
+}
+
+func FuzzCommitEntry(f *testing.F) {
+	set := testHelper.CreateTestBlockSet(nil)
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return NewCommitEntry() }, testHelper.NewCommitEntry(set.EBlock))
+}
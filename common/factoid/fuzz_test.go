@@ -0,0 +1,31 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factoid_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func FuzzFBlock(f *testing.F) {
+	var seeds []interfaces.BinaryMarshallable
+	for _, set := range testHelper.CreateFullTestBlockSet()[:3] {
+		seeds = append(seeds, set.FBlock)
+	}
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return new(FBlock) }, seeds...)
+}
+
+func FuzzTransaction(f *testing.F) {
+	var seeds []interfaces.BinaryMarshallable
+	for _, set := range testHelper.CreateFullTestBlockSet()[:3] {
+		for _, tx := range set.FBlock.GetTransactions() {
+			seeds = append(seeds, tx)
+		}
+	}
+	testHelper.FuzzBinary(f, func() interfaces.BinaryMarshallable { return new(Transaction) }, seeds...)
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factoid
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// A MultisigSignature is one of the signatures in the signature block of an RCD_2.
+// The RCD_2 only holds the addresses of its signers, so each signature carries the
+// public key that made it.  The address of that key (its RCD_1) picks out which of
+// the RCD_2 addresses signed.
+type MultisigSignature struct {
+	PublicKey [constants.ADDRESS_LENGTH]byte   `json:"publickey"`
+	Signature [constants.SIGNATURE_LENGTH]byte `json:"signature"`
+}
+
+var _ interfaces.ISignature = (*MultisigSignature)(nil)
+
+func (s *MultisigSignature) IsSameAs(sig interfaces.ISignature) bool {
+	return primitives.AreBytesEqual(s.Bytes(), sig.Bytes())
+}
+
+// IsEmpty is true for a slot in a signature block that no one has signed yet
+func (s *MultisigSignature) IsEmpty() bool {
+	return s.PublicKey == [constants.ADDRESS_LENGTH]byte{}
+}
+
+// GetAddress returns the address of the key that made this signature
+func (s *MultisigSignature) GetAddress() interfaces.IAddress {
+	rcd := RCD_1{PublicKey: s.PublicKey}
+	address, _ := rcd.GetAddress()
+	return address
+}
+
+func (s *MultisigSignature) Bytes() []byte {
+	return append(s.PublicKey[:], s.Signature[:]...)
+}
+
+func (s *MultisigSignature) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(s)
+}
+
+func (s *MultisigSignature) JSONString() (string, error) {
+	return primitives.EncodeJSONString(s)
+}
+
+// MarshalJSON writes the key and the signature as hex, like the FactoidSignature
+func (s *MultisigSignature) MarshalJSON() (rval []byte, err error) {
+	defer func(pe *error) {
+		if *pe != nil {
+			fmt.Fprintf(os.Stderr, "MultisigSignature.MarshalJSON err:%v", *pe)
+		}
+	}(&err)
+	return json.Marshal(map[string]string{
+		"publickey": hex.EncodeToString(s.PublicKey[:]),
+		"signature": hex.EncodeToString(s.Signature[:]),
+	})
+}
+
+func (s MultisigSignature) String() string {
+	txt, err := s.CustomMarshalText()
+	if err != nil {
+		return "<error>"
+	}
+	return string(txt)
+}
+
+func (s *MultisigSignature) SetSignature(sig []byte) error {
+	if len(sig) != constants.SIGNATURE_LENGTH {
+		return fmt.Errorf("Bad MultisigSignature.  Should not happen")
+	}
+	copy(s.Signature[:], sig)
+	return nil
+}
+
+func (s *MultisigSignature) GetSignature() *[constants.SIGNATURE_LENGTH]byte {
+	return &s.Signature
+}
+
+func (s MultisigSignature) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(s.PublicKey[:])
+	buf.Write(s.Signature[:])
+	return buf.DeepCopyBytes(), nil
+}
+
+func (s MultisigSignature) CustomMarshalText() ([]byte, error) {
+	var out primitives.Buffer
+
+	out.WriteString(" MultisigSignature: ")
+	out.WriteString(hex.EncodeToString(s.PublicKey[:]))
+	out.WriteString(" ")
+	out.WriteString(hex.EncodeToString(s.Signature[:]))
+	out.WriteString("\n")
+
+	return out.DeepCopyBytes(), nil
+}
+
+func (s *MultisigSignature) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	if data == nil || len(data) < constants.ADDRESS_LENGTH+constants.SIGNATURE_LENGTH {
+		return nil, fmt.Errorf("Not enough data to unmarshal")
+	}
+	copy(s.PublicKey[:], data[:constants.ADDRESS_LENGTH])
+	data = data[constants.ADDRESS_LENGTH:]
+	copy(s.Signature[:], data[:constants.SIGNATURE_LENGTH])
+	return data[constants.SIGNATURE_LENGTH:], nil
+}
+
+func (s *MultisigSignature) UnmarshalBinary(data []byte) error {
+	_, err := s.UnmarshalBinaryData(data)
+	return err
+}
+
+// NewMultisigSignature signs the data with one of the keys of an RCD_2.  The private
+// key may be 32 bytes, or 64 bytes with the public key on the end.
+func NewMultisigSignature(priv, data []byte) (*MultisigSignature, error) {
+	pub, err := primitives.PrivateKeyToPublicKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	ms := new(MultisigSignature)
+	copy(ms.PublicKey[:], pub)
+	copy(ms.Signature[:], primitives.Sign(priv, data))
+	return ms, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package factoid_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestUnmarshalNilMultisigSignature(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic caught during the test - %v", r)
+		}
+	}()
+
+	a := new(MultisigSignature)
+	err := a.UnmarshalBinary(nil)
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+
+	err = a.UnmarshalBinary([]byte{})
+	if err == nil {
+		t.Errorf("Error is nil when it shouldn't be")
+	}
+}
+
+func TestMultisigSignatureMarshalUnmarshal(t *testing.T) {
+	sig, err := NewMultisigSignature(testHelper.NewPrivKey(1), []byte("some data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, _ := testHelper.NewFactoidRCDAddress(1).GetAddress()
+	if !sig.GetAddress().IsSameAs(address) {
+		t.Errorf("Signature has the address %x, not %x", sig.GetAddress().Bytes(), address.Bytes())
+	}
+
+	data, err := sig.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2 := new(MultisigSignature)
+	rest, err := sig2.UnmarshalBinaryData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("Returned %d bytes of spare data", len(rest))
+	}
+	if !sig.IsSameAs(sig2) {
+		t.Errorf("Signatures are not equal")
+	}
+}
+
+func TestMultisigSignatureBlock(t *testing.T) {
+	data := []byte("some data")
+	sigblk := NewMultisigSignatureBlock(2)
+	for _, key := range []uint64{1, 2, 1} { // Signing again with a key replaces its signature
+		sig, err := NewMultisigSignature(testHelper.NewPrivKey(key), data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigblk.AddSignature(sig)
+	}
+	if len(sigblk.GetSignatures()) != 2 {
+		t.Errorf("Expected 2 signatures, found %d", len(sigblk.GetSignatures()))
+	}
+
+	bin, err := sigblk.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigblk2 := NewMultisigSignatureBlock(2)
+	if _, err := sigblk2.UnmarshalBinaryData(bin); err != nil {
+		t.Fatal(err)
+	}
+	if !sigblk.IsSameAs(sigblk2) {
+		t.Errorf("Signature blocks are not equal")
+	}
+}
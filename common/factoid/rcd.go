@@ -53,6 +53,9 @@ func NewRCD_2(n int, m int, addresses []interfaces.IAddress) (interfaces.IRCD, e
 	if len(addresses) != m {
 		return nil, fmt.Errorf("Improper number of addresses.  m = %d n = %d #addresses = %d", m, n, len(addresses))
 	}
+	if n < 1 || n > m {
+		return nil, fmt.Errorf("Improper number of signatures required.  m = %d n = %d", m, n)
+	}
 
 	au := new(RCD_2)
 	au.N = n
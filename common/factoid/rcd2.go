@@ -7,8 +7,11 @@ package factoid
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
 
+	"github.com/FactomProject/ed25519"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 )
@@ -18,36 +21,39 @@ import (
  ************************/
 
 // Type 2 RCD implement multisig
-// m of n
+// n of m
 // Must have m addresses from which to choose, no fewer, no more
-// Must have n RCD, no fewer no more.
-// NOTE: This does mean you can have a multisig nested in a
-// multisig.  It just works.
+// Must have n signatures, no fewer no more.
+// Each address is the RCD_1 address of a public key, and each signature
+// in the signature block carries the key that made it.  Multisig nested
+// in a multisig is not supported.
 
 type RCD_2 struct {
-	M           int                   // Number signatures required
-	N           int                   // Total signatures possible
-	N_Addresses []interfaces.IAddress // n addresses
+	M           int                   // Number of addresses, the signatures possible
+	N           int                   // Number of signatures required
+	N_Addresses []interfaces.IAddress // m addresses
 }
 
 var _ interfaces.IRCD = (*RCD_2)(nil)
 
-/*************************************
- *       Stubs
- *************************************/
+/***************************************
+ *       Methods
+ ***************************************/
 
+// GetAddress returns the hash of the RCD, the same way the address of an RCD_1 is
+// the hash of its type and public key
 func (b RCD_2) GetAddress() (interfaces.IAddress, error) {
-	return nil, nil
+	data, err := b.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return CreateAddress(primitives.Shad(data)), nil
 }
 
 func (b RCD_2) NumberOfSignatures() int {
-	return 1
+	return b.N
 }
 
-/***************************************
- *       Methods
- ***************************************/
-
 func (b RCD_2) IsSameAs(rcd interfaces.IRCD) bool {
 	return b.String() == rcd.String()
 }
@@ -57,20 +63,72 @@ func (b RCD_2) UnmarshalBinary(data []byte) error {
 	return err
 }
 
+// CheckSig is true if the signature block holds exactly N valid signatures of the
+// transaction, each made by the key behind a different one of the RCD's addresses
 func (b RCD_2) CheckSig(trans interfaces.ITransaction, sigblk interfaces.ISignatureBlock) bool {
-	return false
+	if sigblk == nil || b.N < 1 || b.N > b.M || len(b.N_Addresses) != b.M {
+		return false
+	}
+	sigs := sigblk.GetSignatures()
+	if len(sigs) != b.N {
+		return false
+	}
+	data, err := trans.MarshalBinarySig()
+	if err != nil {
+		return false
+	}
+
+	signed := make(map[int]bool, b.N)
+	for _, sig := range sigs {
+		ms, ok := sig.(*MultisigSignature)
+		if !ok || ms.IsEmpty() {
+			return false
+		}
+		i := b.addressIndex(ms.GetAddress())
+		if i < 0 || signed[i] { // Not one of ours, or signed twice by the same key
+			return false
+		}
+		if !ed25519.VerifyCanonical(&ms.PublicKey, data, &ms.Signature) {
+			return false
+		}
+		signed[i] = true
+	}
+	return true
+}
+
+// addressIndex returns the index of the address in the RCD, or -1 if it isn't there
+func (b RCD_2) addressIndex(address interfaces.IAddress) int {
+	for i, a := range b.N_Addresses {
+		if primitives.AreBytesEqual(a.Bytes(), address.Bytes()) {
+			return i
+		}
+	}
+	return -1
 }
 
 func (e *RCD_2) JSONByte() ([]byte, error) {
 	return primitives.EncodeJSON(e)
 }
 
-// TODO: Fix Json marshaling of RCD_2. Right now the RCD type
-// is not included in the json marshal.
 func (e *RCD_2) JSONString() (string, error) {
 	return primitives.EncodeJSONString(e)
 }
 
+// MarshalJSON will write the RCD the way RCD_1 does, as the hex of its binary,
+// which starts with the RCD type
+func (e *RCD_2) MarshalJSON() (rval []byte, err error) {
+	defer func(pe *error) {
+		if *pe != nil {
+			fmt.Fprintf(os.Stderr, "RCD_2.MarshalJSON err:%v", *pe)
+		}
+	}(&err)
+	data, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fmt.Sprintf("%x", data))
+}
+
 func (b RCD_2) String() string {
 	txt, err := b.CustomMarshalText()
 	if err != nil {
@@ -91,6 +149,10 @@ func (w RCD_2) Clone() interfaces.IRCD {
 }
 
 func (a RCD_2) MarshalBinary() ([]byte, error) {
+	if len(a.N_Addresses) != a.M {
+		return nil, fmt.Errorf("RCD_2 has %d addresses, but m = %d", len(a.N_Addresses), a.M)
+	}
+
 	var out primitives.Buffer
 
 	binary.Write(&out, binary.BigEndian, uint8(2))
@@ -152,6 +214,10 @@ func (t *RCD_2) UnmarshalBinaryData(data []byte) (newData []byte, err error) {
 }
 
 func (a RCD_2) CustomMarshalText() ([]byte, error) {
+	if len(a.N_Addresses) != a.M {
+		return nil, fmt.Errorf("RCD_2 has %d addresses, but m = %d", len(a.N_Addresses), a.M)
+	}
+
 	var out primitives.Buffer
 
 	primitives.WriteNumber8(&out, uint8(2)) // Type 2 Authorization
@@ -10,6 +10,7 @@ import (
 
 	. "github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
 )
 
 func TestUnmarshalNilRCD_2(t *testing.T) {
@@ -128,3 +129,148 @@ func nextAuth2_rcd2() *RCD_2 {
 	rcd, _ := NewRCD_2(n, m, addresses)
 	return rcd.(*RCD_2)
 }
+
+func TestRCD2JSONMarshal(t *testing.T) {
+	rcd := nextAuth2_rcd2()
+	s, err := rcd.JSONString()
+	if err != nil {
+		t.Error(err)
+	}
+	if s[1:3] != "02" {
+		t.Errorf("Not prepended by rcd type, found %s", s)
+	}
+}
+
+// newMultisig returns an n of m RCD_2 whose addresses are those of testHelper keys 0 to m-1
+func newMultisig(n, m int) *RCD_2 {
+	addresses := make([]interfaces.IAddress, m)
+	for i := range addresses {
+		addresses[i], _ = testHelper.NewFactoidRCDAddress(uint64(i)).GetAddress()
+	}
+	rcd, err := NewRCD_2(n, m, addresses)
+	if err != nil {
+		panic(err)
+	}
+	return rcd.(*RCD_2)
+}
+
+// newMultisigTransaction spends from a 2 of 3 RCD_2, signed by the given testHelper keys
+func newMultisigTransaction(t *testing.T, signers ...uint64) *Transaction {
+	rcd := newMultisig(2, 3)
+	address, err := rcd.GetAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := new(Transaction)
+	tx.AddInput(address, 1000000)
+	tx.AddOutput(testHelper.NewFactoidAddress(10), 900000)
+	tx.AddAuthorization(rcd)
+
+	data, err := tx.MarshalBinarySig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigblk := NewMultisigSignatureBlock(rcd.NumberOfSignatures())
+	for _, signer := range signers {
+		sig, err := NewMultisigSignature(testHelper.NewPrivKey(signer), data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigblk.AddSignature(sig)
+	}
+	tx.SetSignatureBlock(0, sigblk)
+	return tx
+}
+
+func TestRCD2CheckSig(t *testing.T) {
+	tx := newMultisigTransaction(t, 0, 2)
+	if err := tx.Validate(1); err != nil {
+		t.Error(err)
+	}
+	if err := tx.ValidateSignatures(); err != nil {
+		t.Error(err)
+	}
+
+	// The signatures survive marshalling
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2 := new(Transaction)
+	rest, err := tx2.UnmarshalBinaryData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("Returned %d bytes of spare data", len(rest))
+	}
+	if !tx.IsSameAs(tx2) {
+		t.Errorf("Transactions are not equal")
+	}
+	if err := tx2.ValidateSignatures(); err != nil {
+		t.Error(err)
+	}
+
+	// Each signature is charged for
+	fee1, err := newMultisigTransaction(t, 0).CalculateFee(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee2, err := tx.CalculateFee(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee2 != fee1 {
+		t.Errorf("Fee depends on the signatures given, %d vs %d", fee1, fee2)
+	}
+	if fee1 != 1000*(1+10+2) {
+		t.Errorf("Wrong fee %d", fee1)
+	}
+}
+
+func TestRCD2CheckSigFails(t *testing.T) {
+	for _, signers := range [][]uint64{
+		{},        // unsigned
+		{1},       // too few
+		{0, 3},    // key 3 isn't one of the addresses
+		{0, 1, 2}, // more than the signatures required
+	} {
+		tx := newMultisigTransaction(t, signers...)
+		if tx.ValidateSignatures() == nil {
+			t.Errorf("Signed by %v, but the signatures validated", signers)
+		}
+	}
+
+	// The same key twice
+	tx := newMultisigTransaction(t, 1)
+	data, err := tx.MarshalBinarySig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := NewMultisigSignature(testHelper.NewPrivKey(1), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.GetSignatureBlock(0).(*SignatureBlock).Signatures[1] = sig
+	if tx.ValidateSignatures() == nil {
+		t.Errorf("Signed twice by one key, but the signatures validated")
+	}
+
+	// A signature of some other transaction
+	tx = newMultisigTransaction(t, 0, 1)
+	tx.AddOutput(testHelper.NewFactoidAddress(11), 1)
+	if tx.ValidateSignatures() == nil {
+		t.Errorf("Transaction changed after signing, but the signatures validated")
+	}
+}
+
+func TestNewRCD2Bad(t *testing.T) {
+	addresses := newMultisig(1, 3).N_Addresses
+	if _, err := NewRCD_2(0, 3, addresses); err == nil {
+		t.Errorf("Made an RCD_2 needing no signatures")
+	}
+	if _, err := NewRCD_2(4, 3, addresses); err == nil {
+		t.Errorf("Made an RCD_2 needing more signatures than addresses")
+	}
+}
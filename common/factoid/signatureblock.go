@@ -64,7 +64,13 @@ func (b SignatureBlock) String() string {
 	return string(txt)
 }
 
+// AddSignature sets the signature of an RCD_1.  A MultisigSignature instead replaces
+// the one made by the same key, or else fills the first empty slot of the block.
 func (s *SignatureBlock) AddSignature(sig interfaces.ISignature) {
+	if ms, ok := sig.(*MultisigSignature); ok {
+		s.addMultisigSignature(ms)
+		return
+	}
 	if len(s.Signatures) > 0 {
 		s.Signatures[0] = sig
 	} else {
@@ -72,6 +78,25 @@ func (s *SignatureBlock) AddSignature(sig interfaces.ISignature) {
 	}
 }
 
+func (s *SignatureBlock) addMultisigSignature(sig *MultisigSignature) {
+	free := -1
+	for i, old := range s.Signatures {
+		ms, ok := old.(*MultisigSignature)
+		if ok && ms.PublicKey == sig.PublicKey {
+			s.Signatures[i] = sig
+			return
+		}
+		if free < 0 && (!ok || ms.IsEmpty()) {
+			free = i
+		}
+	}
+	if free < 0 {
+		s.Signatures = append(s.Signatures, sig)
+		return
+	}
+	s.Signatures[free] = sig
+}
+
 func (s SignatureBlock) GetSignature(index int) interfaces.ISignature {
 	if len(s.Signatures) <= index {
 		return nil
@@ -116,13 +141,20 @@ func (s SignatureBlock) CustomMarshalText() ([]byte, error) {
 	return out.DeepCopyBytes(), nil
 }
 
+// UnmarshalBinaryData reads a signature into each slot of the block, so a block made
+// by NewMultisigSignatureBlock reads the signatures of an RCD_2.  A block without slots
+// reads the one signature of an RCD_1.
 func (s *SignatureBlock) UnmarshalBinaryData(data []byte) ([]byte, error) {
 	buf := primitives.NewBuffer(data)
-	s.Signatures = make([]interfaces.ISignature, 1)
-	s.Signatures[0] = new(FactoidSignature)
-	err := buf.PopBinaryMarshallable(s.Signatures[0])
-	if err != nil {
-		return nil, err
+	if s.Signatures == nil {
+		s.Signatures = make([]interfaces.ISignature, 1)
+		s.Signatures[0] = new(FactoidSignature)
+	}
+	for _, sig := range s.Signatures {
+		err := buf.PopBinaryMarshallable(sig)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return buf.DeepCopyBytes(), nil
 }
@@ -132,3 +164,30 @@ func NewSingleSignatureBlock(priv, data []byte) *SignatureBlock {
 	s.AddSignature(NewED25519Signature(priv, data))
 	return s
 }
+
+// NewMultisigSignatureBlock returns the signature block of an RCD_2 that requires n
+// signatures, with n empty slots for AddSignature to fill
+func NewMultisigSignatureBlock(n int) *SignatureBlock {
+	s := new(SignatureBlock)
+	s.Signatures = make([]interfaces.ISignature, n)
+	for i := range s.Signatures {
+		s.Signatures[i] = new(MultisigSignature)
+	}
+	return s
+}
+
+// multisigLayout returns the signatures of the block laid out the way an RCD_2 that
+// requires n signatures is marshalled: n MultisigSignatures, empty where the block
+// has none.
+func multisigLayout(sigblk interfaces.ISignatureBlock, n int) *SignatureBlock {
+	s := NewMultisigSignatureBlock(n)
+	if sigblk == nil {
+		return s
+	}
+	for i, sig := range sigblk.GetSignatures() {
+		if ms, ok := sig.(*MultisigSignature); ok && i < n {
+			s.Signatures[i] = ms
+		}
+	}
+	return s
+}
@@ -437,6 +437,46 @@ func (t *Transaction) GetRCD(i int) (interfaces.IRCD, error) {
 // UnmarshalBinary assumes that the Binary is all good.  We do error
 // out if there isn't enough data, or the transaction is too large.
 func (t *Transaction) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	data, err := t.UnmarshalBinarySigData(data)
+	if err != nil {
+		return nil, err
+	}
+	buf := primitives.NewBuffer(data)
+
+	t.RCDs = make([]interfaces.IRCD, len(t.Inputs))
+	t.SigBlocks = make([]interfaces.ISignatureBlock, len(t.Inputs))
+
+	for i := 0; i < len(t.Inputs); i++ {
+		b, err := buf.PeekByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != 1 && b != 2 {
+			return nil, fmt.Errorf("Transaction has an RCD of unknown type %d", b)
+		}
+		t.RCDs[i] = CreateRCD([]byte{b})
+		err = buf.PopBinaryMarshallable(t.RCDs[i])
+		if err != nil {
+			return nil, err
+		}
+		if rcd2, ok := t.RCDs[i].(*RCD_2); ok {
+			t.SigBlocks[i] = NewMultisigSignatureBlock(rcd2.N)
+		} else {
+			t.SigBlocks[i] = new(SignatureBlock)
+		}
+		err = buf.PopBinaryMarshallable(t.SigBlocks[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.DeepCopyBytes(), nil
+}
+
+// UnmarshalBinarySigData unmarshals what MarshalBinarySig() writes, a
+// transaction without its RCDs and signature blocks, as it is before
+// it is signed.
+func (t *Transaction) UnmarshalBinarySigData(data []byte) ([]byte, error) {
 	buf := primitives.NewBuffer(data)
 
 	v, err := buf.PopVarInt()
@@ -499,26 +539,8 @@ func (t *Transaction) UnmarshalBinaryData(data []byte) ([]byte, error) {
 		t.OutECs[i].(*TransAddress).UserAddress = primitives.ConvertECAddressToUserStr(t.OutECs[i].(*TransAddress).Address)
 	}
 
-	t.RCDs = make([]interfaces.IRCD, len(t.Inputs))
-	t.SigBlocks = make([]interfaces.ISignatureBlock, len(t.Inputs))
-
-	for i := 0; i < len(t.Inputs); i++ {
-		b, err := buf.PeekByte()
-		if err != nil {
-			return nil, err
-		}
-		t.RCDs[i] = CreateRCD([]byte{b})
-		err = buf.PopBinaryMarshallable(t.RCDs[i])
-		if err != nil {
-			return nil, err
-		}
-		t.SigBlocks[i] = new(SignatureBlock)
-		err = buf.PopBinaryMarshallable(t.SigBlocks[i])
-		if err != nil {
-			return nil, err
-		}
-	}
-
+	t.RCDs = nil
+	t.SigBlocks = nil
 	t.Txid = t.GetSigHash()
 	return buf.DeepCopyBytes(), nil
 }
@@ -606,15 +628,17 @@ func (t Transaction) MarshalBinary() ([]byte, error) {
 			return nil, err
 		}
 
-		// Then write its signature blocks.  This needs to be
-		// reworked so we use the information from the RCD block
-		// to control the writing of the signatures.  After all,
-		// we don't want to restrict what might be required to
-		// sign an input.
+		// Then write its signature blocks.  The RCD controls the
+		// writing of the signatures: an RCD_1 has the one, and an
+		// RCD_2 has a slot for each of the signatures it requires.
 		if len(t.SigBlocks) <= i {
 			t.SigBlocks = append(t.SigBlocks, new(SignatureBlock))
 		}
-		err = buf.PushBinaryMarshallable(t.SigBlocks[i])
+		var sigblk interfaces.ISignatureBlock = t.SigBlocks[i]
+		if rcd2, ok := rcd.(*RCD_2); ok {
+			sigblk = multisigLayout(sigblk, rcd2.N)
+		}
+		err = buf.PushBinaryMarshallable(sigblk)
 		if err != nil {
 			return nil, err
 		}
@@ -148,7 +148,9 @@ func getSignedTrans() interfaces.ITransaction {
 	}
 
 	for i := 0; i < 2; i++ {
-		t.AddAuthorization(nextAuth2())
+		rcd := nextAuth2()
+		t.AddAuthorization(rcd)
+		t.SetSignatureBlock(3+i, NewMultisigSignatureBlock(rcd.NumberOfSignatures()))
 	}
 
 	return nb
@@ -307,3 +309,26 @@ func TestHasUserAddress(t *testing.T) {
 		}
 	}
 }
+
+func TestUnmarshalBinarySigData(t *testing.T) {
+	tx := getDeterministicTransaction()
+	data, err := tx.MarshalBinarySig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx2 := new(Transaction)
+	rest, err := tx2.UnmarshalBinarySigData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("Returned %d bytes of spare data", len(rest))
+	}
+	if len(tx2.GetRCDs()) != 0 {
+		t.Errorf("Unsigned transaction has %d RCDs", len(tx2.GetRCDs()))
+	}
+	if !tx2.GetSigHash().IsSameAs(tx.GetSigHash()) {
+		t.Errorf("SigHash %v is not %v", tx2.GetSigHash(), tx.GetSigHash())
+	}
+}
@@ -68,8 +68,20 @@ type FactomParams struct {
 	StderrLog                string
 	DebugLogRegEx            string
 	ConfigPath               string
-	CheckChainHeads          bool // Run checkchain heads on boot
-	FixChainHeads            bool // Only matters if CheckChainHeads == true
+	CheckChainHeads          bool    // Run checkchain heads on boot
+	FixChainHeads            bool    // Only matters if CheckChainHeads == true
+	IntegrityCheck           bool    // Check the database in the background while running
+	IntegrityRepair          bool    // Only matters if IntegrityCheck == true
+	AddressIndex             bool    // Index the history of every factoid and entry credit address
+	BalanceCheckpoints       int     // Keep the balances at every height, with a full copy every so many heights.  0 is off
+	FinalArchive             string  // Write an archive of the final state of the Factom era to this file
+	Scenario                 string  // Run the simulation described by this scenario file
+	SimClock                 bool    // Run the simulation on a virtual clock
+	SimSeed                  int64   // Seed of the virtual clock, 0 to pick one
+	SimSpeed                 float64 // Virtual seconds per real second
+	SimReplay                string  // Replay the run recorded in this file
+	Replay                   bool    // Fed a journal by factomd replay, so it doesn't journal itself
+	Invariants               bool    // Check the safety invariants of the consensus while simulating
 	ControlPanelSetting      string
 	WriteProcessedDBStates   bool // Write processed DBStates to debug file
 	NodeName                 string
@@ -1,8 +1,12 @@
 package identity
 
 import (
+	"sort"
+	"sync"
+
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/identityEntries"
+	"github.com/FactomProject/factomd/common/interfaces"
 )
 
 // CoinbaseCancelManager handles keeping track of coinbase cancel signals
@@ -23,6 +27,9 @@ type CoinbaseCancelManager struct {
 
 	// Need a reference to the authority set
 	im *IdentityManager
+
+	// Held while the maps change, so Votes can be called from outside the state's goroutine
+	mtx sync.RWMutex
 }
 
 func NewCoinbaseCancelManager(im *IdentityManager) *CoinbaseCancelManager {
@@ -37,6 +44,8 @@ func NewCoinbaseCancelManager(im *IdentityManager) *CoinbaseCancelManager {
 // GC is garbage collecting old proposals
 //		dbheight is the current height.
 func (c *CoinbaseCancelManager) GC(dbheight uint32) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
 	count := 0
 	// These are sorting in incrementing order.
 	for _, h := range c.ProposalsList {
@@ -56,6 +65,8 @@ func (c *CoinbaseCancelManager) GC(dbheight uint32) {
 
 // AddCancel will add a proposal to the list. It assumes the height check has already been done
 func (cm *CoinbaseCancelManager) AddCancel(cc identityEntries.NewCoinbaseCancelStruct) {
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
 	list, ok := cm.Proposals[cc.CoinbaseDescriptorHeight]
 	if !ok {
 		// A new height is added, we also need to insert it into our proposalsList
@@ -128,9 +139,29 @@ func (cm *CoinbaseCancelManager) isCoinbaseCancelled(descriptorHeight, index uin
 	return false
 }
 
+// Votes returns the identities that voted to cancel each output of a descriptor, sorted by chain
+// id, leaving out the outputs already recorded as cancelled in an admin block
+func (cm *CoinbaseCancelManager) Votes(descriptorHeight uint32) map[uint32][]interfaces.IHash {
+	cm.mtx.RLock()
+	defer cm.mtx.RUnlock()
+	votes := make(map[uint32][]interfaces.IHash)
+	for index, list := range cm.Proposals[descriptorHeight] {
+		if cm.AdminBlockRecord[descriptorHeight][index] || len(list) == 0 {
+			continue
+		}
+		for _, v := range list {
+			votes[index] = append(votes[index], v.RootIdentityChainID)
+		}
+		sort.Slice(votes[index], func(i, j int) bool { return votes[index][i].String() < votes[index][j].String() })
+	}
+	return votes
+}
+
 // MarkAdminBlockRecorded will mark a given index for a descriptor already canceled. This is to prevent
 // a given index from being recorded multiple times
 func (cm *CoinbaseCancelManager) MarkAdminBlockRecorded(descriptorHeight uint32, index uint32) {
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
 	if _, ok := cm.AdminBlockRecord[descriptorHeight]; !ok {
 		cm.AddNewProposalHeight(descriptorHeight)
 		cm.AdminBlockRecord[descriptorHeight] = make(map[uint32]bool, 0)
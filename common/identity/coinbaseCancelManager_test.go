@@ -135,6 +135,28 @@ func newCoinbaseCancel(id *Authority, h, i uint32) identityEntries.NewCoinbaseCa
 	return *cc
 }
 
+func TestCancelVotes(t *testing.T) {
+	im := RandomIdentityManagerWithCounts(3, 0)
+	c := NewCoinbaseCancelManager(im)
+	auths := im.GetSortedAuthorities()
+
+	c.AddCancel(newCoinbaseCancel(auths[2].(*Authority), 10, 0))
+	c.AddCancel(newCoinbaseCancel(auths[0].(*Authority), 10, 0))
+	c.AddCancel(newCoinbaseCancel(auths[1].(*Authority), 10, 1))
+	c.MarkAdminBlockRecorded(10, 1)
+
+	votes := c.Votes(10)
+	if len(votes) != 1 || len(votes[0]) != 2 {
+		t.Fatalf("expected 2 votes for output 0 only, found %v", votes)
+	}
+	if !votes[0][0].IsSameAs(auths[0].GetAuthorityChainID()) || !votes[0][1].IsSameAs(auths[2].GetAuthorityChainID()) {
+		t.Errorf("votes not sorted by chain id: %v", votes[0])
+	}
+	if len(c.Votes(11)) != 0 {
+		t.Errorf("expected no votes at height 11")
+	}
+}
+
 func TestCancelGC(t *testing.T) {
 	dbheight := uint32(10)
 
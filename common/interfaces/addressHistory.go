@@ -0,0 +1,20 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// AddressTxRef is a transaction that changed the balance of a factoid or entry credit address
+type AddressTxRef struct {
+	DBHeight uint32 `json:"dbheight"` // Height of the block holding the transaction
+	TxID     string `json:"txid"`     // Factoid transaction id, or the id of the EC commit
+	Delta    int64  `json:"delta"`    // Change to the balance, in factoshis or entry credits
+	Balance  int64  `json:"balance"`  // Balance after the transaction
+}
+
+// AddressHistory is a page of the transactions that touched an address
+type AddressHistory struct {
+	IndexHeight  uint32          `json:"indexheight"`  // The index holds all the blocks up to this height
+	Total        uint32          `json:"total"`        // Number of transactions that touched the address
+	Transactions []*AddressTxRef `json:"transactions"` // Oldest first
+}
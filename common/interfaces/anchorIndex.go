@@ -0,0 +1,44 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// The chains directory blocks are anchored in
+const (
+	AnchorChainBitcoin  = "bitcoin"
+	AnchorChainEthereum = "ethereum"
+)
+
+// AnchorRef is an anchor record of a directory block, or of a window of them, read from the
+// Bitcoin or Ethereum anchor chain
+type AnchorRef struct {
+	Chain       string `json:"chain"`              // AnchorChainBitcoin or AnchorChainEthereum
+	EntryHash   string `json:"entryhash"`          // Anchor chain entry holding the record
+	DBHeight    uint32 `json:"dbheight"`           // Highest directory block anchored by the record
+	KeyMR       string `json:"keymr,omitempty"`    // KeyMR of that directory block, if the record has it
+	DBHeightMin uint32 `json:"dbheightmin"`        // The directory blocks anchored by the record
+	DBHeightMax uint32 `json:"dbheightmax"`        // Both are DBHeight for a record of one block
+	WindowMR    string `json:"windowmr,omitempty"` // Merkle root of the KeyMRs of a window of blocks
+	TxID        string `json:"txid"`               // Bitcoin or Ethereum transaction holding the anchor
+	BlockHeight int64  `json:"blockheight"`        // Bitcoin or Ethereum block holding the transaction
+	BlockHash   string `json:"blockhash"`          // and its hash
+	TxIndex     int64  `json:"txindex"`            // Offset of the Bitcoin transaction, or index of the Ethereum one, in its block
+	Address     string `json:"address"`            // Bitcoin address, or Ethereum contract, of the anchor
+}
+
+// AnchorStatus is how a directory block is anchored.  A chain is nil until a record
+// anchoring the block is found on it.
+type AnchorStatus struct {
+	DBHeight uint32     `json:"dbheight"`
+	KeyMR    string     `json:"keymr"`
+	Bitcoin  *AnchorRef `json:"bitcoin"`
+	Ethereum *AnchorRef `json:"ethereum"`
+}
+
+// LatestAnchors are the records anchoring the highest directory block anchored on each
+// chain, nil for a chain with no anchors yet
+type LatestAnchors struct {
+	Bitcoin  *AnchorRef `json:"bitcoin"`
+	Ethereum *AnchorRef `json:"ethereum"`
+}
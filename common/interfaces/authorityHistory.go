@@ -0,0 +1,59 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// The changes an admin block entry makes to an authority
+const (
+	AuthorityAddedFederated  = "added-federated"  // New federated server
+	AuthorityAddedAudit      = "added-audit"      // New audit server
+	AuthorityPromoted        = "promoted"         // Audit server made a federated server
+	AuthorityDemoted         = "demoted"          // Federated server made an audit server
+	AuthorityRemoved         = "removed"          // Federated or audit server removed
+	AuthoritySigningKey      = "signing-key"      // New block signing key
+	AuthorityAnchorKey       = "anchor-key"       // New Bitcoin anchor key
+	AuthorityEfficiency      = "efficiency"       // New efficiency
+	AuthorityCoinbaseAddress = "coinbase-address" // New coinbase address
+	AuthorityMatryoshkaHash  = "matryoshka-hash"  // New matryoshka hash
+	AuthorityStatusFederated = "federated"
+	AuthorityStatusAudit     = "audit"
+)
+
+// AuthorityAtHeight is an authority as it was when a block was built
+type AuthorityAtHeight struct {
+	ChainID           string   `json:"chainid"`
+	ManagementChainID string   `json:"managementchainid,omitempty"` // From the identity chains, if the identity is known
+	Status            string   `json:"status"`                      // AuthorityStatusFederated or AuthorityStatusAudit
+	SigningKey        string   `json:"signingkey"`
+	MatryoshkaHash    string   `json:"matryoshkahash"`
+	AnchorKeys        []string `json:"anchorkeys"` // Bitcoin anchor keys, by key level
+	Efficiency        uint16   `json:"efficiency"`
+	CoinbaseAddress   string   `json:"coinbaseaddress"`
+}
+
+// AuthoritySet is the federated and audit servers that built a block, each sorted by chain id
+type AuthoritySet struct {
+	DBHeight  uint32               `json:"dbheight"`
+	Federated []*AuthorityAtHeight `json:"federated"`
+	Audit     []*AuthorityAtHeight `json:"audit"`
+}
+
+// AuthorityChange is an admin block entry that changed an authority
+type AuthorityChange struct {
+	DBHeight     uint32 `json:"dbheight"`           // Admin block holding the entry
+	ActiveHeight uint32 `json:"activeheight"`       // First block built with the change
+	EntryIndex   int    `json:"entryindex"`         // Position of the entry in the admin block
+	Entry        string `json:"entry"`              // Type of the entry, such as AddFederatedServer
+	Change       string `json:"change"`             // One of the Authority... changes
+	ChainID      string `json:"chainid"`            // Identity of the authority
+	Previous     string `json:"previous,omitempty"` // Status, key, efficiency or address before the change
+	Value        string `json:"value,omitempty"`    // and after it
+}
+
+// AuthorityChanges is a page of the authority change log
+type AuthorityChanges struct {
+	IndexHeight uint32             `json:"indexheight"` // The log holds all the admin blocks up to this height
+	Total       uint32             `json:"total"`       // Number of changes matching the query
+	Changes     []*AuthorityChange `json:"changes"`     // Oldest first
+}
@@ -0,0 +1,16 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+import "time"
+
+// IClock is where the timers, timeouts and timestamps of a node get the time from.  It is the
+// wall clock when running for real, and can be a virtual clock in the simulator.
+type IClock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// The kinds of coinbase descriptor
+const (
+	CoinbaseAuthorities = "authorities" // Pays the authorities, declared on heights divisible by the payout frequency
+	CoinbaseGrants      = "grants"      // Pays the grants, declared on the heights after them
+)
+
+// AdminEntryLink points to an entry of a saved admin block
+type AdminEntryLink struct {
+	DBHeight   uint32 `json:"dbheight"`   // Admin block holding the entry
+	EntryIndex int    `json:"entryindex"` // Position of the entry in the admin block
+	Entry      string `json:"entry"`      // Type of the entry, such as CoinbaseDescriptor
+}
+
+// CoinbaseOutput is an output of a coinbase descriptor
+type CoinbaseOutput struct {
+	Index     uint32                   `json:"index"`
+	Address   string                   `json:"address"`
+	Amount    uint64                   `json:"amount"`              // Factoshis
+	Authority *CoinbaseOutputAuthority `json:"authority,omitempty"` // The authority paid, for an authority descriptor
+	Cancelled *AdminEntryLink          `json:"cancelled,omitempty"` // The entry cancelling the output, if one is saved
+	Votes     *CoinbaseCancelVotes     `json:"votes,omitempty"`     // Votes to cancel it not recorded in an admin block yet
+}
+
+// CoinbaseOutputAuthority is the authority an output pays, and the admin block entries that set
+// its efficiency and coinbase address
+type CoinbaseOutputAuthority struct {
+	ChainID         string          `json:"chainid"`
+	Efficiency      uint16          `json:"efficiency"`
+	EfficiencyEntry *AdminEntryLink `json:"efficiencyentry,omitempty"` // Nil for the default efficiency or a pending one
+	AddressEntry    *AdminEntryLink `json:"addressentry,omitempty"`
+}
+
+// CoinbaseCancelVotes are the votes in identity chains to cancel an output.  An output with
+// enough votes is cancelled by an entry of the next admin block.
+type CoinbaseCancelVotes struct {
+	Votes  []string `json:"votes"`  // Authorities that voted, by chain id
+	Needed int      `json:"needed"` // Votes of current authorities a cancel takes
+}
+
+// CoinbasePendingChange is an efficiency or coinbase address set in an authority's identity
+// chains that no admin block holds yet.  The next admin block holds it, so it applies to the
+// descriptors declared from the height after that.
+type CoinbasePendingChange struct {
+	ChainID      string `json:"chainid"`
+	Change       string `json:"change"` // AuthorityEfficiency or AuthorityCoinbaseAddress
+	Previous     string `json:"previous"`
+	Value        string `json:"value"`
+	ActiveHeight uint32 `json:"activeheight"` // First descriptor height it applies to
+}
+
+// CoinbasePayout is a coinbase payout, as it was paid for a saved block and as it is projected
+// to be paid for a future one
+type CoinbasePayout struct {
+	PayoutHeight        uint32                   `json:"payoutheight"`     // Block holding the coinbase transaction
+	DescriptorHeight    uint32                   `json:"descriptorheight"` // Block declaring its outputs
+	Kind                string                   `json:"kind"`             // CoinbaseAuthorities or CoinbaseGrants
+	Projected           bool                     `json:"projected"`        // The payout block isn't saved yet
+	DescriptorProjected bool                     `json:"descriptorprojected"`
+	Descriptor          *AdminEntryLink          `json:"descriptor,omitempty"` // The descriptor entry, once saved
+	Outputs             []*CoinbaseOutput        `json:"outputs"`
+	Total               uint64                   `json:"total"`                   // Factoshis paid by the outputs not cancelled
+	PendingChanges      []*CoinbasePendingChange `json:"pendingchanges"`          // Changes not in an admin block yet
+	TransactionID       string                   `json:"transactionid,omitempty"` // The coinbase transaction, once saved
+}
@@ -42,7 +42,6 @@ type DBOverlaySimple interface {
 	ProcessECBlockMultiBatch(IEntryCreditBlock, bool) (err error)
 	ProcessFBlockMultiBatch(DatabaseBlockWithEntries) error
 	FetchDirBlockInfoByKeyMR(hash IHash) (IDirBlockInfo, error)
-	SetExportData(path string)
 	StartMultiBatch()
 	Trim()
 	FetchAllEntriesByChainID(chainID IHash) ([]IEBEntry, error)
@@ -58,7 +57,6 @@ type DBOverlay interface {
 	IDatabase
 
 	FetchHeadIndexByChainID(chainID IHash) (IHash, error)
-	SetExportData(path string)
 
 	StartMultiBatch()
 	PutInMultiBatch(records []Record)
@@ -0,0 +1,58 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// The outcomes of an election
+const (
+	ElectionRunning   = "running"   // Still electing
+	ElectionElected   = "elected"   // An audit server replaced the faulted leader
+	ElectionAbandoned = "abandoned" // The block moved on before an audit server was elected
+)
+
+// ElectionRecord is the timeline of an election, as this node saw it
+type ElectionRecord struct {
+	ID            uint64               `json:"id"`           // Numbers the elections seen by this node
+	DBHeight      uint32               `json:"dbheight"`     // Block of the missing message
+	Minute        int                  `json:"minute"`       // Minute of the missing EOM, -1 for a DBSig
+	VMIndex       int                  `json:"vmindex"`      // VM of the faulted leader
+	FaultedIndex  int                  `json:"faultedindex"` // Federated server index of the faulted leader
+	FaultedID     string               `json:"faultedid"`    // and its identity
+	Started       int64                `json:"started"`      // Unix milliseconds the fault was detected
+	Ended         int64                `json:"ended"`        // and the election ended, 0 while running
+	DurationMs    int64                `json:"durationms"`   // Milliseconds from the fault to the end
+	Rounds        int                  `json:"rounds"`       // Rounds of audit server volunteers
+	Outcome       string               `json:"outcome"`      // One of the Election... outcomes
+	ElectedID     string               `json:"electedid"`    // Audit server elected, if one was
+	Federated     []string             `json:"federated"`    // The federated and audit servers of the election
+	Audit         []string             `json:"audit"`
+	Volunteers    []*ElectionVolunteer `json:"volunteers"`    // Audit servers that volunteered, in order
+	Votes         []*ElectionVotes     `json:"votes"`         // Votes received for each volunteer
+	Levels        []*ElectionLevel     `json:"levels"`        // Level messages received
+	LevelMessages int                  `json:"levelmessages"` // Level messages received, which may be more than the Levels kept
+}
+
+// ElectionVolunteer is an audit server volunteering to replace the faulted leader
+type ElectionVolunteer struct {
+	ServerID string `json:"serverid"`
+	Round    int    `json:"round"`
+	Time     int64  `json:"time"` // Unix milliseconds
+}
+
+// ElectionVotes are the federated servers that voted for a volunteer
+type ElectionVotes struct {
+	ServerID string   `json:"serverid"` // The volunteer
+	Votes    int      `json:"votes"`
+	Voters   []string `json:"voters"`
+}
+
+// ElectionLevel is a level message of a federated server, voting at a level and rank for a volunteer
+type ElectionLevel struct {
+	Signer    string `json:"signer"`
+	Volunteer string `json:"volunteer"`
+	Level     uint32 `json:"level"`
+	Rank      uint32 `json:"rank"`
+	Committed bool   `json:"committed"`
+	Time      int64  `json:"time"` // Unix milliseconds
+}
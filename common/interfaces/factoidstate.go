@@ -54,4 +54,8 @@ type IFactoidState interface {
 	GetMultipleFactoidBalances([32]byte) (uint32, uint32, int64, int64, string)
 
 	GetMultipleECBalances([32]byte) (uint32, uint32, int64, int64, string)
+
+	// Get all the factoid and entry credit balances at the end of a past block.  The maps
+	// must not be changed.  Needs the balance history to be kept.
+	GetBalancesAt(dbheight uint32) (fct map[[32]byte]int64, ec map[[32]byte]int64, err error)
 }
@@ -0,0 +1,32 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// IntegrityFinding is a single problem found in the database by the background
+// integrity checker.
+type IntegrityFinding struct {
+	Kind     string `json:"kind"`     // What kind of problem this is, eg "missing-entry"
+	DBHeight uint32 `json:"dbheight"` // Directory block height the problem was found at
+	Hash     string `json:"hash"`     // Hash of the block, entry or chain involved
+	Detail   string `json:"detail"`   // Human readable description
+	Repaired bool   `json:"repaired"` // True if a repair was written or requested
+	Pass     int    `json:"pass"`     // The pass over the database that found the problem
+	Time     int64  `json:"time"`     // Unix time in seconds when the problem was found
+}
+
+// IntegrityReport summarises the state of the background integrity checker
+type IntegrityReport struct {
+	Enabled       bool                `json:"enabled"`
+	Repair        bool                `json:"repair"`
+	Running       bool                `json:"running"`
+	Pass          int                 `json:"pass"`          // Number of the current (or last) pass
+	Position      uint32              `json:"position"`      // Height being checked in the current pass
+	End           uint32              `json:"end"`           // Last height the current pass will check
+	PassStarted   int64               `json:"passstarted"`   // Unix time the current pass started
+	LastPassEnded int64               `json:"lastpassended"` // Unix time the last complete pass ended
+	TotalFindings int                 `json:"totalfindings"` // Findings over the life of the node
+	Counts        map[string]int      `json:"counts"`        // Findings over the life of the node, by kind
+	Findings      []*IntegrityFinding `json:"findings"`      // Most recent findings
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// LoadProfile describes the load the simulator's load generator puts on the network
+type LoadProfile struct {
+	Rate      float64       `json:"rate" yaml:"rate"`           // Messages per second, 0 stops the load
+	Curve     string        `json:"curve" yaml:"curve"`         // How the rate changes over time: constant (the default), burst or sine
+	Period    float64       `json:"period" yaml:"period"`       // burst, sine: seconds the curve repeats over
+	Burst     float64       `json:"burst" yaml:"burst"`         // burst: how many times the rate is multiplied by during a burst
+	Duty      float64       `json:"duty" yaml:"duty"`           // burst: share of each period spent bursting, 0 to 1
+	Amplitude float64       `json:"amplitude" yaml:"amplitude"` // sine: share of the rate the curve swings by, 0 to 1
+	Mix       LoadMix       `json:"mix" yaml:"mix"`
+	Chains    int           `json:"chains" yaml:"chains"` // Entries are written into this many chains, made as needed.  1 if not set.
+	EntrySize LoadEntrySize `json:"entrysize" yaml:"entrysize"`
+}
+
+// LoadMix weighs the kinds of message the load generator sends.  Entries only if none are set.
+type LoadMix struct {
+	ChainCreates  float64 `json:"chaincreates" yaml:"chaincreates"`
+	Entries       float64 `json:"entries" yaml:"entries"`
+	Transfers     float64 `json:"transfers" yaml:"transfers"`         // Factoid transfers to new addresses
+	ECPurchases   float64 `json:"ecpurchases" yaml:"ecpurchases"`     // Entry credit purchases
+	BadSignatures float64 `json:"badsignatures" yaml:"badsignatures"` // Entry commits with a broken signature
+	DoubleSpends  float64 `json:"doublespends" yaml:"doublespends"`   // Factoid transfers of funds already spent
+	Replays       float64 `json:"replays" yaml:"replays"`             // Messages sent again
+}
+
+// LoadEntrySize is the distribution of the size of the content of the entries written
+type LoadEntrySize struct {
+	Min          int    `json:"min" yaml:"min"`                   // Bytes, 128 if neither is set
+	Max          int    `json:"max" yaml:"max"`                   // Bytes, 255 if neither is set
+	Distribution string `json:"distribution" yaml:"distribution"` // uniform (the default), normal or exponential
+}
+
+// LoadLatency summarizes how long messages took, in milliseconds
+type LoadLatency struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+// LoadTypeStats are the statistics for one kind of message sent by the load generator.  For
+// the invalid kinds any message acknowledged is a bug.
+type LoadTypeStats struct {
+	Sent         int         `json:"sent"`
+	Acked        int         `json:"acked"`        // Acknowledged by the leaders
+	InBlock      int         `json:"inblock"`      // Saved in a directory block
+	Pending      int         `json:"pending"`      // Sent, not yet in a block
+	Rejected     int         `json:"rejected"`     // Found invalid by the node the API talks to
+	Lost         int         `json:"lost"`         // Given up on, not in a block after 10 blocks
+	AckLatency   LoadLatency `json:"acklatency"`   // From sent to acknowledged
+	BlockLatency LoadLatency `json:"blocklatency"` // From sent to saved in a directory block
+}
+
+// LoadStats are the statistics of the load generator since a profile was last started
+type LoadStats struct {
+	Running bool                      `json:"running"`
+	Profile *LoadProfile              `json:"profile"`
+	Rate    float64                   `json:"rate"` // Messages per second being sent now
+	Types   map[string]*LoadTypeStats `json:"types"`
+}
+
+// ILoadGenerator is the simulator's load generator, as the debug API sees it
+type ILoadGenerator interface {
+	// SetProfile starts a load, resetting the statistics, or stops it with a rate of 0
+	SetProfile(p *LoadProfile) error
+	Stats() *LoadStats
+}
@@ -0,0 +1,15 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// IMempoolMessage is a commit, reveal or factoid transaction that is not yet in a
+// saved block, with where it is and why it waits
+type IMempoolMessage struct {
+	Msg       IMsg
+	Status    string   // One of the constants.MempoolStatus strings
+	Reason    string   // One of the constants.MempoolReason strings, if held
+	DBHeight  uint32   // The height of its process list, or the height it is held for
+	DependsOn [32]byte // What a held message waits on
+}
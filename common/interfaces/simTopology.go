@@ -0,0 +1,45 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package interfaces
+
+// SimLink is how the simulator treats the messages sent one way over a link between two nodes
+type SimLink struct {
+	Latency      int64   `json:"latency" yaml:"latency"`           // Milliseconds a message typically takes to arrive
+	Jitter       int64   `json:"jitter" yaml:"jitter"`             // Milliseconds the latency varies by
+	Distribution string  `json:"distribution" yaml:"distribution"` // How the latency varies: uniform (the default), normal or exponential
+	Loss         float64 `json:"loss" yaml:"loss"`                 // Share of the messages lost, 0 to 1
+	Bandwidth    int     `json:"bandwidth" yaml:"bandwidth"`       // Bytes per second the link carries, 0 for no cap
+}
+
+// SimLinkInfo is a link set in the simulator's topology.  A node of -1 is any node.
+type SimLinkInfo struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+	SimLink
+}
+
+// SimTopology is the network between the nodes of a simulation: the links set, and the
+// partitions splitting it
+type SimTopology struct {
+	Nodes      int                `json:"nodes"`
+	Links      []*SimLinkInfo     `json:"links"`
+	Partitions map[string][][]int `json:"partitions"` // Groups of nodes that can talk to each other, by partition name
+}
+
+// ISimTopology changes the network between the nodes of a simulation while it runs.  Nodes
+// are numbered like the simulator's FNodes.
+type ISimTopology interface {
+	// SetLink sets how messages from one node to another are treated.  Either can be -1 for
+	// any node; the most specific link set is used.
+	SetLink(from int, to int, link *SimLink) error
+	// ClearLink removes a link set by SetLink
+	ClearLink(from int, to int) bool
+	// Partition splits the network, so only nodes in the same group can talk.  Nodes in no
+	// group are cut off from everyone.  Replaces any partition with the same name.
+	Partition(name string, groups [][]int) error
+	// Heal removes a partition, or all of them if the name is empty
+	Heal(name string) bool
+	Topology() *SimTopology
+}
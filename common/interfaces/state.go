@@ -119,7 +119,7 @@ type IState interface {
 	NetworkInvalidMsgQueue() chan IMsg
 
 	// Journaling
-	JournalMessage(IMsg)
+	JournalMessage(queue string, source string, msg IMsg)
 	GetJournalMessages() [][]byte
 
 	// Consensus
@@ -207,6 +207,7 @@ type IState interface {
 	IncDBStateAnswerCnt()
 
 	GetPendingTransactions(string) []IPendingTransaction
+	GetMempool() []IMempoolMessage
 	// MISC
 	// ====
 
@@ -314,6 +315,17 @@ type IState interface {
 	GetMissingEntryCount() uint32
 	GetEntryBlockDBHeightProcessing() uint32
 	GetEntryBlockDBHeightComplete() uint32
+	GetIntegrityReport() *IntegrityReport
+	GetAddressHistory(ec bool, address [32]byte, offset uint32, limit uint32) (*AddressHistory, error)
+	GetBalanceAtHeight(ec bool, address [32]byte, dbheight uint32) (int64, error)
+	GetAnchorStatus(dbheight uint32) (*AnchorStatus, error)
+	GetLatestAnchors() (*LatestAnchors, error)
+	GetAuthoritiesAtHeight(dbheight uint32) (*AuthoritySet, error)
+	GetAuthorityChanges(chainID IHash, start uint32, end uint32, offset uint32, limit uint32) (*AuthorityChanges, error)
+	GetElectionLog() ([]*ElectionRecord, error)
+	GetCoinbasePayout(dbheight uint32) (*CoinbasePayout, error)
+	GetSimTopology() ISimTopology
+	GetLoadGenerator() ILoadGenerator
 	GetCurrentBlockStartTime() int64
 	GetCurrentMinute() int
 	GetCurrentMinuteStartTime() int64
@@ -338,6 +350,7 @@ type IState interface {
 
 	// Access to Holding Queue
 	LoadHoldingMap() map[[32]byte]IMsg
+	LoadDependentHoldingMap() map[[32]byte][]IMsg
 	LoadAcksMap() map[[32]byte]IMsg
 
 	// Plugins
@@ -235,7 +235,10 @@ func (m *BounceReply) String() string {
 		len(m.Stamps),
 		sz)
 
-	elapse := primitives.NewTimestampNow().GetTimeMilli() - m.Stamps[len(m.Stamps)-1].GetTimeMilli()
+	var elapse int64
+	if len(m.Stamps) > 0 {
+		elapse = primitives.NewTimestampNow().GetTimeMilli() - m.Stamps[len(m.Stamps)-1].GetTimeMilli()
+	}
 
 	str = str + fmt.Sprintf("Last Hop Took %d.%03d", elapse/1000, elapse%1000)
 	return str
@@ -114,8 +114,15 @@ func (m *CommitChainMsg) Type() byte {
 //  0   -- Cannot tell if message is Valid
 //  1   -- Message is valid
 func (m *CommitChainMsg) Validate(state interfaces.IState) int {
+	v, _, _ := m.ValidateWithReason(state)
+	return v
+}
+
+// ValidateWithReason validates the message as Validate does, and also returns why it is held
+// or dropped
+func (m *CommitChainMsg) ValidateWithReason(state interfaces.IState) (int, ValidateReason, error) {
 	if !m.validsig && !m.CommitChain.IsValid() {
-		return -1
+		return -1, ReasonBadCommitSignature, fmt.Errorf("the commit is not signed by its entry credit key")
 	}
 	m.validsig = true
 
@@ -124,10 +131,11 @@ func (m *CommitChainMsg) Validate(state interfaces.IState) int {
 	if v < 0 {
 		// return 0  // old way add to scanned holding queue
 		// new holding mechanism added it to a list of messages dependent on the EC address
-		return state.Add(m.CommitChain.ECPubKey.Fixed(), m)
+		return state.Add(m.CommitChain.ECPubKey.Fixed(), m), ReasonShortCredits,
+			fmt.Errorf("the entry credit address has %d credits, the commit pays %d", ebal, m.CommitChain.Credits)
 	}
 
-	return 1
+	return 1, ReasonNone, nil
 }
 
 func (m *CommitChainMsg) ComputeVMIndex(state interfaces.IState) {
@@ -232,18 +232,25 @@ func (m *CommitEntryMsg) LogFields() log.Fields {
 //  0   -- Cannot tell if message is Valid
 //  1   -- Message is valid
 func (m *CommitEntryMsg) Validate(state interfaces.IState) int {
+	v, _, _ := m.ValidateWithReason(state)
+	return v
+}
+
+// ValidateWithReason validates the message as Validate does, and also returns why it is held
+// or dropped
+func (m *CommitEntryMsg) ValidateWithReason(state interfaces.IState) (int, ValidateReason, error) {
 	if !m.validsig && !m.CommitEntry.IsValid() {
-		return -1
+		return -1, ReasonBadCommitSignature, fmt.Errorf("the commit is not signed by its entry credit key")
 	}
 	m.validsig = true
-
 	ebal := state.GetFactoidState().GetECBalance(*m.CommitEntry.ECPubKey)
 	if int(m.CommitEntry.Credits) > int(ebal) {
 		// return 0  // old way add to scanned holding queue
 		// new holding mechanism added it to a list of messages dependent on the EC address
-		return state.Add(m.CommitEntry.ECPubKey.Fixed(), m)
+		return state.Add(m.CommitEntry.ECPubKey.Fixed(), m), ReasonShortCredits,
+			fmt.Errorf("the entry credit address has %d credits, the commit pays %d", ebal, m.CommitEntry.Credits)
 	}
-	return 1
+	return 1, ReasonNone, nil
 }
 
 func (m *CommitEntryMsg) ComputeVMIndex(state interfaces.IState) {
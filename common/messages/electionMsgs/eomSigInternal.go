@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages/msgbase"
@@ -81,12 +82,20 @@ func (m *EomSigInternal) GetMsgHash() (rval interfaces.IHash) {
 	}
 	return m.MsgHash
 }
+
+// StartFault starts the timeout of the current election.  It runs on the clock's schedule, so a
+// simulation on a virtual clock times out the same way every run.
+func StartFault(e *elections.Elections, sigtype bool, timeoutDuration time.Duration) {
+	dbheight, minute, timeOutId := e.DBHeight, e.Minute, e.FaultId.Load()
+	clock.Go(func() { Fault(e, dbheight, minute, timeOutId, &e.FaultId, sigtype, timeoutDuration) })
+}
+
 func Fault(e *elections.Elections, dbheight int, minute int, timeOutId int, currentTimeoutId *atomic.AtomicInt, sigtype bool, timeoutDuration time.Duration) {
 	//	e.LogPrintf("election", "Start Timeout %d", timeOutId)
 	for !e.State.(*state.State).DBFinished || e.State.(*state.State).IgnoreMissing {
-		time.Sleep(timeoutDuration)
+		clock.Sleep(timeoutDuration)
 	}
-	time.Sleep(timeoutDuration)
+	clock.Sleep(timeoutDuration)
 
 	if currentTimeoutId.Load() == timeOutId {
 		//		e.LogPrintf("election", "Timeout %d", timeOutId)
@@ -151,6 +160,8 @@ func (m *EomSigInternal) ElectionProcess(is interfaces.IState, elect interfaces.
 		if int(m.DBHeight) > e.DBHeight && e.Electing != -1 {
 			e.Electing = -1
 		}
+		// An election of the minute we moved on from, if one didn't end, never will
+		electionLog(is).Abandon()
 
 		// Sort leaders, on block boundaries
 		s.LogPrintf("elections", "Election Sort FedServers EomSigInternal2")
@@ -175,7 +186,7 @@ func (m *EomSigInternal) ElectionProcess(is interfaces.IState, elect interfaces.
 		s.Election0 = Title()
 
 		e.FaultId.Store(e.FaultId.Load() + 1) // increment the timeout counter
-		go Fault(e, e.DBHeight, e.Minute, e.FaultId.Load(), &e.FaultId, m.SigType, e.Timeout)
+		StartFault(e, m.SigType, e.Timeout)
 
 		// Drain all waiting messages as we have advanced, they can now be processed again
 		// as moving forward in mins/blocks may invalidate/validate some messages
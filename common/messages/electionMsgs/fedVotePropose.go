@@ -58,6 +58,7 @@ func (m *FedVoteProposalMsg) ElectionProcess(is interfaces.IState, elect interfa
 	// When we get a propose, we should first execute the volunteer msg. Then execute the
 	// propose. This is because the embedded information may be new to us.
 	m.Volunteer.ElectionProcess(is, elect)
+	electionLog(is).Vote(m.Signer, m.Volunteer.ServerID)
 
 	// Leaders will respond with a message,
 	// followers will respond with nil
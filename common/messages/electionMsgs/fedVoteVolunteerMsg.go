@@ -79,6 +79,7 @@ func (m *FedVoteVolunteerMsg) ElectionProcess(is interfaces.IState, elect interf
 	e.Msg = m.Missing
 	e.Ack = m.Ack
 	e.VName = m.ServerName
+	electionLog(is).Volunteer(m.ServerID, m.Round)
 
 	/******  Election Adapter Control   ******/
 	/**	Controlling the inner election state**/
@@ -0,0 +1,14 @@
+package electionMsgs_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// go test -fuzz=FuzzFedVoteLevel ./common/messages/electionMsgs/
+
+func FuzzFedVoteVolunteer(f *testing.F) { testHelper.FuzzMessage(f, constants.VOLUNTEERAUDIT) }
+func FuzzFedVoteProposal(f *testing.F)  { testHelper.FuzzMessage(f, constants.VOLUNTEERPROPOSAL) }
+func FuzzFedVoteLevel(f *testing.F)     { testHelper.FuzzMessage(f, constants.VOLUNTEERLEVELVOTE) }
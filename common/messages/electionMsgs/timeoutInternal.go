@@ -174,6 +174,7 @@ func (m *TimeoutInternal) ElectionProcess(is interfaces.IState, elect interfaces
 		e.LogPrintf("election", "**** Start an Election for %d[%x] missing %s %d/%d-:- min %d ****", e.Electing, e.FedID.Bytes()[3:6], sync, m.DBHeight, e.VMIndex, e.Minute)
 		e.LogPrintf("faulting", "**** Start an Election for %d[%x] missing %s %d/%d-:- min %d ****", e.Electing, e.FedID.Bytes()[3:6], sync, m.DBHeight, e.VMIndex, e.Minute)
 		e.LogPrintLeaders("election")
+		electionLog(is).Start(uint32(m.DBHeight), m.ComparisonMinute(), e.VMIndex, e.Electing, e.FedID, e.Federated, e.Audit)
 
 		// Begin a new Election for a specific vm/min/height
 		m.InitiateElectionAdapter(is) // <-- Election Started
@@ -190,12 +191,13 @@ func (m *TimeoutInternal) ElectionProcess(is interfaces.IState, elect interfaces
 
 	// New timeout, new round of elections.
 	e.Round[e.Electing]++
+	electionLog(is).Round(e.Round[e.Electing])
 
 	// If we don't have all our sync messages, we will have to come back around and see if all is well.
 	// Start our timer to timeout this sync
 
 	e.FaultId.Store(e.FaultId.Load() + 1) // increment the timeout counter
-	go Fault(e, e.DBHeight, e.Minute, e.FaultId.Load(), &e.FaultId, m.SigType, e.RoundTimeout)
+	StartFault(e, m.SigType, e.RoundTimeout)
 
 	auditIdx := 0
 	if len(e.Audit) > 0 {
@@ -331,3 +333,11 @@ func (m *TimeoutInternal) String() string {
 func (a *TimeoutInternal) IsSameAs(b *TimeoutInternal) bool {
 	return true
 }
+
+// electionLog returns the election log of the state, nil if there isn't one
+func electionLog(is interfaces.IState) *state.ElectionLog {
+	if s, ok := is.(*state.State); ok {
+		return s.ElectionLog
+	}
+	return nil
+}
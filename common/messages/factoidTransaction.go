@@ -102,26 +102,35 @@ func (m *FactoidTransaction) Type() byte {
 //  0   -- Cannot tell if message is Valid
 //  1   -- Message is valid
 func (m *FactoidTransaction) Validate(state interfaces.IState) int {
+	v, _, _ := m.ValidateWithReason(state)
+	return v
+}
+
+// ValidateWithReason validates the message as Validate does, and also returns why it is held
+// or dropped
+func (m *FactoidTransaction) ValidateWithReason(state interfaces.IState) (int, ValidateReason, error) {
 	// Is the transaction well formed?
 	err := m.Transaction.Validate(1)
 	if err != nil {
-		return -1 // No, object!
+		return -1, ReasonMalformedTransaction, err // No, object!
 	}
 
 	// Is the transaction properly signed?
 	err = m.Transaction.ValidateSignatures()
 	if err != nil {
-		return -1 // No, object!
+		return -1, ReasonBadSignatures, err // No, object!
 	}
 
 	// Is the transaction valid at this point in time?
+	reason, short := ReasonNone, error(nil)
 	holdAddr := [32]byte{}
 	err, holdAddr = state.GetFactoidState().Validate(1, m.Transaction)
 	if err != nil {
 		if holdAddr != [32]byte{} { // hold for an address that is short
 			state.Add(holdAddr, m)
+			reason, short = ReasonShortFunds, err
 		} else {
-			return -1 // message was invalid for another reason
+			return -1, ReasonInvalidTransaction, err // message was invalid for another reason
 		}
 	}
 
@@ -131,11 +140,14 @@ func (m *FactoidTransaction) Validate(state interfaces.IState) int {
 		oldv := state.GetFactoidState().GetFactoidBalance(adr)
 		v := oldv - int64(input.GetAmount())
 		if v < 0 {
-			return 0
+			if short == nil {
+				short = fmt.Errorf("the input %s has %d factoshis, short of %d", input.GetUserAddress(), oldv, input.GetAmount())
+			}
+			return 0, ReasonShortFunds, short
 		}
 	}
 
-	return 1
+	return 1, reason, short
 }
 
 func (m *FactoidTransaction) ComputeVMIndex(state interfaces.IState) {
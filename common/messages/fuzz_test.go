@@ -0,0 +1,37 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// go test -fuzz=FuzzAck ./common/messages/
+
+func FuzzUnmarshalMessage(f *testing.F) { testHelper.FuzzMessage(f, 0xff) }
+
+func FuzzEOM(f *testing.F)             { testHelper.FuzzMessage(f, constants.EOM_MSG) }
+func FuzzAck(f *testing.F)             { testHelper.FuzzMessage(f, constants.ACK_MSG) }
+func FuzzCommitChain(f *testing.F)     { testHelper.FuzzMessage(f, constants.COMMIT_CHAIN_MSG) }
+func FuzzCommitEntry(f *testing.F)     { testHelper.FuzzMessage(f, constants.COMMIT_ENTRY_MSG) }
+func FuzzRevealEntry(f *testing.F)     { testHelper.FuzzMessage(f, constants.REVEAL_ENTRY_MSG) }
+func FuzzDBSig(f *testing.F)           { testHelper.FuzzMessage(f, constants.DIRECTORY_BLOCK_SIGNATURE_MSG) }
+func FuzzFactoidTx(f *testing.F)       { testHelper.FuzzMessage(f, constants.FACTOID_TRANSACTION_MSG) }
+func FuzzHeartbeat(f *testing.F)       { testHelper.FuzzMessage(f, constants.HEARTBEAT_MSG) }
+func FuzzMissingMsg(f *testing.F)      { testHelper.FuzzMessage(f, constants.MISSING_MSG) }
+func FuzzMissingResponse(f *testing.F) { testHelper.FuzzMessage(f, constants.MISSING_MSG_RESPONSE) }
+func FuzzMissingData(f *testing.F)     { testHelper.FuzzMessage(f, constants.MISSING_DATA) }
+func FuzzDataResponse(f *testing.F)    { testHelper.FuzzMessage(f, constants.DATA_RESPONSE) }
+func FuzzRequestBlock(f *testing.F)    { testHelper.FuzzMessage(f, constants.REQUEST_BLOCK_MSG) }
+func FuzzDBStateMissing(f *testing.F)  { testHelper.FuzzMessage(f, constants.DBSTATE_MISSING_MSG) }
+func FuzzDBState(f *testing.F)         { testHelper.FuzzMessage(f, constants.DBSTATE_MSG) }
+func FuzzAddServer(f *testing.F)       { testHelper.FuzzMessage(f, constants.ADDSERVER_MSG) }
+func FuzzChangeServerKey(f *testing.F) { testHelper.FuzzMessage(f, constants.CHANGESERVER_KEY_MSG) }
+func FuzzRemoveServer(f *testing.F)    { testHelper.FuzzMessage(f, constants.REMOVESERVER_MSG) }
+func FuzzBounce(f *testing.F)          { testHelper.FuzzMessage(f, constants.BOUNCE_MSG) }
+func FuzzBounceReply(f *testing.F)     { testHelper.FuzzMessage(f, constants.BOUNCEREPLY_MSG) }
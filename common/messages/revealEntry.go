@@ -112,13 +112,20 @@ func CheckChainID(state interfaces.IState, ExternalIDs [][]byte, msg *RevealEntr
 //  1   -- Message is valid
 // Also return the matching commit, if 1 (Don't put it back into the Commit List)
 func (m *RevealEntryMsg) Validate(state interfaces.IState) int {
+	v, _, _ := m.ValidateWithReason(state)
+	return v
+}
+
+// ValidateWithReason validates the message as Validate does, and also returns why it is held
+// or dropped
+func (m *RevealEntryMsg) ValidateWithReason(state interfaces.IState) (int, ValidateReason, error) {
 	commit := state.NextCommit(m.Entry.GetHash())
 
 	if commit == nil {
 		state.LogMessage("executeMsg", "Hold, no commit", m)
 		// old holding return 0
 		state.LogPrintf("dependentHolding", "Hold, no commit M-%x is waiting on H-%x", m.GetMsgHash().Bytes()[:3], m.Entry.GetHash().Bytes()[:3])
-		return state.Add(m.Entry.GetHash().Fixed(), m) // hold for a commit
+		return state.Add(m.Entry.GetHash().Fixed(), m), ReasonNoCommit, fmt.Errorf("no commit for the entry") // hold for a commit
 
 	}
 	//
@@ -128,7 +135,7 @@ func (m *RevealEntryMsg) Validate(state interfaces.IState) int {
 	m.commitEntry, okEntry = commit.(*CommitEntryMsg)
 	if !okChain && !okEntry { // What is this trash doing here?  Not a commit at all!
 		state.LogMessage("executeMsg", "drop, bad commit", m)
-		return -1
+		return -1, ReasonNotACommit, fmt.Errorf("the commit for the entry is a %s", commit.String())
 	}
 
 	// Any entry over 10240 bytes will be rejected
@@ -136,7 +143,7 @@ func (m *RevealEntryMsg) Validate(state interfaces.IState) int {
 		data, _ := m.Entry.MarshalBinary()
 		state.LogMessage("executeMsg", "drop, oversized", m)
 		state.LogPrintf("executeMsg", "Size = %d %dk", len(data), m.Entry.KSize())
-		return -1
+		return -1, ReasonOversizedEntry, fmt.Errorf("the entry is %d bytes, over 10240", len(data))
 	}
 
 	// Now make sure the proper amount of credits were paid to record the entry.
@@ -149,7 +156,8 @@ func (m *RevealEntryMsg) Validate(state interfaces.IState) int {
 			state.LogMessage("executeMsg", "Hold, underpaid", m)
 			// old holding .... return 0 // not enough payments on the EC to reveal this entry.  Return 0 to wait on another commit
 			state.LogPrintf("dependentHolding", "Hold, underpaid M-%x is waiting on M-%x", m.GetMsgHash().Bytes()[:3], m.Entry.GetHash().Bytes()[:3])
-			return state.Add(m.Entry.GetHash().Fixed(), m) // hold for a new commit
+			return state.Add(m.Entry.GetHash().Fixed(), m), ReasonUnderpaid,
+				fmt.Errorf("the commit pays %d credits, the entry costs %d", ECs, m.Entry.KSize()) // hold for a new commit
 		}
 
 		// Make sure we have a chain.  If we don't, then bad things happen.
@@ -173,10 +181,11 @@ func (m *RevealEntryMsg) Validate(state interfaces.IState) int {
 			// No chain, we have to leave it be and maybe one will be made.
 			//old holding .., return 0
 			state.LogPrintf("dependentHolding", "Hold, No Chain M-%x is waiting on chain %x", m.GetMsgHash().Bytes()[:3], m.Entry.GetChainID().Bytes()[:6])
-			return state.Add(m.Entry.GetChainID().Fixed(), m) // hold for a new commit
+			return state.Add(m.Entry.GetChainID().Fixed(), m), ReasonNoChain,
+				fmt.Errorf("the chain %s does not exist", m.Entry.GetChainID().String()) // hold for a new commit
 
 		}
-		return 1
+		return 1, ReasonNone, nil
 	} else {
 		m.IsEntry = false
 		ECs := int(m.CommitChain.CommitChain.Credits)
@@ -184,16 +193,17 @@ func (m *RevealEntryMsg) Validate(state interfaces.IState) int {
 			state.LogMessage("executeMsg", "Hold, under paid", m)
 			// old holding .... return 0 // not enough payments on the EC to reveal this chain.  Return 0 to wait on another commit
 			state.LogPrintf("dependentHolding", "Hold, underpaid M-%x is waiting on M-%x", m.GetMsgHash().Bytes()[:3], m.Entry.GetHash().Bytes()[:3])
-			return state.Add(m.Entry.GetHash().Fixed(), m) // hold for a new commit
+			return state.Add(m.Entry.GetHash().Fixed(), m), ReasonUnderpaid,
+				fmt.Errorf("the commit pays %d credits, the chain costs %d", ECs, m.Entry.KSize()+10) // hold for a new commit
 		}
 
 		if !CheckChainID(state, m.Entry.ExternalIDs(), m) {
 			state.LogMessage("executeMsg", "drop, chainID does not match hash of ExtIDs", m)
-			return -1
+			return -1, ReasonBadChainID, fmt.Errorf("the chain ID is not the hash of the ExtIDs")
 		}
 	}
 
-	return 1
+	return 1, ReasonNone, nil
 }
 
 // Returns true if this is a message for this server to execute as
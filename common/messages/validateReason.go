@@ -0,0 +1,26 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package messages
+
+// ValidateReason is why Validate holds or drops a message a user submits: a factoid
+// transaction, a commit or a reveal.  ValidateWithReason returns it along with Validate's
+// result, for the callers that have to tell the reasons apart.
+type ValidateReason int
+
+const (
+	ReasonNone                 ValidateReason = iota // Nothing is wrong with the message
+	ReasonMalformedTransaction                       // The transaction is not well formed
+	ReasonBadSignatures                              // The transaction is not properly signed
+	ReasonInvalidTransaction                         // The transaction is invalid whatever the balances
+	ReasonShortFunds                                 // An input has less than it spends, held for the address
+	ReasonBadCommitSignature                         // The commit is not signed by its entry credit key
+	ReasonShortCredits                               // The entry credit address can't pay the commit, held for it
+	ReasonNoCommit                                   // The reveal has no commit yet, held for one
+	ReasonNotACommit                                 // What the reveal found for its commit is not one
+	ReasonOversizedEntry                             // The entry is over 10KiB
+	ReasonUnderpaid                                  // The commit doesn't pay for the entry, held for another
+	ReasonNoChain                                    // The entry's chain doesn't exist, held for it
+	ReasonBadChainID                                 // The chain ID is not the hash of the first entry's ExtIDs
+)
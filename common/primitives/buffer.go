@@ -296,6 +296,9 @@ func (b *Buffer) PopIHash() (interfaces.IHash, error) {
 
 // PopLen reads a number of bytes equal to the input length from the Buffer
 func (b *Buffer) PopLen(l int) ([]byte, error) {
+	if l < 0 || b.Len() < l {
+		return nil, errors.New(fmt.Sprintf("End of Buffer Looking for %d but only have %d", l, b.Len()))
+	}
 	answer := make([]byte, l)
 	_, err := b.Read(answer)
 	if err != nil {
@@ -11,15 +11,16 @@ import (
 	"os"
 	"time"
 
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/interfaces"
 )
 
 func GetTimeMilli() uint64 {
-	return uint64(time.Now().UnixNano()) / 1000000 // 10^-9 >> 10^-3
+	return uint64(clock.Now().UnixNano()) / 1000000 // 10^-9 >> 10^-3
 }
 
 func GetTime() uint64 {
-	return uint64(time.Now().Unix())
+	return uint64(clock.Now().Unix())
 }
 
 //A structure for handling timestamps for messages
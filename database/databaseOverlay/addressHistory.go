@@ -0,0 +1,180 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The address history index lists every transaction that touched a factoid or entry credit
+// address.  The records of an address are numbered from 0 in the order they happened:
+//   ADDRESS_HISTORY          [type][address][number] => AddressHistoryRecord
+//   ADDRESS_HISTORY_SUMMARY  [type][address]         => AddressHistorySummary
+// where type is 0 for factoid addresses and 1 for entry credit addresses.  The next height
+// to be indexed is kept in the KEY_VALUE_STORE.
+
+// AddressHistoryRecord is one transaction touching an address
+type AddressHistoryRecord struct {
+	DBHeight uint32           // Height of the block holding the transaction
+	TxID     interfaces.IHash // Factoid transaction, or EC commit, id
+	Delta    int64            // Change to the balance, in factoshis or entry credits
+	Balance  int64            // Balance after the transaction
+}
+
+var _ interfaces.BinaryMarshallable = (*AddressHistoryRecord)(nil)
+
+func (r *AddressHistoryRecord) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if err := buf.PushUInt32(r.DBHeight); err != nil {
+		return nil, err
+	}
+	if err := buf.PushIHash(r.TxID); err != nil {
+		return nil, err
+	}
+	if err := buf.PushInt64(r.Delta); err != nil {
+		return nil, err
+	}
+	if err := buf.PushInt64(r.Balance); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (r *AddressHistoryRecord) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	if r.DBHeight, err = buf.PopUInt32(); err != nil {
+		return nil, err
+	}
+	if r.TxID, err = buf.PopIHash(); err != nil {
+		return nil, err
+	}
+	if r.Delta, err = buf.PopInt64(); err != nil {
+		return nil, err
+	}
+	if r.Balance, err = buf.PopInt64(); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (r *AddressHistoryRecord) UnmarshalBinary(data []byte) error {
+	_, err := r.UnmarshalBinaryData(data)
+	return err
+}
+
+// AddressHistorySummary is the number of records of an address, and its latest balance
+type AddressHistorySummary struct {
+	Count   uint32
+	Balance int64
+}
+
+var _ interfaces.BinaryMarshallable = (*AddressHistorySummary)(nil)
+
+func (s *AddressHistorySummary) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if err := buf.PushUInt32(s.Count); err != nil {
+		return nil, err
+	}
+	if err := buf.PushInt64(s.Balance); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (s *AddressHistorySummary) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	if s.Count, err = buf.PopUInt32(); err != nil {
+		return nil, err
+	}
+	if s.Balance, err = buf.PopInt64(); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (s *AddressHistorySummary) UnmarshalBinary(data []byte) error {
+	_, err := s.UnmarshalBinaryData(data)
+	return err
+}
+
+// AddressHistoryUpdate is the change to the index for one address at one height
+type AddressHistoryUpdate struct {
+	EC      bool
+	Address [32]byte
+	Summary *AddressHistorySummary  // The summary after the height
+	Records []*AddressHistoryRecord // The new records, the last of them is number Summary.Count-1
+}
+
+var AddressHistoryHeightKey = []byte("AddressHistoryHeight")
+
+func addressHistoryKey(ec bool, address [32]byte) []byte {
+	key := make([]byte, 33)
+	if ec {
+		key[0] = 1
+	}
+	copy(key[1:], address[:])
+	return key
+}
+
+func addressHistoryRecordKey(ec bool, address [32]byte, n uint32) []byte {
+	key := make([]byte, 37)
+	copy(key, addressHistoryKey(ec, address))
+	binary.BigEndian.PutUint32(key[33:], n)
+	return key
+}
+
+// SaveAddressHistory writes the changes to the index for a height, and moves the index height
+// on to next, in one batch
+func (db *Overlay) SaveAddressHistory(updates []*AddressHistoryUpdate, next uint32) error {
+	batch := []interfaces.Record{}
+	for _, u := range updates {
+		first := u.Summary.Count - uint32(len(u.Records))
+		for i, r := range u.Records {
+			batch = append(batch, interfaces.Record{Bucket: ADDRESS_HISTORY, Key: addressHistoryRecordKey(u.EC, u.Address, first+uint32(i)), Data: r})
+		}
+		batch = append(batch, interfaces.Record{Bucket: ADDRESS_HISTORY_SUMMARY, Key: addressHistoryKey(u.EC, u.Address), Data: u.Summary})
+	}
+
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(next)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	batch = append(batch, interfaces.Record{Bucket: KEY_VALUE_STORE, Key: AddressHistoryHeightKey, Data: bs})
+
+	return db.DB.PutInBatch(batch)
+}
+
+// FetchAddressHistoryHeight returns the next height to be added to the address history index
+func (db *Overlay) FetchAddressHistoryHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	data, err := db.FetchKeyValueStore(AddressHistoryHeightKey, bs)
+	if err != nil || data == nil {
+		return 0, err
+	}
+	return primitives.NewBuffer(bs.Bytes).PopUInt32()
+}
+
+// FetchAddressHistorySummary returns the summary of an address, nil if it has no history
+func (db *Overlay) FetchAddressHistorySummary(ec bool, address [32]byte) (*AddressHistorySummary, error) {
+	summary, err := db.DB.Get(ADDRESS_HISTORY_SUMMARY, addressHistoryKey(ec, address), new(AddressHistorySummary))
+	if err != nil || summary == nil {
+		return nil, err
+	}
+	return summary.(*AddressHistorySummary), nil
+}
+
+// FetchAddressHistoryRecord returns record n of an address, nil if there isn't one
+func (db *Overlay) FetchAddressHistoryRecord(ec bool, address [32]byte, n uint32) (*AddressHistoryRecord, error) {
+	record, err := db.DB.Get(ADDRESS_HISTORY, addressHistoryRecordKey(ec, address, n), new(AddressHistoryRecord))
+	if err != nil || record == nil {
+		return nil, err
+	}
+	return record.(*AddressHistoryRecord), nil
+}
@@ -0,0 +1,58 @@
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAddressHistory(t *testing.T) {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+
+	next, err := dbo.FetchAddressHistoryHeight()
+	if err != nil || next != 0 {
+		t.Errorf("expected an empty index, got %d %v", next, err)
+	}
+
+	var fa, ec [32]byte
+	fa[0] = 1
+	ec[0] = 1 // The same bytes, but an entry credit address
+	tx := testHelper.NewRepeatingHash(7)
+
+	updates := []*AddressHistoryUpdate{
+		{Address: fa, Summary: &AddressHistorySummary{Count: 2, Balance: 30}, Records: []*AddressHistoryRecord{
+			{DBHeight: 5, TxID: tx, Delta: 10, Balance: 10},
+			{DBHeight: 5, TxID: tx, Delta: 20, Balance: 30},
+		}},
+		{EC: true, Address: ec, Summary: &AddressHistorySummary{Count: 1, Balance: -4}, Records: []*AddressHistoryRecord{
+			{DBHeight: 5, TxID: tx, Delta: -4, Balance: -4},
+		}},
+	}
+	if err := dbo.SaveAddressHistory(updates, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	if next, err = dbo.FetchAddressHistoryHeight(); err != nil || next != 6 {
+		t.Errorf("expected index height 6, got %d %v", next, err)
+	}
+	summary, err := dbo.FetchAddressHistorySummary(false, fa)
+	if err != nil || summary == nil || summary.Count != 2 || summary.Balance != 30 {
+		t.Errorf("wrong factoid summary %v %v", summary, err)
+	}
+	summary, err = dbo.FetchAddressHistorySummary(true, ec)
+	if err != nil || summary == nil || summary.Count != 1 || summary.Balance != -4 {
+		t.Errorf("wrong entry credit summary %v %v", summary, err)
+	}
+
+	r, err := dbo.FetchAddressHistoryRecord(false, fa, 1)
+	if err != nil || r == nil {
+		t.Fatalf("missing record %v", err)
+	}
+	if r.DBHeight != 5 || !r.TxID.IsSameAs(tx) || r.Delta != 20 || r.Balance != 30 {
+		t.Errorf("wrong record %+v", r)
+	}
+	if r, err = dbo.FetchAddressHistoryRecord(false, fa, 2); r != nil || err != nil {
+		t.Errorf("found a record past the end %v %v", r, err)
+	}
+}
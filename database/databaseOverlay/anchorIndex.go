@@ -0,0 +1,254 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The anchor index holds, for each directory block height, a signed record anchoring it on
+// each anchor chain:
+//   ANCHOR_INDEX  [chain][height] => AnchorIndexRecord
+// where chain is 0 for Bitcoin and 1 for Ethereum.  A record anchoring a window of blocks
+// is indexed at every height of the window.  The highest height anchored on each chain is
+// kept in the KEY_VALUE_STORE.
+
+const (
+	anchorIndexBitcoin  byte = 0
+	anchorIndexEthereum byte = 1
+)
+
+// AnchorIndexRecord is an anchor record, and the anchor chain entry it was read from
+type AnchorIndexRecord struct {
+	EntryHash interfaces.IHash
+	Record    *anchor.AnchorRecord
+}
+
+var _ interfaces.BinaryMarshallable = (*AnchorIndexRecord)(nil)
+
+func (r *AnchorIndexRecord) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if err := buf.PushIHash(r.EntryHash); err != nil {
+		return nil, err
+	}
+	data, err := r.Record.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err := buf.PushBytes(data); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (r *AnchorIndexRecord) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	if r.EntryHash, err = buf.PopIHash(); err != nil {
+		return nil, err
+	}
+	record, err := buf.PopBytes()
+	if err != nil {
+		return nil, err
+	}
+	r.Record = new(anchor.AnchorRecord)
+	if err := json.Unmarshal(record, r.Record); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (r *AnchorIndexRecord) UnmarshalBinary(data []byte) error {
+	_, err := r.UnmarshalBinaryData(data)
+	return err
+}
+
+// Ref returns the record as the API and the live feed show it
+func (r *AnchorIndexRecord) Ref() *interfaces.AnchorRef {
+	ar := r.Record
+	ref := new(interfaces.AnchorRef)
+	ref.EntryHash = r.EntryHash.String()
+	ref.DBHeightMin, ref.DBHeightMax = anchorRecordHeights(ar)
+	ref.DBHeight = ref.DBHeightMax
+	ref.KeyMR = ar.KeyMR
+	ref.WindowMR = ar.WindowMR
+	if ar.Bitcoin != nil {
+		ref.Chain = interfaces.AnchorChainBitcoin
+		ref.TxID = ar.Bitcoin.TXID
+		ref.BlockHeight = int64(ar.Bitcoin.BlockHeight)
+		ref.BlockHash = ar.Bitcoin.BlockHash
+		ref.TxIndex = int64(ar.Bitcoin.Offset)
+		ref.Address = ar.Bitcoin.Address
+	} else if ar.Ethereum != nil {
+		ref.Chain = interfaces.AnchorChainEthereum
+		ref.TxID = ar.Ethereum.TxID
+		ref.BlockHeight = ar.Ethereum.BlockHeight
+		ref.BlockHash = ar.Ethereum.BlockHash
+		ref.TxIndex = ar.Ethereum.TxIndex
+		ref.Address = ar.Ethereum.ContractAddress
+	}
+	return ref
+}
+
+// anchorRecordHeights returns the lowest and highest directory block heights anchored by the record
+func anchorRecordHeights(ar *anchor.AnchorRecord) (uint32, uint32) {
+	if ar.WindowMR == "" || ar.DBHeightMax < ar.DBHeightMin {
+		return ar.DBHeight, ar.DBHeight
+	}
+	return ar.DBHeightMin, ar.DBHeightMax
+}
+
+var anchorIndexLatestKey = []byte("AnchorIndexLatest")
+
+func anchorIndexKey(chain byte, height uint32) []byte {
+	key := make([]byte, 5)
+	key[0] = chain
+	binary.BigEndian.PutUint32(key[1:], height)
+	return key
+}
+
+func anchorIndexLatestHeightKey(chain byte) []byte {
+	return append(append([]byte{}, anchorIndexLatestKey...), chain)
+}
+
+// anchorIndexLatest caches the highest height anchored on each chain, as records saved in a
+// multibatch can't be read back until it is executed
+type anchorIndexLatest struct {
+	sync.Mutex
+	heights map[byte]uint32
+}
+
+// latestAnchorHeight returns the highest height anchored on the chain, and false if none is
+func (db *Overlay) latestAnchorHeight(chain byte) (uint32, bool, error) {
+	db.anchorIndexLatest.Lock()
+	defer db.anchorIndexLatest.Unlock()
+	if height, ok := db.anchorIndexLatest.heights[chain]; ok {
+		return height, true, nil
+	}
+
+	bs := new(primitives.ByteSlice)
+	data, err := db.FetchKeyValueStore(anchorIndexLatestHeightKey(chain), bs)
+	if err != nil || data == nil {
+		return 0, false, err
+	}
+	height, err := primitives.NewBuffer(bs.Bytes).PopUInt32()
+	if err != nil {
+		return 0, false, err
+	}
+	if db.anchorIndexLatest.heights == nil {
+		db.anchorIndexLatest.heights = make(map[byte]uint32)
+	}
+	db.anchorIndexLatest.heights[chain] = height
+	return height, true, nil
+}
+
+// SaveAnchorIndex adds a valid anchor record to the anchor index, at each height it anchors
+// that isn't anchored on its chain yet.  If the record anchors a new height, it is sent to
+// the live feed.
+func (db *Overlay) SaveAnchorIndex(entry interfaces.IEBEntry, ar *anchor.AnchorRecord, multiBatch bool) error {
+	chain := anchorIndexBitcoin
+	if ar.Ethereum != nil {
+		chain = anchorIndexEthereum
+	} else if ar.Bitcoin == nil {
+		return nil
+	}
+
+	r := &AnchorIndexRecord{EntryHash: entry.GetHash(), Record: ar}
+	batch := []interfaces.Record{}
+	low, high := anchorRecordHeights(ar)
+	for h := low; h <= high && h >= low; h++ {
+		existing, err := db.FetchAnchorIndexRecord(chain == anchorIndexEthereum, h)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			batch = append(batch, interfaces.Record{Bucket: ANCHOR_INDEX, Key: anchorIndexKey(chain, h), Data: r})
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	latest, ok, err := db.latestAnchorHeight(chain)
+	if err != nil {
+		return err
+	}
+	if !ok || high > latest {
+		buf := primitives.NewBuffer(nil)
+		buf.PushUInt32(high)
+		bs := new(primitives.ByteSlice)
+		bs.Bytes = buf.DeepCopyBytes()
+		batch = append(batch, interfaces.Record{Bucket: KEY_VALUE_STORE, Key: anchorIndexLatestHeightKey(chain), Data: bs})
+	}
+
+	if multiBatch {
+		db.PutInMultiBatch(batch)
+	} else if err := db.PutInBatch(batch); err != nil {
+		return err
+	}
+	if !ok || high > latest {
+		db.anchorIndexLatest.Lock()
+		if db.anchorIndexLatest.heights == nil {
+			db.anchorIndexLatest.heights = make(map[byte]uint32)
+		}
+		db.anchorIndexLatest.heights[chain] = high
+		db.anchorIndexLatest.Unlock()
+	}
+
+	if db.parentState != nil {
+		db.parentState.GetEventService().EmitDirectoryBlockAnchorRecordEvent(r.Ref())
+	}
+	return nil
+}
+
+// FetchAnchorIndexRecord returns the record anchoring a height on Bitcoin, or on Ethereum,
+// nil if there isn't one yet
+func (db *Overlay) FetchAnchorIndexRecord(ethereum bool, height uint32) (*AnchorIndexRecord, error) {
+	chain := anchorIndexBitcoin
+	if ethereum {
+		chain = anchorIndexEthereum
+	}
+	record, err := db.DB.Get(ANCHOR_INDEX, anchorIndexKey(chain, height), new(AnchorIndexRecord))
+	if err != nil || record == nil {
+		return nil, err
+	}
+	return record.(*AnchorIndexRecord), nil
+}
+
+// FetchLatestAnchorIndexRecord returns the record anchoring the highest height anchored on
+// Bitcoin, or on Ethereum, nil if nothing is anchored there yet
+func (db *Overlay) FetchLatestAnchorIndexRecord(ethereum bool) (*AnchorIndexRecord, error) {
+	chain := anchorIndexBitcoin
+	if ethereum {
+		chain = anchorIndexEthereum
+	}
+	height, ok, err := db.latestAnchorHeight(chain)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return db.FetchAnchorIndexRecord(ethereum, height)
+}
+
+// ClearAnchorIndex empties the anchor index, so it can be rebuilt from the anchor chains
+func (db *Overlay) ClearAnchorIndex() error {
+	db.anchorIndexLatest.Lock()
+	defer db.anchorIndexLatest.Unlock()
+	if err := db.Clear(ANCHOR_INDEX); err != nil {
+		return err
+	}
+	for _, chain := range []byte{anchorIndexBitcoin, anchorIndexEthereum} {
+		if err := db.Delete(KEY_VALUE_STORE, anchorIndexLatestHeightKey(chain)); err != nil {
+			return err
+		}
+	}
+	db.anchorIndexLatest.heights = nil
+	return nil
+}
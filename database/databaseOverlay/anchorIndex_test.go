@@ -0,0 +1,155 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAnchorIndexRecordMarshal(t *testing.T) {
+	anchors, err := CreateAnchors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &AnchorIndexRecord{EntryHash: primitives.RandomHash(), Record: anchors[0]}
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2 := new(AnchorIndexRecord)
+	rest, err := r2.UnmarshalBinaryData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("%v bytes left over", len(rest))
+	}
+	if r2.EntryHash.IsSameAs(r.EntryHash) == false || r2.Record.IsSame(r.Record) == false {
+		t.Errorf("Unmarshalled record is not the same")
+	}
+
+	ref := r2.Ref()
+	if ref.Chain != interfaces.AnchorChainBitcoin || ref.DBHeight != 8 || ref.DBHeightMin != 8 || ref.DBHeightMax != 8 {
+		t.Errorf("Wrong ref %+v", ref)
+	}
+	if ref.TxID != anchors[0].Bitcoin.TXID || ref.BlockHeight != 372579 || ref.TxIndex != 1185 {
+		t.Errorf("Wrong bitcoin transaction in ref %+v", ref)
+	}
+}
+
+func TestAnchorIndex(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	dbo.BitcoinAnchorRecordPublicKeys = []interfaces.Verifier{testHelper.NewPrimitivesPrivateKey(0).Pub}
+	dbo.EthereumAnchorRecordPublicKeys = []interfaces.Verifier{testHelper.NewPrimitivesPrivateKey(0).Pub}
+	blocks := testHelper.CreateFullTestBlockSet()
+
+	// The anchor chain entries were saved before there were keys to check them with
+	latest, err := dbo.FetchLatestAnchorIndexRecord(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != nil {
+		t.Errorf("Found anchor %v before the anchor chains were parsed", latest.Ref())
+	}
+
+	err = dbo.ReparseAnchorChains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each block is anchored in Bitcoin by the next one
+	last := uint32(len(blocks) - 2)
+	for h := uint32(0); h <= last; h++ {
+		r, err := dbo.FetchAnchorIndexRecord(false, h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r == nil {
+			t.Errorf("Height %v is not anchored in Bitcoin", h)
+			continue
+		}
+		if r.Record.DBHeight != h || r.Record.KeyMR != blocks[h].DBlock.DatabasePrimaryIndex().String() {
+			t.Errorf("Height %v is anchored by the record of %v %v", h, r.Record.DBHeight, r.Record.KeyMR)
+		}
+	}
+	latest, err = dbo.FetchLatestAnchorIndexRecord(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest == nil || latest.Record.DBHeight != last {
+		t.Fatalf("Latest Bitcoin anchor is %v, not of height %v", latest, last)
+	}
+	latest, err = dbo.FetchLatestAnchorIndexRecord(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != nil {
+		t.Errorf("Found an Ethereum anchor %v", latest.Ref())
+	}
+
+	// An Ethereum record anchors a window of blocks
+	ar := new(anchor.AnchorRecord)
+	ar.AnchorRecordVer = 2
+	ar.DBHeightMin = 1
+	ar.DBHeightMax = 3
+	ar.DBHeight = 3
+	ar.WindowMR = primitives.RandomHash().String()
+	ar.Ethereum = &anchor.EthereumStruct{TxID: "0x01", BlockHeight: 100}
+	data, sig, err := ar.MarshalAndSignV2(testHelper.NewPrimitivesPrivateKey(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := entryBlock.NewEntry()
+	entry.ChainID, _ = primitives.HexToHash(EthereumAnchorChainID)
+	entry.Content = primitives.ByteSlice{Bytes: data}
+	entry.ExtIDs = []primitives.ByteSlice{{Bytes: sig}}
+
+	dbo.StartMultiBatch()
+	err = dbo.InsertEntryMultiBatch(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dbo.ExecuteMultiBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for h := uint32(0); h <= 4; h++ {
+		r, err := dbo.FetchAnchorIndexRecord(true, h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (r != nil) != (h >= 1 && h <= 3) {
+			t.Errorf("Height %v anchored in Ethereum: %v", h, r != nil)
+		} else if r != nil && r.EntryHash.IsSameAs(entry.GetHash()) == false {
+			t.Errorf("Height %v anchored by entry %v", h, r.EntryHash)
+		}
+	}
+	latest, err = dbo.FetchLatestAnchorIndexRecord(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest == nil || latest.Ref().DBHeight != 3 || latest.Ref().Chain != interfaces.AnchorChainEthereum {
+		t.Errorf("Latest Ethereum anchor is %v", latest)
+	}
+
+	// Rebuilding the index starts from nothing
+	err = dbo.ClearAnchorIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err = dbo.FetchLatestAnchorIndexRecord(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != nil {
+		t.Errorf("Found anchor %v in a cleared index", latest.Ref())
+	}
+}
@@ -64,6 +64,10 @@ func (dbo *Overlay) ReparseAnchorChains() error {
 	if err != nil {
 		return err
 	}
+	err = dbo.ClearAnchorIndex()
+	if err != nil {
+		return err
+	}
 
 	// Fetch all potential anchor records
 	btcChainID, err := primitives.NewShaHashFromStr(BitcoinAnchorChainID)
@@ -124,6 +128,11 @@ func (dbo *Overlay) SaveAnchorInfoFromEntry(entry interfaces.IEBEntry, multiBatc
 		dbi.EthereumAnchorRecordEntryHash = entry.GetHash()
 	}
 
+	err = dbo.SaveAnchorIndex(entry, anchorRecord, multiBatch)
+	if err != nil {
+		return err
+	}
+
 	if multiBatch {
 		return dbo.ProcessDirBlockInfoMultiBatch(dbi)
 	}
@@ -0,0 +1,64 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The balance history keeps the balances of every address at each height:
+//   BALANCE_CHECKPOINT  [height] => all the balances at the end of the height
+//   BALANCE_DELTA       [height] => the balances changed by the height, and the checkpoint to start from
+// The formats of the records belong to the state.  The next height to be added is kept in
+// the KEY_VALUE_STORE.
+
+var BalanceHistoryHeightKey = []byte("BalanceHistoryHeight")
+
+func balanceHistoryKey(dbheight uint32) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, dbheight)
+	return key
+}
+
+// SaveBalanceHistory writes the delta of a height, and its checkpoint if not nil, and moves
+// the history height on past it, in one batch
+func (db *Overlay) SaveBalanceHistory(dbheight uint32, delta interfaces.BinaryMarshallable, checkpoint interfaces.BinaryMarshallable) error {
+	batch := []interfaces.Record{}
+	batch = append(batch, interfaces.Record{Bucket: BALANCE_DELTA, Key: balanceHistoryKey(dbheight), Data: delta})
+	if checkpoint != nil {
+		batch = append(batch, interfaces.Record{Bucket: BALANCE_CHECKPOINT, Key: balanceHistoryKey(dbheight), Data: checkpoint})
+	}
+
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(dbheight + 1)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	batch = append(batch, interfaces.Record{Bucket: KEY_VALUE_STORE, Key: BalanceHistoryHeightKey, Data: bs})
+
+	return db.DB.PutInBatch(batch)
+}
+
+// FetchBalanceHistoryHeight returns the next height to be added to the balance history
+func (db *Overlay) FetchBalanceHistoryHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	data, err := db.FetchKeyValueStore(BalanceHistoryHeightKey, bs)
+	if err != nil || data == nil {
+		return 0, err
+	}
+	return primitives.NewBuffer(bs.Bytes).PopUInt32()
+}
+
+// FetchBalanceDelta reads the delta of a height into dst, returning nil if there isn't one
+func (db *Overlay) FetchBalanceDelta(dbheight uint32, dst interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	return db.DB.Get(BALANCE_DELTA, balanceHistoryKey(dbheight), dst)
+}
+
+// FetchBalanceCheckpoint reads the checkpoint of a height into dst, returning nil if there isn't one
+func (db *Overlay) FetchBalanceCheckpoint(dbheight uint32, dst interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	return db.DB.Get(BALANCE_CHECKPOINT, balanceHistoryKey(dbheight), dst)
+}
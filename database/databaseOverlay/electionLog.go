@@ -0,0 +1,103 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The election log holds the timelines of the last elections this node saw:
+//   ELECTION_LOG  [id] => ElectionLogRecord
+// Older elections are deleted as new ones are saved.
+
+// ElectionLogRecord is an election timeline as saved in the database
+type ElectionLogRecord struct {
+	Record *interfaces.ElectionRecord
+}
+
+var _ interfaces.BinaryMarshallableAndCopyable = (*ElectionLogRecord)(nil)
+
+func (r *ElectionLogRecord) New() interfaces.BinaryMarshallableAndCopyable {
+	return new(ElectionLogRecord)
+}
+
+func (r *ElectionLogRecord) MarshalBinary() ([]byte, error) {
+	data, err := json.Marshal(r.Record)
+	if err != nil {
+		return nil, err
+	}
+	buf := primitives.NewBuffer(nil)
+	if err := buf.PushBytes(data); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (r *ElectionLogRecord) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	record, err := buf.PopBytes()
+	if err != nil {
+		return nil, err
+	}
+	r.Record = new(interfaces.ElectionRecord)
+	if err := json.Unmarshal(record, r.Record); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (r *ElectionLogRecord) UnmarshalBinary(data []byte) error {
+	_, err := r.UnmarshalBinaryData(data)
+	return err
+}
+
+func electionLogKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
+
+// SaveElectionRecord saves an election timeline, replacing any with the same id, and deletes
+// the ones more than keep elections older
+func (db *Overlay) SaveElectionRecord(record *interfaces.ElectionRecord, keep uint64) error {
+	if err := db.Put(ELECTION_LOG, electionLogKey(record.ID), &ElectionLogRecord{Record: record}); err != nil {
+		return err
+	}
+	if keep == 0 || record.ID < keep {
+		return nil
+	}
+
+	keys, err := db.ListAllKeys(ELECTION_LOG)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if len(key) == 8 && binary.BigEndian.Uint64(key) <= record.ID-keep {
+			if err := db.Delete(ELECTION_LOG, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FetchElectionRecords returns the election timelines saved, oldest first
+func (db *Overlay) FetchElectionRecords() ([]*interfaces.ElectionRecord, error) {
+	all, _, err := db.GetAll(ELECTION_LOG, new(ElectionLogRecord))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*interfaces.ElectionRecord, 0, len(all))
+	for _, r := range all {
+		records = append(records, r.(*ElectionLogRecord).Record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records, nil
+}
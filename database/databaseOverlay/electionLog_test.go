@@ -0,0 +1,62 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestElectionLogRecordMarshal(t *testing.T) {
+	r := &ElectionLogRecord{Record: &interfaces.ElectionRecord{
+		ID:         3,
+		DBHeight:   100,
+		Minute:     -1,
+		FaultedID:  primitives.RandomHash().String(),
+		Outcome:    interfaces.ElectionElected,
+		Volunteers: []*interfaces.ElectionVolunteer{{ServerID: primitives.RandomHash().String(), Round: 1}},
+	}}
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2 := new(ElectionLogRecord)
+	rest, err := r2.UnmarshalBinaryData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("%v bytes left over", len(rest))
+	}
+	if r2.Record.ID != 3 || r2.Record.Minute != -1 || r2.Record.FaultedID != r.Record.FaultedID || len(r2.Record.Volunteers) != 1 {
+		t.Errorf("Unmarshalled record is not the same: %+v", r2.Record)
+	}
+}
+
+func TestSaveElectionRecord(t *testing.T) {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+
+	for id := uint64(0); id < 10; id++ {
+		if err := dbo.SaveElectionRecord(&interfaces.ElectionRecord{ID: id, Outcome: interfaces.ElectionAbandoned}, 4); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := dbo.FetchElectionRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected the last 4 elections, found %d", len(records))
+	}
+	for i, r := range records {
+		if r.ID != uint64(6+i) {
+			t.Errorf("expected election %d at %d, found %d", 6+i, i, r.ID)
+		}
+	}
+}
@@ -19,7 +19,6 @@ import (
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
-	"github.com/FactomProject/factomd/database/blockExtractor"
 )
 
 // the "table" prefix
@@ -71,6 +70,20 @@ var (
 	PAID_FOR = []byte("PaidFor")
 
 	KEY_VALUE_STORE = []byte("KeyValueStore")
+
+	//Transactions touching each factoid and entry credit address
+	ADDRESS_HISTORY         = []byte("AddressHistory")
+	ADDRESS_HISTORY_SUMMARY = []byte("AddressHistorySummary")
+
+	//Balances of every address at each height
+	BALANCE_CHECKPOINT = []byte("BalanceCheckpoint")
+	BALANCE_DELTA      = []byte("BalanceDelta")
+
+	//Anchor records of each directory block height
+	ANCHOR_INDEX = []byte("AnchorIndex")
+
+	//Timelines of the last elections
+	ELECTION_LOG = []byte("ElectionLog")
 )
 
 var ConstantNamesMap map[string]string
@@ -111,21 +124,25 @@ func init() {
 	ConstantNamesMap[string(PAID_FOR)] = "PaidFor"
 	ConstantNamesMap[string(KEY_VALUE_STORE)] = "KeyValueStore"
 
+	ConstantNamesMap[string(ADDRESS_HISTORY)] = "AddressHistory"
+	ConstantNamesMap[string(ADDRESS_HISTORY_SUMMARY)] = "AddressHistorySummary"
+	ConstantNamesMap[string(BALANCE_CHECKPOINT)] = "BalanceCheckpoint"
+	ConstantNamesMap[string(BALANCE_DELTA)] = "BalanceDelta"
+	ConstantNamesMap[string(ANCHOR_INDEX)] = "AnchorIndex"
+	ConstantNamesMap[string(ELECTION_LOG)] = "ElectionLog"
+
 	RegisterPrometheus()
 }
 
 type Overlay struct {
 	DB interfaces.IDatabase
 
-	ExportData     bool
-	ExportDataPath string
-
 	BatchSemaphore sync.Mutex
 	MultiBatch     []interfaces.Record
-	BlockExtractor blockExtractor.BlockExtractor
 
 	BitcoinAnchorRecordPublicKeys  []interfaces.Verifier
 	EthereumAnchorRecordPublicKeys []interfaces.Verifier
+	anchorIndexLatest              anchorIndexLatest
 
 	// We need access to the state to be able emit anchor events
 	parentState events.StateEventServices
@@ -138,12 +155,6 @@ func (db *Overlay) ListAllBuckets() ([][]byte, error) {
 	return db.DB.ListAllBuckets()
 }
 
-func (db *Overlay) SetExportData(path string) {
-	db.ExportData = true
-	db.ExportDataPath = path
-	db.BlockExtractor.DataStorePath = path
-}
-
 func (db *Overlay) StartMultiBatch() {
 	db.BatchSemaphore.Lock()
 	db.MultiBatch = make([]interfaces.Record, 0, 128)
@@ -335,13 +346,6 @@ func (db *Overlay) ProcessBlockMultiBatch(blockBucket, numberBucket, secondaryIn
 
 	db.PutInMultiBatch(batch)
 
-	if db.ExportData {
-		err := db.BlockExtractor.ExportBlock(block)
-		if err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
 
@@ -370,13 +374,6 @@ func (db *Overlay) ProcessBlockBatch(blockBucket, numberBucket, secondaryIndexBu
 		return err
 	}
 
-	if db.ExportData {
-		err = db.BlockExtractor.ExportBlock(block)
-		if err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
 
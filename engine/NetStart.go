@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/constants/runstate"
 	. "github.com/FactomProject/factomd/common/globals"
@@ -78,9 +79,12 @@ func NetStart(s *state.State, p *FactomParams) {
 	s.OneLeader = p.Rotate
 	s.TimeOffset = primitives.NewTimestampFromMilliseconds(uint64(p.TimeOffset))
 	s.StartDelayLimit = p.StartDelay * 1000
-	s.Journaling = p.Journaling
+	s.Journaling = (s.Journaling || p.Journaling) && !p.Replay // A replay would rotate away its own journal
 	s.FactomdVersion = FactomdVersion
 	s.EFactory = new(electionMsgs.ElectionsFactory)
+	if p.SimClock || p.SimReplay != "" {
+		startSimClock(p)
+	}
 
 	log.SetOutput(os.Stdout)
 	switch strings.ToLower(p.Loglvl) {
@@ -182,6 +186,13 @@ func NetStart(s *state.State, p *FactomParams) {
 
 	s.CheckChainHeads.CheckChainHeads = p.CheckChainHeads
 	s.CheckChainHeads.Fix = p.FixChainHeads
+	s.IntegrityCheck.Enabled = p.IntegrityCheck
+	s.IntegrityCheck.Repair = p.IntegrityRepair
+	s.AddressIndexOn = p.AddressIndex
+	s.FinalArchivePath = p.FinalArchive
+	if p.BalanceCheckpoints > 0 {
+		s.BalanceCheckpoints = uint32(p.BalanceCheckpoints)
+	}
 
 	p2pconf := p2p.DefaultP2PConfiguration()
 	p2pconf.TargetPeers = 32
@@ -301,6 +312,12 @@ func NetStart(s *state.State, p *FactomParams) {
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "rotate", p.Rotate))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "timeOffset", p.TimeOffset))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "keepMismatch", p.KeepMismatch))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "integrityCheck", p.IntegrityCheck))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "integrityRepair", p.IntegrityRepair))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "addressIndex", p.AddressIndex))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "balanceCheckpoints", p.BalanceCheckpoints))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %q\n", "finalArchive", p.FinalArchive))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "invariants", p.Invariants))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "startDelay", p.StartDelay))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "Network", s.Network))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %x (%s)\n", "customnet", p.CustomNet, p.CustomNetName))
@@ -617,7 +634,13 @@ func NetStart(s *state.State, p *FactomParams) {
 
 	go controlPanel.ServeControlPanel(fnodes[0].State.ControlPanelChannel, fnodes[0].State, connectionMetricsChannel, network, Build, p.NodeName)
 
-	go SimControl(p.ListenTo, p.Sim_Stdin)
+	if p.Invariants {
+		startInvariantChecker()
+	}
+
+	if !p.Replay { // factomd replay reads stdin at its breakpoints
+		go SimControl(p.ListenTo, p.Sim_Stdin)
+	}
 
 }
 
@@ -679,7 +702,28 @@ func startServer(i int, fnode *FactomNode, load bool) {
 	go fnode.State.EntrySync.SyncHeight()
 	go fnode.State.WriteEntries()
 
-	go Timer(fnode.State)
+	if fnode.State.IntegrityCheck.Enabled {
+		fnode.State.IntegrityChecker = state.NewIntegrityChecker(fnode.State, fnode.State.IntegrityCheck.Repair)
+		go fnode.State.IntegrityChecker.Run()
+	}
+
+	if fnode.State.ExportPipeline != nil {
+		go fnode.State.ExportPipeline.Run()
+	}
+
+	if fnode.State.AddressIndex != nil {
+		go fnode.State.AddressIndex.Backfill()
+	}
+
+	if fnode.State.BalanceHistory != nil {
+		go fnode.State.BalanceHistory.Backfill()
+	}
+
+	if fnode.State.FinalArchivePath != "" {
+		go fnode.State.RunFinalArchive()
+	}
+
+	clock.Go(func() { Timer(fnode.State) })
 	go elections.Run(fnode.State)
 	go fnode.State.ValidatorLoop()
 
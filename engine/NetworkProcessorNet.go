@@ -6,15 +6,16 @@ package engine
 
 import (
 	"fmt"
-	"math/rand"
 	"reflect"
 	"time"
 
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
 )
 
 var _ = fmt.Print
@@ -349,22 +350,28 @@ func sendToExecute(msg interfaces.IMsg, fnode *FactomNode, source string) {
 func Q1(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	fnode.State.LogMessage("NetworkInputs", source+", enqueue", msg)
 	fnode.State.LogMessage("InMsgQueue", source+", enqueue", msg)
+	fnode.State.JournalMessage(state.JournalInMsgQueue, source, msg)
 	fnode.State.InMsgQueue().Enqueue(msg)
 }
 
 func Q2(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	fnode.State.LogMessage("NetworkInputs", source+", enqueue2", msg)
 	fnode.State.LogMessage("InMsgQueue2", source+", enqueue2", msg)
+	fnode.State.JournalMessage(state.JournalInMsgQueue2, source, msg)
 	fnode.State.InMsgQueue2().Enqueue(msg)
 }
 
 func DataQ(fnode *FactomNode, source string, msg interfaces.IMsg) {
 	q := fnode.State.DataMsgQueue()
 	fnode.State.LogMessage("DataQueue", fmt.Sprintf(source+", enqueue %v", len(q)), msg)
+	fnode.State.JournalMessage(state.JournalDataQueue, source, msg)
 	q <- msg
 }
 
 func NetworkOutputs(fnode *FactomNode) {
+	// Peers to send to and messages to drop are picked at random, from the virtual clock's seed
+	// when simulating on one
+	random := clock.NewRand("outputs " + fnode.State.FactomNodeName)
 	for {
 		// if len(fnode.State.NetworkOutMsgQueue()) > 500 {
 		// 	fmt.Print(fnode.State.GetFactomNodeName(), "-", len(fnode.State.NetworkOutMsgQueue()), " ")
@@ -387,6 +394,9 @@ func NetworkOutputs(fnode *FactomNode) {
 			fnode.State.LogMessage("NetworkOutputs", "Drop, no repeat hash", msg)
 			continue
 		}
+		if msg = chaosOutput(fnode, msg, random); msg == nil {
+			continue // Held up, to be sent later
+		}
 
 		regex, _ := fnode.State.GetOutputRegEx()
 		if regex != nil {
@@ -435,13 +445,13 @@ func NetworkOutputs(fnode *FactomNode) {
 			if len(fnode.Peers) > 0 {
 				if p < 0 {
 					fnode.P2PIndex = (fnode.P2PIndex + 1) % len(fnode.Peers)
-					p = rand.Int() % len(fnode.Peers)
+					p = random.Int() % len(fnode.Peers)
 				}
 				peer := fnode.Peers[p]
 				fnode.MLog.Add2(fnode, true, peer.GetNameTo(), "P2P out", true, msg)
 				if !fnode.State.GetNetStateOff() { // don't Send p2p messages if he is OFF
 					// Don't do a rand int if drop rate is 0
-					if fnode.State.GetDropRate() > 0 && rand.Int()%1000 < fnode.State.GetDropRate() {
+					if fnode.State.GetDropRate() > 0 && random.Int()%1000 < fnode.State.GetDropRate() {
 						//drop the message, rather than processing it normally
 
 						fnode.State.LogMessage("NetworkOutputs", "Drop, simCtrl", msg)
@@ -474,7 +484,7 @@ func NetworkOutputs(fnode *FactomNode) {
 					bco := fmt.Sprintf("%s/%d/%d", "BCast", p, i)
 					fnode.MLog.Add2(fnode, true, peer.GetNameTo(), bco, true, msg)
 					if !fnode.State.GetNetStateOff() { // Don't send him broadcast message if he is off
-						if fnode.State.GetDropRate() > 0 && rand.Int()%1000 < fnode.State.GetDropRate() && !msg.IsFullBroadcast() {
+						if fnode.State.GetDropRate() > 0 && random.Int()%1000 < fnode.State.GetDropRate() && !msg.IsFullBroadcast() {
 							//drop the message, rather than processing it normally
 
 							fnode.State.LogMessage("NetworkOutputs", "Drop, simCtrl", msg)
@@ -11,6 +11,7 @@ import (
 
 	"math/rand"
 
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages/msgsupport"
 )
@@ -44,6 +45,13 @@ type SimPeer struct {
 
 	RateOut int // Rate of Bytes output per ms
 	RateIn  int // Rate of Bytes input per ms
+
+	// The nodes at each end, and the topology that decides what becomes of what is sent
+	FromNode int
+	ToNode   int
+	Topology *SimTopology
+
+	random *rand.Rand // Picks the delays
 }
 
 var _ interfaces.IPeer = (*SimPeer)(nil)
@@ -89,7 +97,7 @@ func (f *SimPeer) Init(fromName, toName string) interfaces.IPeer {
 	f.ToName = toName
 	f.FromName = fromName
 	f.BroadcastOut = make(chan *SimPacket, 10000)
-	f.Last = time.Now().UnixNano()
+	f.Last = clock.Now().UnixNano()
 	return f
 }
 
@@ -101,7 +109,7 @@ func (f *SimPeer) GetNameTo() string {
 }
 
 func (f *SimPeer) computeBandwidth() {
-	now := time.Now().UnixNano()
+	now := clock.Now().UnixNano()
 	delta := (now - f.Last) / 1000000000 // Make delta seconds
 	if delta < 5 {
 		// Wait atleast 5 seconds.
@@ -123,14 +131,28 @@ func (f *SimPeer) Send(msg interfaces.IMsg) error {
 		return err
 	}
 
-	go func() {
-		if f.Delay > 0 {
-			// Sleep some random number of milliseconds, then send the packet
-			time.Sleep(time.Duration(rand.Intn(int(f.Delay))) * time.Millisecond)
+	var delay time.Duration
+	if f.Topology != nil {
+		var lost bool
+		delay, lost = f.Topology.Deliver(f.FromNode, f.ToNode, len(data))
+		if lost {
+			return nil
+		}
+	}
+
+	if f.Delay > 0 {
+		// Add some random number of milliseconds
+		if f.random == nil {
+			f.random = clock.NewRand(fmt.Sprintf("peer %d-%d", f.FromNode, f.ToNode))
 		}
-		packet := SimPacket{data: data, sent: time.Now().UnixNano() / 1000000}
+		delay += time.Duration(f.random.Int63n(f.Delay)) * time.Millisecond
+	}
+
+	clock.Go(func() {
+		clock.Sleep(delay)
+		packet := SimPacket{data: data, sent: clock.Now().UnixNano() / 1000000}
 		f.BroadcastOut <- &packet
-	}()
+	})
 
 	return nil
 }
@@ -195,6 +217,12 @@ func AddSimPeer(fnodes []*FactomNode, i1 int, i2 int) {
 	peer21 := new(SimPeer).Init(f2.State.FactomNodeName, f1.State.FactomNodeName).(*SimPeer)
 	peer12.BroadcastIn = peer21.BroadcastOut
 	peer21.BroadcastIn = peer12.BroadcastOut
+	peer12.FromNode, peer12.ToNode, peer12.Topology = i1, i2, simTopology
+	peer21.FromNode, peer21.ToNode, peer21.Topology = i2, i1, simTopology
+
+	// The debug API reaches the topology through the state of a simulated node
+	f1.State.SimTopology = simTopology
+	f2.State.SimTopology = simTopology
 
 	f1.Peers = append(f1.Peers, peer12)
 	f2.Peers = append(f2.Peers, peer21)
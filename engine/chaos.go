@@ -0,0 +1,178 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/clock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/state"
+)
+
+// chaosReleased holds the DBSigs that have been held up long enough, and are to be sent
+var chaosReleased sync.Map
+
+// chaosOutput applies the chaos a node has been told to show to a message it is sending.  It
+// returns the message to send in its place, or nil if the message is being held up and will
+// be sent later.
+func chaosOutput(fnode *FactomNode, msg interfaces.IMsg, random *rand.Rand) interfaces.IMsg {
+	if !fnode.State.ChaosOn() {
+		return msg
+	}
+	c := fnode.State.GetChaos()
+	switch m := msg.(type) {
+	case *messages.DirectoryBlockSignature:
+		if c.DBSigDelay <= 0 {
+			return msg
+		}
+		if _, ok := chaosReleased.Load(msg); ok {
+			chaosReleased.Delete(msg)
+			return msg
+		}
+		fnode.State.LogMessage("NetworkOutputs", fmt.Sprintf("Chaos: delay %dms", c.DBSigDelay), msg)
+		clock.Go(func() {
+			clock.Sleep(time.Duration(c.DBSigDelay) * time.Millisecond)
+			chaosReleased.Store(msg, true)
+			fnode.State.NetworkOutMsgQueue().Enqueue(msg)
+		})
+		return nil
+
+	case *messages.Ack:
+		if c.CorruptAcks <= 0 || !m.LeaderChainID.IsSameAs(fnode.State.IdentityChainID) || random.Intn(1000) >= c.CorruptAcks {
+			return msg
+		}
+		bad, err := corruptAck(m)
+		if err != nil {
+			fnode.State.LogPrintf("NetworkOutputs", "Chaos: can't corrupt an ack: %v", err)
+			return msg
+		}
+		fnode.State.LogMessage("NetworkOutputs", "Chaos: corrupt", msg)
+		return bad
+	}
+	return msg
+}
+
+// corruptAck returns a copy of the ack with the last byte of its signature flipped, so it
+// fails to validate
+func corruptAck(ack *messages.Ack) (interfaces.IMsg, error) {
+	data, err := ack.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	data = append([]byte(nil), data...) // The ack keeps what it marshalled
+	data[len(data)-1] ^= 0xff
+	bad := new(messages.Ack)
+	if err := bad.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	bad.SetOrigin(ack.GetOrigin())
+	bad.SetNetworkOrigin(ack.GetNetworkOrigin())
+	return bad, nil
+}
+
+// changeChaos changes the chaos each of the nodes has been told to show
+func changeChaos(nodes []*FactomNode, change func(c *state.Chaos)) {
+	for _, fn := range nodes {
+		c := fn.State.GetChaos()
+		change(&c)
+		fn.State.SetChaos(c)
+	}
+}
+
+// armChaosCrash gives every node the crash, so whichever of them leads the VM when the time
+// comes goes down
+func armChaosCrash(before string, vm int) error {
+	crash, err := state.NewChaosCrash(before, vm)
+	if err != nil {
+		return err
+	}
+	changeChaos(fnodes, func(c *state.Chaos) { c.Crash = crash })
+	return nil
+}
+
+func chaosString() string {
+	var b strings.Builder
+	for _, fn := range fnodes {
+		off := ""
+		if fn.State.GetNetStateOff() {
+			off = " (off the network)"
+		}
+		fmt.Fprintf(&b, "%-10s %s%s\n", fn.State.FactomNodeName, fn.State.GetChaos().String(), off)
+	}
+	return b.String()
+}
+
+// chaosCommand runs the X commands of the simulator console on the node with the focus, and
+// returns what to print
+//
+//	X                  Show the chaos each node has been told to show
+//	Xc<e|d>[vm]        Crash the leader of the VM (any if not given) just before its EOM or DBSig
+//	Xe                 Toggle sending a second, conflicting ack for every ack
+//	Xd<ms>             Hold up the DBSigs sent
+//	Xa<n>              Corrupt n out of every thousand acks sent
+//	Xs<ms>             Skew the clock, negative for behind
+//	Xr                 Make every node behave again
+func chaosCommand(b string, node int) string {
+	if len(b) == 1 {
+		return chaosString()
+	}
+	if node < 0 || node >= len(fnodes) {
+		return "No Factom Node selected\n"
+	}
+	fn := fnodes[node]
+	arg := b[2:]
+	switch b[1] {
+	case 'c':
+		if arg == "" {
+			return "Xc needs e to crash before the EOM or d before the DBSig\n"
+		}
+		before := map[byte]string{'e': state.ChaosBeforeEOM, 'd': state.ChaosBeforeDBSig}[arg[0]]
+		vm := -1
+		if len(arg) > 1 {
+			var err error
+			if vm, err = strconv.Atoi(arg[1:]); err != nil {
+				return fmt.Sprintf("%q is not a VM\n", arg[1:])
+			}
+		}
+		if err := armChaosCrash(before, vm); err != nil {
+			return err.Error() + "\n"
+		}
+	case 'e':
+		changeChaos([]*FactomNode{fn}, func(c *state.Chaos) { c.Equivocate = !c.Equivocate })
+	case 'd', 'a', 's':
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Sprintf("X%c needs a number\n", b[1])
+		}
+		switch {
+		case b[1] == 'd' && (n < 0 || n > 99999):
+			return "Specify a delay between 0 and 99999 milliseconds\n"
+		case b[1] == 'a' && (n < 0 || n > 1000):
+			return "Specify the acks to corrupt out of every thousand, 0 to 1000\n"
+		}
+		changeChaos([]*FactomNode{fn}, func(c *state.Chaos) {
+			switch b[1] {
+			case 'd':
+				c.DBSigDelay = int64(n)
+			case 'a':
+				c.CorruptAcks = n
+			case 's':
+				c.ClockSkew = int64(n)
+			}
+		})
+	case 'r':
+		changeChaos(fnodes, func(c *state.Chaos) { *c = state.Chaos{} })
+	default:
+		return "Unknown command.  Try X, Xc, Xe, Xd, Xa, Xs or Xr\n"
+	}
+	return chaosString()
+}
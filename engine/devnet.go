@@ -0,0 +1,571 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	ed "github.com/FactomProject/ed25519"
+	"github.com/FactomProject/factom"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/util"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// DevnetFile holds the description of a devnet in its directory
+const DevnetFile = "devnet.json"
+
+// Devnet is a network of factomd processes on localhost, each with a home directory of its own,
+// on a custom network whose genesis block makes node 0 the only leader.  The other nodes get
+// the identities of the simulator, and are promoted to their roles once the network is running.
+type Devnet struct {
+	Name              string       `json:"name"`     // The custom network
+	Roles             string       `json:"roles"`    // Role of each node, like a scenario's nodes: LLAF
+	BasePort          int          `json:"baseport"` // Node i uses the ports from BasePort+10*i
+	BlkTime           int          `json:"blktime"`
+	Flags             []string     `json:"flags"` // More factomd flags for every node
+	BootstrapIdentity string       `json:"bootstrapidentity"`
+	BootstrapKey      string       `json:"bootstrapkey"` // Private key that signs the promotions
+	Promoted          bool         `json:"promoted"`     // The nodes have been given their roles
+	Nodes             []DevnetNode `json:"nodes"`
+
+	dir string
+}
+
+// DevnetNode is one factomd process of a devnet
+type DevnetNode struct {
+	Identity   string `json:"identity"`
+	PrivateKey string `json:"privatekey"`
+	PublicKey  string `json:"publickey"`
+	Pid        int    `json:"pid"` // 0 when stopped
+}
+
+// The ports of a node, from its base port
+const (
+	devnetP2PPort = iota
+	devnetAPIPort
+	devnetControlPanelPort
+	devnetLogPort
+)
+
+// NewDevnet makes a devnet in a directory, writing the home directory and config of each node
+func NewDevnet(dir string, name string, roles string, basePort int, blkTime int, flags []string) (*Devnet, error) {
+	var ready []hardCodedAuthority
+	for _, a := range buildMessages() {
+		if a.Ready {
+			ready = append(ready, a)
+		}
+	}
+	if roles == "" || roles[0] != 'L' || strings.Trim(roles, "LAF") != "" {
+		return nil, fmt.Errorf("the roles must be L, A or F for each node, starting with the L of node 0, not %q", roles)
+	}
+	if len(roles) > len(ready)+1 {
+		return nil, fmt.Errorf("there are identities for %d nodes at most", len(ready)+1)
+	}
+	if name == "" || basePort < 1024 || basePort+10*len(roles) > 65535 || blkTime < 1 {
+		return nil, fmt.Errorf("a devnet needs a name, a base port from 1024 and a block time")
+	}
+
+	d := new(Devnet)
+	d.dir = dir
+	d.Name = name
+	d.Roles = roles
+	d.BasePort = basePort
+	d.BlkTime = blkTime
+	d.Flags = flags
+
+	key := primitives.RandomPrivateKey()
+	d.BootstrapIdentity = primitives.RandomHash().String()
+	d.BootstrapKey = key.PrivateKeyString()
+	d.Nodes = append(d.Nodes, DevnetNode{Identity: d.BootstrapIdentity, PrivateKey: key.PrivateKeyString(), PublicKey: key.PublicKeyString()})
+	for i := 1; i < len(roles); i++ {
+		// The key the identity registers, see makeBlockKey
+		id := ready[i-1].ChainID.String()
+		_, priv, err := ed.GenerateKey(bytes.NewBufferString(id))
+		if err != nil {
+			return nil, err
+		}
+		key := primitives.NewPrivateKeyFromHexBytes(priv[:])
+		d.Nodes = append(d.Nodes, DevnetNode{Identity: id, PrivateKey: key.PrivateKeyString(), PublicKey: key.PublicKeyString()})
+	}
+
+	for i := range d.Nodes {
+		if err := d.writeConfig(i); err != nil {
+			return nil, err
+		}
+	}
+	return d, d.save()
+}
+
+// ReadDevnet reads the devnet in a directory
+func ReadDevnet(dir string) (*Devnet, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, DevnetFile))
+	if err != nil {
+		return nil, err
+	}
+	d := new(Devnet)
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("%s: %v", filepath.Join(dir, DevnetFile), err)
+	}
+	d.dir = dir
+	return d, nil
+}
+
+func (d *Devnet) save() error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(d.dir, DevnetFile), data, 0600)
+}
+
+// Port returns one of the ports of a node
+func (d *Devnet) Port(node int, port int) int {
+	return d.BasePort + 10*node + port
+}
+
+// Home returns the home directory of a node
+func (d *Devnet) Home(node int) string {
+	return filepath.Join(d.dir, fmt.Sprintf("node%d", node))
+}
+
+func (d *Devnet) writeConfig(i int) error {
+	n := d.Nodes[i]
+	dir := filepath.Join(d.Home(i), ".factom", "m2")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	mode := "FULL"
+	if d.Roles[i] != 'F' {
+		mode = "SERVER"
+	}
+	config := fmt.Sprintf(`; Written by factomd devnet for node %d of %q
+[app]
+PortNumber                = %d
+ControlPanelPort          = %d
+ControlPanelSetting       = readonly
+DirectoryBlockInSeconds   = %d
+Network                   = CUSTOM
+CustomNetworkPort         = %d
+CustomSeedURL             = ""
+CustomBootstrapIdentity   = %s
+CustomBootstrapKey        = %s
+NodeMode                  = %s
+IdentityChainID           = %s
+LocalServerPrivKey        = %s
+LocalServerPublicKey      = %s
+
+[log]
+logLevel                  = error
+`, i, d.Name, d.Port(i, devnetAPIPort), d.Port(i, devnetControlPanelPort), d.BlkTime, d.Port(i, devnetP2PPort),
+		d.BootstrapIdentity, d.Nodes[0].PublicKey, mode, n.Identity, n.PrivateKey, n.PublicKey)
+	return ioutil.WriteFile(filepath.Join(dir, "factomd.conf"), []byte(config), 0600)
+}
+
+// CommandLine returns the flags a node is started with
+func (d *Devnet) CommandLine(i int) []string {
+	var peers []string
+	for j := range d.Nodes {
+		if j != i {
+			peers = append(peers, fmt.Sprintf("127.0.0.1:%d", d.Port(j, devnetP2PPort)))
+		}
+	}
+	args := []string{
+		"-factomhome=" + d.Home(i),
+		"-network=CUSTOM",
+		"-customnet=" + d.Name,
+		fmt.Sprintf("-nodename=devnet%d", i),
+		"-sim_stdin=false",
+		"-exclusive",
+		"-peers=" + strings.Join(peers, ","),
+		fmt.Sprintf("-blktime=%d", d.BlkTime),
+		fmt.Sprintf("-networkport=%d", d.Port(i, devnetP2PPort)),
+		fmt.Sprintf("-port=%d", d.Port(i, devnetAPIPort)),
+		fmt.Sprintf("-controlpanelport=%d", d.Port(i, devnetControlPanelPort)),
+		fmt.Sprintf("-logPort=%d", d.Port(i, devnetLogPort)),
+	}
+	return append(args, d.Flags...)
+}
+
+// running returns true if the process of a node is alive
+func (d *Devnet) running(i int) bool {
+	if d.Nodes[i].Pid == 0 {
+		return false
+	}
+	p, err := os.FindProcess(d.Nodes[i].Pid)
+	return err == nil && p.Signal(syscall.Signal(0)) == nil
+}
+
+// Start starts the nodes that aren't running, and then promotes them to their roles if that
+// hasn't been done yet
+func (d *Devnet) Start() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	for i := range d.Nodes {
+		if d.running(i) {
+			continue
+		}
+		log, err := os.OpenFile(filepath.Join(d.Home(i), "factomd.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command(exe, d.CommandLine(i)...)
+		cmd.Dir = d.Home(i)
+		cmd.Stdout = log
+		cmd.Stderr = log
+		err = cmd.Start()
+		log.Close()
+		if err != nil {
+			return fmt.Errorf("node %d: %v", i, err)
+		}
+		d.Nodes[i].Pid = cmd.Process.Pid
+		cmd.Process.Release()
+		fmt.Printf("Started node %d, pid %d, API on port %d\n", i, d.Nodes[i].Pid, d.Port(i, devnetAPIPort))
+	}
+	if err := d.save(); err != nil {
+		return err
+	}
+
+	if d.Promoted {
+		return nil
+	}
+	if err := d.promote(); err != nil {
+		return err
+	}
+	d.Promoted = true
+	return d.save()
+}
+
+// promote gives the nodes their roles the way the simulator does, by writing their identities
+// to the blockchain and then adding them as servers with messages signed by the bootstrap key
+func (d *Devnet) promote() error {
+	port := d.Port(0, devnetAPIPort)
+	fmt.Printf("Waiting for node 0 to make blocks\n")
+	if _, err := d.waitHeight(1); err != nil {
+		return err
+	}
+	if strings.Trim(d.Roles[1:], "F") == "" {
+		return nil // Node 0 is the only leader from the start
+	}
+
+	// Buy the entry credits for the identities
+	sec, _ := hex.DecodeString(ecSec)
+	ec, _ := factom.MakeECAddress(sec[:32])
+	rate := new(wsapi.EntryCreditRateResponse)
+	if err := devnetCall(port, "v2", "entry-credit-rate", nil, rate); err != nil {
+		return err
+	}
+	trans, err := ComposeEcTransaction(bankSecret(), primitives.NewHash(ec.PubBytes()), primitives.NewTimestampNow(), uint64(100*len(d.Roles)*int(rate.Rate)), uint64(rate.Rate))
+	if err != nil {
+		return err
+	}
+	data, _ := trans.MarshalBinary()
+	if err := devnetCall(port, "v2", "factoid-submit", wsapi.TransactionRequest{Transaction: hex.EncodeToString(data)}, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("Writing the identities\n")
+	buildMainChain(port)
+	var ready []hardCodedAuthority
+	for _, a := range buildMessages() {
+		if a.Ready {
+			ready = append(ready, a)
+		}
+	}
+	for i, role := range d.Roles {
+		if i > 0 && role != 'F' {
+			submitAuthority(&ready[i-1], ec, port)
+		}
+	}
+	h, err := d.waitHeight(0)
+	if err != nil {
+		return err
+	}
+	if _, err := d.waitHeight(h + 3); err != nil { // The identities are scanned a block later
+		return err
+	}
+
+	fmt.Printf("Promoting the nodes to %s\n", d.Roles)
+	key, err := primitives.NewPrivateKeyFromHex(d.BootstrapKey)
+	if err != nil {
+		return err
+	}
+	for i, role := range d.Roles {
+		if i == 0 || role == 'F' {
+			continue
+		}
+		msg := new(messages.AddServerMsg)
+		msg.ServerChainID, _ = primitives.HexToHash(d.Nodes[i].Identity)
+		if role == 'A' {
+			msg.ServerType = 1
+		}
+		msg.Timestamp = primitives.NewTimestampNow()
+		if err := msg.Sign(key); err != nil {
+			return err
+		}
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if err := devnetCall(port, "v2", "send-raw-message", wsapi.SendRawMessageRequest{Message: hex.EncodeToString(data)}, nil); err != nil {
+			return err
+		}
+	}
+	h, err = d.waitHeight(0)
+	if err != nil {
+		return err
+	}
+	if _, err := d.waitHeight(h + 2); err != nil {
+		return err
+	}
+	if roles := d.currentRoles(); roles != d.Roles {
+		return fmt.Errorf("failed to promote the nodes to %s, got %s.  The logs are in %s", d.Roles, roles, d.dir)
+	}
+	return nil
+}
+
+// waitHeight waits for node 0 to save a directory block at a height, returning the height it has
+func (d *Devnet) waitHeight(height int64) (int64, error) {
+	deadline := time.Now().Add(time.Duration(3*d.BlkTime)*time.Second + 2*time.Minute)
+	for {
+		h := new(wsapi.HeightsResponse)
+		err := devnetCall(d.Port(0, devnetAPIPort), "v2", "heights", nil, h)
+		if err == nil && h.DirectoryBlockHeight >= height {
+			return h.DirectoryBlockHeight, nil
+		}
+		if time.Now().After(deadline) {
+			if err == nil {
+				err = fmt.Errorf("at height %d", h.DirectoryBlockHeight)
+			}
+			return 0, fmt.Errorf("node 0 didn't reach height %d: %v.  The logs are in %s", height, err, d.dir)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// currentRoles returns the role of each node as seen by the first node running, like Roles, or
+// nothing if no node answers
+func (d *Devnet) currentRoles() string {
+	var servers struct {
+		FederatedServers []struct{ ChainID string }
+		AuditServers     []struct{ ChainID string }
+	}
+	answered := false
+	for i := range d.Nodes {
+		port := d.Port(i, devnetAPIPort)
+		if d.running(i) && devnetCall(port, "debug", "federated-servers", nil, &servers) == nil &&
+			devnetCall(port, "debug", "audit-servers", nil, &servers) == nil {
+			answered = true
+			break
+		}
+	}
+	if !answered {
+		return ""
+	}
+	roles := make(map[string]byte)
+	for _, s := range servers.FederatedServers {
+		roles[s.ChainID] = 'L'
+	}
+	for _, s := range servers.AuditServers {
+		roles[s.ChainID] = 'A'
+	}
+	var b strings.Builder
+	for _, n := range d.Nodes {
+		if role, ok := roles[n.Identity]; ok {
+			b.WriteByte(role)
+		} else {
+			b.WriteByte('F')
+		}
+	}
+	return b.String()
+}
+
+// Status writes the state of each node
+func (d *Devnet) Status() {
+	roles := d.currentRoles()
+	fmt.Printf("Devnet %q in %s\n", d.Name, d.dir)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "node\trole\tnow\tpid\trunning\theight\tapi\tcontrol panel\tp2p")
+	for i, n := range d.Nodes {
+		now := byte('-')
+		if roles != "" {
+			now = roles[i]
+		}
+		height := "-"
+		h := new(wsapi.HeightsResponse)
+		if d.running(i) && devnetCall(d.Port(i, devnetAPIPort), "v2", "heights", nil, h) == nil {
+			height = fmt.Sprint(h.DirectoryBlockHeight)
+		}
+		fmt.Fprintf(w, "%d\t%c\t%c\t%d\t%v\t%s\t%d\t%d\t%d\n", i, d.Roles[i], now, n.Pid, d.running(i), height,
+			d.Port(i, devnetAPIPort), d.Port(i, devnetControlPanelPort), d.Port(i, devnetP2PPort))
+	}
+	w.Flush()
+}
+
+// Stop interrupts every node, killing any that hasn't stopped after half a minute
+func (d *Devnet) Stop() error {
+	for i := range d.Nodes {
+		if d.running(i) {
+			p, _ := os.FindProcess(d.Nodes[i].Pid)
+			if p.Signal(os.Interrupt) != nil {
+				p.Kill()
+			}
+		}
+	}
+	deadline := time.Now().Add(30 * time.Second)
+	for i := range d.Nodes {
+		for d.running(i) && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if d.running(i) {
+			p, _ := os.FindProcess(d.Nodes[i].Pid)
+			p.Kill()
+		}
+		if d.Nodes[i].Pid != 0 {
+			fmt.Printf("Stopped node %d\n", i)
+		}
+		d.Nodes[i].Pid = 0
+	}
+	return d.save()
+}
+
+// devnetCall calls an API method of a node, on the v2 or the debug path
+func devnetCall(port int, path string, method string, params interface{}, result interface{}) error {
+	return apiCall(fmt.Sprintf("localhost:%d", port), path, method, params, result)
+}
+
+// apiError is an error returned by an API method
+type apiError struct {
+	Method string
+	*primitives.JSONError
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s: %s %v", e.Method, e.Message, e.Data)
+}
+
+// apiCall calls an API method of the node at host, on the v2 or the debug path
+func apiCall(host string, path string, method string, params interface{}, result interface{}) error {
+	req, err := json.Marshal(primitives.NewJSON2Request(method, 0, params))
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(fmt.Sprintf("http://%s/%s", host, path), "application/json", bytes.NewBuffer(req))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var r struct {
+		Result json.RawMessage       `json:"result"`
+		Error  *primitives.JSONError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return err
+	}
+	if r.Error != nil {
+		return &apiError{method, r.Error}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(r.Result, result)
+}
+
+// RunDevnet runs the devnet command, returning the exit code
+func RunDevnet(args []string) int {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, `Usage: factomd devnet start|status|stop|reset [flags] [factomd flags]
+
+  start   makes the devnet if there isn't one, starts its nodes and promotes them to their roles
+  status  shows the role, process and height of each node
+  stop    stops the nodes
+  reset   stops the nodes and deletes the devnet, so the next start makes a new one
+
+The flags after the devnet's are passed to every node when the devnet is made.
+
+`)
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+	command := args[0]
+	switch command {
+	case "start", "status", "stop", "reset":
+	default:
+		usage()
+		return 2
+	}
+
+	fs := flag.NewFlagSet("devnet", flag.ContinueOnError)
+	dir := fs.String("dir", filepath.Join(util.GetHomeDir(), ".factom", "devnet"), "Directory of the devnet")
+	roles := fs.String("nodes", "LLAF", "Role of each node when the devnet is made: L, A or F")
+	name := fs.String("name", "devnet", "Custom network name when the devnet is made")
+	port := fs.Int("port", 8200, "First port when the devnet is made, node i uses 4 ports from port+10*i")
+	blkTime := fs.Int("blktime", 30, "Seconds per block when the devnet is made")
+	fs.Usage = func() {
+		usage()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	*dir, _ = filepath.Abs(*dir)
+
+	d, err := ReadDevnet(*dir)
+	if command != "start" && os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "There is no devnet in %s, factomd devnet start makes one\n", *dir)
+		return 1
+	}
+	if command != "start" && err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch command {
+	case "start":
+		if os.IsNotExist(err) {
+			fmt.Printf("Making devnet %q with nodes %s in %s\n", *name, *roles, *dir)
+			d, err = NewDevnet(*dir, *name, *roles, *port, *blkTime, fs.Args())
+		}
+		if err == nil {
+			err = d.Start()
+		}
+		if d != nil {
+			d.Status()
+		}
+	case "status":
+		d.Status()
+	case "stop":
+		err = d.Stop()
+	case "reset":
+		if err = d.Stop(); err == nil {
+			err = os.RemoveAll(*dir)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package engine_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestNewDevnet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, roles := range []string{"", "F", "AL", "LX"} {
+		if _, err := NewDevnet(dir, "test", roles, 8200, 10, nil); err == nil {
+			t.Errorf("expected the roles %q to be refused", roles)
+		}
+	}
+
+	d, err := NewDevnet(dir, "test", "LAF", 8200, 10, []string{"-debuglog=."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(d.Nodes))
+	}
+	if d.Nodes[0].Identity != d.BootstrapIdentity {
+		t.Error("expected node 0 to be the bootstrap identity")
+	}
+	for i, n := range d.Nodes[1:] {
+		if !strings.HasPrefix(n.Identity, "888888") {
+			t.Errorf("expected node %d to have a simulator identity, got %s", i+1, n.Identity)
+		}
+	}
+
+	config, err := ioutil.ReadFile(filepath.Join(d.Home(1), ".factom", "m2", "factomd.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{d.Nodes[1].Identity, d.Nodes[1].PrivateKey, d.Nodes[0].PublicKey, "NodeMode                  = SERVER"} {
+		if !strings.Contains(string(config), want) {
+			t.Errorf("expected the config of node 1 to have %q", want)
+		}
+	}
+
+	args := strings.Join(d.CommandLine(1), " ")
+	if !strings.Contains(args, "-peers=127.0.0.1:8200,127.0.0.1:8220 ") || !strings.Contains(args, "-networkport=8210") {
+		t.Errorf("expected node 1 to use port 8210 and peer with the others, got %s", args)
+	}
+	if !strings.HasSuffix(args, "-debuglog=.") {
+		t.Errorf("expected the extra flags last, got %s", args)
+	}
+
+	read, err := ReadDevnet(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Roles != "LAF" || read.BootstrapKey != d.BootstrapKey || read.Nodes[2].Identity != d.Nodes[2].Identity {
+		t.Error("expected to read back the devnet written")
+	}
+}
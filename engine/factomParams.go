@@ -33,11 +33,22 @@ func init() {
 	flag.StringVar(&p.ConfigPath, "config", "", "Override the config file location (factomd.conf)")
 	flag.BoolVar(&p.CheckChainHeads, "checkheads", true, "Enables checking chain heads on boot")
 	flag.BoolVar(&p.FixChainHeads, "fixheads", true, "If --checkheads is enabled, then this will also correct any errors reported")
+	flag.BoolVar(&p.IntegrityCheck, "integritycheck", false, "Continuously check the database for integrity problems in the background")
+	flag.BoolVar(&p.IntegrityRepair, "integrityrepair", false, "If --integritycheck is enabled, then this will also repair problems and ask peers for missing data")
+	flag.StringVar(&p.FinalArchive, "finalarchive", "", "Once the final block of the Factom era (the FactomMaxHeight activation) is saved, write an archive of all the balances, chain heads and authorities, with a Merkle root signed by this node's identity, to this file")
+	flag.IntVar(&p.BalanceCheckpoints, "balancecheckpoints", 0, "Keep the balances of every address at every height, so the balance API calls can take a height. Every N blocks a full copy of the balances is stored, and the changes in between. 0 is off")
+	flag.BoolVar(&p.AddressIndex, "addressindex", false, "Index the transactions of every factoid and entry credit address for the address-history and balance-at-height API calls. The index of the existing blocks is built in the background")
 	flag.BoolVar(&p.AckbalanceHash, "balancehash", true, "If false, then don't pass around balance hashes")
 	flag.BoolVar(&p.EnableNet, "enablenet", true, "Enable or disable networking")
 	flag.BoolVar(&p.WaitEntries, "waitentries", false, "Wait for Entries to be validated prior to execution of messages")
 	flag.IntVar(&p.ListenTo, "node", 0, "Node Number the simulator will set as the focus")
 	flag.IntVar(&p.Cnt, "count", 1, "The number of nodes to generate")
+	flag.StringVar(&p.Scenario, "scenario", "", "Run the simulation described by this YAML or JSON scenario file, then exit with 0 if all its assertions passed")
+	flag.BoolVar(&p.SimClock, "simclock", false, "Run the simulated nodes on a virtual clock, with the random choices of the simulator seeded, and record the seed in simclock.json")
+	flag.Int64Var(&p.SimSeed, "simseed", 0, "Seed for --simclock.  0 picks one")
+	flag.Float64Var(&p.SimSpeed, "simspeed", 1, "How many times faster than real time the --simclock runs")
+	flag.StringVar(&p.SimReplay, "simreplay", "", "Run the simulation with the seed and start of a run recorded by --simclock in its simclock.json")
+	flag.BoolVar(&p.Invariants, "invariants", false, "Check the safety of the consensus on every simulated node as it runs, stopping the simulation on a violation")
 	flag.StringVar(&p.Net, "net", "alot+", "The default algorithm to build the network connections")
 	flag.StringVar(&p.Fnet, "fnet", "", "Read the given file to build the network connections")
 	flag.IntVar(&p.DropRate, "drop", 0, "Number of messages to drop out of every thousand")
@@ -90,7 +101,7 @@ func init() {
 	flag.StringVar(&p.NodeName, "nodename", "", "Assign a name to the node")
 	flag.StringVar(&p.ControlPanelSetting, "controlpanelsetting", "", "Can set to 'disabled', 'readonly', or 'readwrite' to overwrite config file")
 	flag.BoolVar(&p.FullHashesLog, "fullhasheslog", false, "true create a log of all unique hashes seen during processing")
-	flag.BoolVar(&p.ReparseAnchorChains, "reparseanchorchains", false, "If true, reparse bitcoin and ethereum anchor chains in the database")
+	flag.BoolVar(&p.ReparseAnchorChains, "reparseanchorchains", false, "If true, reparse bitcoin and ethereum anchor chains in the database, and rebuild the anchor index")
 
 	// Live feed API params
 	flag.BoolVar(&p.EnableLiveFeedAPI, "enablelivefeedapi", false, "Enable life feed events service; default false")
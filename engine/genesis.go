@@ -0,0 +1,128 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/FactomProject/factomd/state"
+	"gopkg.in/yaml.v2"
+)
+
+// GenesisFile is the name factomd genesis gives the genesis blocks it writes
+const GenesisFile = "genesis.json"
+
+// ReadGenesisSpec reads a genesis spec from a JSON file, or a YAML one if it doesn't end in .json
+func ReadGenesisSpec(filename string) (*state.GenesisSpec, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	g := new(state.GenesisSpec)
+	if filepath.Ext(filename) == ".json" {
+		err = json.Unmarshal(data, g)
+	} else {
+		err = yaml.UnmarshalStrict(data, g)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if err := g.Check(); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return g, nil
+}
+
+// GenesisConfig returns the factomd.conf of a node of a custom genesis, a follower if server is nil
+func GenesisConfig(g *state.GenesisSpec, genesisFile string, server *state.GenesisServer) string {
+	config := fmt.Sprintf(`; Written by factomd genesis for network %q
+[app]
+Network                   = CUSTOM
+DirectoryBlockInSeconds   = %d
+CustomBootstrapIdentity   = %s
+CustomBootstrapKey        = %s
+CustomGenesis             = %s
+`, g.Network, g.BlockTime, g.Bootstrap.Identity, g.Bootstrap.Key, genesisFile)
+	if server == nil {
+		return config + "NodeMode                  = FULL\n"
+	}
+	return config + fmt.Sprintf(`NodeMode                  = SERVER
+IdentityChainID           = %s
+LocalServerPrivKey        = %s
+LocalServerPublicKey      = %s
+`, server.Identity, server.PrivateKey, server.Key)
+}
+
+// RunGenesis runs factomd genesis, which writes the genesis blocks of a spec with a config for
+// followers, and one for each server whose private key is in the spec
+func RunGenesis(args []string) int {
+	fs := flag.NewFlagSet("genesis", flag.ContinueOnError)
+	out := fs.String("out", ".", "Directory the genesis blocks and configs are written to")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: factomd genesis [-out dir] spec.yaml\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	err := func() error {
+		g, err := ReadGenesisSpec(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		dir, err := filepath.Abs(*out)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		genesisFile := filepath.Join(dir, GenesisFile)
+		keyMR, err := state.WriteGenesisFile(genesisFile, g)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote the genesis of network %q to %s, its directory block is %s\n", g.Network, genesisFile, keyMR)
+
+		write := func(name string, server *state.GenesisServer, who string) error {
+			filename := filepath.Join(dir, name)
+			if err := ioutil.WriteFile(filename, []byte(GenesisConfig(g, genesisFile, server)), 0600); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s for %s\n", filename, who)
+			return nil
+		}
+		if err := write("factomd.conf", nil, "followers"); err != nil {
+			return err
+		}
+		servers := append([]state.GenesisServer{g.Bootstrap}, g.Authorities...)
+		for i := range servers {
+			if servers[i].PrivateKey == "" {
+				continue
+			}
+			if err := write(fmt.Sprintf("server%d.conf", i), &servers[i], "server "+servers[i].Identity); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("\nStart a node with: factomd -network=CUSTOM -customnet=%s -config=%s\n", g.Network, filepath.Join(dir, "factomd.conf"))
+		return nil
+	}()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
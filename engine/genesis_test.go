@@ -0,0 +1,58 @@
+package engine_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestReadGenesisSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g, err := ReadGenesisSpec(writeScenario(t, dir, "spec.yaml", `
+network: specnet
+blocktime: 60
+exchangerate: 1000
+bootstrap:
+  identity: 73dfd781235078c0a7c8feeffc88c07a1dc821c17361371f29bf0eb409655cba
+  privatekey: c23131ba92b2ed5dc3516fffe2afa85689b5ef2ca0735b42279c139707ac7989
+authorities:
+  - identity: 888888d68f00a41cf8caddd8d3d37f22507c39d9beddb037a222effa522d1485
+    key: b52ee1f9e51cb2c667353c2a6beb1f4eb835cfbfeeade905c7c1b12e44fcf9d3
+    role: audit
+factoids:
+  - {address: FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q, amount: 10}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Network != "specnet" || g.BlockTime != 60 || len(g.Authorities) != 1 || g.Factoids[0].Amount != "10" {
+		t.Errorf("read %+v", g)
+	}
+
+	server := GenesisConfig(g, "/genesis.json", &g.Bootstrap)
+	for _, want := range []string{"DirectoryBlockInSeconds   = 60", "CustomGenesis             = /genesis.json", "NodeMode                  = SERVER",
+		"LocalServerPrivKey        = c23131ba92b2ed5dc3516fffe2afa85689b5ef2ca0735b42279c139707ac7989",
+		"CustomBootstrapKey        = c314b0181aeab97adc21ab6215bc0d3f74ea03f3ac6a9a85e5e562a2ddf206a4"} {
+		if !strings.Contains(server, want) {
+			t.Errorf("expected the config of the bootstrap server to have %q", want)
+		}
+	}
+	if follower := GenesisConfig(g, "/genesis.json", nil); !strings.Contains(follower, "NodeMode                  = FULL") || strings.Contains(follower, "LocalServerPrivKey") {
+		t.Errorf("expected a follower config, got %s", follower)
+	}
+
+	if _, err := ReadGenesisSpec(writeScenario(t, dir, "bad.json", `{"network": "x", "exchangerate": 1000, "blocks": 3}`)); err == nil {
+		t.Error("expected an unknown field to be refused")
+	}
+	if _, err := ReadGenesisSpec(writeScenario(t, dir, "bad.yaml", "network: x\nexchangerate: 1000\nbootstrap: {identity: 12}\n")); err == nil {
+		t.Error("expected a bad identity to be refused")
+	}
+}
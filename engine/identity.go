@@ -0,0 +1,887 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/btcutil/base58"
+	ed "github.com/FactomProject/ed25519"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/identity"
+	"github.com/FactomProject/factomd/common/identityEntries"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util"
+	"github.com/FactomProject/factomd/wsapi"
+)
+
+// IdentityKeys are the keys and chains of a server identity, as saved in its key file.  The
+// private keys are hex, the level 1 identity key signs the identity entries.
+type IdentityKeys struct {
+	RootChainID       string    `json:"rootchainid"`
+	RootNonce         string    `json:"rootnonce"`
+	ManagementChainID string    `json:"managementchainid"`
+	ManagementNonce   string    `json:"managementnonce"`
+	IdentityKeys      [4]string `json:"identitykeys"`
+	SigningKey        string    `json:"signingkey"`
+}
+
+// NewIdentityKeys generates the keys of a new identity and mines the nonces of its chains
+func NewIdentityKeys() (*IdentityKeys, error) {
+	k := new(IdentityKeys)
+	for i := range k.IdentityKeys {
+		key := new(primitives.PrivateKey)
+		if err := key.GenerateKey(); err != nil {
+			return nil, err
+		}
+		k.IdentityKeys[i] = key.PrivateKeyString()
+	}
+	key := new(primitives.PrivateKey)
+	if err := key.GenerateKey(); err != nil {
+		return nil, err
+	}
+	k.SigningKey = key.PrivateKeyString()
+
+	root, err := k.rootChain()
+	if err != nil {
+		return nil, err
+	}
+	root.Nonce = mineChainNonce(root.ToExternalIDs())
+	k.RootNonce = hex.EncodeToString(root.Nonce)
+	k.RootChainID = root.GetChainID().String()
+
+	management := k.managementChain(root.GetChainID())
+	management.Nonce = mineChainNonce(management.ToExternalIDs())
+	k.ManagementNonce = hex.EncodeToString(management.Nonce)
+	k.ManagementChainID = management.GetChainID().String()
+	return k, nil
+}
+
+// ReadIdentityKeys reads a key file
+func ReadIdentityKeys(filename string) (*IdentityKeys, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	k := new(IdentityKeys)
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if _, err := k.chains(); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return k, nil
+}
+
+// Save writes the key file, readable only by its owner
+func (k *IdentityKeys) Save(filename string) error {
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0600)
+}
+
+func (k *IdentityKeys) identityKey(level int) (*primitives.PrivateKey, error) {
+	key, err := primitives.NewPrivateKeyFromHex(k.IdentityKeys[level])
+	if err != nil {
+		return nil, fmt.Errorf("identity key %d: %v", level+1, err)
+	}
+	return key, nil
+}
+
+// rootChain is the first entry of the root chain, without its nonce
+func (k *IdentityKeys) rootChain() (*identityEntries.IdentityChainStructure, error) {
+	var keys [4]interfaces.IHash
+	for i := range keys {
+		key, err := k.identityKey(i)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = primitives.Shad(preimage(key))
+	}
+	ic := new(identityEntries.IdentityChainStructure)
+	ic.FunctionName = []byte("Identity Chain")
+	ic.Key1, ic.Key2, ic.Key3, ic.Key4 = keys[0], keys[1], keys[2], keys[3]
+	return ic, nil
+}
+
+// managementChain is the first entry of the management chain, without its nonce
+func (k *IdentityKeys) managementChain(root interfaces.IHash) *identityEntries.ServerManagementStructure {
+	sm := new(identityEntries.ServerManagementStructure)
+	sm.FunctionName = []byte("Server Management")
+	sm.RootIdentityChainID = root
+	return sm
+}
+
+// chains returns the first entries of the root and management chains, checking they make
+// the chain ids of the key file
+func (k *IdentityKeys) chains() ([2]*entryBlock.Entry, error) {
+	var chains [2]*entryBlock.Entry
+	root, err := k.rootChain()
+	if err != nil {
+		return chains, err
+	}
+	if root.Nonce, err = hex.DecodeString(k.RootNonce); err != nil {
+		return chains, fmt.Errorf("root nonce: %v", err)
+	}
+	if root.GetChainID().String() != k.RootChainID {
+		return chains, fmt.Errorf("the keys and nonce make root chain %s, not %s", root.GetChainID(), k.RootChainID)
+	}
+	management := k.managementChain(root.GetChainID())
+	if management.Nonce, err = hex.DecodeString(k.ManagementNonce); err != nil {
+		return chains, fmt.Errorf("management nonce: %v", err)
+	}
+	if management.GetChainID().String() != k.ManagementChainID {
+		return chains, fmt.Errorf("the root chain and nonce make management chain %s, not %s", management.GetChainID(), k.ManagementChainID)
+	}
+	chains[0] = newIdentityEntry(root.GetChainID(), root.ToExternalIDs())
+	chains[1] = newIdentityEntry(management.GetChainID(), management.ToExternalIDs())
+	return chains, nil
+}
+
+// RootChain returns the id of the root chain
+func (k *IdentityKeys) RootChain() interfaces.IHash {
+	h, _ := primitives.HexToHash(k.RootChainID)
+	return h
+}
+
+// ManagementChain returns the id of the management chain
+func (k *IdentityKeys) ManagementChain() interfaces.IHash {
+	h, _ := primitives.HexToHash(k.ManagementChainID)
+	return h
+}
+
+// IdentityEntry is an identity entry to submit, and whether it is the first entry of its chain
+type IdentityEntry struct {
+	Entry    *entryBlock.Entry
+	NewChain bool
+}
+
+// RegisterEntries returns the entries that create an identity: its root chain, its registration,
+// its management chain, the registration of the management chain and its block signing key
+func (k *IdentityKeys) RegisterEntries(now interfaces.Timestamp) ([]IdentityEntry, error) {
+	chains, err := k.chains()
+	if err != nil {
+		return nil, err
+	}
+	key, err := k.identityKey(0)
+	if err != nil {
+		return nil, err
+	}
+
+	rfi := new(identityEntries.RegisterFactomIdentityStructure)
+	rfi.FunctionName = []byte("Register Factom Identity")
+	rfi.IdentityChainID = k.RootChain()
+	rfi.PreimageIdentityKey = preimage(key)
+	rfi.Signature = sign(key, rfi.MarshalForSig())
+	register, _ := primitives.HexToHash(state.MAIN_FACTOM_IDENTITY_LIST)
+
+	rsm := new(identityEntries.RegisterServerManagementStructure)
+	rsm.FunctionName = []byte("Register Server Management")
+	rsm.SubchainChainID = k.ManagementChain()
+	rsm.PreimageIdentityKey = preimage(key)
+	rsm.Signature = sign(key, rsm.MarshalForSig())
+
+	signingKey, err := primitives.NewPrivateKeyFromHex(k.SigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("signing key: %v", err)
+	}
+	sk, err := k.SigningKeyEntry(signingKey.Pub[:], now)
+	if err != nil {
+		return nil, err
+	}
+
+	return []IdentityEntry{
+		{chains[0], true},
+		{newIdentityEntry(register, rfi.ToExternalIDs()), false},
+		{chains[1], true},
+		{newIdentityEntry(k.RootChain(), rsm.ToExternalIDs()), false},
+		sk,
+	}, nil
+}
+
+// SigningKeyEntry returns the entry that sets the public key the server signs blocks with
+func (k *IdentityKeys) SigningKeyEntry(pub []byte, now interfaces.Timestamp) (IdentityEntry, error) {
+	key, err := k.identityKey(0)
+	if err != nil {
+		return IdentityEntry{}, err
+	}
+	nbsk := new(identityEntries.NewBlockSigningKeyStruct)
+	nbsk.FunctionName = []byte("New Block Signing Key")
+	nbsk.RootIdentityChainID = k.RootChain()
+	nbsk.NewPublicKey = pub
+	nbsk.Timestamp = timestampBytes(now)
+	nbsk.PreimageIdentityKey = preimage(key)
+	nbsk.Signature = sign(key, nbsk.MarshalForSig())
+	return IdentityEntry{newIdentityEntry(k.ManagementChain(), nbsk.ToExternalIDs()), false}, nil
+}
+
+// BitcoinKeyEntry returns the entry that sets the Bitcoin key of a level, of type 0 for P2PKH
+// or 1 for P2SH
+func (k *IdentityKeys) BitcoinKeyEntry(level byte, keyType byte, btcKey [20]byte, now interfaces.Timestamp) (IdentityEntry, error) {
+	key, err := k.identityKey(0)
+	if err != nil {
+		return IdentityEntry{}, err
+	}
+	nbk := new(identityEntries.NewBitcoinKeyStructure)
+	nbk.FunctionName = []byte("New Bitcoin Key")
+	nbk.RootIdentityChainID = k.RootChain()
+	nbk.BitcoinKeyLevel = level
+	nbk.KeyType = keyType
+	nbk.NewKey = btcKey
+	nbk.Timestamp = timestampBytes(now)
+	nbk.PreimageIdentityKey = preimage(key)
+	nbk.Signature = sign(key, nbk.MarshalForSig())
+	return IdentityEntry{newIdentityEntry(k.ManagementChain(), nbk.ToExternalIDs()), false}, nil
+}
+
+// EfficiencyEntry returns the entry that sets the efficiency, in hundredths of a percent
+func (k *IdentityKeys) EfficiencyEntry(efficiency uint16, now interfaces.Timestamp) (IdentityEntry, error) {
+	key, err := k.identityKey(0)
+	if err != nil {
+		return IdentityEntry{}, err
+	}
+	nses := new(identityEntries.NewServerEfficiencyStruct)
+	nses.SetFunctionName()
+	nses.RootIdentityChainID = k.RootChain()
+	nses.Efficiency = efficiency
+	nses.Timestamp = timestampBytes(now)
+	nses.PreimageIdentityKey = preimage(key)
+	nses.Signature = sign(key, nses.MarshalForSig())
+	return IdentityEntry{newIdentityEntry(k.ManagementChain(), nses.ToExternalIDs()), false}, nil
+}
+
+// CoinbaseAddressEntry returns the entry that sets the factoid address the coinbase pays
+func (k *IdentityKeys) CoinbaseAddressEntry(address interfaces.IHash, now interfaces.Timestamp) (IdentityEntry, error) {
+	key, err := k.identityKey(0)
+	if err != nil {
+		return IdentityEntry{}, err
+	}
+	ncas := new(identityEntries.NewCoinbaseAddressStruct)
+	ncas.SetFunctionName()
+	ncas.RootIdentityChainID = k.RootChain()
+	ncas.CoinbaseAddress = address
+	ncas.Timestamp = timestampBytes(now)
+	ncas.PreimageIdentityKey = preimage(key)
+	ncas.Signature = sign(key, ncas.MarshalForSig())
+	return IdentityEntry{newIdentityEntry(k.RootChain(), ncas.ToExternalIDs()), false}, nil
+}
+
+func newIdentityEntry(chainID interfaces.IHash, extIDs [][]byte) *entryBlock.Entry {
+	e := entryBlock.NewEntry()
+	e.ChainID = chainID
+	for _, x := range extIDs {
+		e.ExtIDs = append(e.ExtIDs, primitives.ByteSlice{Bytes: x})
+	}
+	return e
+}
+
+// preimage is the identity key preimage of the entries, the hash of which is the identity key
+func preimage(key *primitives.PrivateKey) []byte {
+	return append([]byte{0x01}, key.Pub[:]...)
+}
+
+func sign(key *primitives.PrivateKey, data []byte) []byte {
+	sig := ed.Sign(key.Key, data)
+	return sig[:]
+}
+
+func timestampBytes(now interfaces.Timestamp) []byte {
+	by := make([]byte, 8)
+	binary.BigEndian.PutUint64(by, uint64(now.GetTimeSeconds()))
+	return by
+}
+
+// mineChainNonce returns a nonce for the last external id that makes the chain id of the
+// external ids start with 888888, as identity chains must
+func mineChainNonce(extIDs [][]byte) []byte {
+	last := len(extIDs) - 1
+	sums := make([]byte, 0, 32*len(extIDs))
+	for _, x := range extIDs[:last] {
+		sum := sha256.Sum256(x)
+		sums = append(sums, sum[:]...)
+	}
+	sums = sums[:32*len(extIDs)]
+
+	nonce := make([]byte, 8)
+	rand.Read(nonce)
+	n := binary.BigEndian.Uint64(nonce)
+	for ; ; n++ {
+		binary.BigEndian.PutUint64(nonce, n)
+		sum := sha256.Sum256(nonce)
+		copy(sums[32*last:], sum[:])
+		id := sha256.Sum256(sums)
+		if id[0] == 0x88 && id[1] == 0x88 && id[2] == 0x88 {
+			return nonce
+		}
+	}
+}
+
+// ValidateIdentityEntries processes the entries with the identity manager, as the blockchain
+// will at the given height and time, failing on the first one it rejects
+func ValidateIdentityEntries(im *identity.IdentityManager, entries []IdentityEntry, height uint32, now interfaces.Timestamp) error {
+	for _, e := range entries {
+		if _, err := im.ProcessIdentityEntry(e.Entry, height, now, false); err != nil {
+			return fmt.Errorf("entry %s of chain %s: %v", e.Entry.GetHash(), e.Entry.GetChainID(), err)
+		}
+	}
+	return nil
+}
+
+// IdentityView is how the factomd identity command shows an identity
+type IdentityView struct {
+	RootChainID          string           `json:"rootchainid"`
+	Registered           uint32           `json:"registered"`
+	Created              uint32           `json:"created"`
+	ManagementChainID    string           `json:"managementchainid,omitempty"`
+	ManagementRegistered uint32           `json:"managementregistered"`
+	ManagementCreated    uint32           `json:"managementcreated"`
+	IdentityKeys         []string         `json:"identitykeys"`
+	SigningKey           string           `json:"signingkey,omitempty"`
+	BitcoinKeys          []BitcoinKeyView `json:"bitcoinkeys,omitempty"`
+	Efficiency           float64          `json:"efficiency"`
+	CoinbaseAddress      string           `json:"coinbaseaddress,omitempty"`
+}
+
+// BitcoinKeyView is how the factomd identity command shows a Bitcoin key
+type BitcoinKeyView struct {
+	Level uint8  `json:"level"`
+	Type  string `json:"type"`
+	Key   string `json:"key"`
+}
+
+func isSet(h interfaces.IHash) bool {
+	return h != nil && !h.IsZero()
+}
+
+// NewIdentityView returns the view of an identity, with the efficiency in percent and
+// the addresses human readable
+func NewIdentityView(id *identity.Identity) *IdentityView {
+	v := new(IdentityView)
+	v.RootChainID = id.IdentityChainID.String()
+	v.Registered = id.IdentityRegistered
+	v.Created = id.IdentityCreated
+	if isSet(id.ManagementChainID) {
+		v.ManagementChainID = id.ManagementChainID.String()
+	}
+	v.ManagementRegistered = id.ManagementRegistered
+	v.ManagementCreated = id.ManagementCreated
+	for _, k := range id.Keys {
+		if isSet(k) {
+			v.IdentityKeys = append(v.IdentityKeys, k.String())
+		}
+	}
+	if isSet(id.SigningKey) {
+		v.SigningKey = id.SigningKey.String()
+	}
+	for _, k := range id.AnchorKeys {
+		b := BitcoinKeyView{Level: k.KeyLevel, Type: "P2PKH", Key: bitcoinAddress(0x00, k.SigningKey)}
+		if k.KeyType == 1 {
+			b.Type, b.Key = "P2SH", bitcoinAddress(0x05, k.SigningKey)
+		}
+		v.BitcoinKeys = append(v.BitcoinKeys, b)
+	}
+	v.Efficiency = float64(id.Efficiency) / 100
+	if isSet(id.CoinbaseAddress) {
+		v.CoinbaseAddress = id.GetCoinbaseHumanReadable()
+	}
+	return v
+}
+
+// bitcoinAddress is the mainnet address of a key hash, base58check with one version byte
+func bitcoinAddress(version byte, key [20]byte) string {
+	b := append([]byte{version}, key[:]...)
+	sum := sha256.Sum256(b)
+	sum = sha256.Sum256(sum[:])
+	return base58.Encode(append(b, sum[:4]...))
+}
+
+// IdentityNode reads identity chains from a node and submits identity entries to it, through
+// its API at Host
+type IdentityNode struct {
+	Host string
+}
+
+// identityChainEntry is an entry read from an identity chain, with the height and time of
+// its directory block
+type identityChainEntry struct {
+	Entry     *entryBlock.Entry
+	Height    uint32
+	Timestamp interfaces.Timestamp
+}
+
+func (n *IdentityNode) call(method string, params interface{}, result interface{}) error {
+	return apiCall(n.Host, "v2", method, params, result)
+}
+
+// chainExists tells whether the chain has entries in a block or in the process list
+func (n *IdentityNode) chainExists(chainID string) (bool, error) {
+	head := new(wsapi.ChainHeadResponse)
+	err := n.call("chain-head", wsapi.ChainIDRequest{ChainID: chainID}, head)
+	if e, ok := err.(*apiError); ok && e.Code == wsapi.NewMissingChainHeadError().Code {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// chainEntries returns the entries of a chain in blocks, oldest first
+func (n *IdentityNode) chainEntries(chainID string) ([]identityChainEntry, error) {
+	head := new(wsapi.ChainHeadResponse)
+	err := n.call("chain-head", wsapi.ChainIDRequest{ChainID: chainID}, head)
+	if e, ok := err.(*apiError); ok && e.Code == wsapi.NewMissingChainHeadError().Code {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []*wsapi.EBlock
+	for keyMR := head.ChainHead; keyMR != "" && keyMR != primitives.ZeroHash.String(); {
+		eb := new(wsapi.EBlock)
+		if err := n.call("entry-block", wsapi.KeyMRRequest{KeyMR: keyMR}, eb); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, eb)
+		keyMR = eb.Header.PrevKeyMR
+	}
+
+	var entries []identityChainEntry
+	for i := len(blocks) - 1; i >= 0; i-- {
+		eb := blocks[i]
+		for _, addr := range eb.EntryList {
+			r := new(wsapi.EntryResponse)
+			if err := n.call("entry", wsapi.HashRequest{Hash: addr.EntryHash}, r); err != nil {
+				return nil, err
+			}
+			e, err := entryFromResponse(r)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, identityChainEntry{e, uint32(eb.Header.DBHeight), primitives.NewTimestampFromSeconds(uint32(eb.Header.Timestamp))})
+		}
+	}
+	return entries, nil
+}
+
+func entryFromResponse(r *wsapi.EntryResponse) (*entryBlock.Entry, error) {
+	e := entryBlock.NewEntry()
+	chainID, err := primitives.HexToHash(r.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	e.ChainID = chainID
+	for _, x := range r.ExtIDs {
+		b, err := hex.DecodeString(x)
+		if err != nil {
+			return nil, err
+		}
+		e.ExtIDs = append(e.ExtIDs, primitives.ByteSlice{Bytes: b})
+	}
+	if e.Content.Bytes, err = hex.DecodeString(r.Content); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// LoadIdentity rebuilds an identity from its root chain, its entries in the identity
+// registration chain and its management chain, the way the node does.  It returns the
+// identity manager holding it and the height of the node.
+func (n *IdentityNode) LoadIdentity(rootChainID string) (*identity.IdentityManager, uint32, error) {
+	heights := new(wsapi.HeightsResponse)
+	if err := n.call("heights", nil, heights); err != nil {
+		return nil, 0, err
+	}
+
+	im := identity.NewIdentityManager()
+	process := func(chainID string, filter func(*entryBlock.Entry) bool) error {
+		entries, err := n.chainEntries(chainID)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if filter == nil || filter(e.Entry) {
+				// The entries the node rejected are in the chain too, so errors are ignored
+				im.ProcessIdentityEntry(e.Entry, e.Height, e.Timestamp, true)
+			}
+		}
+		im.ProcessOldEntries()
+		return nil
+	}
+	if err := process(rootChainID, nil); err != nil {
+		return nil, 0, err
+	}
+	root, err := primitives.HexToHash(rootChainID)
+	if err != nil {
+		return nil, 0, err
+	}
+	ours := func(e *entryBlock.Entry) bool {
+		extIDs := e.ExternalIDs()
+		return len(extIDs) > 2 && root.IsSameAs(primitives.NewHash(extIDs[2]))
+	}
+	if err := process(state.MAIN_FACTOM_IDENTITY_LIST, ours); err != nil {
+		return nil, 0, err
+	}
+	if id := im.GetIdentity(root); id != nil && isSet(id.ManagementChainID) {
+		if err := process(id.ManagementChainID.String(), nil); err != nil {
+			return nil, 0, err
+		}
+	}
+	return im, uint32(heights.DirectoryBlockHeight), nil
+}
+
+// Submit commits and reveals the entries, paying with the entry credit key, after checking
+// its balance covers them
+func (n *IdentityNode) Submit(entries []IdentityEntry, ec *primitives.PrivateKey) error {
+	var cost int64
+	for _, e := range entries {
+		data, err := e.Entry.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		c, err := util.EntryCost(data)
+		if err != nil {
+			return err
+		}
+		cost += int64(c)
+		if e.NewChain {
+			cost += 10
+		}
+	}
+	balance := new(wsapi.EntryCreditBalanceResponse)
+	if err := n.call("entry-credit-balance", wsapi.AddressRequest{Address: hex.EncodeToString(ec.Pub[:])}, balance); err != nil {
+		return err
+	}
+	if balance.Balance < cost {
+		return fmt.Errorf("the entries cost %d entry credits, but %s has %d", cost, primitives.ConvertECAddressToUserStr(factoid.NewAddress(ec.Pub[:])), balance.Balance)
+	}
+
+	for _, e := range entries {
+		commit, err := composeCommit(e, ec)
+		if err != nil {
+			return err
+		}
+		reveal, err := e.Entry.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		method := "entry"
+		if e.NewChain {
+			method = "chain"
+		}
+		if err := n.call("commit-"+method, wsapi.MessageRequest{Message: hex.EncodeToString(commit)}, nil); err != nil {
+			return err
+		}
+		if err := n.call("reveal-"+method, wsapi.EntryRequest{Entry: hex.EncodeToString(reveal)}, nil); err != nil {
+			return err
+		}
+		fmt.Printf("Submitted %s %s of chain %s\n", method, e.Entry.GetHash(), e.Entry.GetChainID())
+	}
+	return nil
+}
+
+// composeCommit returns the signed commit of the entry, or of its chain
+func composeCommit(e IdentityEntry, ec *primitives.PrivateKey) ([]byte, error) {
+	data, err := e.Entry.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	credits, err := util.EntryCost(data)
+	if err != nil {
+		return nil, err
+	}
+	var b6 primitives.ByteSlice6
+	copy(b6[:], milliTime(0))
+	var b32 primitives.ByteSlice32
+	copy(b32[:], ec.Pub[:])
+
+	if e.NewChain {
+		commit := entryCreditBlock.NewCommitChain()
+		commit.Credits = credits + 10
+		commit.EntryHash = e.Entry.GetHash()
+		commit.MilliTime = &b6
+		commit.ECPubKey = &b32
+		commit.Weld = e.Entry.GetWeldHash()
+		commit.ChainIDHash = primitives.Shad(e.Entry.ChainID.Bytes())
+		if err := commit.Sign(ec.Key[:]); err != nil {
+			return nil, err
+		}
+		return commit.MarshalBinary()
+	}
+	commit := entryCreditBlock.NewCommitEntry()
+	commit.Credits = credits
+	commit.EntryHash = e.Entry.GetHash()
+	commit.MilliTime = &b6
+	commit.ECPubKey = &b32
+	if err := commit.Sign(ec.Key[:]); err != nil {
+		return nil, err
+	}
+	return commit.MarshalBinary()
+}
+
+// ParseECKey reads an entry credit private key, Es... or hex
+func ParseECKey(s string) (*primitives.PrivateKey, error) {
+	if strings.HasPrefix(s, "Es") {
+		key, err := primitives.HumanReadableECPrivateKeyToPrivateKey(s)
+		if err != nil {
+			return nil, err
+		}
+		s = hex.EncodeToString(key)
+	}
+	return primitives.NewPrivateKeyFromHex(s)
+}
+
+// ParseBitcoinKey reads a Bitcoin key, either an address, the type of which it gives, or
+// the 20 byte hash of the key in hex, which is of type P2PKH unless p2sh
+func ParseBitcoinKey(s string, p2sh bool) (byte, [20]byte, error) {
+	var key [20]byte
+	if b, err := hex.DecodeString(s); err == nil && len(b) == 20 {
+		copy(key[:], b)
+		if p2sh {
+			return 1, key, nil
+		}
+		return 0, key, nil
+	}
+	b, version, err := base58.CheckDecodeWithOneVersionByte(s)
+	if err != nil {
+		return 0, key, fmt.Errorf("%s is not a Bitcoin address or 20 hex bytes: %v", s, err)
+	}
+	if len(b) != 20 {
+		return 0, key, fmt.Errorf("%s is not a Bitcoin address", s)
+	}
+	copy(key[:], b)
+	switch version {
+	case 0x00, 0x6f:
+		return 0, key, nil
+	case 0x05, 0xc4:
+		return 1, key, nil
+	}
+	return 0, key, fmt.Errorf("%s is not a P2PKH or P2SH Bitcoin address", s)
+}
+
+// ParseEfficiency reads an efficiency in percent, returning it in hundredths of a percent
+func ParseEfficiency(s string) (uint16, error) {
+	var percent float64
+	if _, err := fmt.Sscan(s, &percent); err != nil || percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("the efficiency must be a percent from 0 to 100, not %s", s)
+	}
+	return uint16(math.Round(percent * 100)), nil
+}
+
+// RunIdentity runs factomd identity, which writes the entries of a server identity
+func RunIdentity(args []string) int {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, `Usage: factomd identity keygen|register|signing-key|btc-key|efficiency|coinbase|show [flags]
+
+  keygen       generates the keys of a new identity and mines its chain ids into the key file
+  register     creates the root and management chains, registers them and sets the signing key
+  signing-key  sets a new block signing key, saving it to the key file
+  btc-key      sets the Bitcoin key of a level
+  efficiency   sets the efficiency, the percent of the coinbase that goes to the grant pool
+  coinbase     sets the factoid address the coinbase pays
+  show         shows the identity as the node has it
+
+Every entry is checked against the identity before it is submitted.  The commands that
+submit entries then show the identity as it will be once they are in a block.
+
+`)
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+	command := args[0]
+	switch command {
+	case "keygen", "register", "signing-key", "btc-key", "efficiency", "coinbase", "show":
+	default:
+		usage()
+		return 2
+	}
+
+	fs := flag.NewFlagSet("identity "+command, flag.ContinueOnError)
+	keyFile := fs.String("keys", "identity.json", "Key file of the identity")
+	var ecKey, host, chainID, btcKey, efficiency, address *string
+	var level *uint
+	var p2sh, dryRun *bool
+	if command != "keygen" {
+		host = fs.String("s", "localhost:8088", "Host and port of the node's API")
+	}
+	switch command {
+	case "register", "signing-key", "btc-key", "efficiency", "coinbase":
+		ecKey = fs.String("ec", "", "Entry credit private key, Es... or hex, that pays for the entries")
+		dryRun = fs.Bool("dryrun", false, "Check the entries and show the identity without submitting them")
+	case "show":
+		chainID = fs.String("chain", "", "Root chain id of the identity, instead of the one of the key file")
+	}
+	switch command {
+	case "btc-key":
+		level = fs.Uint("level", 0, "Level of the key, 0 to 3")
+		btcKey = fs.String("key", "", "Bitcoin address, or hash of the key in hex")
+		p2sh = fs.Bool("p2sh", false, "A key in hex is a P2SH one")
+	case "efficiency":
+		efficiency = fs.String("efficiency", "", "Efficiency in percent, to two decimals")
+	case "coinbase":
+		address = fs.String("address", "", "Factoid address, FA...")
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: factomd identity %s [flags]\n\n", command)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || (ecKey != nil && *ecKey == "" && !*dryRun) {
+		fs.Usage()
+		return 2
+	}
+
+	err := func() error {
+		if command == "keygen" {
+			if _, err := os.Stat(*keyFile); err == nil {
+				return fmt.Errorf("%s already exists", *keyFile)
+			}
+			fmt.Printf("Mining the chain ids, which takes a while\n")
+			k, err := NewIdentityKeys()
+			if err != nil {
+				return err
+			}
+			if err := k.Save(*keyFile); err != nil {
+				return err
+			}
+			signingKey, _ := primitives.NewPrivateKeyFromHex(k.SigningKey)
+			fmt.Printf("Wrote the keys of identity %s to %s\n\nThe factomd.conf of its server:\n", k.RootChainID, *keyFile)
+			fmt.Printf("IdentityChainID           = %s\nLocalServerPrivKey        = %s\nLocalServerPublicKey      = %s\n", k.RootChainID, k.SigningKey, signingKey.PublicKeyString())
+			return nil
+		}
+
+		node := &IdentityNode{Host: *host}
+		var k *IdentityKeys
+		root := ""
+		if chainID != nil && *chainID != "" {
+			root = *chainID
+		} else {
+			var err error
+			if k, err = ReadIdentityKeys(*keyFile); err != nil {
+				return err
+			}
+			root = k.RootChainID
+		}
+		rootHash, err := primitives.HexToHash(root)
+		if err != nil {
+			return fmt.Errorf("chain id %s: %v", root, err)
+		}
+		im, height, err := node.LoadIdentity(root)
+		if err != nil {
+			return err
+		}
+
+		now := primitives.NewTimestampNow()
+		var entries []IdentityEntry
+		var newSigningKey *primitives.PrivateKey
+		add := func(e IdentityEntry, err error) error {
+			entries = append(entries, e)
+			return err
+		}
+		switch command {
+		case "register":
+			exists, err := node.chainExists(k.RootChainID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("root chain %s already exists", k.RootChainID)
+			}
+			if entries, err = k.RegisterEntries(now); err != nil {
+				return err
+			}
+		case "signing-key":
+			newSigningKey = new(primitives.PrivateKey)
+			if err := newSigningKey.GenerateKey(); err != nil {
+				return err
+			}
+			err = add(k.SigningKeyEntry(newSigningKey.Pub[:], now))
+		case "btc-key":
+			if *level > 3 {
+				return fmt.Errorf("the level must be 0 to 3, not %d", *level)
+			}
+			keyType, key, err := ParseBitcoinKey(*btcKey, *p2sh)
+			if err != nil {
+				return err
+			}
+			if err := add(k.BitcoinKeyEntry(byte(*level), keyType, key, now)); err != nil {
+				return err
+			}
+		case "efficiency":
+			eff, err := ParseEfficiency(*efficiency)
+			if err != nil {
+				return err
+			}
+			if err := add(k.EfficiencyEntry(eff, now)); err != nil {
+				return err
+			}
+		case "coinbase":
+			if !primitives.ValidateFUserStr(*address) {
+				return fmt.Errorf("%q is not a factoid address", *address)
+			}
+			err = add(k.CoinbaseAddressEntry(primitives.NewHash(primitives.ConvertUserStrToAddress(*address)), now))
+		}
+		if err != nil {
+			return err
+		}
+
+		if command != "register" && command != "show" && im.GetIdentity(rootHash) == nil {
+			return fmt.Errorf("identity %s isn't on the node, run factomd identity register first", root)
+		}
+		if err := ValidateIdentityEntries(im, entries, height+1, now); err != nil {
+			return err
+		}
+		if len(entries) > 0 && !*dryRun {
+			ec, err := ParseECKey(*ecKey)
+			if err != nil {
+				return fmt.Errorf("entry credit key: %v", err)
+			}
+			if err := node.Submit(entries, ec); err != nil {
+				return err
+			}
+			if newSigningKey != nil {
+				k.SigningKey = newSigningKey.PrivateKeyString()
+				if err := k.Save(*keyFile); err != nil {
+					return err
+				}
+				fmt.Printf("Saved the new signing key to %s, its public key is %s\n", *keyFile, newSigningKey.PublicKeyString())
+			}
+		}
+
+		id := im.GetIdentity(rootHash)
+		if id == nil {
+			return fmt.Errorf("identity %s isn't on the node", root)
+		}
+		data, err := json.MarshalIndent(NewIdentityView(id), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
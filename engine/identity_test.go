@@ -0,0 +1,166 @@
+package engine_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/identity"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/engine"
+)
+
+// testIdentityKeys were made by factomd identity keygen, so the tests don't mine the chain ids
+var testIdentityKeys = IdentityKeys{
+	RootChainID:       "888888406b3d5d2dd3b90db2c70b9479921f7c0b89bd2eec97da03f63ddcb9d2",
+	RootNonce:         "77a83c44234626ca",
+	ManagementChainID: "888888f775c627c5a1cbd7beb38b27517d20ed746f7cbac604f82d599725d96f",
+	ManagementNonce:   "79a9c363316ef293",
+	IdentityKeys: [4]string{
+		"acce2c85a17e38cd868163c16981899c69640243925e8efd76fac78c189eff25",
+		"d672b27e055f2b61222229015e71591ffe45965a682e43d5a61cb2021947d8e7",
+		"45fbbdf2838294a914e14d569abe519a9bad2a66e60fc84f2957979382016287",
+		"8fa3acb162d3bffb60fbaa49dc1d34b1ccd47a9cc8c694c7d6d7e862c10fe415",
+	},
+	SigningKey: "2ae5515f361b267385e5302b7fca13ee97b7b078866bd3f78e53454bc6d4dc33",
+}
+
+func TestReadIdentityKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "identity.json")
+	k := testIdentityKeys
+	if err := k.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadIdentityKeys(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *read != k {
+		t.Errorf("read %v, saved %v", read, k)
+	}
+
+	k.RootNonce = "00"
+	if err := k.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadIdentityKeys(filename); err == nil {
+		t.Errorf("a key file with the wrong nonce was read")
+	}
+}
+
+func TestIdentityEntries(t *testing.T) {
+	k := testIdentityKeys
+	now := primitives.NewTimestampNow()
+	im := identity.NewIdentityManager()
+
+	entries, err := k.RegisterEntries(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 || !entries[0].NewChain || !entries[2].NewChain {
+		t.Fatalf("wrong register entries %v", entries)
+	}
+	if err := ValidateIdentityEntries(im, entries, 10, now); err != nil {
+		t.Fatal(err)
+	}
+
+	signingKey, _ := primitives.NewPrivateKeyFromHex(k.SigningKey)
+	keyType, btcKey, err := ParseBitcoinKey("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	efficiency, err := ParseEfficiency("45.25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := primitives.Sha([]byte("coinbase"))
+	fa := primitives.ConvertFctAddressToUserStr(factoid.NewAddress(address.Bytes()))
+
+	var more []IdentityEntry
+	add := func(e IdentityEntry, err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		more = append(more, e)
+	}
+	add(k.BitcoinKeyEntry(0, keyType, btcKey, now))
+	add(k.EfficiencyEntry(efficiency, now))
+	add(k.CoinbaseAddressEntry(primitives.NewHash(primitives.ConvertUserStrToAddress(fa)), now))
+	if err := ValidateIdentityEntries(im, more, 11, now); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewIdentityView(im.GetIdentity(k.RootChain()))
+	if v.RootChainID != k.RootChainID || v.ManagementChainID != k.ManagementChainID || v.Registered != 10 || v.Created != 10 {
+		t.Errorf("wrong chains %+v", v)
+	}
+	if v.SigningKey != signingKey.PublicKeyString() {
+		t.Errorf("signing key %s, want %s", v.SigningKey, signingKey.PublicKeyString())
+	}
+	if len(v.BitcoinKeys) != 1 || v.BitcoinKeys[0].Key != "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2" || v.BitcoinKeys[0].Type != "P2PKH" {
+		t.Errorf("wrong Bitcoin keys %+v", v.BitcoinKeys)
+	}
+	if v.Efficiency != 45.25 || v.CoinbaseAddress != fa {
+		t.Errorf("efficiency %v and coinbase address %s, want 45.25 and %s", v.Efficiency, v.CoinbaseAddress, fa)
+	}
+
+	// An entry signed with another key than the level 1 one is rejected
+	other := k
+	other.IdentityKeys[0] = other.IdentityKeys[1]
+	e, err := other.EfficiencyEntry(5000, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateIdentityEntries(im, []IdentityEntry{e}, 12, now); err == nil {
+		t.Errorf("an entry signed with the wrong key was accepted")
+	}
+
+	// So is one too old for the block
+	e, err = k.EfficiencyEntry(5000, primitives.NewTimestampFromSeconds(uint32(now.GetTimeSeconds()-24*60*60)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateIdentityEntries(im, []IdentityEntry{e}, 12, now); err == nil {
+		t.Errorf("an entry a day old was accepted")
+	}
+}
+
+func TestParseIdentityValues(t *testing.T) {
+	for _, c := range []struct {
+		key      string
+		p2sh     bool
+		keyType  byte
+		rejected bool
+	}{
+		{"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", false, 0, false},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", false, 1, false},
+		{"c5b7fd920dce5f61934e792c7e6fcc829aff533d", false, 0, false},
+		{"c5b7fd920dce5f61934e792c7e6fcc829aff533d", true, 1, false},
+		{"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3", false, 0, true},
+		{"c5b7fd92", false, 0, true},
+	} {
+		keyType, _, err := ParseBitcoinKey(c.key, c.p2sh)
+		if (err != nil) != c.rejected || (err == nil && keyType != c.keyType) {
+			t.Errorf("%s: type %d, error %v", c.key, keyType, err)
+		}
+	}
+
+	for s, want := range map[string]uint16{"0": 0, "45.25": 4525, "100": 10000, "12.345": 1235} {
+		if eff, err := ParseEfficiency(s); err != nil || eff != want {
+			t.Errorf("%s: %d %v, want %d", s, eff, err, want)
+		}
+	}
+	for _, s := range []string{"-1", "100.5", "half"} {
+		if _, err := ParseEfficiency(s); err == nil {
+			t.Errorf("efficiency %s was accepted", s)
+		}
+	}
+}
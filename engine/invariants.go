@@ -0,0 +1,388 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util"
+)
+
+// The invariants the InvariantChecker checks
+const (
+	InvariantKeyMR       = "keymr"       // Every node that saved a height has the same directory block there
+	InvariantAcks        = "acks"        // No two acks processed for the same VM and height differ
+	InvariantBalances    = "balances"    // No factoid or entry credit balance is negative
+	InvariantProcessList = "processlist" // The leaders' process lists match at the end of each minute
+	InvariantSupply      = "supply"      // The factoids held are those paid by coinbases less those burnt
+)
+
+// InvariantViolation is a safety property of the consensus found broken
+type InvariantViolation struct {
+	Invariant string
+	DBHeight  uint32
+	Nodes     []string // The nodes that disagree, or the node that broke it
+	Detail    string
+}
+
+func (v *InvariantViolation) String() string {
+	return fmt.Sprintf("%s invariant violated at height %d by %s: %s", v.Invariant, v.DBHeight, strings.Join(v.Nodes, ", "), v.Detail)
+}
+
+// InvariantChecker watches every node of a simulation and checks the safety of the consensus as
+// it runs, rather than only what a test asserts at the end.  It stops at the first violation.
+type InvariantChecker struct {
+	Interval time.Duration
+
+	mutex       sync.Mutex
+	stop        chan struct{}
+	violation   *InvariantViolation
+	onViolation func(v *InvariantViolation)
+
+	keyMRs  map[uint32]invariantSeen // The directory block of each saved height, and who saved it first
+	acks    map[[3]int]invariantSeen // The ack processed at each height/VM/position
+	minutes map[[3]int]invariantSeen // The serial hash of each height/VM at the EOM of each minute, on the leaders
+	nodes   map[*state.State]*invariantNode
+}
+
+// invariantSeen is a hash, and the node it was seen on first
+type invariantSeen struct {
+	hash [32]byte
+	node string
+}
+
+// invariantNode is how far each invariant has been checked on a node
+type invariantNode struct {
+	keyMRHeight   int            // Last height whose directory block was checked
+	acked         map[[2]int]int // Positions of each height/VM checked
+	processHeight uint32         // Last height processed, seen the check before
+	balanceHeight int            // Last height the balances and supply were checked at
+	mismatches    int            // Checks in a row the supply has been off at processHeight
+	supplyHeight  int            // Last factoid block counted into created and burnt
+	created       int64          // Factoshis paid by the coinbases
+	burnt         int64          // Factoshis burnt as fees and for entry credits
+}
+
+// invariantMismatches is the checks in a row the supply must be off for, so a check made while
+// a node is part way through processing a block isn't a violation
+const invariantMismatches = 3
+
+var invariantChecker *InvariantChecker
+
+// GetInvariantChecker returns the invariant checker of the simulation, nil if not checking
+func GetInvariantChecker() *InvariantChecker {
+	return invariantChecker
+}
+
+func NewInvariantChecker() *InvariantChecker {
+	c := new(InvariantChecker)
+	c.Interval = 100 * time.Millisecond
+	c.stop = make(chan struct{})
+	c.keyMRs = make(map[uint32]invariantSeen)
+	c.acks = make(map[[3]int]invariantSeen)
+	c.minutes = make(map[[3]int]invariantSeen)
+	c.nodes = make(map[*state.State]*invariantNode)
+	return c
+}
+
+// Run checks the nodes every Interval till stopped or an invariant is violated
+func (c *InvariantChecker) Run() {
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(c.Interval):
+		}
+		if v := c.Check(); v != nil {
+			c.mutex.Lock()
+			onViolation := c.onViolation
+			c.mutex.Unlock()
+			if onViolation == nil {
+				onViolation = stopOnViolation
+			}
+			onViolation(v)
+			return
+		}
+	}
+}
+
+// SetOnViolation sets what is done with the first violation found.  By default the node states
+// are dumped, the nodes shut down and the checker panics, failing a test at once.
+func (c *InvariantChecker) SetOnViolation(f func(v *InvariantViolation)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.onViolation = f
+}
+
+// Stop stops Run
+func (c *InvariantChecker) Stop() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+}
+
+// Violation returns the violation found, nil if none has been
+func (c *InvariantChecker) Violation() *InvariantViolation {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.violation
+}
+
+// Check checks every node once, and returns the first violation found
+func (c *InvariantChecker) Check() *InvariantViolation {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.violation != nil {
+		return c.violation
+	}
+	var highest uint32
+	for _, fn := range fnodes {
+		if v := c.checkNode(fn.State); v != nil {
+			c.violation = v
+			return v
+		}
+		if h := fn.State.GetHighestSavedBlk(); h > highest {
+			highest = h
+		}
+	}
+	c.forget(highest)
+	return nil
+}
+
+// checkNode checks one node.  The node is running while it is checked, so a panic from catching
+// it part way through changing something is taken as nothing to check this time.
+func (c *InvariantChecker) checkNode(s *state.State) (v *InvariantViolation) {
+	defer func() {
+		if e := recover(); e != nil {
+			v = nil
+		}
+	}()
+	n := c.nodes[s]
+	if n == nil {
+		n = &invariantNode{keyMRHeight: -1, balanceHeight: -1, supplyHeight: -1, acked: make(map[[2]int]int)}
+		c.nodes[s] = n
+	}
+	if v := c.checkKeyMRs(s, n); v != nil {
+		return v
+	}
+	if v := c.checkProcessLists(s, n); v != nil {
+		return v
+	}
+	return c.checkBalances(s, n)
+}
+
+// forget drops the acks of process lists too old for any node to still be processing
+func (c *InvariantChecker) forget(highest uint32) {
+	for key := range c.acks {
+		if key[0]+2 < int(highest) {
+			delete(c.acks, key)
+		}
+	}
+	for key := range c.minutes {
+		if key[0]+2 < int(highest) {
+			delete(c.minutes, key)
+		}
+	}
+	for _, n := range c.nodes {
+		for key := range n.acked {
+			if key[0]+2 < int(highest) {
+				delete(n.acked, key)
+			}
+		}
+	}
+}
+
+// checkKeyMRs checks the directory blocks the node has saved since the last check
+func (c *InvariantChecker) checkKeyMRs(s *state.State, n *invariantNode) *InvariantViolation {
+	saved := int(s.GetHighestSavedBlk())
+	for h := n.keyMRHeight + 1; h <= saved; h++ {
+		dblock, err := s.DB.FetchDBlockByHeight(uint32(h))
+		if err != nil || dblock == nil {
+			return nil // Not written yet
+		}
+		keyMR := dblock.GetKeyMR().Fixed()
+		if seen, ok := c.keyMRs[uint32(h)]; !ok {
+			c.keyMRs[uint32(h)] = invariantSeen{keyMR, s.FactomNodeName}
+		} else if seen.hash != keyMR {
+			return &InvariantViolation{InvariantKeyMR, uint32(h), []string{seen.node, s.FactomNodeName},
+				fmt.Sprintf("directory block %x is %x on the other", seen.hash[:6], keyMR[:6])}
+		}
+		n.keyMRHeight = h
+	}
+	return nil
+}
+
+// checkProcessLists checks the messages the node has processed since the last check
+func (c *InvariantChecker) checkProcessLists(s *state.State, n *invariantNode) *InvariantViolation {
+	for h := s.GetHighestSavedBlk(); h <= s.LLeaderHeight; h++ {
+		pl := s.ProcessLists.GetSafe(h)
+		if pl == nil {
+			continue
+		}
+		for vmIndex, vm := range pl.VMs {
+			key := [2]int{int(h), vmIndex}
+			for i := n.acked[key]; i < vm.Height && i < len(vm.ListAck); i++ {
+				ack, msg := vm.ListAck[i], vm.List[i]
+				if ack == nil || msg == nil {
+					return nil
+				}
+				at := [3]int{int(h), vmIndex, i}
+				if seen, ok := c.acks[at]; !ok {
+					c.acks[at] = invariantSeen{ack.MessageHash.Fixed(), s.FactomNodeName}
+				} else if seen.hash != ack.MessageHash.Fixed() {
+					return &InvariantViolation{InvariantAcks, h, []string{seen.node, s.FactomNodeName},
+						fmt.Sprintf("VM %d position %d acknowledges message %x and %x", vmIndex, i, seen.hash[:6], ack.MessageHash.Bytes()[:6])}
+				}
+
+				if eom, ok := msg.(*messages.EOM); ok && s.Leader {
+					at := [3]int{int(h), vmIndex, int(eom.Minute)}
+					if seen, ok := c.minutes[at]; !ok {
+						c.minutes[at] = invariantSeen{ack.SerialHash.Fixed(), s.FactomNodeName}
+					} else if seen.hash != ack.SerialHash.Fixed() {
+						return &InvariantViolation{InvariantProcessList, h, []string{seen.node, s.FactomNodeName},
+							fmt.Sprintf("VM %d differs at the end of minute %d", vmIndex, eom.Minute)}
+					}
+				}
+				n.acked[key] = i + 1
+			}
+		}
+	}
+	return nil
+}
+
+// checkBalances checks the balances once a node has processed a block, and has had a check's
+// time to finish with it
+func (c *InvariantChecker) checkBalances(s *state.State, n *invariantNode) *InvariantViolation {
+	height := s.DBStates.ProcessHeight
+	if height != n.processHeight {
+		n.processHeight = height
+		n.mismatches = 0
+		return nil
+	}
+	if int(height) == n.balanceHeight {
+		return nil
+	}
+
+	var held int64
+	s.FactoidBalancesPMutex.Lock()
+	for address, balance := range s.FactoidBalancesP {
+		if balance < 0 {
+			s.FactoidBalancesPMutex.Unlock()
+			return &InvariantViolation{InvariantBalances, height, []string{s.FactomNodeName},
+				fmt.Sprintf("factoid address %x has %d factoshis", address[:6], balance)}
+		}
+		held += balance
+	}
+	s.FactoidBalancesPMutex.Unlock()
+	s.ECBalancesPMutex.Lock()
+	for address, balance := range s.ECBalancesP {
+		if balance < 0 {
+			s.ECBalancesPMutex.Unlock()
+			return &InvariantViolation{InvariantBalances, height, []string{s.FactomNodeName},
+				fmt.Sprintf("entry credit address %x has %d entry credits", address[:6], balance)}
+		}
+	}
+	s.ECBalancesPMutex.Unlock()
+
+	if int(height) < n.supplyHeight {
+		// The node went back, to apply a block again
+		n.supplyHeight, n.created, n.burnt = -1, 0, 0
+	}
+	for h := n.supplyHeight + 1; h <= int(height); h++ {
+		fblock := invariantFBlock(s, uint32(h))
+		if fblock == nil {
+			return nil
+		}
+		for _, txn := range fblock.GetTransactions() {
+			in, _ := txn.TotalInputs()
+			out, _ := txn.TotalOutputs()
+			if len(txn.GetInputs()) == 0 {
+				n.created += int64(out)
+			} else {
+				n.burnt += int64(in) - int64(out)
+			}
+		}
+		n.supplyHeight = h
+	}
+	if supply := n.created - n.burnt; held != supply {
+		n.mismatches++
+		if n.mismatches < invariantMismatches {
+			return nil
+		}
+		return &InvariantViolation{InvariantSupply, height, []string{s.FactomNodeName},
+			fmt.Sprintf("%d factoshis are held, but the coinbases paid %d and %d were burnt, leaving %d", held, n.created, n.burnt, supply)}
+	}
+	n.balanceHeight = int(height)
+	return nil
+}
+
+// invariantFBlock returns the factoid block of a height the node has processed, which it may
+// not have saved yet
+func invariantFBlock(s *state.State, height uint32) interfaces.IFBlock {
+	if d := s.DBStates.Get(int(height)); d != nil && d.FactoidBlock != nil {
+		return d.FactoidBlock
+	}
+	fblock, err := s.DB.FetchFBlockByHeight(height)
+	if err != nil {
+		return nil
+	}
+	return fblock
+}
+
+// DumpNodes writes the state of the nodes a violation involves to a file in the home directory,
+// and returns its name
+func (v *InvariantViolation) DumpNodes() (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n%s\n", v.String(), GetSystemStatus(0, 0))
+	for _, fn := range fnodes {
+		s := fn.State
+		involved := false
+		for _, name := range v.Nodes {
+			involved = involved || name == s.FactomNodeName
+		}
+		if !involved {
+			continue
+		}
+		fmt.Fprintf(&b, "\n==================== %s ====================\n%s\n\n%s\n", s.FactomNodeName, s.ShortString(), s.DBStates.String())
+		if pl := s.ProcessLists.GetSafe(v.DBHeight); pl != nil {
+			fmt.Fprintf(&b, "\n%s\n", pl.String())
+		}
+	}
+	name := filepath.Join(util.GetHomeDir(), ".factom", "m2", fmt.Sprintf("invariant-%s-%d.txt", v.Invariant, v.DBHeight))
+	return name, ioutil.WriteFile(name, []byte(b.String()), 0644)
+}
+
+// stopOnViolation dumps the nodes, shuts them down and panics
+func stopOnViolation(v *InvariantViolation) {
+	fmt.Fprintf(os.Stderr, "\n%s\n", v.String())
+	if name, err := v.DumpNodes(); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not dump the nodes: %v\n", err)
+	} else {
+		fmt.Fprintf(os.Stderr, "The states of the nodes are in %s\n", name)
+	}
+	for _, fn := range fnodes {
+		fn.State.ShutdownNode(1)
+	}
+	panic(v.String())
+}
+
+// startInvariantChecker checks the invariants of the simulation till it stops
+func startInvariantChecker() {
+	invariantChecker = NewInvariantChecker()
+	go invariantChecker.Run()
+}
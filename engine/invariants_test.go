@@ -0,0 +1,18 @@
+package engine_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestInvariantViolation(t *testing.T) {
+	v := &InvariantViolation{Invariant: InvariantKeyMR, DBHeight: 12, Nodes: []string{"FNode0", "FNode02"}, Detail: "saved 1a2b and 3c4d"}
+	if s := v.String(); s != "keymr invariant violated at height 12 by FNode0, FNode02: saved 1a2b and 3c4d" {
+		t.Errorf("got %s", s)
+	}
+
+	if v := NewInvariantChecker().Check(); v != nil {
+		t.Errorf("expected no violation without any nodes, got %s", v)
+	}
+}
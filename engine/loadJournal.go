@@ -15,6 +15,7 @@ import (
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages/msgsupport"
+	"github.com/FactomProject/factomd/state"
 )
 
 func LoadJournal(s interfaces.IState, journal string) {
@@ -25,6 +26,10 @@ func LoadJournal(s interfaces.IState, journal string) {
 	}
 	defer f.Close()
 	r := bufio.NewReaderSize(f, 4*1024)
+	if header, _ := r.Peek(4); state.IsJournal(header) {
+		fmt.Printf("%s is a binary journal, replay it with factomd replay\n", journal)
+		return
+	}
 
 	LoadJournalFromReader(s, r)
 }
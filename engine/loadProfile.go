@@ -0,0 +1,161 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"path/filepath"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"gopkg.in/yaml.v2"
+)
+
+// The kinds of message the load generator sends, as named in its statistics
+const (
+	loadChain       = "chain"
+	loadEntry       = "entry"
+	loadTransfer    = "transfer"
+	loadECPurchase  = "ecpurchase"
+	loadBadSig      = "badsignature"
+	loadDoubleSpend = "doublespend"
+	loadReplay      = "replay"
+)
+
+// ReadLoadProfile reads a load profile from a JSON file, or a YAML one if it doesn't end in .json
+func ReadLoadProfile(filename string) (*interfaces.LoadProfile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	p := new(interfaces.LoadProfile)
+	if filepath.Ext(filename) == ".json" {
+		err = json.Unmarshal(data, p)
+	} else {
+		err = yaml.UnmarshalStrict(data, p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if err := checkLoadProfile(p); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return p, nil
+}
+
+func checkLoadProfile(p *interfaces.LoadProfile) error {
+	if p.Rate < 0 {
+		return fmt.Errorf("the rate can't be negative")
+	}
+	switch p.Curve {
+	case "", "constant":
+	case "burst":
+		if p.Period <= 0 || p.Burst < 0 || p.Duty <= 0 || p.Duty > 1 {
+			return fmt.Errorf("a burst curve needs a period, a burst that isn't negative and a duty of 0 to 1")
+		}
+	case "sine":
+		if p.Period <= 0 || p.Amplitude < 0 || p.Amplitude > 1 {
+			return fmt.Errorf("a sine curve needs a period and an amplitude of 0 to 1")
+		}
+	default:
+		return fmt.Errorf("the curve must be constant, burst or sine, not %q", p.Curve)
+	}
+	m := p.Mix
+	for _, w := range []float64{m.ChainCreates, m.Entries, m.Transfers, m.ECPurchases, m.BadSignatures, m.DoubleSpends, m.Replays} {
+		if w < 0 {
+			return fmt.Errorf("the weights of the mix can't be negative")
+		}
+	}
+	if p.Chains < 0 {
+		return fmt.Errorf("the number of chains can't be negative")
+	}
+	min, max := entrySizeRange(p)
+	if min < 0 || max < min || max > 10000 {
+		return fmt.Errorf("the entry size must be from 0 to 10000 bytes, with the min no more than the max")
+	}
+	switch p.EntrySize.Distribution {
+	case "", "uniform", "normal", "exponential":
+	default:
+		return fmt.Errorf("the entry size distribution must be uniform, normal or exponential, not %q", p.EntrySize.Distribution)
+	}
+	return nil
+}
+
+// loadRate is the rate of a profile t seconds after it was set
+func loadRate(p *interfaces.LoadProfile, t float64) float64 {
+	switch p.Curve {
+	case "burst":
+		if math.Mod(t, p.Period) < p.Duty*p.Period {
+			return p.Rate * p.Burst
+		}
+	case "sine":
+		return p.Rate * (1 + p.Amplitude*math.Sin(2*math.Pi*t/p.Period))
+	}
+	return p.Rate
+}
+
+func entrySizeRange(p *interfaces.LoadProfile) (min int, max int) {
+	if p.EntrySize.Min == 0 && p.EntrySize.Max == 0 {
+		return 128, 255
+	}
+	return p.EntrySize.Min, p.EntrySize.Max
+}
+
+// entrySize picks the size of the content of an entry
+func entrySize(p *interfaces.LoadProfile, r *rand.Rand) int {
+	min, max := entrySizeRange(p)
+	span := float64(max - min)
+	var size float64
+	switch p.EntrySize.Distribution {
+	case "normal":
+		size = float64(min) + span/2 + r.NormFloat64()*span/6
+	case "exponential":
+		size = float64(min) + r.ExpFloat64()*span/4
+	default:
+		size = float64(min) + r.Float64()*(span+1)
+	}
+	if size < float64(min) {
+		return min
+	}
+	if size > float64(max) {
+		return max
+	}
+	return int(size)
+}
+
+// loadKind picks the kind of the next message
+func loadKind(p *interfaces.LoadProfile, r *rand.Rand) string {
+	m := p.Mix
+	weights := []struct {
+		kind   string
+		weight float64
+	}{
+		{loadChain, m.ChainCreates},
+		{loadEntry, m.Entries},
+		{loadTransfer, m.Transfers},
+		{loadECPurchase, m.ECPurchases},
+		{loadBadSig, m.BadSignatures},
+		{loadDoubleSpend, m.DoubleSpends},
+		{loadReplay, m.Replays},
+	}
+	total := 0.
+	for _, w := range weights {
+		total += w.weight
+	}
+	if total == 0 {
+		return loadEntry
+	}
+	pick := r.Float64() * total
+	for _, w := range weights {
+		if pick < w.weight {
+			return w.kind
+		}
+		pick -= w.weight
+	}
+	return loadEntry
+}
@@ -0,0 +1,60 @@
+package engine_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestReadLoadProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "load")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p, err := ReadLoadProfile(writeScenario(t, dir, "mix.yaml", `
+rate: 20
+curve: burst
+period: 60
+burst: 4
+duty: 0.25
+chains: 5
+mix: {chaincreates: 1, entries: 10, transfers: 2, ecpurchases: 1, badsignatures: 1, doublespends: 1, replays: 1}
+entrysize: {min: 100, max: 1000, distribution: exponential}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rate != 20 || p.Curve != "burst" || p.Chains != 5 || p.Mix.Entries != 10 || p.EntrySize.Max != 1000 {
+		t.Errorf("read %+v", p)
+	}
+
+	p, err = ReadLoadProfile(writeScenario(t, dir, "sine.json", `{"rate": 5, "curve": "sine", "period": 30, "amplitude": 0.5}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Curve != "sine" || p.Amplitude != 0.5 {
+		t.Errorf("read %+v", p)
+	}
+
+	bad := map[string]string{
+		"rate can't be negative": "rate: -1\n",
+		"curve must be":          "rate: 1\ncurve: square\n",
+		"a burst curve needs":    "rate: 1\ncurve: burst\nperiod: 10\n",
+		"a sine curve needs":     "rate: 1\ncurve: sine\nperiod: 10\namplitude: 2\n",
+		"weights of the mix":     "rate: 1\nmix: {entries: -1}\n",
+		"entry size must be":     "rate: 1\nentrysize: {min: 500, max: 100}\n",
+		"size distribution must": "rate: 1\nentrysize: {min: 1, max: 100, distribution: pareto}\n",
+		"field speed not found":  "speed: 1\n",
+	}
+	for expected, data := range bad {
+		_, err := ReadLoadProfile(writeScenario(t, dir, "bad.yaml", data))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected an error with %q, got %v", expected, err)
+		}
+	}
+}
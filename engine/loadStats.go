@@ -0,0 +1,215 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/clock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/state"
+)
+
+// maxLoadSamples is how many of the latest latencies of each kind of message are kept
+const maxLoadSamples = 10000
+
+// loadTracker follows the messages sent by the load generator until they are saved in a block,
+// timing how long they take to be acknowledged and to get into a directory block
+type loadTracker struct {
+	mtx     sync.Mutex
+	pending []*loadSent
+	kinds   map[string]*loadKindStats
+	resets  int // Times the statistics were reset, so a check across a reset is thrown away
+}
+
+type loadSent struct {
+	kind   string
+	txid   interfaces.IHash
+	commit bool // An entry or chain commit, looked up by its transaction ID
+	sent   time.Time
+	acked  bool
+}
+
+type loadKindStats struct {
+	sent, acked, inBlock, rejected, lost int
+	ackLatency, blockLatency             loadSamples
+}
+
+// loadSamples are latencies in milliseconds, the oldest overwritten once there are maxLoadSamples
+type loadSamples struct {
+	samples []float64
+	next    int
+}
+
+func (l *loadSamples) add(d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+	if len(l.samples) < maxLoadSamples {
+		l.samples = append(l.samples, ms)
+		return
+	}
+	l.samples[l.next] = ms
+	l.next = (l.next + 1) % maxLoadSamples
+}
+
+func (l *loadSamples) summary() interfaces.LoadLatency {
+	var s interfaces.LoadLatency
+	s.Count = len(l.samples)
+	if s.Count == 0 {
+		return s
+	}
+	sorted := append([]float64{}, l.samples...)
+	sort.Float64s(sorted)
+	total := 0.
+	for _, ms := range sorted {
+		total += ms
+	}
+	at := func(p float64) float64 { return sorted[int(p*float64(len(sorted)-1))] }
+	s.Min = sorted[0]
+	s.Max = sorted[len(sorted)-1]
+	s.Mean = total / float64(len(sorted))
+	s.P50, s.P90, s.P99 = at(.5), at(.9), at(.99)
+	return s
+}
+
+func newLoadTracker() *loadTracker {
+	t := new(loadTracker)
+	t.kinds = make(map[string]*loadKindStats)
+	return t
+}
+
+func (t *loadTracker) kind(kind string) *loadKindStats {
+	k := t.kinds[kind]
+	if k == nil {
+		k = new(loadKindStats)
+		t.kinds[kind] = k
+	}
+	return k
+}
+
+// sent records a message sent.  Messages with no transaction ID are only counted.
+func (t *loadTracker) sent(kind string, txid interfaces.IHash, commit bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.kind(kind).sent++
+	if txid != nil {
+		t.pending = append(t.pending, &loadSent{kind: kind, txid: txid, commit: commit, sent: clock.Now()})
+	}
+}
+
+// check looks up the messages still pending in the state of a node
+func (t *loadTracker) check(s *state.State) {
+	t.mtx.Lock()
+	pending := t.pending
+	resets := t.resets
+	t.mtx.Unlock()
+
+	type result struct {
+		status int
+		at     time.Time
+	}
+	results := make([]result, len(pending))
+	for i, p := range pending {
+		if p.commit {
+			results[i].status, _, _, _ = s.GetEntryCommitAckByTXID(p.txid)
+		} else {
+			results[i].status, _, _, _, _ = s.GetACKStatus(p.txid)
+		}
+		results[i].at = clock.Now()
+	}
+	giveUp := time.Duration(10*s.GetDirectoryBlockInSeconds()) * time.Second
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.resets != resets {
+		return
+	}
+	var still []*loadSent
+	for i, p := range pending {
+		k := t.kind(p.kind)
+		r := results[i]
+		if !p.acked && (r.status == constants.AckStatusACK || r.status == constants.AckStatus1Minute || r.status == constants.AckStatusDBlockConfirmed) {
+			p.acked = true
+			k.acked++
+			k.ackLatency.add(r.at.Sub(p.sent))
+		}
+		switch {
+		case r.status == constants.AckStatusDBlockConfirmed:
+			k.inBlock++
+			k.blockLatency.add(r.at.Sub(p.sent))
+		case r.status == constants.AckStatusInvalid:
+			k.rejected++
+		case r.at.Sub(p.sent) > giveUp:
+			k.lost++
+		default:
+			still = append(still, p)
+		}
+	}
+	// Keep anything sent while the state was being checked
+	t.pending = append(still, t.pending[len(pending):]...)
+}
+
+// run checks the pending messages on the node the API talks to every tenth of a second
+func (t *loadTracker) run() {
+	for {
+		clock.Sleep(100 * time.Millisecond)
+		t.check(fnodes[wsapiNode].State)
+	}
+}
+
+// reset starts the statistics again
+func (t *loadTracker) reset() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.pending = nil
+	t.kinds = make(map[string]*loadKindStats)
+	t.resets++
+}
+
+func (t *loadTracker) stats() map[string]*interfaces.LoadTypeStats {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	pending := make(map[string]int)
+	for _, p := range t.pending {
+		pending[p.kind]++
+	}
+	stats := make(map[string]*interfaces.LoadTypeStats)
+	for kind, k := range t.kinds {
+		s := new(interfaces.LoadTypeStats)
+		s.Sent = k.sent
+		s.Acked = k.acked
+		s.InBlock = k.inBlock
+		s.Pending = pending[kind]
+		s.Rejected = k.rejected
+		s.Lost = k.lost
+		s.AckLatency = k.ackLatency.summary()
+		s.BlockLatency = k.blockLatency.summary()
+		stats[kind] = s
+	}
+	return stats
+}
+
+// loadStatsString formats the statistics of the load generator for the simulator console
+func loadStatsString(stats *interfaces.LoadStats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Load generator running %v at %.1f messages per second\n", stats.Running, stats.Rate)
+	var kinds []string
+	for kind := range stats.Types {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	fmt.Fprintf(&b, "%-13s %7s %7s %7s %7s %8s %7s %9s %9s %9s %9s\n", "kind", "sent", "acked", "inblock", "pending", "rejected", "lost",
+		"ack p50", "ack p99", "blk p50", "blk p99")
+	for _, kind := range kinds {
+		t := stats.Types[kind]
+		fmt.Fprintf(&b, "%-13s %7d %7d %7d %7d %8d %7d %7.0fms %7.0fms %7.0fms %7.0fms\n", kind, t.Sent, t.Acked, t.InBlock, t.Pending, t.Rejected, t.Lost,
+			t.AckLatency.P50, t.AckLatency.P99, t.BlockLatency.P50, t.BlockLatency.P99)
+	}
+	return b.String()
+}
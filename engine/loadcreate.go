@@ -4,15 +4,20 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math/rand"
+	"sync"
 	"time"
 
 	"crypto/sha256"
 
+	ed "github.com/FactomProject/ed25519"
+	"github.com/FactomProject/factomd/common/clock"
+	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/entryBlock"
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
 	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/messages/msgsupport"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/common/primitives/random"
 	"github.com/FactomProject/factomd/state"
@@ -29,15 +34,37 @@ type LoadGenerator struct {
 	tight     atomic.AtomicBool      // Only allocate ECs as needed (more EC purchases)
 	txoffset  int64                  // Offset to be added to the timestamp of created tx to test time limits.
 	state     *state.State           // Access to logging
+
+	mtx        sync.Mutex
+	profile    *interfaces.LoadProfile // What to send if set, rather than PerSecond entries
+	profileSet time.Time               // When the profile was set, for its rate curve
+	rate       float64                 // Messages per second being sent
+	random     *rand.Rand
+	chains     []interfaces.IHash // Chains made by the profile, to write entries into
+	sentMsgs   []interfaces.IMsg  // The latest messages sent, to replay
+	spender    interfaces.IHash   // Private key of the factoid address double spends are made from now
+	spend      interfaces.IHash   // The last transaction of the double spends, waited on to be acknowledged
+	spent      uint64             // Amount the spender spent, to be spent again
+	tracker    *loadTracker
 }
 
+var _ interfaces.ILoadGenerator = (*LoadGenerator)(nil)
+
 // NewLoadGenerator makes a new load generator. The state is used for funding the transaction
 func NewLoadGenerator(s *state.State) *LoadGenerator {
 	lg := new(LoadGenerator)
 	lg.ECKey, _ = primitives.NewPrivateKeyFromHex(ecSec)
 	lg.stop = make(chan bool, 5)
 	lg.state = s
-
+	lg.random = clock.NewRand("load")
+	lg.spender = primitives.Sha([]byte("load generator double spends"))
+	lg.tracker = newLoadTracker()
+	go lg.tracker.run()
+
+	// The debug API reaches the load generator through the state of a simulated node
+	for _, fnode := range fnodes {
+		fnode.State.LoadGenerator = lg
+	}
 	return lg
 }
 
@@ -50,16 +77,39 @@ func (lg *LoadGenerator) Run() {
 	//FundWallet(fnodes[wsapiNode].State, 15000e8)
 
 	// Every second add the per second amount
-	ticker := time.NewTicker(time.Second)
-	for range ticker.C {
+	next := clock.Now()
+	var owed float64 // Messages of a profile owed, carried over to the next second
+	for {
+		next = next.Add(time.Second)
+		clock.Sleep(next.Sub(clock.Now()))
 		select {
 		case <-lg.stop:
+			lg.setRate(0)
 			lg.running.Store(false)
 			return
 		default:
 
 		}
+
+		if p, set := lg.getProfile(); p != nil {
+			rate := loadRate(p, clock.Since(set).Seconds())
+			lg.setRate(rate)
+			if p.Rate == 0 {
+				lg.running.Store(false)
+				return
+			}
+			owed += rate
+			top := int(owed)
+			owed -= float64(top)
+			for i := 0; i < top; i++ {
+				lg.send(p)
+				clock.Sleep(time.Duration(800/top) * time.Millisecond) // spread the load out over 800ms + overhead
+			}
+			continue
+		}
+
 		addSend := lg.PerSecond.Load()
+		lg.setRate(float64(addSend) / 10)
 		lg.ToSend += addSend
 		top := lg.ToSend / 10      // ToSend is in tenths so get the integer part
 		lg.ToSend = lg.ToSend % 10 // save an fractional part for next iteration
@@ -73,25 +123,248 @@ func (lg *LoadGenerator) Run() {
 			var c interfaces.IMsg
 			e := RandomEntry()
 			if chain == nil {
-				c = lg.NewCommitChain(e)
+				cc := lg.NewCommitChain(e)
+				lg.tracker.sent(loadChain, cc.CommitChain.GetSigHash(), true)
+				c = cc
 				chain = e.ChainID
 			} else {
 				e.ChainID = chain
-				c = lg.NewCommitEntry(e)
+				ce := lg.NewCommitEntry(e)
+				lg.tracker.sent(loadEntry, ce.CommitEntry.GetSigHash(), true)
+				c = ce
 			}
 			r := lg.NewRevealEntry(e)
 			s := fnodes[wsapiNode].State
 			s.APIQueue().Enqueue(c)
 			s.APIQueue().Enqueue(r)
-			time.Sleep(time.Duration(800/top) * time.Millisecond) // spread the load out over 800ms + overhead
+			clock.Sleep(time.Duration(800/top) * time.Millisecond) // spread the load out over 800ms + overhead
+		}
+	}
+}
+
+// SetProfile starts sending the load a profile describes, starting the statistics again, or
+// stops the load if its rate is 0
+func (lg *LoadGenerator) SetProfile(p *interfaces.LoadProfile) error {
+	if err := checkLoadProfile(p); err != nil {
+		return err
+	}
+	c := *p
+	lg.mtx.Lock()
+	lg.profile = &c
+	lg.profileSet = clock.Now()
+	lg.mtx.Unlock()
+
+	if p.Rate > 0 {
+		lg.tracker.reset()
+		go lg.Run()
+	}
+	return nil
+}
+
+// ClearProfile goes back to writing PerSecond entries
+func (lg *LoadGenerator) ClearProfile() {
+	lg.mtx.Lock()
+	defer lg.mtx.Unlock()
+	lg.profile = nil
+}
+
+func (lg *LoadGenerator) getProfile() (*interfaces.LoadProfile, time.Time) {
+	lg.mtx.Lock()
+	defer lg.mtx.Unlock()
+	return lg.profile, lg.profileSet
+}
+
+func (lg *LoadGenerator) setRate(rate float64) {
+	lg.mtx.Lock()
+	defer lg.mtx.Unlock()
+	lg.rate = rate
+}
+
+func (lg *LoadGenerator) Stats() *interfaces.LoadStats {
+	stats := new(interfaces.LoadStats)
+	stats.Running = lg.running.Load()
+	lg.mtx.Lock()
+	if lg.profile != nil {
+		p := *lg.profile
+		stats.Profile = &p
+	}
+	if stats.Running {
+		stats.Rate = lg.rate
+	}
+	lg.mtx.Unlock()
+	stats.Types = lg.tracker.stats()
+	return stats
+}
+
+// send sends one message of the kind picked by a profile
+func (lg *LoadGenerator) send(p *interfaces.LoadProfile) {
+	s := fnodes[wsapiNode].State
+	kind := loadKind(p, lg.random)
+
+	chains := p.Chains
+	if chains == 0 {
+		chains = 1
+	}
+	if kind == loadReplay && len(lg.sentMsgs) == 0 {
+		kind = loadEntry
+	}
+	if kind == loadEntry && len(lg.chains) < chains {
+		kind = loadChain // Make the chains to write into first
+	}
+
+	switch kind {
+	case loadChain:
+		e := lg.randomEntry(p)
+		c := lg.NewCommitChain(e)
+		lg.chains = append(lg.chains, e.ChainID)
+		lg.enqueue(s, kind, c, c.CommitChain.GetSigHash(), true)
+		lg.enqueue(s, "", lg.NewRevealEntry(e), nil, false)
+
+	case loadEntry:
+		e := lg.randomEntry(p)
+		e.ChainID = lg.chains[len(lg.chains)-1-lg.random.Intn(chains)]
+		c := lg.NewCommitEntry(e)
+		lg.enqueue(s, kind, c, c.CommitEntry.GetSigHash(), true)
+		lg.enqueue(s, "", lg.NewRevealEntry(e), nil, false)
+
+	case loadTransfer:
+		lg.transfer(s, kind, bankSecret(), lg.randomAddress(), 1e6)
+
+	case loadECPurchase:
+		ecPrice := s.GetFactoshisPerEC()
+		ts := primitives.NewTimestampFromMilliseconds(uint64(primitives.NewTimestampNow().GetTimeMilli() + lg.txoffset))
+		outEC, _ := primitives.HexToHash("c23ae8eec2beb181a0da926bd2344e988149fbe839fbc7489f2096e7d6110243")
+		trans, err := ComposeEcTransaction(bankSecret(), outEC, ts, 100*ecPrice, ecPrice)
+		if err != nil {
+			lg.state.LogPrintf("loadgenerator", "Failed to make an EC purchase: %v", err)
+			return
+		}
+		lg.enqueueTransaction(s, kind, trans)
+
+	case loadBadSig:
+		e := lg.randomEntry(p)
+		c := lg.NewCommitEntry(e)
+		c.CommitEntry.Sig[0] ^= 0xff // Break the signature of the commit, not of the message
+		c.Sign(lg.ECKey)
+		lg.enqueue(s, kind, c, c.CommitEntry.GetSigHash(), true)
+
+	case loadDoubleSpend:
+		lg.doubleSpend(s)
+
+	case loadReplay:
+		data, err := lg.sentMsgs[lg.random.Intn(len(lg.sentMsgs))].MarshalBinary()
+		if err != nil {
+			return
+		}
+		m, err := msgsupport.UnmarshalMessage(data)
+		if err != nil {
+			return
+		}
+		lg.enqueue(s, kind, m, nil, false)
+	}
+}
+
+// enqueue sends a message and tracks it under its kind, if it has one
+func (lg *LoadGenerator) enqueue(s *state.State, kind string, m interfaces.IMsg, txid interfaces.IHash, commit bool) {
+	s.APIQueue().Enqueue(m)
+	if kind == "" {
+		return
+	}
+	lg.tracker.sent(kind, txid, commit)
+	if kind != loadReplay && kind != loadBadSig && kind != loadDoubleSpend {
+		// Remember the valid messages sent lately, to replay
+		if len(lg.sentMsgs) >= 100 {
+			lg.sentMsgs = lg.sentMsgs[1:]
+		}
+		lg.sentMsgs = append(lg.sentMsgs, m)
+	}
+}
+
+func (lg *LoadGenerator) enqueueTransaction(s *state.State, kind string, trans *factoid.Transaction) {
+	msg := new(messages.FactoidTransaction)
+	msg.SetTransaction(trans)
+	lg.enqueue(s, kind, msg, trans.GetSigHash(), false)
+}
+
+func (lg *LoadGenerator) transfer(s *state.State, kind string, from interfaces.IHash, to interfaces.IHash, amt uint64) interfaces.IHash {
+	trans, err := ComposeFctTransaction(amt, from, to, s.GetFactoshisPerEC())
+	if err != nil {
+		lg.state.LogPrintf("loadgenerator", "Failed to make a transfer: %v", err)
+		return nil
+	}
+	lg.enqueueTransaction(s, kind, trans)
+	return trans.GetSigHash()
+}
+
+// doubleSpend takes a spender through funding, spending all it was funded with, and then
+// spending it again, each step once the one before has been acknowledged.  The leaders hold a
+// double spend until its address has the funds, so each spender is only funded once.
+func (lg *LoadGenerator) doubleSpend(s *state.State) {
+	if lg.spend != nil {
+		status, _, _, _, _ := s.GetACKStatus(lg.spend)
+		switch status {
+		case constants.AckStatusACK, constants.AckStatus1Minute, constants.AckStatusDBlockConfirmed:
+		case constants.AckStatusInvalid:
+			lg.spend = nil // Start again
+			return
+		default:
+			return // Nothing is sent while waiting
+		}
+	}
+	switch {
+	case lg.spend == nil:
+		lg.spender = primitives.Sha(lg.spender.Bytes())
+		lg.spent = 0
+		var sec [64]byte
+		copy(sec[:32], lg.spender.Bytes())
+		rcd := factoid.NewRCD_1(ed.GetPublicKey(&sec)[:])
+		addr, _ := rcd.GetAddress()
+		lg.spend = lg.transfer(s, loadTransfer, bankSecret(), addr, 1e8)
+	case lg.spent == 0:
+		// The first spend is good.  Its fee is the input less the amount.
+		trans, err := ComposeFctTransaction(1, lg.spender, lg.randomAddress(), s.GetFactoshisPerEC())
+		if err != nil {
+			return
 		}
+		in, _ := trans.GetInput(0)
+		lg.spent = 1e8 - (in.GetAmount() - 1)
+		lg.spend = lg.transfer(s, loadTransfer, lg.spender, lg.randomAddress(), lg.spent)
+	default:
+		lg.transfer(s, loadDoubleSpend, lg.spender, lg.randomAddress(), lg.spent)
+		lg.spend = nil
 	}
 }
 
+func (lg *LoadGenerator) randomEntry(p *interfaces.LoadProfile) *entryBlock.Entry {
+	e := RandomEntry()
+	content := make([]byte, entrySize(p, lg.random))
+	lg.random.Read(content)
+	e.Content = primitives.ByteSlice{Bytes: content}
+	return e
+}
+
+func (lg *LoadGenerator) randomAddress() interfaces.IHash {
+	a := make([]byte, 32)
+	lg.random.Read(a)
+	return primitives.NewHash(a)
+}
+
+// bankSecret is the private key of the factoid address funded by the simulator's genesis block
+func bankSecret() interfaces.IHash {
+	inSec, _ := primitives.HexToHash("FB3B471B1DCDADFEB856BD0B02D8BF49ACE0EDD372A3D9F2A95B78EC12A324D6")
+	return inSec
+}
+
 func (lg *LoadGenerator) Stop() {
 	lg.stop <- true
 }
 
+// profiled returns true if a profile is sending load
+func (lg *LoadGenerator) profiled() bool {
+	p, _ := lg.getProfile()
+	return p != nil && p.Rate > 0
+}
+
 func RandomEntry() *entryBlock.Entry {
 	entry := entryBlock.NewEntry()
 	entry.Content = primitives.ByteSlice{Bytes: random.RandByteSliceOfLen(rand.Intn(128) + 128)}
@@ -142,7 +415,7 @@ func (lg *LoadGenerator) KeepUsFunded() {
 		}
 
 		//EC3Eh7yQKShgjkUSFrPbnQpboykCzf4kw9QHxi47GGz5P2k3dbab is EC address
-		if lg.PerSecond == 0 && limitBuys {
+		if lg.PerSecond == 0 && !lg.profiled() && limitBuys {
 			if i%100 == 0 {
 				// Log our occasional realization that we have nothing to do.
 				outEC, _ := primitives.HexToHash("c23ae8eec2beb181a0da926bd2344e988149fbe839fbc7489f2096e7d6110243")
@@ -0,0 +1,135 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/receipts"
+	"github.com/FactomProject/factomd/util"
+)
+
+// ReadReceipt reads a receipt saved as is, as the result of the receipt api call, or
+// as the whole response to the call
+func ReadReceipt(filename string) (*receipts.Receipt, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Receipt *receipts.Receipt
+		Result  *struct {
+			Receipt *receipts.Receipt
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	switch {
+	case doc.Result != nil && doc.Result.Receipt != nil:
+		return doc.Result.Receipt, nil
+	case doc.Receipt != nil:
+		return doc.Receipt, nil
+	}
+	return receipts.DecodeReceiptString(string(data))
+}
+
+// VerifyReceipt checks the receipt proves its entry or transaction is in its directory
+// block, and that its anchors are signed by the keys of their chains.  If keyMR is given,
+// the directory block must be the one with that KeyMR.  What the receipt proves is
+// written to w.
+func VerifyReceipt(w io.Writer, r *receipts.Receipt, keyMR string, bitcoinKeys, ethereumKeys []interfaces.Verifier) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if keyMR != "" && r.DirectoryBlockKeyMR.String() != keyMR {
+		return fmt.Errorf("the receipt is for directory block %s, not %s", r.DirectoryBlockKeyMR, keyMR)
+	}
+	if err := r.ValidateAnchors(bitcoinKeys, ethereumKeys); err != nil {
+		return err
+	}
+
+	switch {
+	case r.Entry != nil:
+		fmt.Fprintf(w, "Entry %s is in entry block %s\n", r.Entry.EntryHash, r.EntryBlockKeyMR)
+	case r.EntryCreditBlock != "":
+		fmt.Fprintf(w, "Commit %s is in the entry credit block\n", r.Transaction.Hash)
+	default:
+		fmt.Fprintf(w, "Transaction %s is in factoid block %s\n", r.Transaction.Hash, r.FactoidBlockKeyMR)
+	}
+	fmt.Fprintf(w, "which is in directory block %s at height %d\n", r.DirectoryBlockKeyMR, r.DirectoryBlockHeight)
+	for _, a := range r.Anchors {
+		switch {
+		case a.Record.Bitcoin != nil:
+			fmt.Fprintf(w, "anchored in Bitcoin transaction %s of block %d\n", a.Record.Bitcoin.TXID, a.Record.Bitcoin.BlockHeight)
+		case a.Record.Ethereum != nil:
+			fmt.Fprintf(w, "anchored in Ethereum transaction %s of block %d, with blocks %d to %d\n", a.Record.Ethereum.TxID, a.Record.Ethereum.BlockHeight, a.Record.DBHeightMin, a.Record.DBHeightMax)
+		}
+	}
+	if len(r.Anchors) == 0 {
+		fmt.Fprintf(w, "the receipt has no anchors\n")
+	}
+	return nil
+}
+
+// RunReceipt runs factomd receipt, which checks receipts without a node or database
+func RunReceipt(args []string) int {
+	fs := flag.NewFlagSet("receipt", flag.ContinueOnError)
+	keyMR := fs.String("keymr", "", "KeyMR of the directory block the receipt must be for")
+	btcKeys := fs.String("btckeys", strings.Join(util.DefaultBitcoinAnchorRecordPublicKeys, ","), "Keys the Bitcoin anchor records are signed with, comma separated")
+	ethKeys := fs.String("ethkeys", strings.Join(util.DefaultEthereumAnchorRecordPublicKeys, ","), "Keys the Ethereum anchor records are signed with, comma separated")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: factomd receipt verify [-keymr keymr] [-btckeys keys] [-ethkeys keys] receipt.json\n\n")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 || args[0] != "verify" {
+		fs.Usage()
+		return 2
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	err := func() error {
+		bitcoinKeys, err := receipts.AnchorKeysFromHex(splitKeys(*btcKeys))
+		if err != nil {
+			return err
+		}
+		ethereumKeys, err := receipts.AnchorKeysFromHex(splitKeys(*ethKeys))
+		if err != nil {
+			return err
+		}
+		r, err := ReadReceipt(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return VerifyReceipt(os.Stdout, r, *keyMR, bitcoinKeys, ethereumKeys)
+	}()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func splitKeys(keys string) (rval []string) {
+	for _, k := range strings.Split(keys, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			rval = append(rval, k)
+		}
+	}
+	return rval
+}
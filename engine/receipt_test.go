@@ -0,0 +1,62 @@
+package engine_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/engine"
+	"github.com/FactomProject/factomd/receipts"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestVerifyReceipt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "receipt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	blocks := testHelper.CreateFullTestBlockSet()
+	keys := []interfaces.Verifier{testHelper.NewPrimitivesPrivateKey(0).Pub}
+	tx := blocks[4].FBlock.GetTransactions()[0]
+	receipt, err := receipts.CreateTransactionReceipt(dbo, tx.GetSigHash(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receipts.AddAnchors(dbo, receipt, keys, keys); err != nil {
+		t.Fatal(err)
+	}
+
+	// As the whole response to the receipt api call
+	filename := writeScenario(t, dir, "receipt.json", fmt.Sprintf(`{"jsonrpc": "2.0", "id": 0, "result": {"receipt": %s}}`, receipt.CustomMarshalString()))
+	r, err := ReadReceipt(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsSameAs(receipt) {
+		t.Errorf("read %s", r.CustomMarshalString())
+	}
+
+	var out bytes.Buffer
+	if err := VerifyReceipt(&out, r, blocks[4].DBlock.DatabasePrimaryIndex().String(), keys, keys); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Transaction " + tx.GetHash().String(), "at height 4", "anchored in Bitcoin transaction"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in %s", want, out.String())
+		}
+	}
+
+	if err := VerifyReceipt(&out, r, blocks[3].DBlock.DatabasePrimaryIndex().String(), keys, keys); err == nil {
+		t.Error("expected a receipt of another directory block to be refused")
+	}
+	if err := VerifyReceipt(&out, r, "", nil, nil); err == nil {
+		t.Error("expected anchors not signed by the keys to be refused")
+	}
+}
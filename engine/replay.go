@@ -0,0 +1,434 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/clock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/boltdb"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/state"
+)
+
+// ReplayBreak is a point a replay stops at: the first message the recording node received at
+// or after a minute of a block
+type ReplayBreak struct {
+	DBHeight uint32
+	Minute   int
+}
+
+func (b ReplayBreak) String() string {
+	return fmt.Sprintf("%d:%d", b.DBHeight, b.Minute)
+}
+
+// ParseReplayBreak parses a breakpoint given as block or block:minute
+func ParseReplayBreak(s string) (ReplayBreak, error) {
+	var b ReplayBreak
+	parts := strings.SplitN(s, ":", 2)
+	height, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return b, fmt.Errorf("bad breakpoint %q, expected block or block:minute", s)
+	}
+	b.DBHeight = uint32(height)
+	if len(parts) == 2 {
+		if b.Minute, err = strconv.Atoi(parts[1]); err != nil || b.Minute < 0 || b.Minute > 10 {
+			return b, fmt.Errorf("bad breakpoint %q, the minute must be 0 to 10", s)
+		}
+	}
+	return b, nil
+}
+
+// replayBreaks is the -break flag of factomd replay
+type replayBreaks []ReplayBreak
+
+func (r *replayBreaks) String() string {
+	return fmt.Sprint(*r)
+}
+
+func (r *replayBreaks) Set(s string) error {
+	b, err := ParseReplayBreak(s)
+	if err != nil {
+		return err
+	}
+	*r = append(*r, b)
+	return nil
+}
+
+// ReplayOptions say how a journal is replayed
+type ReplayOptions struct {
+	// Speed is 0 to replay as fast as the node takes the messages, 1 at the pace they were
+	// recorded, 2 twice as fast and so on
+	Speed  float64
+	Breaks []ReplayBreak
+	// Break is called at each breakpoint once the node has taken the messages before it, and
+	// returns false to stop the replay
+	Break func(b ReplayBreak, r *state.JournalRecord) bool
+}
+
+// ReplayJournal feeds the records of journals into the queues of a node they were recorded
+// entering, returning how many were fed.  The node must run on a running virtual clock, which
+// the replay keeps at the time of the records so the messages aren't too old for the node.
+func ReplayJournal(s *state.State, sim *clock.Sim, files []string, opts *ReplayOptions) (int, error) {
+	breaks := append([]ReplayBreak(nil), opts.Breaks...)
+	sort.Slice(breaks, func(i, j int) bool {
+		return breaks[i].DBHeight < breaks[j].DBHeight || breaks[i].DBHeight == breaks[j].DBHeight && breaks[i].Minute < breaks[j].Minute
+	})
+
+	fed := 0
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return fed, err
+		}
+		j, err := state.NewJournalReader(f)
+		if err != nil {
+			f.Close()
+			return fed, fmt.Errorf("%s: %v", file, err)
+		}
+		for {
+			r, err := j.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return fed, fmt.Errorf("%s: record %d: %v", file, fed, err)
+			}
+
+			for len(breaks) > 0 && (r.DBHeight > breaks[0].DBHeight || r.DBHeight == breaks[0].DBHeight && r.Minute >= breaks[0].Minute) {
+				b := breaks[0]
+				breaks = breaks[1:]
+				sim.Stop()
+				waitForReplayQueues(s)
+				if opts.Break != nil && !opts.Break(b, r) {
+					f.Close()
+					return fed, nil
+				}
+				go sim.Run()
+			}
+
+			if opts.Speed > 0 {
+				if d := r.Time.Sub(sim.Now()); d > 0 {
+					sim.Sleep(d)
+				}
+			} else {
+				// As fast as the node goes, but not ahead of where the recording node was
+				waitForReplayNode(s, r)
+				if d := r.Time.Sub(sim.Now()); d > 0 {
+					sim.Advance(d)
+				}
+			}
+
+			switch r.Queue {
+			case state.JournalInMsgQueue:
+				s.InMsgQueue().Enqueue(r.Message)
+			case state.JournalInMsgQueue2:
+				s.InMsgQueue2().Enqueue(r.Message)
+			case state.JournalDataQueue:
+				s.DataMsgQueue() <- r.Message
+			default:
+				continue
+			}
+			fed++
+
+			if s.InMsgQueue().Length() > constants.INMSGQUEUE_MED {
+				for s.InMsgQueue().Length() > constants.INMSGQUEUE_LOW {
+					time.Sleep(time.Millisecond * 10)
+				}
+			}
+		}
+		f.Close()
+	}
+	return fed, nil
+}
+
+// waitForReplayNode waits a while for the node to get to where the recording node was when it
+// received a message, as messages from too far ahead are dropped
+func waitForReplayNode(s *state.State, r *state.JournalRecord) {
+	for i := 0; i < 400; i++ {
+		height, minute := s.GetLLeaderHeight(), s.GetCurrentMinute()
+		if height > r.DBHeight || height == r.DBHeight && minute >= r.Minute {
+			return
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
+
+// waitForReplayQueues waits for the node to take the messages fed to it
+func waitForReplayQueues(s *state.State) {
+	for s.InMsgQueue().Length() > 0 || s.InMsgQueue2().Length() > 0 || len(s.DataMsgQueue()) > 0 {
+		time.Sleep(time.Millisecond * 10)
+	}
+	time.Sleep(time.Millisecond * 100)
+}
+
+// DiffDatabases compares the directory blocks of a replay with the ones of the recording node
+// up to a height, returning a line for each height they differ at
+func DiffDatabases(replayed, recorded interfaces.DBOverlaySimple, to uint32) []string {
+	var diffs []string
+	for h := uint32(0); h <= to; h++ {
+		a, err := replayed.FetchDBlockByHeight(h)
+		if err != nil || a == nil {
+			diffs = append(diffs, fmt.Sprintf("%d: the replay has no directory block", h))
+			continue
+		}
+		b, err := recorded.FetchDBlockByHeight(h)
+		if err != nil || b == nil {
+			diffs = append(diffs, fmt.Sprintf("%d: the recording has no directory block", h))
+			continue
+		}
+		if a.GetKeyMR().IsSameAs(b.GetKeyMR()) {
+			continue
+		}
+
+		// Name the blocks that differ
+		chains := make(map[string]interfaces.IDBEntry)
+		for _, e := range b.GetDBEntries() {
+			chains[e.GetChainID().String()] = e
+		}
+		var differ []string
+		for _, e := range a.GetDBEntries() {
+			id := e.GetChainID().String()
+			if r, ok := chains[id]; !ok || !r.GetKeyMR().IsSameAs(e.GetKeyMR()) {
+				differ = append(differ, replayChainName(e.GetChainID()))
+			}
+			delete(chains, id)
+		}
+		for _, e := range chains {
+			differ = append(differ, replayChainName(e.GetChainID()))
+		}
+		sort.Strings(differ)
+		diffs = append(diffs, fmt.Sprintf("%d: directory block %x, recorded %x, differing in %s",
+			h, a.GetKeyMR().Bytes()[:6], b.GetKeyMR().Bytes()[:6], strings.Join(differ, ", ")))
+	}
+	return diffs
+}
+
+func replayChainName(id interfaces.IHash) string {
+	switch {
+	case bytes.Equal(id.Bytes(), constants.ADMIN_CHAINID):
+		return "the admin block"
+	case bytes.Equal(id.Bytes(), constants.EC_CHAINID):
+		return "the entry credit block"
+	case bytes.Equal(id.Bytes(), constants.FACTOID_CHAINID):
+		return "the factoid block"
+	}
+	return "chain " + id.String()[:12]
+}
+
+// OpenDatabase opens the database of a node read only, a LevelDB directory or a Bolt file
+func OpenDatabase(path string) (interfaces.DBOverlaySimple, func(), error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var db interfaces.IDatabase
+	if info.IsDir() {
+		if db, err = leveldb.NewLevelDB(path, false); err != nil {
+			return nil, nil, fmt.Errorf("%s: %v (is its node still running?)", path, err)
+		}
+	} else {
+		db = boltdb.NewBoltDB(nil, path)
+	}
+	overlay := databaseOverlay.NewOverlay(db)
+	return overlay, func() { overlay.Close() }, nil
+}
+
+// dbHeadHeight is the height of the last directory block in a database, -1 if there's none
+func dbHeadHeight(db interfaces.DBOverlaySimple) int {
+	head, err := db.FetchDBlockHead()
+	if err != nil || head == nil {
+		return -1
+	}
+	return int(head.GetDatabaseHeight())
+}
+
+// RunReplay runs factomd replay, which feeds a journal into a fresh follower and compares the
+// blocks it makes with the database of the node that recorded the journal
+func RunReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := fs.Float64("speed", 0, "0 replays as fast as the node takes the messages, 1 at the pace they were recorded, 2 twice as fast")
+	compare := fs.String("compare", "", "Database of the recording node to compare the replay with, its LevelDB directory or Bolt file")
+	settle := fs.Duration("settle", 10*time.Second, "How long the node gets to process the last messages")
+	print := fs.Bool("print", false, "Print the messages of the journal instead of replaying it")
+	var breaks replayBreaks
+	fs.Var(&breaks, "break", "Stop at the first message recorded at a block, or block:minute.  Can be repeated")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: factomd replay [-speed 0] [-break block:minute] [-compare db] journal [factomd flags...]\n")
+		fmt.Fprintf(os.Stderr, "       factomd replay -print journal\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 || *speed < 0 {
+		fs.Usage()
+		return 2
+	}
+	journal := fs.Arg(0)
+
+	files := state.JournalFiles(journal)
+	if len(files) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: no journal\n", journal)
+		return 1
+	}
+	if *print {
+		if err := PrintJournal(os.Stdout, files); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	start, err := firstJournalTime(files[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var recorded interfaces.DBOverlaySimple
+	if *compare != "" {
+		db, closeDB, err := OpenDatabase(*compare)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer closeDB()
+		recorded = db
+	}
+
+	// The node runs on a virtual clock from the time of the first message, which a replay at full
+	// speed moves on as fast as the node goes
+	sim := clock.NewSim(0, start, *speed)
+	clock.Set(sim)
+	go sim.Run()
+
+	params := ParseCmdLine(fs.Args()[1:])
+	params.Replay = true
+	params.EnableNet = false
+	params.Follower = true
+	params.Leader = false
+	params.Cnt = 1
+	if params.Db == "" {
+		params.Db = "Map"
+	}
+	s := Factomd(params).(*state.State)
+	// Messages that come before the node is done booting are ignored
+	for !s.DBFinished || !s.RunLeader || s.IgnoreMissing {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	opts := &ReplayOptions{Speed: *speed, Breaks: breaks}
+	opts.Break = func(b ReplayBreak, r *state.JournalRecord) bool {
+		fmt.Printf("\nBreakpoint %s, at a message recorded at %d:%d from %s\n", b, r.DBHeight, r.Minute, r.Source)
+		fmt.Printf("The node is at %d:%d\n", s.GetLLeaderHeight(), s.GetCurrentMinute())
+		if recorded != nil {
+			printReplayDiff(s.GetDB(), recorded)
+		}
+		fmt.Print("Press enter to go on, or q and enter to stop the replay: ")
+		line, err := stdin.ReadString('\n')
+		return err == nil && strings.TrimSpace(line) != "q"
+	}
+
+	fmt.Printf("Replaying %s\n", strings.Join(files, ", "))
+	fed, err := ReplayJournal(s, sim, files, opts)
+	fmt.Printf("Replayed %d messages\n", fed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	waitForReplayQueues(s)
+	time.Sleep(*settle)
+	fmt.Printf("The node is at %d:%d\n", s.GetLLeaderHeight(), s.GetCurrentMinute())
+
+	code := 0
+	if err != nil {
+		code = 1
+	}
+	if recorded != nil && printReplayDiff(s.GetDB(), recorded) > 0 {
+		code = 1
+	}
+	s.ShutdownNode(code)
+	return code
+}
+
+// printReplayDiff prints the differences of the blocks both databases have, returning how many
+func printReplayDiff(replayed, recorded interfaces.DBOverlaySimple) int {
+	a, b := dbHeadHeight(replayed), dbHeadHeight(recorded)
+	fmt.Printf("The replay has blocks to %d, the recording to %d\n", a, b)
+	to := a
+	if b < to {
+		to = b
+	}
+	if to < 0 {
+		return 0
+	}
+	diffs := DiffDatabases(replayed, recorded, uint32(to))
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	if len(diffs) == 0 {
+		fmt.Printf("Blocks 0 to %d are the same\n", to)
+	}
+	return len(diffs)
+}
+
+// PrintJournal prints the records of journals, a line each
+func PrintJournal(w io.Writer, files []string) error {
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		j, err := state.NewJournalReader(f)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		for {
+			r, err := j.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			fmt.Fprintf(w, "%s %6d:%-2d %-6s %-10s %s\n", r.Time.Format("15:04:05.000"), r.DBHeight, r.Minute, r.Queue, r.Source, r.Message.String())
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// firstJournalTime is the time of the first record of a journal
+func firstJournalTime(filename string) (time.Time, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+	j, err := state.NewJournalReader(f)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %v", filename, err)
+	}
+	r, err := j.Next()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: no messages", filename)
+	}
+	return r.Time, nil
+}
@@ -0,0 +1,43 @@
+package engine_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/engine"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestParseReplayBreak(t *testing.T) {
+	for s, want := range map[string]ReplayBreak{"1200": {1200, 0}, "1200:5": {1200, 5}, "0:10": {0, 10}} {
+		b, err := ParseReplayBreak(s)
+		if err != nil || b != want {
+			t.Errorf("%s: got %v %v, expected %v", s, b, err, want)
+		}
+	}
+	for _, s := range []string{"", "x", "12:", "12:11", "-1", "12:5:1"} {
+		if _, err := ParseReplayBreak(s); err == nil {
+			t.Errorf("expected %q to be refused", s)
+		}
+	}
+}
+
+func TestDiffDatabases(t *testing.T) {
+	replayed := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	recorded := testHelper.CreateAndPopulateTestDatabaseOverlay()
+	head, err := recorded.FetchDBlockHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := head.GetDatabaseHeight()
+
+	if diffs := DiffDatabases(replayed, recorded, to); len(diffs) != 0 {
+		t.Errorf("expected the same blocks, got %v", diffs)
+	}
+
+	empty := testHelper.CreateEmptyTestDatabaseOverlay()
+	diffs := DiffDatabases(replayed, empty, to)
+	if len(diffs) != int(to)+1 || !strings.Contains(diffs[0], "the recording has no directory block") {
+		t.Errorf("expected every block to be missing from the recording, got %v", diffs)
+	}
+}
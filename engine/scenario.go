@@ -0,0 +1,851 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/clock"
+	"github.com/FactomProject/factomd/common/globals"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util"
+	"gopkg.in/yaml.v2"
+)
+
+// Scenario is a simulation described in a YAML or JSON file: the nodes to run, the authority
+// set to build from them, and then a list of steps that wait, act on the network and check it.
+//
+//	name: leader goes offline
+//	nodes: LLLAF
+//	blktime: 15
+//	steps:
+//	  - wait: {block: 6}
+//	    action: kill
+//	    nodes: [1]
+//	  - wait: {blocks: 2}
+//	    assert:
+//	      authorities: LALLF
+type Scenario struct {
+	Name    string            `json:"name" yaml:"name"`
+	Nodes   string            `json:"nodes" yaml:"nodes"`     // Role of each node, L (leader), A (audit) or F (follower), like SetupSim
+	BlkTime int               `json:"blktime" yaml:"blktime"` // Block time in seconds, 15 if not set
+	Timeout string            `json:"timeout" yaml:"timeout"` // Longest the scenario may run, like "10m".  Worked out from the steps if not set.
+	Options map[string]string `json:"options" yaml:"options"` // Other factomd flags, like "--debuglog": "faulting"
+	Steps   []*ScenarioStep   `json:"steps" yaml:"steps"`
+}
+
+// ScenarioStep waits (if Wait is set), then does the action (if any), then checks the
+// assertions (if any)
+type ScenarioStep struct {
+	Wait *ScenarioWait `json:"wait" yaml:"wait"`
+
+	// One of load, kill, restart, reset, brainswap, drop, delay, link, unlink, partition, heal,
+	// entry, send, cmd, or the chaos actions crash, equivocate, dbsigdelay, corrupt, skew and behave
+	Action  string                  `json:"action" yaml:"action"`
+	Nodes   []int                   `json:"nodes" yaml:"nodes"`     // Nodes the action applies to.  All of them for drop, delay and the chaos actions if not set.
+	Link    *interfaces.SimLink     `json:"link" yaml:"link"`       // link: how messages over the link from Nodes[0] to Nodes[1] are treated
+	Groups  [][]int                 `json:"groups" yaml:"groups"`   // partition: nodes that can still talk to each other
+	Rate    float64                 `json:"rate" yaml:"rate"`       // load: entries per second, 0 stops the load
+	Profile *interfaces.LoadProfile `json:"profile" yaml:"profile"` // load: a profile of the load to send, rather than a rate of entries
+	Drop    int                     `json:"drop" yaml:"drop"`       // drop: messages lost out of every thousand
+	Delay   int64                   `json:"delay" yaml:"delay"`     // delay: most milliseconds a message is held up.  dbsigdelay: milliseconds DBSigs are held up
+	Height  int                     `json:"height" yaml:"height"`   // brainswap: height the two nodes swap identities at
+	Name    string                  `json:"name" yaml:"name"`       // entry: name to refer to the entry by in assertions.  partition, heal: name of the partition.
+	Data    string                  `json:"data" yaml:"data"`       // entry: content of the entry
+	From    string                  `json:"from" yaml:"from"`       // send: private factoid address (Fs...) paying
+	To      string                  `json:"to" yaml:"to"`           // send: public factoid address (FA...) paid
+	Amount  uint64                  `json:"amount" yaml:"amount"`   // send: factoshis
+	Cmd     string                  `json:"cmd" yaml:"cmd"`         // cmd: a command for the simulator console, like "T20"
+	Before  string                  `json:"before" yaml:"before"`   // crash: eom or dbsig, the message the leader crashes before sending
+	VM      int                     `json:"vm" yaml:"vm"`           // crash: the VM whose leader crashes, -1 for any
+	Corrupt int                     `json:"corrupt" yaml:"corrupt"` // corrupt: acks corrupted out of every thousand
+	Skew    int64                   `json:"skew" yaml:"skew"`       // skew: milliseconds the clock is ahead, negative for behind
+
+	Assert *ScenarioAssert `json:"assert" yaml:"assert"`
+}
+
+// ScenarioWait waits for the network, as seen by node 0.  Block and Minute wait for that block
+// and minute, Blocks and Minutes wait that many blocks or minutes from now.
+type ScenarioWait struct {
+	Block   int `json:"block" yaml:"block"`
+	Minute  int `json:"minute" yaml:"minute"`
+	Blocks  int `json:"blocks" yaml:"blocks"`
+	Minutes int `json:"minutes" yaml:"minutes"`
+}
+
+// ScenarioAssert checks the network.  Every check set must pass, on every node on the network
+// (nodes taken off it are skipped).  Within gives them that many blocks to come true.
+type ScenarioAssert struct {
+	Authorities string             `json:"authorities" yaml:"authorities"` // Role of each node, like Nodes
+	Height      int                `json:"height" yaml:"height"`           // All nodes have reached this block
+	Balances    []*ScenarioBalance `json:"balances" yaml:"balances"`
+	Entries     []string           `json:"entries" yaml:"entries"` // Names of entries written by entry steps, or entry hashes
+	Within      int                `json:"within" yaml:"within"`
+}
+
+// ScenarioBalance is the expected balance of a factoid (FA...) or entry credit (EC...) address
+type ScenarioBalance struct {
+	Address string `json:"address" yaml:"address"`
+	Amount  int64  `json:"amount" yaml:"amount"`
+}
+
+// ScenarioReporter gets the progress and the failed assertions of a scenario.  A *testing.T
+// is one.
+type ScenarioReporter interface {
+	Logf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+var scenarioRoles = regexp.MustCompile("^[LAF]+$")
+var scenarioHash = regexp.MustCompile("^[0-9a-fA-F]{64}$")
+
+// ReadScenario reads a scenario file, as JSON if it ends in .json and as YAML otherwise
+func ReadScenario(filename string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	sc := new(Scenario)
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		err = json.Unmarshal(data, sc)
+	} else {
+		err = yaml.UnmarshalStrict(data, sc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if sc.Name == "" {
+		sc.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return sc, nil
+}
+
+// Validate checks the scenario can be run, so a mistake in step 10 doesn't show up ten blocks in
+func (sc *Scenario) Validate() error {
+	if !scenarioRoles.MatchString(sc.Nodes) {
+		return fmt.Errorf("nodes must be a list of L, A and F, not %q", sc.Nodes)
+	}
+	if sc.Nodes[0] == 'A' {
+		return fmt.Errorf("node 0 can't be an audit server")
+	}
+	if sc.BlkTime < 0 {
+		return fmt.Errorf("blktime can't be negative")
+	}
+	if sc.Timeout != "" {
+		if _, err := time.ParseDuration(sc.Timeout); err != nil {
+			return fmt.Errorf("bad timeout: %v", err)
+		}
+	}
+	for option := range sc.Options {
+		if !strings.HasPrefix(option, "--") || flag.Lookup(option[2:]) == nil {
+			return fmt.Errorf("unknown option %q", option)
+		}
+	}
+
+	n := len(sc.Nodes)
+	nodesOK := func(nodes []int) bool {
+		for _, i := range nodes {
+			if i < 0 || i >= n {
+				return false
+			}
+		}
+		return true
+	}
+	names := make(map[string]bool)
+	for i, step := range sc.Steps {
+		fail := func(format string, args ...interface{}) error {
+			return fmt.Errorf("step %d: %s", i+1, fmt.Sprintf(format, args...))
+		}
+		if step == nil {
+			return fail("empty step")
+		}
+		if w := step.Wait; w != nil {
+			if w.Minute < 0 || w.Minute > 9 {
+				return fail("minute must be 0 to 9")
+			}
+			if w.Block < 0 || w.Blocks < 0 || w.Minutes < 0 {
+				return fail("can't wait for the past")
+			}
+		}
+		if step.Action == "link" || step.Action == "unlink" {
+			anyOK := func(i int) bool { return i >= -1 && i < n }
+			if len(step.Nodes) != 2 || !anyOK(step.Nodes[0]) || !anyOK(step.Nodes[1]) {
+				return fail("%s needs the two nodes at the ends of the link, -1 for any node", step.Action)
+			}
+		} else if !nodesOK(step.Nodes) {
+			return fail("nodes must be 0 to %d", n-1)
+		}
+		switch step.Action {
+		case "", "heal", "reset", "unlink":
+		case "link":
+			if step.Link == nil {
+				return fail("link needs the link")
+			}
+			if err := checkSimLink(step.Link); err != nil {
+				return fail("%v", err)
+			}
+		case "load":
+			if step.Rate < 0 {
+				return fail("rate can't be negative")
+			}
+			if step.Profile != nil {
+				if err := checkLoadProfile(step.Profile); err != nil {
+					return fail("%v", err)
+				}
+			}
+		case "kill", "restart":
+			if len(step.Nodes) == 0 {
+				return fail("%s needs the nodes to %s", step.Action, step.Action)
+			}
+		case "brainswap":
+			if len(step.Nodes) != 2 || step.Nodes[0] == step.Nodes[1] {
+				return fail("brainswap needs two nodes")
+			}
+			if step.Height <= 0 {
+				return fail("brainswap needs the height to swap at")
+			}
+		case "drop":
+			if step.Drop < 0 || step.Drop > 999 {
+				return fail("drop must be 0 to 999 out of every thousand")
+			}
+		case "delay", "dbsigdelay":
+			if step.Delay < 0 || step.Delay > 99999 {
+				return fail("delay must be 0 to 99999 milliseconds")
+			}
+		case "partition":
+			if len(step.Groups) == 0 {
+				return fail("partition needs the groups of nodes")
+			}
+			for _, g := range step.Groups {
+				if !nodesOK(g) {
+					return fail("nodes must be 0 to %d", n-1)
+				}
+			}
+		case "entry":
+			if step.Name == "" {
+				return fail("entry needs a name")
+			}
+			if names[step.Name] {
+				return fail("there is already an entry named %q", step.Name)
+			}
+			names[step.Name] = true
+		case "send":
+			if !primitives.ValidateFPrivateUserStr(step.From) {
+				return fail("from must be a private factoid address")
+			}
+			if !primitives.ValidateFUserStr(step.To) {
+				return fail("to must be a factoid address")
+			}
+		case "cmd":
+			if step.Cmd == "" {
+				return fail("cmd needs a command")
+			}
+		case "crash":
+			if _, err := state.NewChaosCrash(step.Before, step.VM); err != nil {
+				return fail("%v", err)
+			}
+		case "corrupt":
+			if step.Corrupt < 0 || step.Corrupt > 1000 {
+				return fail("corrupt must be 0 to 1000 out of every thousand")
+			}
+		case "equivocate", "skew", "behave":
+		default:
+			return fail("unknown action %q", step.Action)
+		}
+
+		if a := step.Assert; a != nil {
+			if a.Authorities != "" && (len(a.Authorities) != n || !scenarioRoles.MatchString(a.Authorities)) {
+				return fail("authorities must have an L, A or F for each of the %d nodes", n)
+			}
+			for _, b := range a.Balances {
+				if !primitives.ValidateFUserStr(b.Address) && !primitives.ValidateECUserStr(b.Address) {
+					return fail("%q is not a factoid or entry credit address", b.Address)
+				}
+			}
+			for _, e := range a.Entries {
+				if !names[e] && !scenarioHash.MatchString(e) {
+					return fail("no entry named %q is written before this step", e)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// timeout is how long the scenario may run, which if not given is a generous guess from the
+// blocks the steps wait for
+func (sc *Scenario) timeout() time.Duration {
+	if sc.Timeout != "" {
+		d, _ := time.ParseDuration(sc.Timeout)
+		return d
+	}
+	blocks := 8 // Building the authority set
+	for _, step := range sc.Steps {
+		if w := step.Wait; w != nil {
+			if w.Block > blocks {
+				blocks = w.Block
+			}
+			blocks += w.Blocks + (w.Minutes+9)/10 + 1
+		}
+		if a := step.Assert; a != nil {
+			if a.Height > blocks {
+				blocks = a.Height
+			}
+			blocks += a.Within
+		}
+	}
+	return time.Duration(blocks*sc.blkTime()*3/2)*time.Second + 2*time.Minute
+}
+
+func (sc *Scenario) blkTime() int {
+	if sc.BlkTime == 0 {
+		return 15
+	}
+	return sc.BlkTime
+}
+
+// params builds the command line for the simulation, with the same defaults as the simTests
+func (sc *Scenario) params(home string) *globals.FactomParams {
+	options := map[string]string{
+		"--db":                  "Map",
+		"--network":             "LOCAL",
+		"--net":                 "alot+",
+		"--enablenet":           "false",
+		"--startdelay":          "1",
+		"--stdoutlog":           filepath.Join(home, "out.txt"),
+		"--stderrlog":           filepath.Join(home, "out.txt"),
+		"--checkheads":          "false",
+		"--controlpanelsetting": "readwrite",
+		"--debuglog":            "faulting|bad",
+		"--logPort":             "37000",
+		"--port":                "37001",
+		"--controlpanelport":    "37002",
+		"--networkport":         "37003",
+		"--faulttimeout":        fmt.Sprint(sc.blkTime() / 5),
+		"--roundtimeout":        fmt.Sprint(sc.blkTime() / 5),
+		"--invariants":          "true",
+	}
+	for option, value := range sc.Options {
+		options[option] = value
+	}
+	options["--blktime"] = fmt.Sprint(sc.blkTime())
+	options["--count"] = fmt.Sprint(len(sc.Nodes))
+	options["--factomhome"] = home
+
+	var args []string
+	for option, value := range options {
+		args = append(args, option+"="+value)
+	}
+	return ParseCmdLine(args)
+}
+
+// scenarioRun is the state of a running scenario
+type scenarioRun struct {
+	sc       *Scenario
+	r        ScenarioReporter
+	s0       *state.State
+	deadline time.Time
+	entries  map[string]interfaces.IHash // Entries written by name
+}
+
+var errScenarioTimeout = fmt.Errorf("the scenario ran out of time")
+
+// RunScenario starts a simulation in the home directory, builds the authority set and runs the
+// steps.  Failed assertions are reported as errors to the reporter; an error is returned if the
+// scenario could not be run to the end.  Only one simulation can be run in a process.
+func RunScenario(sc *Scenario, home string, r ScenarioReporter) error {
+	if err := sc.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".factom", "m2"), 0755); err != nil {
+		return err
+	}
+
+	run := new(scenarioRun)
+	run.sc = sc
+	run.r = r
+	run.entries = make(map[string]interfaces.IHash)
+	run.deadline = time.Now().Add(sc.timeout())
+	r.Logf("Running scenario %q with nodes %s, timeout %s", sc.Name, sc.Nodes, sc.timeout())
+
+	run.s0 = Factomd(sc.params(home)).(*state.State)
+	run.s0.MessageTally = false
+	defer run.shutdown()
+
+	if c := GetInvariantChecker(); c != nil {
+		c.SetOnViolation(func(v *InvariantViolation) {
+			if name, err := v.DumpNodes(); err == nil {
+				r.Logf("The states of the nodes are in %s", name)
+			}
+		})
+	}
+
+	if sim, ok := clock.Get().(*clock.Sim); ok {
+		record := filepath.Join(home, SimClockRecord)
+		if err := sim.WriteRecord(record); err != nil {
+			return err
+		}
+		r.Logf("Running on a virtual clock with seed %d, run again with --simreplay=%s", sim.Seed(), record)
+	}
+
+	if err := run.waitMinutes(1); err != nil { // for the genesis block to be processed
+		return err
+	}
+	if err := run.buildAuthorities(); err != nil {
+		return err
+	}
+
+	for i, step := range sc.Steps {
+		if err := run.step(step); err != nil {
+			return fmt.Errorf("step %d: %v", i+1, err)
+		}
+	}
+	if loadGenerator != nil {
+		if stats := loadGenerator.Stats(); len(stats.Types) > 0 {
+			r.Logf("Load sent:\n%s", loadStatsString(stats))
+		}
+	}
+	r.Logf("Scenario %q done at height %d", sc.Name, run.s0.LLeaderHeight)
+	return nil
+}
+
+// RunScenarioFile reads a scenario and runs it in a new directory under the temp directory,
+// reporting to stderr, and returns the exit code for factomd: 0 if all the assertions passed.
+func RunScenarioFile(filename string) int {
+	sc, err := ReadScenario(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	home, err := ioutil.TempDir("", "factomd-scenario")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	r := new(stderrReporter)
+	if err := RunScenario(sc, home, r); err != nil {
+		r.Errorf("%v", err)
+	}
+	defer time.Sleep(100 * time.Millisecond) // Let the output all complete
+	if r.failed > 0 {
+		fmt.Fprintf(os.Stderr, "Scenario %q FAILED, %d errors.  The logs are in %s\n", sc.Name, r.failed, home)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Scenario %q PASSED\n", sc.Name)
+	return 0
+}
+
+type stderrReporter struct {
+	failed int
+}
+
+func (r *stderrReporter) Logf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "scenario: "+format+"\n", args...)
+}
+
+func (r *stderrReporter) Errorf(format string, args ...interface{}) {
+	r.failed++
+	fmt.Fprintf(os.Stderr, "scenario FAIL: "+format+"\n", args...)
+}
+
+func (run *scenarioRun) cmd(cmd string) {
+	run.r.Logf("Executing: %s", cmd)
+	globals.InputChan <- cmd
+}
+
+// waitFor waits till node 0 reaches the block and minute
+func (run *scenarioRun) waitFor(block int, minute int) error {
+	s := run.s0
+	sleep := time.Duration(run.sc.blkTime()) * time.Second / 40
+	for int(s.LLeaderHeight)*10+s.CurrentMinute < block*10+minute {
+		if time.Now().After(run.deadline) {
+			return errScenarioTimeout
+		}
+		if err := violated(); err != nil {
+			return err
+		}
+		time.Sleep(sleep)
+	}
+	return nil
+}
+
+func (run *scenarioRun) waitMinutes(minutes int) error {
+	now := int(run.s0.LLeaderHeight)*10 + run.s0.CurrentMinute + minutes
+	return run.waitFor(now/10, now%10)
+}
+
+func (run *scenarioRun) waitBlocks(blocks int) error {
+	return run.waitFor(int(run.s0.LLeaderHeight)+blocks, 0)
+}
+
+// buildAuthorities promotes the nodes to their roles, the same way SetupSim does
+func (run *scenarioRun) buildAuthorities() error {
+	nodes := run.sc.Nodes
+	if strings.Trim(nodes[1:], "F") == "" && nodes[0] == 'L' {
+		return nil // Node 0 is the only leader from the start
+	}
+
+	run.cmd(fmt.Sprintf("g%d", len(nodes)+1))
+	if err := run.waitBlocks(3); err != nil { // The identities are scanned a block later
+		return err
+	}
+	if err := run.waitMinutes(1); err != nil {
+		return err
+	}
+	for i, role := range nodes {
+		if i == 0 {
+			continue
+		}
+		switch role {
+		case 'L':
+			run.cmd(fmt.Sprint(i))
+			run.cmd("l")
+		case 'A':
+			run.cmd(fmt.Sprint(i))
+			run.cmd("o")
+		}
+	}
+	if err := run.waitBlocks(2); err != nil {
+		return err
+	}
+
+	if nodes[0] == 'F' {
+		run.cmd("0")
+		run.cmd("z")
+		if err := run.waitBlocks(1); err != nil {
+			return err
+		}
+		run.cmd("0")
+		run.cmd(fmt.Sprintf("t%d", len(nodes)+1)) // take the spare identity
+		if err := run.waitBlocks(1); err != nil {
+			return err
+		}
+	}
+
+	if roles := currentRoles(); roles != nodes {
+		return fmt.Errorf("failed to build the authority set %s, got %s", nodes, roles)
+	}
+	return nil
+}
+
+// currentRoles returns the role of each node as seen by node 0, like Scenario.Nodes
+func currentRoles() string {
+	s0 := fnodes[0].State
+	pl := s0.ProcessLists.Get(s0.LLeaderHeight)
+	roles := make([]byte, len(fnodes))
+	for i, fn := range fnodes {
+		roles[i] = 'F'
+		if fn.State.Leader {
+			roles[i] = 'L'
+		} else if pl != nil {
+			if audit, _ := pl.GetAuditServerIndexHash(fn.State.GetIdentityChainID()); audit {
+				roles[i] = 'A'
+			}
+		}
+	}
+	return string(roles)
+}
+
+func (run *scenarioRun) step(step *ScenarioStep) error {
+	if w := step.Wait; w != nil {
+		var err error
+		switch {
+		case w.Block > 0:
+			err = run.waitFor(w.Block, w.Minute)
+		case w.Minute > 0:
+			next := int(run.s0.LLeaderHeight)
+			if run.s0.CurrentMinute > w.Minute {
+				next++
+			}
+			err = run.waitFor(next, w.Minute)
+		}
+		if err == nil && w.Blocks > 0 {
+			err = run.waitBlocks(w.Blocks)
+		}
+		if err == nil && w.Minutes > 0 {
+			err = run.waitMinutes(w.Minutes)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if step.Action != "" {
+		run.r.Logf("%d-:-%d %s %v", run.s0.LLeaderHeight, run.s0.CurrentMinute, step.Action, step.Nodes)
+		if err := run.action(step); err != nil {
+			return err
+		}
+	}
+
+	if step.Assert != nil {
+		return run.assert(step.Assert)
+	}
+	return nil
+}
+
+// stepNodes returns the nodes an action applies to
+func stepNodes(step *ScenarioStep) []*FactomNode {
+	if len(step.Nodes) == 0 {
+		return fnodes
+	}
+	var nodes []*FactomNode
+	for _, i := range step.Nodes {
+		nodes = append(nodes, fnodes[i])
+	}
+	return nodes
+}
+
+func (run *scenarioRun) action(step *ScenarioStep) error {
+	switch step.Action {
+	case "load":
+		if loadGenerator == nil {
+			return fmt.Errorf("the load generator is not running")
+		}
+		if step.Profile != nil {
+			loadGenerator.PerSecond.Store(0)
+			return loadGenerator.SetProfile(step.Profile)
+		}
+		loadGenerator.ClearProfile()
+		loadGenerator.PerSecond.Store(int(step.Rate * 10)) // in tenths
+		if step.Rate > 0 {
+			go loadGenerator.Run()
+		}
+
+	case "kill", "restart":
+		for _, fn := range stepNodes(step) {
+			fn.State.SetNetStateOff(step.Action == "kill")
+		}
+
+	case "reset":
+		for _, fn := range stepNodes(step) {
+			fn.State.Reset()
+		}
+
+	case "brainswap":
+		return run.brainSwap(fnodes[step.Nodes[0]].State, fnodes[step.Nodes[1]].State, step.Height)
+
+	case "drop":
+		for _, fn := range stepNodes(step) {
+			fn.State.SetDropRate(step.Drop)
+		}
+
+	case "delay":
+		for _, fn := range stepNodes(step) {
+			fn.State.Delay = step.Delay
+			for _, p := range fn.Peers {
+				if sim, ok := p.(*SimPeer); ok {
+					sim.Delay = step.Delay
+				}
+			}
+		}
+
+	case "link":
+		return simTopology.SetLink(step.Nodes[0], step.Nodes[1], step.Link)
+
+	case "unlink":
+		simTopology.ClearLink(step.Nodes[0], step.Nodes[1])
+
+	case "partition":
+		name := step.Name
+		if name == "" {
+			name = "scenario"
+		}
+		return simTopology.Partition(name, step.Groups)
+
+	case "heal":
+		simTopology.Heal(step.Name)
+
+	case "entry":
+		if loadGenerator == nil {
+			return fmt.Errorf("the load generator is not running, and it pays for the entries")
+		}
+		e := RandomEntry()
+		if step.Data != "" {
+			e.Content = primitives.ByteSlice{Bytes: []byte(step.Data)}
+		}
+		s := fnodes[wsapiNode].State
+		s.APIQueue().Enqueue(loadGenerator.NewCommitChain(e))
+		s.APIQueue().Enqueue(loadGenerator.NewRevealEntry(e))
+		run.entries[step.Name] = e.GetHash()
+		run.r.Logf("Entry %q is %s", step.Name, e.GetHash().String())
+
+	case "send":
+		s := fnodes[wsapiNode].State
+		txn, err := NewTransaction(step.Amount, step.From, step.To, s.GetFactoshisPerEC())
+		if err != nil {
+			return err
+		}
+		msg := new(messages.FactoidTransaction)
+		msg.SetTransaction(txn)
+		s.APIQueue().Enqueue(msg)
+
+	case "cmd":
+		run.cmd(step.Cmd)
+
+	case "crash":
+		crash, err := state.NewChaosCrash(step.Before, step.VM)
+		if err != nil {
+			return err
+		}
+		changeChaos(stepNodes(step), func(c *state.Chaos) { c.Crash = crash })
+
+	case "equivocate":
+		changeChaos(stepNodes(step), func(c *state.Chaos) { c.Equivocate = true })
+
+	case "dbsigdelay":
+		changeChaos(stepNodes(step), func(c *state.Chaos) { c.DBSigDelay = step.Delay })
+
+	case "corrupt":
+		changeChaos(stepNodes(step), func(c *state.Chaos) { c.CorruptAcks = step.Corrupt })
+
+	case "skew":
+		changeChaos(stepNodes(step), func(c *state.Chaos) { c.ClockSkew = step.Skew })
+
+	case "behave":
+		changeChaos(stepNodes(step), func(c *state.Chaos) { *c = state.Chaos{} })
+	}
+	return nil
+}
+
+// brainSwap swaps the identities of two nodes at a height, by writing their config files
+// with each other's identity and the ChangeAcksHeight they reload it at
+func (run *scenarioRun) brainSwap(a *state.State, b *state.State, height int) error {
+	if height <= int(run.s0.LLeaderHeight) {
+		return fmt.Errorf("too late to brainswap at height %d", height)
+	}
+
+	// A node without a config file of its own reads the default one, which would have it swap
+	// too.  Give every node its own first.
+	for i, fn := range fnodes {
+		s := fn.State
+		if s.ConfigFilePath != "" {
+			continue
+		}
+		path := filepath.Join(util.GetHomeDir(), ".factom", "m2", "simConfig", fmt.Sprintf("factomd%03d.conf", i))
+		if err := writeIdentityConfig(path, s, 0); err != nil {
+			return err
+		}
+		s.ConfigFilePath = path
+	}
+
+	if err := writeIdentityConfig(a.ConfigFilePath, b, height); err != nil {
+		return err
+	}
+	return writeIdentityConfig(b.ConfigFilePath, a, height)
+}
+
+// writeIdentityConfig writes a config file holding the identity of a node
+func writeIdentityConfig(path string, s *state.State, changeAcksHeight int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	config := fmt.Sprintf("[app]\nIdentityChainID = %s\nLocalServerPrivKey = %s\nLocalServerPublicKey = %s\n",
+		s.IdentityChainID.String(), s.LocalServerPrivKey, s.GetServerPublicKey().String())
+	if changeAcksHeight > 0 {
+		config += fmt.Sprintf("ChangeAcksHeight = %d\n", changeAcksHeight)
+	}
+	return ioutil.WriteFile(path, []byte(config), 0644)
+}
+
+// assert checks the network until the assertions pass or the blocks given run out, and
+// reports the ones that failed
+func (run *scenarioRun) assert(a *ScenarioAssert) error {
+	end := int(run.s0.LLeaderHeight) + a.Within
+	sleep := time.Duration(run.sc.blkTime()) * time.Second / 40
+	for {
+		failures := run.check(a)
+		if len(failures) == 0 {
+			return nil
+		}
+		if int(run.s0.LLeaderHeight) >= end {
+			PrintOneStatus(0, 0)
+			for _, f := range failures {
+				run.r.Errorf("%d-:-%d %s", run.s0.LLeaderHeight, run.s0.CurrentMinute, f)
+			}
+			return nil
+		}
+		if time.Now().After(run.deadline) {
+			return errScenarioTimeout
+		}
+		if err := violated(); err != nil {
+			return err
+		}
+		time.Sleep(sleep)
+	}
+}
+
+// violated returns the violation the invariant checker found, if it found one
+func violated() error {
+	if c := GetInvariantChecker(); c != nil {
+		if v := c.Violation(); v != nil {
+			return fmt.Errorf("%s", v.String())
+		}
+	}
+	return nil
+}
+
+// check returns the assertions that fail right now
+func (run *scenarioRun) check(a *ScenarioAssert) (failures []string) {
+	if a.Authorities != "" {
+		if roles := currentRoles(); roles != a.Authorities {
+			failures = append(failures, fmt.Sprintf("expected the authority set %s, got %s", a.Authorities, roles))
+		}
+	}
+
+	for _, fn := range fnodes {
+		s := fn.State
+		if s.GetNetStateOff() {
+			continue
+		}
+		if a.Height > 0 && int(s.LLeaderHeight) < a.Height {
+			failures = append(failures, fmt.Sprintf("%s is at height %d, expected %d", s.FactomNodeName, s.LLeaderHeight, a.Height))
+		}
+		for _, b := range a.Balances {
+			var balance int64
+			if primitives.ValidateECUserStr(b.Address) {
+				balance = GetBalanceEC(s, b.Address)
+			} else {
+				balance = GetBalance(s, b.Address)
+			}
+			if balance != b.Amount {
+				failures = append(failures, fmt.Sprintf("%s has a balance of %d for %s, expected %d", s.FactomNodeName, balance, b.Address, b.Amount))
+			}
+		}
+		for _, name := range a.Entries {
+			hash := run.entries[name]
+			if hash == nil {
+				hash, _ = primitives.HexToHash(name)
+			}
+			entry, err := s.FetchEntryByHash(hash)
+			if err != nil || entry == nil {
+				failures = append(failures, fmt.Sprintf("%s doesn't have the entry %s", s.FactomNodeName, name))
+			}
+		}
+	}
+	return failures
+}
+
+func (run *scenarioRun) shutdown() {
+	for _, fn := range fnodes {
+		fn.State.ShutdownNode(0)
+	}
+	// Long enough for everyone to see the shutdown
+	time.Sleep(time.Duration(run.sc.blkTime()) * time.Second)
+}
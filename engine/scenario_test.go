@@ -0,0 +1,118 @@
+package engine_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func writeScenario(t *testing.T, dir string, name string, data string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	yamlFile := writeScenario(t, dir, "swap.yaml", `
+nodes: LLAF
+options:
+  --debuglog: faulting
+steps:
+  - wait: {block: 4, minute: 2}
+    action: brainswap
+    nodes: [1, 3]
+    height: 6
+  - action: entry
+    name: hello
+    data: hello world
+  - wait: {blocks: 2}
+    assert:
+      authorities: LFAL
+      entries: [hello]
+      balances:
+        - address: FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q
+          amount: 5
+      within: 1
+`)
+	jsonFile := writeScenario(t, dir, "swap.json", `{
+  "nodes": "LLAF",
+  "options": {"--debuglog": "faulting"},
+  "steps": [
+    {"wait": {"block": 4, "minute": 2}, "action": "brainswap", "nodes": [1, 3], "height": 6},
+    {"action": "entry", "name": "hello", "data": "hello world"},
+    {"wait": {"blocks": 2}, "assert": {"authorities": "LFAL", "entries": ["hello"],
+      "balances": [{"address": "FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q", "amount": 5}], "within": 1}}
+  ]
+}`)
+
+	for _, file := range []string{yamlFile, jsonFile} {
+		sc, err := ReadScenario(file)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if sc.Name != "swap" || sc.Nodes != "LLAF" || len(sc.Steps) != 3 || sc.Options["--debuglog"] != "faulting" {
+			t.Errorf("%s: read %+v", file, sc)
+			continue
+		}
+		if w := sc.Steps[0].Wait; w == nil || w.Block != 4 || w.Minute != 2 {
+			t.Errorf("%s: wrong wait %+v", file, w)
+		}
+		if s := sc.Steps[0]; s.Action != "brainswap" || len(s.Nodes) != 2 || s.Nodes[1] != 3 || s.Height != 6 {
+			t.Errorf("%s: wrong brainswap %+v", file, s)
+		}
+		a := sc.Steps[2].Assert
+		if a == nil || a.Authorities != "LFAL" || len(a.Entries) != 1 || len(a.Balances) != 1 || a.Balances[0].Amount != 5 || a.Within != 1 {
+			t.Errorf("%s: wrong assert %+v", file, a)
+		}
+	}
+}
+
+func TestScenarioValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bad := map[string]string{
+		"nodes must be":          "nodes: LLX\n",
+		"can't be an audit":      "nodes: ALL\n",
+		"unknown option":         "nodes: LL\noptions:\n  --nosuchflag: 1\n",
+		"unknown action":         "nodes: LL\nsteps:\n  - action: explode\n",
+		"nodes must be 0 to 1":   "nodes: LL\nsteps:\n  - action: kill\n    nodes: [2]\n",
+		"needs two nodes":        "nodes: LL\nsteps:\n  - action: brainswap\n    nodes: [1]\n    height: 5\n",
+		"minute must be":         "nodes: LL\nsteps:\n  - wait: {minute: 10}\n",
+		"no entry named":         "nodes: LL\nsteps:\n  - assert:\n      entries: [missing]\n",
+		"authorities must have":  "nodes: LL\nsteps:\n  - assert:\n      authorities: LLF\n",
+		"private factoid":        "nodes: LL\nsteps:\n  - action: send\n    from: FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q\n    to: FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q\n",
+		"field speed not found":  "nodes: LL\nsteps:\n  - action: load\n    speed: 5\n",
+		"drop must be 0 to 999":  "nodes: LL\nsteps:\n  - action: drop\n    drop: 1000\n",
+		"curve must be":          "nodes: LL\nsteps:\n  - action: load\n    profile: {rate: 1, curve: square}\n",
+		"needs the groups":       "nodes: LL\nsteps:\n  - action: partition\n",
+		"ends of the link":       "nodes: LL\nsteps:\n  - action: link\n    nodes: [0, 2]\n    link: {latency: 5}\n",
+		"link needs the link":    "nodes: LL\nsteps:\n  - action: link\n    nodes: [0, -1]\n",
+		"distribution must be":   "nodes: LL\nsteps:\n  - action: link\n    nodes: [-1, 1]\n    link: {distribution: poisson}\n",
+		"already an entry named": "nodes: LL\nsteps:\n  - action: entry\n    name: a\n  - action: entry\n    name: a\n",
+		"crash is before an eom": "nodes: LL\nsteps:\n  - action: crash\n    before: ack\n",
+		"there is no VM -2":      "nodes: LL\nsteps:\n  - action: crash\n    before: dbsig\n    vm: -2\n",
+		"corrupt must be":        "nodes: LL\nsteps:\n  - action: corrupt\n    corrupt: 1001\n",
+	}
+	for expected, data := range bad {
+		_, err := ReadScenario(writeScenario(t, dir, "bad.yaml", data))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected an error with %q, got %v", expected, err)
+		}
+	}
+}
@@ -205,169 +205,174 @@ func authorityToBlockchain(total int, st *state.State) ([]hardCodedAuthority, in
 		}
 
 		nextAuthority++
-		//for _, mes := range ele.ChainCommits {
-		/*m := new(wsapi.MessageRequest)
-		m.Message = mes
-		j := primitives.NewJSON2Request("commit-chain", i, m)
-		_, err := v2Request(j, st.GetPort())
+		submitAuthority(&ele, ec, st.GetPort())
+
+		madeAuths = append(madeAuths, ele)
+		authKeyLibrary = append(authKeyLibrary, ele)
+	}
+	return madeAuths, skipped, nil
+}
+
+// submitAuthority writes the chains and entries of an identity through the API on a port
+func submitAuthority(ele *hardCodedAuthority, ec *factom.ECAddress, port int) {
+	//for _, mes := range ele.ChainCommits {
+	/*m := new(wsapi.MessageRequest)
+	m.Message = mes
+	j := primitives.NewJSON2Request("commit-chain", i, m)
+	_, err := v2Request(j, port)
+	if err != nil {
+		log.Println("Error in making identities: " + err.Error())
+	}
+	time.Sleep(50 * time.Millisecond)*/
+	//}
+	for i, mes := range ele.ChainReveals {
+		entry, err := getFactomPackageEntryFromString(mes)
+		if err != nil {
+			continue
+		}
+		paramsRev := new(wsapi.EntryRequest)
+		paramsCom := new(wsapi.MessageRequest)
+
+		chain := factom.NewChain(entry)
+		com, rev := getMessageStringChain(chain, ec)
+		paramsCom.Message = com
+		paramsRev.Entry = rev
+		jCommit := primitives.NewJSON2Request("commit-chain", i, paramsCom)
+		jRev := primitives.NewJSON2Request("reveal-chain", i, paramsRev)
+
+		_, err = v2Request(jCommit, port)
+		//_, err = wsapi.HandleV2Request(st, jCommit)
+
 		if err != nil {
 			log.Println("Error in making identities: " + err.Error())
 		}
-		time.Sleep(50 * time.Millisecond)*/
-		//}
-		for i, mes := range ele.ChainReveals {
-			entry, err := getFactomPackageEntryFromString(mes)
-			if err != nil {
-				continue
-			}
-			paramsRev := new(wsapi.EntryRequest)
-			paramsCom := new(wsapi.MessageRequest)
+		_, err = v2Request(jRev, port)
+		//_, err = wsapi.HandleV2Request(st, jRev)
+		if err != nil {
+			log.Println("Error in making identities: " + err.Error())
+		}
+		//_ = err
 
-			chain := factom.NewChain(entry)
-			com, rev := getMessageStringChain(chain, ec)
-			paramsCom.Message = com
-			paramsRev.Entry = rev
-			jCommit := primitives.NewJSON2Request("commit-chain", i, paramsCom)
-			jRev := primitives.NewJSON2Request("reveal-chain", i, paramsRev)
+		/*m := new(wsapi.EntryRequest)
+		m.Entry = mes
+		j := primitives.NewJSON2Request("reveal-chain", i, m)
+		_, err := v2Request(j, port)
+		if err != nil {
+			log.Println("Error in making identities: " + err.Error())
+		}
+		time.Sleep(50 * time.Millisecond)*/
+	}
+	time.Sleep(100 * time.Millisecond)
 
-			_, err = v2Request(jCommit, st.GetPort())
-			//_, err = wsapi.HandleV2Request(st, jCommit)
+	//for i, mes := range ele.EntryCommits {
+	/*m := new(wsapi.EntryRequest)
+	m.Entry = mes
+	j := primitives.NewJSON2Request("commit-entry", i, m)
+	_, err := v2Request(j, port)
+	if err != nil {
+		log.Println("Error in making identities: " + err.Error())
+	}*/
+	//}
+	for i, mes := range ele.EntryReveals {
+		entry, err := getFactomPackageEntryFromString(mes)
+		if err != nil {
+			continue
+		}
+		paramsRev := new(wsapi.EntryRequest)
+		paramsCom := new(wsapi.MessageRequest)
 
-			if err != nil {
-				log.Println("Error in making identities: " + err.Error())
-			}
-			_, err = v2Request(jRev, st.GetPort())
-			//_, err = wsapi.HandleV2Request(st, jRev)
-			if err != nil {
-				log.Println("Error in making identities: " + err.Error())
-			}
-			//_ = err
+		com, rev := getMessageStringEntry(entry, ec)
+		paramsCom.Message = com
+		paramsRev.Entry = rev
+		jCommit := primitives.NewJSON2Request("commit-entry", i, paramsCom)
+		jRev := primitives.NewJSON2Request("reveal-entry", i, paramsRev)
 
-			/*m := new(wsapi.EntryRequest)
-			m.Entry = mes
-			j := primitives.NewJSON2Request("reveal-chain", i, m)
-			_, err := v2Request(j, st.GetPort())
-			if err != nil {
-				log.Println("Error in making identities: " + err.Error())
-			}
-			time.Sleep(50 * time.Millisecond)*/
+		_, err = v2Request(jCommit, port)
+		//_, err = wsapi.HandleV2Request(st, jCommit)
+		if err != nil {
+			log.Println("Error in making identities: " + err.Error())
+		}
+		_, err = v2Request(jRev, port)
+		//_, err = wsapi.HandleV2Request(st, jRev)
+		if err != nil {
+			log.Println("Error in making identities: " + err.Error())
 		}
-		time.Sleep(100 * time.Millisecond)
+		//_ = err
 
-		//for i, mes := range ele.EntryCommits {
 		/*m := new(wsapi.EntryRequest)
 		m.Entry = mes
-		j := primitives.NewJSON2Request("commit-entry", i, m)
-		_, err := v2Request(j, st.GetPort())
+		j := primitives.NewJSON2Request("reveal-entry", i, m)
+		_, err := v2Request(j, port)
 		if err != nil {
 			log.Println("Error in making identities: " + err.Error())
 		}*/
-		//}
-		for i, mes := range ele.EntryReveals {
-			entry, err := getFactomPackageEntryFromString(mes)
-			if err != nil {
-				continue
-			}
-			paramsRev := new(wsapi.EntryRequest)
-			paramsCom := new(wsapi.MessageRequest)
+	}
 
-			com, rev := getMessageStringEntry(entry, ec)
-			paramsCom.Message = com
-			paramsRev.Entry = rev
-			jCommit := primitives.NewJSON2Request("commit-entry", i, paramsCom)
-			jRev := primitives.NewJSON2Request("reveal-entry", i, paramsRev)
+	com, rev, key, _ := makeBlockKey(*ele, ec, false)
+	ele.NewBlockKey = key
+	mC := new(wsapi.MessageRequest)
+	mC.Message = com
+	j := primitives.NewJSON2Request("commit-entry", 0, mC)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
 
-			_, err = v2Request(jCommit, st.GetPort())
-			//_, err = wsapi.HandleV2Request(st, jCommit)
-			if err != nil {
-				log.Println("Error in making identities: " + err.Error())
-			}
-			_, err = v2Request(jRev, st.GetPort())
-			//_, err = wsapi.HandleV2Request(st, jRev)
-			if err != nil {
-				log.Println("Error in making identities: " + err.Error())
-			}
-			//_ = err
+	mR := new(wsapi.EntryRequest)
+	mR.Entry = rev
+	j = primitives.NewJSON2Request("reveal-entry", 0, mR)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
 
-			/*m := new(wsapi.EntryRequest)
-			m.Entry = mes
-			j := primitives.NewJSON2Request("reveal-entry", i, m)
-			_, err := v2Request(j, st.GetPort())
-			if err != nil {
-				log.Println("Error in making identities: " + err.Error())
-			}*/
-		}
-
-		com, rev, key, _ := makeBlockKey(ele, ec, false)
-		ele.NewBlockKey = key
-		mC := new(wsapi.MessageRequest)
-		mC.Message = com
-		j := primitives.NewJSON2Request("commit-entry", 0, mC)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		mR := new(wsapi.EntryRequest)
-		mR.Entry = rev
-		j = primitives.NewJSON2Request("reveal-entry", 0, mR)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		com, rev, _ = makeMHash(ele, ec)
-		mC = new(wsapi.MessageRequest)
-		mC.Message = com
-		j = primitives.NewJSON2Request("commit-entry", 0, mC)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		mR = new(wsapi.EntryRequest)
-		mR.Entry = rev
-		j = primitives.NewJSON2Request("reveal-entry", 0, mR)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		com, rev, _ = makeBTCKey(ele, ec)
-		mC = new(wsapi.MessageRequest)
-		mC.Message = com
-		j = primitives.NewJSON2Request("commit-entry", 0, mC)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		mR = new(wsapi.EntryRequest)
-		mR.Entry = rev
-		j = primitives.NewJSON2Request("reveal-entry", 0, mR)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		com, rev, _ = makeServerCoinbaseAddress(ele, ec, primitives.RandomHash())
-		mC = new(wsapi.MessageRequest)
-		mC.Message = com
-		j = primitives.NewJSON2Request("commit-entry", 0, mC)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		mR = new(wsapi.EntryRequest)
-		mR.Entry = rev
-		j = primitives.NewJSON2Request("reveal-entry", 0, mR)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		com, rev, _ = makeServerEfficiency(ele, ec, 1000)
-		mC = new(wsapi.MessageRequest)
-		mC.Message = com
-		j = primitives.NewJSON2Request("commit-entry", 0, mC)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
-
-		mR = new(wsapi.EntryRequest)
-		mR.Entry = rev
-		j = primitives.NewJSON2Request("reveal-entry", 0, mR)
-		_, _ = v2Request(j, st.GetPort())
-		//_, _ = wsapi.HandleV2Request(st, j)
+	com, rev, _ = makeMHash(*ele, ec)
+	mC = new(wsapi.MessageRequest)
+	mC.Message = com
+	j = primitives.NewJSON2Request("commit-entry", 0, mC)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
 
-		madeAuths = append(madeAuths, ele)
-		authKeyLibrary = append(authKeyLibrary, ele)
-	}
-	return madeAuths, skipped, nil
+	mR = new(wsapi.EntryRequest)
+	mR.Entry = rev
+	j = primitives.NewJSON2Request("reveal-entry", 0, mR)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
+
+	com, rev, _ = makeBTCKey(*ele, ec)
+	mC = new(wsapi.MessageRequest)
+	mC.Message = com
+	j = primitives.NewJSON2Request("commit-entry", 0, mC)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
+
+	mR = new(wsapi.EntryRequest)
+	mR.Entry = rev
+	j = primitives.NewJSON2Request("reveal-entry", 0, mR)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
+
+	com, rev, _ = makeServerCoinbaseAddress(*ele, ec, primitives.RandomHash())
+	mC = new(wsapi.MessageRequest)
+	mC.Message = com
+	j = primitives.NewJSON2Request("commit-entry", 0, mC)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
+
+	mR = new(wsapi.EntryRequest)
+	mR.Entry = rev
+	j = primitives.NewJSON2Request("reveal-entry", 0, mR)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
+
+	com, rev, _ = makeServerEfficiency(*ele, ec, 1000)
+	mC = new(wsapi.MessageRequest)
+	mC.Message = com
+	j = primitives.NewJSON2Request("commit-entry", 0, mC)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
+
+	mR = new(wsapi.EntryRequest)
+	mR.Entry = rev
+	j = primitives.NewJSON2Request("reveal-entry", 0, mR)
+	_, _ = v2Request(j, port)
+	//_, _ = wsapi.HandleV2Request(st, j)
 }
 
 func makeBlockKey(ele hardCodedAuthority, ec *factom.ECAddress, random bool) (string, string, string, *factom.Entry) {
@@ -0,0 +1,42 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/common/clock"
+	"github.com/FactomProject/factomd/common/globals"
+)
+
+// SimClockRecord is the file the seed and start of a run on the virtual clock are recorded in, so
+// it can be run again
+const SimClockRecord = "simclock.json"
+
+// startSimClock puts the simulation on a virtual clock, either a new one or one with the seed
+// and start of a recorded run.  Must be called before the nodes are made.
+func startSimClock(p *globals.FactomParams) {
+	var sim *clock.Sim
+	if p.SimReplay != "" {
+		r, err := clock.ReadRecord(p.SimReplay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't replay the virtual clock: %v\n", err)
+			os.Exit(1)
+		}
+		sim = clock.NewSimFromRecord(r)
+	} else {
+		sim = clock.NewSim(p.SimSeed, time.Now(), p.SimSpeed)
+		if err := sim.WriteRecord(SimClockRecord); err != nil {
+			fmt.Fprintf(os.Stderr, "Can't record the virtual clock: %v\n", err)
+		}
+	}
+	clock.Set(sim)
+	go sim.Run()
+
+	r := sim.Record()
+	fmt.Fprintf(os.Stderr, "%20s seed %d, starting %s, speed %gx\n", "virtual clock", r.Seed, r.Start.Format(time.RFC3339), r.Speed)
+}
@@ -1166,6 +1166,10 @@ func SimControl(listenTo int, listenStdin bool) {
 						}
 					}
 				}
+			case 'N' == b[0]:
+				os.Stderr.WriteString(simTopologyCommand(cmd))
+			case 'X' == b[0]:
+				os.Stderr.WriteString(chaosCommand(b, ListenTo))
 			case 'J' == b[0]:
 				elect := fnodes[listenTo].State.Elections.(*elections2.Elections)
 				flist := elect.Federated
@@ -1250,6 +1254,24 @@ func SimControl(listenTo int, listenStdin bool) {
 					continue
 				}
 
+				if b[1] == 'p' {
+					// A load profile from a file, or just R to stop it
+					p, err := ReadLoadProfile(b[2:])
+					if err != nil {
+						os.Stderr.WriteString(err.Error() + "\n")
+						continue
+					}
+					loadGenerator.PerSecond.Store(0)
+					loadGenerator.SetProfile(p)
+					os.Stderr.WriteString(fmt.Sprintf("Sending the load of %s at %g messages per second\n", b[2:], p.Rate))
+					continue
+				}
+
+				if b[1] == 's' {
+					os.Stderr.WriteString(loadStatsString(loadGenerator.Stats()))
+					continue
+				}
+
 				if b[1] == 't' {
 					if len(b) >= 3 {
 						nn, err := strconv.Atoi(b[2:])
@@ -1280,6 +1302,7 @@ func SimControl(listenTo int, listenStdin bool) {
 					}
 					nn = nn * 10
 				}
+				loadGenerator.ClearProfile()
 				loadGenerator.PerSecond.Store(nn)
 				go loadGenerator.Run()
 				os.Stderr.WriteString(fmt.Sprintf("Writing entries at %d.%d per second\n", nn/10, nn%10))
@@ -1453,6 +1476,20 @@ func SimControl(listenTo int, listenStdin bool) {
 				os.Stderr.WriteString("Onnn          Set Drop Rate to nnn on this node\n")
 				os.Stderr.WriteString("Dnnn          Set the Delay on messages from the current node to nnn milliseconds\n")
 				os.Stderr.WriteString("Fnnn          Set the Delay on messages from all nodes to nnn milliseconds\n")
+				os.Stderr.WriteString("N             Show the network topology: the links set and the partitions\n")
+				os.Stderr.WriteString("Nlf.t l j p b [d]  Set the link from node f to node t (* for any node): latency l and jitter j in ms,\n")
+				os.Stderr.WriteString("                 loss p (0 to 1), bandwidth b in bytes/sec (0 no cap), distribution d uniform/normal/exponential\n")
+				os.Stderr.WriteString("Ncf.t         Clear the link from node f to node t\n")
+				os.Stderr.WriteString("Npname 0,1 2,3  Partition the network into groups of nodes that can only talk to each other\n")
+				os.Stderr.WriteString("Nh[name]      Heal the named partition, or all of them\n")
+				os.Stderr.WriteString("X             Show the chaos each node has been told to show\n")
+				os.Stderr.WriteString("Xce[vm]       Crash the leader of VM vm (any if not given) just before it sends its EOM\n")
+				os.Stderr.WriteString("Xcd[vm]       Crash the leader of VM vm (any if not given) just before it sends its DBSig\n")
+				os.Stderr.WriteString("Xe            Toggle the current node sending a second, conflicting ack for every ack\n")
+				os.Stderr.WriteString("Xdnnn         Hold up the DBSigs the current node sends nnn milliseconds\n")
+				os.Stderr.WriteString("Xannn         Corrupt nnn of every thousand acks the current node sends\n")
+				os.Stderr.WriteString("Xsnnn         Skew the clock of the current node nnn milliseconds, negative for behind\n")
+				os.Stderr.WriteString("Xr            Make every node behave again\n")
 				os.Stderr.WriteString("/             Toggle the sort order between ChainID and Factom Node Name\n")
 				os.Stderr.WriteString("Pnnn          Set's the efficiency of the given node to nnn\n")
 				os.Stderr.WriteString("B             Set's the coinbase address to a random one. Tyoe BFA... for a specific\n")
@@ -1460,6 +1497,8 @@ func SimControl(listenTo int, listenStdin bool) {
 				os.Stderr.WriteString("Rnnn          Set load generator to write entries at nnn per second\n")
 				os.Stderr.WriteString("Re            Turn on 'tight' mode, that buys ECs in only small amounts when running Rnnn\n")
 				os.Stderr.WriteString("Rtnnn         Add a signed constant to the timestamp of load generator FCT TXs.\n")
+				os.Stderr.WriteString("Rpfile        Send the load described by a YAML or JSON load profile file\n")
+				os.Stderr.WriteString("Rs            Show the load generator's statistics, by kind of message\n")
 
 				//os.Stderr.WriteString("i[m/b/a][N]   Shows only the Mhash, block signing key, or anchor key up to the Nth identity\n")
 				//os.Stderr.WriteString("isN           Shows only Nth identity\n")
@@ -0,0 +1,371 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/clock"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// SimTopology models the network between the simulated nodes.  Each SimPeer asks it what
+// becomes of a message it sends: lost to a partition or to the link's loss rate, or delayed
+// by the link's latency and by the messages queued ahead of it on a bandwidth capped link.
+// Links are one way, so a network can be made asymmetric.
+type SimTopology struct {
+	mtx        sync.Mutex
+	links      map[[2]int]*interfaces.SimLink
+	partitions map[string][][]int
+	groups     []map[int]int        // For each partition, the group each node is in, counting from 1
+	busy       map[[2]int]time.Time // When each bandwidth capped link has sent what is queued on it
+	random     map[[2]int]*rand.Rand
+}
+
+var _ interfaces.ISimTopology = (*SimTopology)(nil)
+
+var simTopology = NewSimTopology()
+
+// GetSimTopology returns the topology of the simulated network
+func GetSimTopology() *SimTopology {
+	return simTopology
+}
+
+func NewSimTopology() *SimTopology {
+	t := new(SimTopology)
+	t.links = make(map[[2]int]*interfaces.SimLink)
+	t.partitions = make(map[string][][]int)
+	t.busy = make(map[[2]int]time.Time)
+	t.random = make(map[[2]int]*rand.Rand)
+	return t
+}
+
+func checkSimNode(node int, any bool) error {
+	if (any && node == -1) || node >= 0 {
+		return nil
+	}
+	return fmt.Errorf("there is no node %d", node)
+}
+
+func checkSimLink(link *interfaces.SimLink) error {
+	switch link.Distribution {
+	case "", "uniform", "normal", "exponential":
+	default:
+		return fmt.Errorf("the distribution must be uniform, normal or exponential, not %q", link.Distribution)
+	}
+	if link.Latency < 0 || link.Jitter < 0 || link.Bandwidth < 0 {
+		return fmt.Errorf("the latency, jitter and bandwidth can't be negative")
+	}
+	if link.Loss < 0 || link.Loss > 1 {
+		return fmt.Errorf("the loss must be 0 to 1")
+	}
+	return nil
+}
+
+func (t *SimTopology) SetLink(from int, to int, link *interfaces.SimLink) error {
+	if err := checkSimNode(from, true); err != nil {
+		return err
+	}
+	if err := checkSimNode(to, true); err != nil {
+		return err
+	}
+	if err := checkSimLink(link); err != nil {
+		return err
+	}
+	l := *link
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.links[[2]int{from, to}] = &l
+	return nil
+}
+
+func (t *SimTopology) ClearLink(from int, to int) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	key := [2]int{from, to}
+	_, ok := t.links[key]
+	delete(t.links, key)
+	return ok
+}
+
+func (t *SimTopology) Partition(name string, groups [][]int) error {
+	if name == "" {
+		return fmt.Errorf("a partition needs a name")
+	}
+	if len(groups) == 0 {
+		return fmt.Errorf("a partition needs groups of nodes")
+	}
+	seen := make(map[int]bool)
+	for _, g := range groups {
+		for _, node := range g {
+			if err := checkSimNode(node, false); err != nil {
+				return err
+			}
+			if seen[node] {
+				return fmt.Errorf("node %d is in more than one group", node)
+			}
+			seen[node] = true
+		}
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.partitions[name] = groups
+	t.groupNodes()
+	return nil
+}
+
+func (t *SimTopology) Heal(name string) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	_, ok := t.partitions[name]
+	if name == "" {
+		ok = len(t.partitions) > 0
+		t.partitions = make(map[string][][]int)
+	}
+	delete(t.partitions, name)
+	t.groupNodes()
+	return ok
+}
+
+// groupNodes works out which group each node is in for each partition.  Must hold the mutex.
+func (t *SimTopology) groupNodes() {
+	t.groups = nil
+	for _, groups := range t.partitions {
+		group := make(map[int]int)
+		for g, nodes := range groups {
+			for _, node := range nodes {
+				group[node] = g + 1
+			}
+		}
+		t.groups = append(t.groups, group)
+	}
+}
+
+// cut returns true if a partition stops messages from one node reaching another.  Must hold
+// the mutex.
+func (t *SimTopology) cut(from int, to int) bool {
+	for _, group := range t.groups {
+		if group[from] == 0 || group[from] != group[to] {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *SimTopology) Topology() *interfaces.SimTopology {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	top := new(interfaces.SimTopology)
+	top.Nodes = len(fnodes)
+	top.Links = []*interfaces.SimLinkInfo{}
+	for key, link := range t.links {
+		top.Links = append(top.Links, &interfaces.SimLinkInfo{From: key[0], To: key[1], SimLink: *link})
+	}
+	sort.Slice(top.Links, func(i, j int) bool {
+		a, b := top.Links[i], top.Links[j]
+		return a.From < b.From || (a.From == b.From && a.To < b.To)
+	})
+	top.Partitions = make(map[string][][]int)
+	for name, groups := range t.partitions {
+		top.Partitions[name] = groups
+	}
+	return top
+}
+
+// link returns the most specific link set for a pair of nodes, or nil.  Must hold the mutex.
+func (t *SimTopology) link(from int, to int) *interfaces.SimLink {
+	for _, key := range [][2]int{{from, to}, {from, -1}, {-1, to}, {-1, -1}} {
+		if link := t.links[key]; link != nil {
+			return link
+		}
+	}
+	return nil
+}
+
+// Deliver decides what becomes of a message of size bytes sent from one node to another: lost,
+// or how long it takes to arrive
+func (t *SimTopology) Deliver(from int, to int, size int) (delay time.Duration, lost bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	key := [2]int{from, to}
+	if t.cut(from, to) {
+		return 0, true
+	}
+	link := t.link(from, to)
+	if link == nil {
+		return 0, false
+	}
+	random := t.random[key]
+	if random == nil {
+		// Each link has its own random numbers, so a replay on the virtual clock loses and
+		// delays the same messages however the goroutines sending them are scheduled
+		random = clock.NewRand(fmt.Sprintf("link %d-%d", from, to))
+		t.random[key] = random
+	}
+	if link.Loss > 0 && random.Float64() < link.Loss {
+		return 0, true
+	}
+
+	if link.Bandwidth > 0 {
+		// The message is sent once the ones ahead of it have been
+		now := clock.Now()
+		start := t.busy[key]
+		if start.Before(now) {
+			start = now
+		}
+		done := start.Add(time.Duration(size) * time.Second / time.Duration(link.Bandwidth))
+		t.busy[key] = done
+		delay = done.Sub(now)
+	}
+
+	latency := float64(link.Latency)
+	jitter := float64(link.Jitter)
+	switch link.Distribution {
+	case "normal":
+		latency += random.NormFloat64() * jitter
+	case "exponential":
+		latency += random.ExpFloat64() * jitter
+	default:
+		latency += (random.Float64()*2 - 1) * jitter
+	}
+	if latency > 0 {
+		delay += time.Duration(latency * float64(time.Millisecond))
+	}
+	return delay, false
+}
+
+func (t *SimTopology) String() string {
+	top := t.Topology()
+	if len(top.Links) == 0 && len(top.Partitions) == 0 {
+		return "All nodes are connected, with no latency, loss or bandwidth cap\n"
+	}
+	node := func(i int) string {
+		if i == -1 {
+			return "*"
+		}
+		return strconv.Itoa(i)
+	}
+	var b strings.Builder
+	for _, l := range top.Links {
+		dist := l.Distribution
+		if dist == "" {
+			dist = "uniform"
+		}
+		fmt.Fprintf(&b, "Link %2s -> %-2s latency %5dms jitter %5dms %-11s loss %5.3f bandwidth %d B/s\n",
+			node(l.From), node(l.To), l.Latency, l.Jitter, dist, l.Loss, l.Bandwidth)
+	}
+	var names []string
+	for name := range top.Partitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "Partition %s: %v\n", name, top.Partitions[name])
+	}
+	return b.String()
+}
+
+// parseSimNodes parses the nodes at the ends of a link, like "0.3" or "*.2"
+func parseSimNodes(s string) (from int, to int, err error) {
+	ends := strings.Split(s, ".")
+	if len(ends) != 2 {
+		return 0, 0, fmt.Errorf("give the link as from.to, like 0.3, with * for any node")
+	}
+	var n [2]int
+	for i, end := range ends {
+		if end == "*" {
+			n[i] = -1
+		} else if n[i], err = strconv.Atoi(end); err != nil {
+			return 0, 0, fmt.Errorf("%q is not a node", end)
+		}
+	}
+	return n[0], n[1], nil
+}
+
+// simTopologyCommand runs the N commands of the simulator console, and returns what to print
+//
+//	N                                          Print the topology
+//	Nl<from>.<to> latency jitter loss bandwidth [distribution]
+//	Nc<from>.<to>                              Clear a link
+//	Np<name> 0,1,2 3,4                         Partition the network
+//	Nh[<name>]                                 Heal a partition, or all of them
+func simTopologyCommand(cmd []string) string {
+	b := cmd[0]
+	if len(b) == 1 {
+		return simTopology.String()
+	}
+	arg := b[2:]
+	switch b[1] {
+	case 'l':
+		from, to, err := parseSimNodes(arg)
+		if err != nil {
+			return err.Error() + "\n"
+		}
+		if len(cmd) < 5 || len(cmd) > 6 {
+			return "Nl<from>.<to> needs the latency, jitter, loss and bandwidth, and optionally the distribution\n"
+		}
+		link := new(interfaces.SimLink)
+		link.Latency, err = strconv.ParseInt(cmd[1], 10, 64)
+		if err == nil {
+			link.Jitter, err = strconv.ParseInt(cmd[2], 10, 64)
+		}
+		if err == nil {
+			link.Loss, err = strconv.ParseFloat(cmd[3], 64)
+		}
+		if err == nil {
+			link.Bandwidth, err = strconv.Atoi(cmd[4])
+		}
+		if err != nil {
+			return fmt.Sprintf("Bad link: %v\n", err)
+		}
+		if len(cmd) == 6 {
+			link.Distribution = cmd[5]
+		}
+		if err := simTopology.SetLink(from, to, link); err != nil {
+			return err.Error() + "\n"
+		}
+	case 'c':
+		from, to, err := parseSimNodes(arg)
+		if err != nil {
+			return err.Error() + "\n"
+		}
+		if !simTopology.ClearLink(from, to) {
+			return fmt.Sprintf("There is no link %s\n", arg)
+		}
+	case 'p':
+		var groups [][]int
+		for _, field := range cmd[1:] {
+			var group []int
+			for _, s := range strings.Split(field, ",") {
+				node, err := strconv.Atoi(s)
+				if err != nil {
+					return fmt.Sprintf("%q is not a node\n", s)
+				}
+				group = append(group, node)
+			}
+			groups = append(groups, group)
+		}
+		if err := simTopology.Partition(arg, groups); err != nil {
+			return err.Error() + "\n"
+		}
+	case 'h':
+		if !simTopology.Heal(arg) {
+			return fmt.Sprintf("There is no partition %q\n", arg)
+		}
+	default:
+		return "Unknown command.  Try N, Nl, Nc, Np or Nh\n"
+	}
+	return simTopology.String()
+}
@@ -0,0 +1,148 @@
+package engine_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	. "github.com/FactomProject/factomd/engine"
+)
+
+func TestSimTopologyLinks(t *testing.T) {
+	top := NewSimTopology()
+
+	if delay, lost := top.Deliver(0, 1, 100); delay != 0 || lost {
+		t.Errorf("with no links expected no delay or loss, got %v %v", delay, lost)
+	}
+
+	// Links to every node from node 0 are slow, the one from 0 to 1 loses everything
+	if err := top.SetLink(0, -1, &interfaces.SimLink{Latency: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if err := top.SetLink(0, 1, &interfaces.SimLink{Loss: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, lost := top.Deliver(0, 1, 100); !lost {
+		t.Error("expected the 0 to 1 link to lose the message")
+	}
+	if delay, lost := top.Deliver(0, 2, 100); lost || delay != 100*time.Millisecond {
+		t.Errorf("expected the 0 to any link to delay 100ms, got %v %v", delay, lost)
+	}
+	if delay, lost := top.Deliver(1, 0, 100); delay != 0 || lost {
+		t.Errorf("expected the links to be one way, got %v %v", delay, lost)
+	}
+
+	if !top.ClearLink(0, 1) || top.ClearLink(0, 1) {
+		t.Error("expected to clear the 0 to 1 link once")
+	}
+	if delay, lost := top.Deliver(0, 1, 100); lost || delay != 100*time.Millisecond {
+		t.Errorf("expected the 0 to any link to be used once 0 to 1 is cleared, got %v %v", delay, lost)
+	}
+	if links := top.Topology().Links; len(links) != 1 || links[0].From != 0 || links[0].To != -1 {
+		t.Errorf("expected one link from 0 to any node, got %v", links)
+	}
+}
+
+func TestSimTopologyJitter(t *testing.T) {
+	top := NewSimTopology()
+	for _, dist := range []string{"uniform", "normal", "exponential"} {
+		if err := top.SetLink(-1, -1, &interfaces.SimLink{Latency: 100, Jitter: 20, Distribution: dist}); err != nil {
+			t.Fatal(err)
+		}
+		var total time.Duration
+		for i := 0; i < 1000; i++ {
+			delay, _ := top.Deliver(0, 1, 0)
+			if delay < 0 {
+				t.Fatalf("%s: negative delay %v", dist, delay)
+			}
+			total += delay
+		}
+		mean := total / 1000
+		if mean < 80*time.Millisecond || mean > 140*time.Millisecond {
+			t.Errorf("%s: expected a mean delay near the latency, got %v", dist, mean)
+		}
+	}
+
+	bad := []*interfaces.SimLink{
+		{Distribution: "poisson"},
+		{Latency: -1},
+		{Loss: 1.5},
+	}
+	for _, link := range bad {
+		if err := top.SetLink(0, 1, link); err == nil {
+			t.Errorf("expected %+v to be refused", link)
+		}
+	}
+}
+
+func TestSimTopologyBandwidth(t *testing.T) {
+	top := NewSimTopology()
+	if err := top.SetLink(0, 1, &interfaces.SimLink{Bandwidth: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	// Each 100 byte message takes 100ms, and waits for the ones ahead of it
+	for i := 1; i <= 3; i++ {
+		delay, _ := top.Deliver(0, 1, 100)
+		expected := time.Duration(i) * 100 * time.Millisecond
+		if delay < expected-10*time.Millisecond || delay > expected {
+			t.Errorf("message %d: expected a delay of about %v, got %v", i, expected, delay)
+		}
+	}
+	if delay, _ := top.Deliver(1, 0, 100); delay != 0 {
+		t.Errorf("expected the other way not to be capped, got %v", delay)
+	}
+}
+
+func TestSimTopologyString(t *testing.T) {
+	top := NewSimTopology()
+	if !strings.Contains(top.String(), "All nodes are connected") {
+		t.Errorf("unexpected topology %q", top.String())
+	}
+	top.SetLink(-1, 2, &interfaces.SimLink{Latency: 50, Loss: 0.5})
+	if s := top.String(); !strings.Contains(s, "*") || !strings.Contains(s, "0.500") {
+		t.Errorf("unexpected topology %q", s)
+	}
+}
+
+func TestSimTopologyPartitions(t *testing.T) {
+	top := NewSimTopology()
+	if err := top.Partition("split", [][]int{{0, 1}, {2, 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := top.Partition("alone", [][]int{{0, 1, 2, 3}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		from, to int
+		lost     bool
+	}{{0, 1, false}, {2, 3, false}, {1, 2, true}, {3, 0, true}, {0, 4, true}, {4, 0, true}} {
+		if _, lost := top.Deliver(c.from, c.to, 0); lost != c.lost {
+			t.Errorf("%d to %d: expected lost %v", c.from, c.to, c.lost)
+		}
+	}
+
+	// Node 4 is in no group of "alone", so is cut off until it is healed
+	if !top.Heal("split") || top.Heal("split") {
+		t.Error("expected to heal the split once")
+	}
+	if _, lost := top.Deliver(1, 2, 0); lost {
+		t.Error("expected 1 to reach 2 once the split is healed")
+	}
+	if _, lost := top.Deliver(0, 4, 0); !lost {
+		t.Error("expected 4 to still be cut off")
+	}
+	if !top.Heal("") || len(top.Topology().Partitions) != 0 {
+		t.Error("expected to heal all the partitions")
+	}
+	if _, lost := top.Deliver(0, 4, 0); lost {
+		t.Error("expected 4 to be reachable once everything is healed")
+	}
+
+	if err := top.Partition("", [][]int{{0}}); err == nil {
+		t.Error("expected a partition with no name to be refused")
+	}
+	if err := top.Partition("twice", [][]int{{0, 1}, {1, 2}}); err == nil {
+		t.Error("expected a node in two groups to be refused")
+	}
+}
@@ -167,8 +167,7 @@ func ComposeEcTransaction(inSec interfaces.IHash, outEC interfaces.IHash, timeIn
 	trans := new(factoid.Transaction)
 	trans.AddInput(inAdd, amt)
 	trans.AddECOutput(outAdd, amt)
-	trans.AddRCD(rcd)
-	trans.AddAuthorization(rcd)
+	trans.AddAuthorization(rcd) // Adds the RCD, which adding it again would make invalid
 	trans.SetTimestamp(timeInMilliseconds)
 
 	fee, err := trans.CalculateFee(ecPrice)
@@ -20,6 +20,8 @@ type EventService interface {
 	EmitStateChangeEvent(msg interfaces.IMsg, entityState eventmessages.EntityState)
 	EmitDirectoryBlockCommitEvent(dbState interfaces.IDBState)
 	EmitDirectoryBlockAnchorEvent(dirBlockInfo interfaces.IDirBlockInfo)
+	EmitDirectoryBlockAnchorRecordEvent(anchorRef *interfaces.AnchorRef)
+	EmitElectionEvent(record *interfaces.ElectionRecord)
 	EmitReplayDirectoryBlockCommit(msg interfaces.IMsg)
 	EmitProcessListEventNewBlock(newBlockHeight uint32)
 	EmitProcessListEventNewMinute(newMinute int, blockHeight uint32)
@@ -115,6 +117,20 @@ func (eventEmitter *eventEmitter) EmitDirectoryBlockAnchorEvent(dirBlockInfo int
 	}
 }
 
+func (eventEmitter *eventEmitter) EmitDirectoryBlockAnchorRecordEvent(anchorRef *interfaces.AnchorRef) {
+	if eventEmitter.eventSender != nil {
+		event := eventinput.NewAnchorRecordEvent(eventEmitter.GetStreamSource(), anchorRef)
+		eventEmitter.Send(event)
+	}
+}
+
+func (eventEmitter *eventEmitter) EmitElectionEvent(record *interfaces.ElectionRecord) {
+	if eventEmitter.eventSender != nil {
+		event := eventinput.NewElectionEvent(eventEmitter.GetStreamSource(), record)
+		eventEmitter.Send(event)
+	}
+}
+
 func (eventEmitter *eventEmitter) EmitReplayDirectoryBlockCommit(msg interfaces.IMsg) {
 	if eventEmitter.eventSender != nil {
 		event := eventinput.NewReplayDirectoryBlockEvent(eventmessages.EventSource_REPLAY_BOOT, msg)
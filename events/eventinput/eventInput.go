@@ -38,6 +38,16 @@ type AnchorEvent struct {
 	Payload     interfaces.IDirBlockInfo
 }
 
+type AnchorRecordEvent struct {
+	EventSource eventmessages.EventSource
+	Payload     *interfaces.AnchorRef
+}
+
+type ElectionEvent struct {
+	EventSource eventmessages.EventSource
+	Payload     *interfaces.ElectionRecord
+}
+
 type ProcessListEvent struct {
 	EventSource              eventmessages.EventSource
 	ProcessListEventInstance *eventmessages.ProcessListEvent
@@ -92,6 +102,22 @@ func (event AnchorEvent) GetPayload() interfaces.IDirBlockInfo {
 	return event.Payload
 }
 
+func (event AnchorRecordEvent) GetStreamSource() eventmessages.EventSource {
+	return event.EventSource
+}
+
+func (event AnchorRecordEvent) GetPayload() *interfaces.AnchorRef {
+	return event.Payload
+}
+
+func (event ElectionEvent) GetStreamSource() eventmessages.EventSource {
+	return event.EventSource
+}
+
+func (event ElectionEvent) GetPayload() *interfaces.ElectionRecord {
+	return event.Payload
+}
+
 func (event ProcessListEvent) GetStreamSource() eventmessages.EventSource {
 	return event.EventSource
 }
@@ -144,6 +170,20 @@ func NewAnchorEvent(streamSource eventmessages.EventSource, dbDirBlockInfo inter
 	}
 }
 
+func NewAnchorRecordEvent(streamSource eventmessages.EventSource, anchorRef *interfaces.AnchorRef) *AnchorRecordEvent {
+	return &AnchorRecordEvent{
+		EventSource: streamSource,
+		Payload:     anchorRef,
+	}
+}
+
+func NewElectionEvent(streamSource eventmessages.EventSource, record *interfaces.ElectionRecord) *ElectionEvent {
+	return &ElectionEvent{
+		EventSource: streamSource,
+		Payload:     record,
+	}
+}
+
 func ProcessListEventNewBlock(streamSource eventmessages.EventSource, newBlockHeight uint32) *ProcessListEvent {
 	return &ProcessListEvent{
 		EventSource: streamSource,
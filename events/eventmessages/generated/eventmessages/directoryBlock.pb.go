@@ -24,6 +24,32 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// The chain a directory block is anchored in
+type AnchorChain int32
+
+const (
+	AnchorChain_BITCOIN  AnchorChain = 0
+	AnchorChain_ETHEREUM AnchorChain = 1
+)
+
+var AnchorChain_name = map[int32]string{
+	0: "BITCOIN",
+	1: "ETHEREUM",
+}
+
+var AnchorChain_value = map[string]int32{
+	"BITCOIN":  0,
+	"ETHEREUM": 1,
+}
+
+func (x AnchorChain) String() string {
+	return proto.EnumName(AnchorChain_name, int32(x))
+}
+
+func (AnchorChain) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_470a6f1df5755130, []int{0}
+}
+
 // ====  DIRECTORY BLOCK DETAILS =====
 type DirectoryBlock struct {
 	Header               *DirectoryBlockHeader  `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
@@ -389,11 +415,149 @@ func (m *DirectoryBlockAnchor) GetEthereumConfirmed() bool {
 	return false
 }
 
+// An anchor record of a directory block, or of a window of them, read from an anchor chain
+type DirectoryBlockAnchorRecord struct {
+	Chain                AnchorChain `protobuf:"varint,1,opt,name=chain,proto3,enum=eventmessages.AnchorChain" json:"chain,omitempty"`
+	EntryHash            []byte      `protobuf:"bytes,2,opt,name=entryHash,proto3" json:"entryHash,omitempty"`
+	BlockHeight          uint32      `protobuf:"varint,3,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	KeyMerkleRoot        []byte      `protobuf:"bytes,4,opt,name=keyMerkleRoot,proto3" json:"keyMerkleRoot,omitempty"`
+	BlockHeightMin       uint32      `protobuf:"varint,5,opt,name=blockHeightMin,proto3" json:"blockHeightMin,omitempty"`
+	BlockHeightMax       uint32      `protobuf:"varint,6,opt,name=blockHeightMax,proto3" json:"blockHeightMax,omitempty"`
+	WindowMerkleRoot     []byte      `protobuf:"bytes,7,opt,name=windowMerkleRoot,proto3" json:"windowMerkleRoot,omitempty"`
+	TxID                 string      `protobuf:"bytes,8,opt,name=txID,proto3" json:"txID,omitempty"`
+	AnchorBlockHeight    int64       `protobuf:"varint,9,opt,name=anchorBlockHeight,proto3" json:"anchorBlockHeight,omitempty"`
+	AnchorBlockHash      string      `protobuf:"bytes,10,opt,name=anchorBlockHash,proto3" json:"anchorBlockHash,omitempty"`
+	TxIndex              int64       `protobuf:"varint,11,opt,name=txIndex,proto3" json:"txIndex,omitempty"`
+	Address              string      `protobuf:"bytes,12,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *DirectoryBlockAnchorRecord) Reset()         { *m = DirectoryBlockAnchorRecord{} }
+func (m *DirectoryBlockAnchorRecord) String() string { return proto.CompactTextString(m) }
+func (*DirectoryBlockAnchorRecord) ProtoMessage()    {}
+func (*DirectoryBlockAnchorRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_470a6f1df5755130, []int{4}
+}
+func (m *DirectoryBlockAnchorRecord) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DirectoryBlockAnchorRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DirectoryBlockAnchorRecord.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DirectoryBlockAnchorRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DirectoryBlockAnchorRecord.Merge(m, src)
+}
+func (m *DirectoryBlockAnchorRecord) XXX_Size() int {
+	return m.Size()
+}
+func (m *DirectoryBlockAnchorRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_DirectoryBlockAnchorRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DirectoryBlockAnchorRecord proto.InternalMessageInfo
+
+func (m *DirectoryBlockAnchorRecord) GetChain() AnchorChain {
+	if m != nil {
+		return m.Chain
+	}
+	return AnchorChain_BITCOIN
+}
+
+func (m *DirectoryBlockAnchorRecord) GetEntryHash() []byte {
+	if m != nil {
+		return m.EntryHash
+	}
+	return nil
+}
+
+func (m *DirectoryBlockAnchorRecord) GetBlockHeight() uint32 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *DirectoryBlockAnchorRecord) GetKeyMerkleRoot() []byte {
+	if m != nil {
+		return m.KeyMerkleRoot
+	}
+	return nil
+}
+
+func (m *DirectoryBlockAnchorRecord) GetBlockHeightMin() uint32 {
+	if m != nil {
+		return m.BlockHeightMin
+	}
+	return 0
+}
+
+func (m *DirectoryBlockAnchorRecord) GetBlockHeightMax() uint32 {
+	if m != nil {
+		return m.BlockHeightMax
+	}
+	return 0
+}
+
+func (m *DirectoryBlockAnchorRecord) GetWindowMerkleRoot() []byte {
+	if m != nil {
+		return m.WindowMerkleRoot
+	}
+	return nil
+}
+
+func (m *DirectoryBlockAnchorRecord) GetTxID() string {
+	if m != nil {
+		return m.TxID
+	}
+	return ""
+}
+
+func (m *DirectoryBlockAnchorRecord) GetAnchorBlockHeight() int64 {
+	if m != nil {
+		return m.AnchorBlockHeight
+	}
+	return 0
+}
+
+func (m *DirectoryBlockAnchorRecord) GetAnchorBlockHash() string {
+	if m != nil {
+		return m.AnchorBlockHash
+	}
+	return ""
+}
+
+func (m *DirectoryBlockAnchorRecord) GetTxIndex() int64 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
+func (m *DirectoryBlockAnchorRecord) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
 func init() {
+	proto.RegisterEnum("eventmessages.AnchorChain", AnchorChain_name, AnchorChain_value)
 	proto.RegisterType((*DirectoryBlock)(nil), "eventmessages.DirectoryBlock")
 	proto.RegisterType((*DirectoryBlockHeader)(nil), "eventmessages.DirectoryBlockHeader")
 	proto.RegisterType((*DirectoryBlockEntry)(nil), "eventmessages.DirectoryBlockEntry")
 	proto.RegisterType((*DirectoryBlockAnchor)(nil), "eventmessages.DirectoryBlockAnchor")
+	proto.RegisterType((*DirectoryBlockAnchorRecord)(nil), "eventmessages.DirectoryBlockAnchorRecord")
 }
 
 func init() {
@@ -401,41 +565,52 @@ func init() {
 }
 
 var fileDescriptor_470a6f1df5755130 = []byte{
-	// 544 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xcf, 0x8f, 0xd2, 0x40,
-	0x14, 0xb6, 0x80, 0xfc, 0x78, 0x2c, 0x1b, 0x1d, 0x35, 0x99, 0x6c, 0x56, 0x6c, 0xaa, 0x31, 0xc4,
-	0x98, 0x92, 0xa0, 0x07, 0xa3, 0x5e, 0x96, 0x45, 0xb3, 0x1b, 0x63, 0x4c, 0x9a, 0xf5, 0xe2, 0xad,
-	0xb4, 0x0f, 0xda, 0x40, 0x3b, 0x64, 0x3a, 0xa0, 0x5c, 0xfd, 0x2b, 0xfc, 0x93, 0x3c, 0x7a, 0xf0,
-	0xae, 0xc1, 0x7f, 0xc4, 0xf0, 0xba, 0x85, 0x0e, 0xd4, 0x35, 0xd9, 0x13, 0xbc, 0xef, 0xfb, 0xde,
-	0xfb, 0x66, 0xde, 0x37, 0x29, 0x58, 0xb8, 0xc0, 0x58, 0x45, 0x98, 0x24, 0xee, 0x18, 0x93, 0xae,
-	0x1f, 0x4a, 0xf4, 0x94, 0x90, 0xcb, 0xfe, 0x54, 0x78, 0x13, 0x7b, 0x26, 0x85, 0x12, 0xac, 0xa5,
-	0x69, 0x8e, 0x1e, 0x8c, 0x85, 0x18, 0x4f, 0xb1, 0x4b, 0xe4, 0x70, 0x3e, 0xea, 0xaa, 0x30, 0xc2,
-	0x44, 0xb9, 0xd1, 0x2c, 0xd5, 0x5b, 0xbf, 0x0c, 0x38, 0x1c, 0x68, 0x83, 0xd8, 0x2b, 0xa8, 0x06,
-	0xe8, 0xfa, 0x28, 0xb9, 0x61, 0x1a, 0x9d, 0x66, 0xef, 0xa1, 0xad, 0xcd, 0xb4, 0x75, 0xf9, 0x19,
-	0x49, 0x9d, 0xcb, 0x16, 0xf6, 0x1a, 0x6a, 0x18, 0x2b, 0x19, 0x62, 0xc2, 0x4b, 0x66, 0xb9, 0xd3,
-	0xec, 0x59, 0x57, 0x76, 0xbf, 0x89, 0x95, 0x5c, 0x3a, 0x59, 0x0b, 0x63, 0x50, 0x09, 0xdc, 0x24,
-	0xe0, 0x65, 0xd3, 0xe8, 0x1c, 0x38, 0xf4, 0x9f, 0x71, 0xa8, 0x79, 0x81, 0x1b, 0xc6, 0xe7, 0x03,
-	0x5e, 0x21, 0x38, 0x2b, 0xd9, 0x23, 0x68, 0x4d, 0x70, 0xf9, 0x1e, 0xe5, 0x64, 0x8a, 0x8e, 0x10,
-	0x8a, 0xdf, 0x24, 0x5e, 0x07, 0xad, 0x9f, 0x25, 0xb8, 0x5b, 0x74, 0x64, 0xf6, 0x18, 0x0e, 0x87,
-	0xc2, 0xcf, 0xf7, 0x1b, 0xd4, 0xbf, 0x83, 0xb2, 0xe7, 0x70, 0x6f, 0x26, 0x71, 0x11, 0x8a, 0x79,
-	0xf2, 0x4e, 0xb3, 0x2b, 0x91, 0xbc, 0x98, 0x64, 0x4f, 0xe0, 0x56, 0x46, 0xbc, 0x9d, 0x4f, 0xa7,
-	0x67, 0xdb, 0x6b, 0xed, 0xe1, 0xec, 0x05, 0x34, 0x36, 0xb9, 0xd0, 0x25, 0x9b, 0xbd, 0x23, 0x3b,
-	0x4d, 0xce, 0xce, 0x92, 0xb3, 0x2f, 0x32, 0x85, 0xb3, 0x15, 0x33, 0x13, 0x9a, 0xc3, 0xf4, 0x4a,
-	0xe1, 0x38, 0x48, 0x17, 0xd0, 0x72, 0xf2, 0x10, 0x6b, 0x03, 0x50, 0x79, 0x2a, 0xe6, 0xb1, 0xe2,
-	0x55, 0x12, 0xe4, 0x90, 0xf5, 0x7a, 0x17, 0x28, 0x93, 0x50, 0xc4, 0xbc, 0x46, 0x64, 0x56, 0xb2,
-	0x63, 0x68, 0xc4, 0xa8, 0x3e, 0x0b, 0x39, 0x39, 0x1f, 0xf0, 0x3a, 0x71, 0x5b, 0xc0, 0xfa, 0x08,
-	0x77, 0x0a, 0xa2, 0xcc, 0xa7, 0x65, 0xfc, 0x27, 0xad, 0x52, 0x51, 0x5a, 0x5f, 0x2b, 0xbb, 0x69,
-	0x9d, 0xc4, 0x5e, 0x20, 0x24, 0xb3, 0x81, 0xe9, 0x0f, 0x9e, 0x36, 0x9a, 0x7a, 0x14, 0x30, 0xec,
-	0x25, 0x70, 0x1d, 0xdd, 0x73, 0xfe, 0x27, 0xbf, 0xbb, 0xd5, 0xf2, 0xfe, 0x56, 0xaf, 0x9f, 0xd8,
-	0x31, 0x34, 0x86, 0xca, 0xbb, 0xf8, 0x42, 0xc7, 0x4f, 0x1f, 0xec, 0x16, 0x20, 0xe7, 0x75, 0xf1,
-	0x61, 0x34, 0x4a, 0x30, 0x8b, 0x2b, 0x0f, 0xd1, 0xab, 0x55, 0x5e, 0x3f, 0x77, 0xbc, 0x34, 0xb6,
-	0x1d, 0x94, 0x59, 0x70, 0xb0, 0x41, 0xd6, 0x56, 0x75, 0xb2, 0xd2, 0xb0, 0x4b, 0xcd, 0xa9, 0x88,
-	0x47, 0xa1, 0x8c, 0xd0, 0xe7, 0x0d, 0xd3, 0xe8, 0xd4, 0x1d, 0x0d, 0x63, 0x03, 0xb8, 0x8f, 0x2a,
-	0x40, 0x89, 0xf3, 0x28, 0x4d, 0xc2, 0x41, 0x4f, 0x48, 0x9f, 0xd2, 0xa6, 0xc1, 0x40, 0x83, 0xaf,
-	0x16, 0xb1, 0xa7, 0x70, 0x3b, 0x13, 0x6c, 0xed, 0x9a, 0x64, 0xb7, 0x4f, 0xf4, 0x4f, 0xbe, 0xaf,
-	0xda, 0xc6, 0x8f, 0x55, 0xdb, 0xf8, 0xbd, 0x6a, 0x1b, 0xdf, 0xfe, 0xb4, 0x6f, 0x80, 0xe9, 0x89,
-	0xc8, 0x1e, 0xb9, 0x9e, 0xda, 0xfc, 0xf8, 0xfa, 0x77, 0xe5, 0x93, 0xfe, 0xe1, 0x1b, 0x56, 0x29,
-	0x85, 0x67, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x3a, 0x58, 0x8e, 0xac, 0x34, 0x05, 0x00, 0x00,
+	// 706 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xad, 0x93, 0xb4, 0x49, 0xc6, 0x69, 0x29, 0x0b, 0x48, 0x56, 0x54, 0x42, 0x64, 0x10, 0x8a,
+	0x2a, 0xe4, 0xa2, 0xc0, 0x01, 0x01, 0x97, 0xe6, 0x03, 0x35, 0x42, 0xa5, 0xd2, 0x2a, 0xbd, 0x70,
+	0x73, 0xec, 0x4d, 0x6c, 0x25, 0xf1, 0x56, 0xeb, 0x4d, 0x9b, 0x5e, 0xf9, 0x15, 0xfc, 0x24, 0x8e,
+	0x3d, 0x70, 0x07, 0x95, 0x3f, 0x82, 0x3c, 0x8e, 0xe3, 0x8f, 0x98, 0x82, 0x38, 0xc5, 0xf3, 0xde,
+	0x9b, 0x9d, 0xdd, 0x79, 0xb3, 0x1b, 0xd0, 0xd9, 0x25, 0xf3, 0xe4, 0x9c, 0xf9, 0xbe, 0x39, 0x61,
+	0xfe, 0x91, 0xed, 0x0a, 0x66, 0x49, 0x2e, 0xae, 0x3b, 0x33, 0x6e, 0x4d, 0x8d, 0x0b, 0xc1, 0x25,
+	0x27, 0xbb, 0x29, 0x4d, 0xfd, 0xc9, 0x84, 0xf3, 0xc9, 0x8c, 0x1d, 0x21, 0x39, 0x5a, 0x8c, 0x8f,
+	0xa4, 0x3b, 0x67, 0xbe, 0x34, 0xe7, 0x17, 0xa1, 0x5e, 0xff, 0xa1, 0xc0, 0x5e, 0x2f, 0xb5, 0x10,
+	0x79, 0x07, 0x3b, 0x0e, 0x33, 0x6d, 0x26, 0x34, 0xa5, 0xa9, 0xb4, 0xd4, 0xf6, 0x53, 0x23, 0xb5,
+	0xa6, 0x91, 0x96, 0x9f, 0xa0, 0x94, 0xae, 0x52, 0xc8, 0x7b, 0x28, 0x33, 0x4f, 0x0a, 0x97, 0xf9,
+	0x5a, 0xa1, 0x59, 0x6c, 0xa9, 0x6d, 0xfd, 0xce, 0xec, 0xbe, 0x27, 0xc5, 0x35, 0x8d, 0x52, 0x08,
+	0x81, 0x92, 0x63, 0xfa, 0x8e, 0x56, 0x6c, 0x2a, 0xad, 0x1a, 0xc5, 0x6f, 0xa2, 0x41, 0xd9, 0x72,
+	0x4c, 0xd7, 0x1b, 0xf4, 0xb4, 0x12, 0xc2, 0x51, 0x48, 0x9e, 0xc1, 0xee, 0x94, 0x5d, 0x9f, 0x32,
+	0x31, 0x9d, 0x31, 0xca, 0xb9, 0xd4, 0xb6, 0x91, 0x4f, 0x83, 0xfa, 0xf7, 0x02, 0x3c, 0xcc, 0xdb,
+	0x32, 0x79, 0x0e, 0x7b, 0x23, 0x6e, 0x27, 0xf3, 0x15, 0xcc, 0xcf, 0xa0, 0xe4, 0x35, 0x3c, 0xba,
+	0x10, 0xec, 0xd2, 0xe5, 0x0b, 0xff, 0x63, 0xaa, 0x5c, 0x01, 0xe5, 0xf9, 0x24, 0x39, 0x84, 0xfd,
+	0x88, 0xf8, 0xb0, 0x98, 0xcd, 0x4e, 0xe2, 0x63, 0x6d, 0xe0, 0xe4, 0x0d, 0x54, 0xd7, 0xbe, 0xe0,
+	0x21, 0xd5, 0x76, 0xdd, 0x08, 0x9d, 0x33, 0x22, 0xe7, 0x8c, 0x61, 0xa4, 0xa0, 0xb1, 0x98, 0x34,
+	0x41, 0x1d, 0x85, 0x47, 0x72, 0x27, 0x4e, 0xd8, 0x80, 0x5d, 0x9a, 0x84, 0x48, 0x03, 0x00, 0xc3,
+	0x2e, 0x5f, 0x78, 0x52, 0xdb, 0x41, 0x41, 0x02, 0x09, 0xda, 0x7b, 0xc9, 0x84, 0xef, 0x72, 0x4f,
+	0x2b, 0x23, 0x19, 0x85, 0xe4, 0x00, 0xaa, 0x1e, 0x93, 0x57, 0x5c, 0x4c, 0x07, 0x3d, 0xad, 0x82,
+	0x5c, 0x0c, 0xe8, 0xe7, 0xf0, 0x20, 0xc7, 0xca, 0xa4, 0x5b, 0xca, 0x5f, 0xdc, 0x2a, 0xe4, 0xb9,
+	0xf5, 0xa5, 0x94, 0x75, 0xeb, 0xd8, 0xb3, 0x1c, 0x2e, 0x88, 0x01, 0x24, 0x3d, 0xf0, 0xd8, 0xd1,
+	0xb0, 0x46, 0x0e, 0x43, 0xde, 0x82, 0x96, 0x46, 0x37, 0x2a, 0xff, 0x91, 0xcf, 0x76, 0xb5, 0xb8,
+	0xd9, 0xd5, 0xff, 0x77, 0xec, 0x00, 0xaa, 0x23, 0x69, 0x0d, 0x97, 0xb8, 0xfd, 0x70, 0x60, 0x63,
+	0x00, 0x2b, 0x07, 0xc1, 0xd9, 0x78, 0xec, 0xb3, 0xc8, 0xae, 0x24, 0x84, 0x53, 0x2b, 0xad, 0x4e,
+	0x62, 0x7b, 0xa1, 0x6d, 0x19, 0x94, 0xe8, 0x50, 0x5b, 0x23, 0x41, 0xa9, 0x0a, 0x96, 0x4a, 0x61,
+	0x2b, 0x4d, 0x97, 0x7b, 0x63, 0x57, 0xcc, 0x99, 0xad, 0x55, 0x9b, 0x4a, 0xab, 0x42, 0x53, 0x18,
+	0xe9, 0xc1, 0x63, 0x26, 0x1d, 0x26, 0xd8, 0x62, 0x1e, 0x3a, 0x41, 0x99, 0xc5, 0x85, 0x8d, 0x6e,
+	0xe3, 0xc2, 0x80, 0x0b, 0xdf, 0x2d, 0x22, 0x2f, 0xe0, 0x7e, 0x24, 0x88, 0xcb, 0xa9, 0x58, 0x6e,
+	0x93, 0xd0, 0x6f, 0x8a, 0x50, 0xcf, 0x1b, 0x82, 0x70, 0x55, 0xf2, 0x12, 0xb6, 0x71, 0xa8, 0xd0,
+	0xfd, 0xbd, 0x76, 0x3d, 0xf3, 0xc2, 0x84, 0xda, 0x6e, 0xa0, 0xa0, 0xa1, 0x30, 0x68, 0x3a, 0x5b,
+	0x6f, 0x38, 0x74, 0x3f, 0x06, 0xfe, 0xc1, 0xee, 0x8d, 0xd9, 0x2d, 0xe5, 0xcc, 0x2e, 0x5a, 0x13,
+	0x27, 0x9d, 0xba, 0xde, 0xea, 0x3e, 0x66, 0xd0, 0xac, 0xce, 0x5c, 0xae, 0x7c, 0xce, 0xa0, 0xc1,
+	0x13, 0x72, 0xe5, 0x7a, 0x36, 0xbf, 0x4a, 0x14, 0x2e, 0x87, 0x4f, 0x48, 0x16, 0x0f, 0x5e, 0x4e,
+	0xb9, 0x5c, 0xdd, 0xd3, 0x2a, 0xc5, 0xef, 0xa0, 0xe9, 0x26, 0xf6, 0x22, 0x39, 0x2d, 0x81, 0xc7,
+	0x45, 0xba, 0x49, 0x90, 0x16, 0xdc, 0x4b, 0x82, 0x91, 0xb5, 0x55, 0x9a, 0x85, 0x83, 0x3b, 0x2e,
+	0x97, 0x03, 0xcf, 0x66, 0x4b, 0xb4, 0xb0, 0x48, 0xa3, 0x30, 0x60, 0x4c, 0xdb, 0x16, 0xcc, 0xf7,
+	0xb5, 0x1a, 0xe6, 0x46, 0xe1, 0x61, 0x0b, 0xd4, 0x84, 0x2f, 0x44, 0x85, 0x72, 0x67, 0x30, 0xec,
+	0x9e, 0x0d, 0x3e, 0xed, 0x6f, 0x91, 0x1a, 0x54, 0xfa, 0xc3, 0x93, 0x3e, 0xed, 0x9f, 0x9f, 0xee,
+	0x2b, 0x9d, 0xe3, 0x6f, 0xb7, 0x0d, 0xe5, 0xe6, 0xb6, 0xa1, 0xfc, 0xbc, 0x6d, 0x28, 0x5f, 0x7f,
+	0x35, 0xb6, 0xa0, 0x69, 0xf1, 0xb9, 0x31, 0x36, 0x2d, 0xb9, 0xfe, 0xb1, 0xd3, 0x96, 0x7f, 0x4e,
+	0xff, 0xeb, 0x8d, 0x76, 0xf0, 0x0a, 0xbe, 0xfa, 0x3d, 0x00, 0x17, 0x07, 0x5e, 0xd0, 0x31, 0x07,
+	0x00, 0x00,
 }
 
 func (m *DirectoryBlock) Marshal() (dAtA []byte, err error) {
@@ -742,6 +917,105 @@ func (m *DirectoryBlockAnchor) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *DirectoryBlockAnchorRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DirectoryBlockAnchorRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DirectoryBlockAnchorRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.TxIndex != 0 {
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(m.TxIndex))
+		i--
+		dAtA[i] = 0x58
+	}
+	if len(m.AnchorBlockHash) > 0 {
+		i -= len(m.AnchorBlockHash)
+		copy(dAtA[i:], m.AnchorBlockHash)
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(len(m.AnchorBlockHash)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.AnchorBlockHeight != 0 {
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(m.AnchorBlockHeight))
+		i--
+		dAtA[i] = 0x48
+	}
+	if len(m.TxID) > 0 {
+		i -= len(m.TxID)
+		copy(dAtA[i:], m.TxID)
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(len(m.TxID)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.WindowMerkleRoot) > 0 {
+		i -= len(m.WindowMerkleRoot)
+		copy(dAtA[i:], m.WindowMerkleRoot)
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(len(m.WindowMerkleRoot)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.BlockHeightMax != 0 {
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(m.BlockHeightMax))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.BlockHeightMin != 0 {
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(m.BlockHeightMin))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.KeyMerkleRoot) > 0 {
+		i -= len(m.KeyMerkleRoot)
+		copy(dAtA[i:], m.KeyMerkleRoot)
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(len(m.KeyMerkleRoot)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.BlockHeight != 0 {
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(m.BlockHeight))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.EntryHash) > 0 {
+		i -= len(m.EntryHash)
+		copy(dAtA[i:], m.EntryHash)
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(len(m.EntryHash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Chain != 0 {
+		i = encodeVarintDirectoryBlock(dAtA, i, uint64(m.Chain))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintDirectoryBlock(dAtA []byte, offset int, v uint64) int {
 	offset -= sovDirectoryBlock(v)
 	base := offset
@@ -898,6 +1172,60 @@ func (m *DirectoryBlockAnchor) Size() (n int) {
 	return n
 }
 
+func (m *DirectoryBlockAnchorRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Chain != 0 {
+		n += 1 + sovDirectoryBlock(uint64(m.Chain))
+	}
+	l = len(m.EntryHash)
+	if l > 0 {
+		n += 1 + l + sovDirectoryBlock(uint64(l))
+	}
+	if m.BlockHeight != 0 {
+		n += 1 + sovDirectoryBlock(uint64(m.BlockHeight))
+	}
+	l = len(m.KeyMerkleRoot)
+	if l > 0 {
+		n += 1 + l + sovDirectoryBlock(uint64(l))
+	}
+	if m.BlockHeightMin != 0 {
+		n += 1 + sovDirectoryBlock(uint64(m.BlockHeightMin))
+	}
+	if m.BlockHeightMax != 0 {
+		n += 1 + sovDirectoryBlock(uint64(m.BlockHeightMax))
+	}
+	l = len(m.WindowMerkleRoot)
+	if l > 0 {
+		n += 1 + l + sovDirectoryBlock(uint64(l))
+	}
+	l = len(m.TxID)
+	if l > 0 {
+		n += 1 + l + sovDirectoryBlock(uint64(l))
+	}
+	if m.AnchorBlockHeight != 0 {
+		n += 1 + sovDirectoryBlock(uint64(m.AnchorBlockHeight))
+	}
+	l = len(m.AnchorBlockHash)
+	if l > 0 {
+		n += 1 + l + sovDirectoryBlock(uint64(l))
+	}
+	if m.TxIndex != 0 {
+		n += 1 + sovDirectoryBlock(uint64(m.TxIndex))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovDirectoryBlock(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovDirectoryBlock(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1877,6 +2205,372 @@ func (m *DirectoryBlockAnchor) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *DirectoryBlockAnchorRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDirectoryBlock
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DirectoryBlockAnchorRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DirectoryBlockAnchorRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chain", wireType)
+			}
+			m.Chain = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Chain |= AnchorChain(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EntryHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EntryHash = append(m.EntryHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.EntryHash == nil {
+				m.EntryHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeight", wireType)
+			}
+			m.BlockHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeight |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyMerkleRoot", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KeyMerkleRoot = append(m.KeyMerkleRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.KeyMerkleRoot == nil {
+				m.KeyMerkleRoot = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeightMin", wireType)
+			}
+			m.BlockHeightMin = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeightMin |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeightMax", wireType)
+			}
+			m.BlockHeightMax = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeightMax |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowMerkleRoot", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.WindowMerkleRoot = append(m.WindowMerkleRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.WindowMerkleRoot == nil {
+				m.WindowMerkleRoot = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AnchorBlockHeight", wireType)
+			}
+			m.AnchorBlockHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AnchorBlockHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AnchorBlockHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AnchorBlockHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxIndex", wireType)
+			}
+			m.TxIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TxIndex |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDirectoryBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDirectoryBlock(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDirectoryBlock
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipDirectoryBlock(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
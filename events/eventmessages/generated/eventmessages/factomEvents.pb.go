@@ -140,6 +140,34 @@ func (NodeMessageCode) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_d6566f2e3579336b, []int{3}
 }
 
+type ElectionOutcome int32
+
+const (
+	ElectionOutcome_RUNNING   ElectionOutcome = 0
+	ElectionOutcome_ELECTED   ElectionOutcome = 1
+	ElectionOutcome_ABANDONED ElectionOutcome = 2
+)
+
+var ElectionOutcome_name = map[int32]string{
+	0: "RUNNING",
+	1: "ELECTED",
+	2: "ABANDONED",
+}
+
+var ElectionOutcome_value = map[string]int32{
+	"RUNNING":   0,
+	"ELECTED":   1,
+	"ABANDONED": 2,
+}
+
+func (x ElectionOutcome) String() string {
+	return proto.EnumName(ElectionOutcome_name, int32(x))
+}
+
+func (ElectionOutcome) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_d6566f2e3579336b, []int{4}
+}
+
 // ====  ROOT EVENT =====
 type FactomEvent struct {
 	EventSource     EventSource `protobuf:"varint,1,opt,name=eventSource,proto3,enum=eventmessages.EventSource" json:"eventSource,omitempty"`
@@ -154,6 +182,8 @@ type FactomEvent struct {
 	//	*FactomEvent_ProcessListEvent
 	//	*FactomEvent_NodeMessage
 	//	*FactomEvent_DirectoryBlockAnchor
+	//	*FactomEvent_DirectoryBlockAnchorRecord
+	//	*FactomEvent_ElectionRecord
 	Event                isFactomEvent_Event `protobuf_oneof:"event"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
 	XXX_unrecognized     []byte              `json:"-"`
@@ -223,15 +253,23 @@ type FactomEvent_NodeMessage struct {
 type FactomEvent_DirectoryBlockAnchor struct {
 	DirectoryBlockAnchor *DirectoryBlockAnchor `protobuf:"bytes,11,opt,name=directoryBlockAnchor,proto3,oneof" json:"directoryBlockAnchor,omitempty"`
 }
+type FactomEvent_DirectoryBlockAnchorRecord struct {
+	DirectoryBlockAnchorRecord *DirectoryBlockAnchorRecord `protobuf:"bytes,12,opt,name=directoryBlockAnchorRecord,proto3,oneof" json:"directoryBlockAnchorRecord,omitempty"`
+}
+type FactomEvent_ElectionRecord struct {
+	ElectionRecord *ElectionRecord `protobuf:"bytes,13,opt,name=electionRecord,proto3,oneof" json:"electionRecord,omitempty"`
+}
 
-func (*FactomEvent_ChainCommit) isFactomEvent_Event()          {}
-func (*FactomEvent_EntryCommit) isFactomEvent_Event()          {}
-func (*FactomEvent_EntryReveal) isFactomEvent_Event()          {}
-func (*FactomEvent_StateChange) isFactomEvent_Event()          {}
-func (*FactomEvent_DirectoryBlockCommit) isFactomEvent_Event() {}
-func (*FactomEvent_ProcessListEvent) isFactomEvent_Event()     {}
-func (*FactomEvent_NodeMessage) isFactomEvent_Event()          {}
-func (*FactomEvent_DirectoryBlockAnchor) isFactomEvent_Event() {}
+func (*FactomEvent_ChainCommit) isFactomEvent_Event()                {}
+func (*FactomEvent_EntryCommit) isFactomEvent_Event()                {}
+func (*FactomEvent_EntryReveal) isFactomEvent_Event()                {}
+func (*FactomEvent_StateChange) isFactomEvent_Event()                {}
+func (*FactomEvent_DirectoryBlockCommit) isFactomEvent_Event()       {}
+func (*FactomEvent_ProcessListEvent) isFactomEvent_Event()           {}
+func (*FactomEvent_NodeMessage) isFactomEvent_Event()                {}
+func (*FactomEvent_DirectoryBlockAnchor) isFactomEvent_Event()       {}
+func (*FactomEvent_DirectoryBlockAnchorRecord) isFactomEvent_Event() {}
+func (*FactomEvent_ElectionRecord) isFactomEvent_Event()             {}
 
 func (m *FactomEvent) GetEvent() isFactomEvent_Event {
 	if m != nil {
@@ -317,6 +355,20 @@ func (m *FactomEvent) GetDirectoryBlockAnchor() *DirectoryBlockAnchor {
 	return nil
 }
 
+func (m *FactomEvent) GetDirectoryBlockAnchorRecord() *DirectoryBlockAnchorRecord {
+	if x, ok := m.GetEvent().(*FactomEvent_DirectoryBlockAnchorRecord); ok {
+		return x.DirectoryBlockAnchorRecord
+	}
+	return nil
+}
+
+func (m *FactomEvent) GetElectionRecord() *ElectionRecord {
+	if x, ok := m.GetEvent().(*FactomEvent_ElectionRecord); ok {
+		return x.ElectionRecord
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*FactomEvent) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -328,6 +380,8 @@ func (*FactomEvent) XXX_OneofWrappers() []interface{} {
 		(*FactomEvent_ProcessListEvent)(nil),
 		(*FactomEvent_NodeMessage)(nil),
 		(*FactomEvent_DirectoryBlockAnchor)(nil),
+		(*FactomEvent_DirectoryBlockAnchorRecord)(nil),
+		(*FactomEvent_ElectionRecord)(nil),
 	}
 }
 
@@ -1432,11 +1486,314 @@ func (m *NewMinuteEvent) GetBlockHeight() uint32 {
 	return 0
 }
 
+// ====  ELECTION EVENTS =====
+type ElectionRecord struct {
+	ElectionID             uint64               `protobuf:"varint,1,opt,name=electionID,proto3" json:"electionID,omitempty"`
+	Outcome                ElectionOutcome      `protobuf:"varint,2,opt,name=outcome,proto3,enum=eventmessages.ElectionOutcome" json:"outcome,omitempty"`
+	BlockHeight            uint32               `protobuf:"varint,3,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	Minute                 int32                `protobuf:"varint,4,opt,name=minute,proto3" json:"minute,omitempty"`
+	VmIndex                uint32               `protobuf:"varint,5,opt,name=vmIndex,proto3" json:"vmIndex,omitempty"`
+	FaultedIndex           uint32               `protobuf:"varint,6,opt,name=faultedIndex,proto3" json:"faultedIndex,omitempty"`
+	FaultedIdentityChainID []byte               `protobuf:"bytes,7,opt,name=faultedIdentityChainID,proto3" json:"faultedIdentityChainID,omitempty"`
+	Started                *types.Timestamp     `protobuf:"bytes,8,opt,name=started,proto3" json:"started,omitempty"`
+	Ended                  *types.Timestamp     `protobuf:"bytes,9,opt,name=ended,proto3" json:"ended,omitempty"`
+	DurationMs             int64                `protobuf:"varint,10,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	Rounds                 uint32               `protobuf:"varint,11,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	ElectedIdentityChainID []byte               `protobuf:"bytes,12,opt,name=electedIdentityChainID,proto3" json:"electedIdentityChainID,omitempty"`
+	FederatedServers       [][]byte             `protobuf:"bytes,13,rep,name=federatedServers,proto3" json:"federatedServers,omitempty"`
+	AuditServers           [][]byte             `protobuf:"bytes,14,rep,name=auditServers,proto3" json:"auditServers,omitempty"`
+	Volunteers             []*ElectionVolunteer `protobuf:"bytes,15,rep,name=volunteers,proto3" json:"volunteers,omitempty"`
+	Votes                  []*ElectionVotes     `protobuf:"bytes,16,rep,name=votes,proto3" json:"votes,omitempty"`
+	LevelMessages          uint32               `protobuf:"varint,17,opt,name=levelMessages,proto3" json:"levelMessages,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{}             `json:"-"`
+	XXX_unrecognized       []byte               `json:"-"`
+	XXX_sizecache          int32                `json:"-"`
+}
+
+func (m *ElectionRecord) Reset()         { *m = ElectionRecord{} }
+func (m *ElectionRecord) String() string { return proto.CompactTextString(m) }
+func (*ElectionRecord) ProtoMessage()    {}
+func (*ElectionRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d6566f2e3579336b, []int{16}
+}
+func (m *ElectionRecord) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ElectionRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ElectionRecord.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ElectionRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ElectionRecord.Merge(m, src)
+}
+func (m *ElectionRecord) XXX_Size() int {
+	return m.Size()
+}
+func (m *ElectionRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_ElectionRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ElectionRecord proto.InternalMessageInfo
+
+func (m *ElectionRecord) GetElectionID() uint64 {
+	if m != nil {
+		return m.ElectionID
+	}
+	return 0
+}
+
+func (m *ElectionRecord) GetOutcome() ElectionOutcome {
+	if m != nil {
+		return m.Outcome
+	}
+	return ElectionOutcome_RUNNING
+}
+
+func (m *ElectionRecord) GetBlockHeight() uint32 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *ElectionRecord) GetMinute() int32 {
+	if m != nil {
+		return m.Minute
+	}
+	return 0
+}
+
+func (m *ElectionRecord) GetVmIndex() uint32 {
+	if m != nil {
+		return m.VmIndex
+	}
+	return 0
+}
+
+func (m *ElectionRecord) GetFaultedIndex() uint32 {
+	if m != nil {
+		return m.FaultedIndex
+	}
+	return 0
+}
+
+func (m *ElectionRecord) GetFaultedIdentityChainID() []byte {
+	if m != nil {
+		return m.FaultedIdentityChainID
+	}
+	return nil
+}
+
+func (m *ElectionRecord) GetStarted() *types.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *ElectionRecord) GetEnded() *types.Timestamp {
+	if m != nil {
+		return m.Ended
+	}
+	return nil
+}
+
+func (m *ElectionRecord) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
+}
+
+func (m *ElectionRecord) GetRounds() uint32 {
+	if m != nil {
+		return m.Rounds
+	}
+	return 0
+}
+
+func (m *ElectionRecord) GetElectedIdentityChainID() []byte {
+	if m != nil {
+		return m.ElectedIdentityChainID
+	}
+	return nil
+}
+
+func (m *ElectionRecord) GetFederatedServers() [][]byte {
+	if m != nil {
+		return m.FederatedServers
+	}
+	return nil
+}
+
+func (m *ElectionRecord) GetAuditServers() [][]byte {
+	if m != nil {
+		return m.AuditServers
+	}
+	return nil
+}
+
+func (m *ElectionRecord) GetVolunteers() []*ElectionVolunteer {
+	if m != nil {
+		return m.Volunteers
+	}
+	return nil
+}
+
+func (m *ElectionRecord) GetVotes() []*ElectionVotes {
+	if m != nil {
+		return m.Votes
+	}
+	return nil
+}
+
+func (m *ElectionRecord) GetLevelMessages() uint32 {
+	if m != nil {
+		return m.LevelMessages
+	}
+	return 0
+}
+
+type ElectionVolunteer struct {
+	IdentityChainID      []byte           `protobuf:"bytes,1,opt,name=identityChainID,proto3" json:"identityChainID,omitempty"`
+	Round                uint32           `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Timestamp            *types.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ElectionVolunteer) Reset()         { *m = ElectionVolunteer{} }
+func (m *ElectionVolunteer) String() string { return proto.CompactTextString(m) }
+func (*ElectionVolunteer) ProtoMessage()    {}
+func (*ElectionVolunteer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d6566f2e3579336b, []int{17}
+}
+func (m *ElectionVolunteer) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ElectionVolunteer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ElectionVolunteer.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ElectionVolunteer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ElectionVolunteer.Merge(m, src)
+}
+func (m *ElectionVolunteer) XXX_Size() int {
+	return m.Size()
+}
+func (m *ElectionVolunteer) XXX_DiscardUnknown() {
+	xxx_messageInfo_ElectionVolunteer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ElectionVolunteer proto.InternalMessageInfo
+
+func (m *ElectionVolunteer) GetIdentityChainID() []byte {
+	if m != nil {
+		return m.IdentityChainID
+	}
+	return nil
+}
+
+func (m *ElectionVolunteer) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *ElectionVolunteer) GetTimestamp() *types.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+type ElectionVotes struct {
+	IdentityChainID      []byte   `protobuf:"bytes,1,opt,name=identityChainID,proto3" json:"identityChainID,omitempty"`
+	Votes                uint32   `protobuf:"varint,2,opt,name=votes,proto3" json:"votes,omitempty"`
+	Voters               [][]byte `protobuf:"bytes,3,rep,name=voters,proto3" json:"voters,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ElectionVotes) Reset()         { *m = ElectionVotes{} }
+func (m *ElectionVotes) String() string { return proto.CompactTextString(m) }
+func (*ElectionVotes) ProtoMessage()    {}
+func (*ElectionVotes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d6566f2e3579336b, []int{18}
+}
+func (m *ElectionVotes) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ElectionVotes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ElectionVotes.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ElectionVotes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ElectionVotes.Merge(m, src)
+}
+func (m *ElectionVotes) XXX_Size() int {
+	return m.Size()
+}
+func (m *ElectionVotes) XXX_DiscardUnknown() {
+	xxx_messageInfo_ElectionVotes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ElectionVotes proto.InternalMessageInfo
+
+func (m *ElectionVotes) GetIdentityChainID() []byte {
+	if m != nil {
+		return m.IdentityChainID
+	}
+	return nil
+}
+
+func (m *ElectionVotes) GetVotes() uint32 {
+	if m != nil {
+		return m.Votes
+	}
+	return 0
+}
+
+func (m *ElectionVotes) GetVoters() [][]byte {
+	if m != nil {
+		return m.Voters
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("eventmessages.EventSource", EventSource_name, EventSource_value)
 	proto.RegisterEnum("eventmessages.EntityState", EntityState_name, EntityState_value)
 	proto.RegisterEnum("eventmessages.Level", Level_name, Level_value)
 	proto.RegisterEnum("eventmessages.NodeMessageCode", NodeMessageCode_name, NodeMessageCode_value)
+	proto.RegisterEnum("eventmessages.ElectionOutcome", ElectionOutcome_name, ElectionOutcome_value)
 	proto.RegisterType((*FactomEvent)(nil), "eventmessages.FactomEvent")
 	proto.RegisterType((*ChainCommit)(nil), "eventmessages.ChainCommit")
 	proto.RegisterType((*EntryCommit)(nil), "eventmessages.EntryCommit")
@@ -1453,100 +1810,125 @@ func init() {
 	proto.RegisterType((*ProcessListEvent)(nil), "eventmessages.ProcessListEvent")
 	proto.RegisterType((*NewBlockEvent)(nil), "eventmessages.NewBlockEvent")
 	proto.RegisterType((*NewMinuteEvent)(nil), "eventmessages.NewMinuteEvent")
+	proto.RegisterType((*ElectionRecord)(nil), "eventmessages.ElectionRecord")
+	proto.RegisterType((*ElectionVolunteer)(nil), "eventmessages.ElectionVolunteer")
+	proto.RegisterType((*ElectionVotes)(nil), "eventmessages.ElectionVotes")
 }
 
 func init() { proto.RegisterFile("eventmessages/factomEvents.proto", fileDescriptor_d6566f2e3579336b) }
 
 var fileDescriptor_d6566f2e3579336b = []byte{
-	// 1399 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xcf, 0x73, 0xdb, 0xc4,
-	0x17, 0xb7, 0xfc, 0x23, 0x89, 0x9f, 0xec, 0xc4, 0xdd, 0x49, 0xfb, 0xf5, 0x37, 0x14, 0xd7, 0x23,
-	0x0a, 0x13, 0x32, 0x8c, 0x3b, 0x13, 0x98, 0x01, 0x06, 0x28, 0xd8, 0xb2, 0x52, 0xbb, 0x75, 0xec,
-	0xb0, 0x71, 0xe9, 0xa4, 0x97, 0x8c, 0x2c, 0x6d, 0x13, 0x81, 0x2d, 0x65, 0x24, 0x39, 0x6d, 0xff,
-	0x08, 0x0e, 0xbd, 0xc1, 0x81, 0x3f, 0x80, 0x23, 0x07, 0x4e, 0xfc, 0x03, 0x1c, 0x39, 0x70, 0x87,
-	0x29, 0x47, 0xfe, 0x09, 0x66, 0x77, 0x65, 0x6b, 0xb5, 0x52, 0xda, 0xb4, 0x3d, 0xc5, 0xfb, 0xf6,
-	0xf3, 0xd9, 0x7d, 0xef, 0xed, 0x67, 0xdf, 0x3e, 0x05, 0x9a, 0xe4, 0x9c, 0xb8, 0xe1, 0x8c, 0x04,
-	0x81, 0x79, 0x42, 0x82, 0x5b, 0x8f, 0x4c, 0x2b, 0xf4, 0x66, 0x06, 0xb5, 0x05, 0xad, 0x33, 0xdf,
-	0x0b, 0x3d, 0x54, 0x4d, 0x20, 0xb6, 0x6e, 0x9c, 0x78, 0xde, 0xc9, 0x94, 0xdc, 0x62, 0x93, 0x93,
-	0xf9, 0xa3, 0x5b, 0xa1, 0x33, 0x23, 0x41, 0x68, 0xce, 0xce, 0x38, 0x7e, 0xab, 0x91, 0x5c, 0xd1,
-	0xb4, 0x67, 0x8e, 0xdb, 0x99, 0x7a, 0xd6, 0x77, 0xd1, 0xbc, 0x96, 0x9c, 0xb7, 0x1d, 0x9f, 0x58,
-	0xa1, 0xe7, 0x3f, 0x15, 0x31, 0xd2, 0x1a, 0xc4, 0x0d, 0x93, 0xf3, 0x59, 0x5e, 0x3b, 0xb6, 0x80,
-	0xd0, 0xfe, 0x2d, 0x81, 0xba, 0x17, 0x07, 0x83, 0x3e, 0x07, 0x95, 0x71, 0x0e, 0xbd, 0xb9, 0x6f,
-	0x91, 0xba, 0xd2, 0x54, 0xb6, 0xd7, 0x77, 0xb7, 0x5a, 0x89, 0x75, 0x5a, 0x46, 0x8c, 0xc0, 0x22,
-	0x1c, 0xbd, 0x07, 0xeb, 0x3c, 0x33, 0x43, 0xcf, 0x26, 0x43, 0x73, 0x46, 0xea, 0xf9, 0xa6, 0xb2,
-	0x5d, 0xc6, 0x92, 0x15, 0x6d, 0xc3, 0x86, 0x63, 0x13, 0x37, 0x74, 0xc2, 0xa7, 0xfa, 0xa9, 0xe9,
-	0xb8, 0xfd, 0x6e, 0xbd, 0xd0, 0x54, 0xb6, 0x2b, 0x58, 0x36, 0xa3, 0xdb, 0xa0, 0x5a, 0xf4, 0xa7,
-	0xee, 0xcd, 0x66, 0x4e, 0x58, 0x2f, 0x36, 0x95, 0x6d, 0x35, 0xe5, 0x8f, 0x1e, 0x23, 0x7a, 0x39,
-	0x2c, 0x12, 0x28, 0x9f, 0x65, 0x25, 0xe2, 0x97, 0x32, 0xf9, 0x46, 0x8c, 0xa0, 0x7c, 0x81, 0xb0,
-	0xe4, 0x63, 0x72, 0x4e, 0xcc, 0x69, 0x7d, 0xe5, 0x62, 0x3e, 0x47, 0x2c, 0xf9, 0x7c, 0x48, 0xf9,
-	0x41, 0x68, 0x86, 0x44, 0x3f, 0x35, 0xdd, 0x13, 0x52, 0x5f, 0xcd, 0xe4, 0x1f, 0xc6, 0x08, 0xca,
-	0x17, 0x08, 0xe8, 0x08, 0x36, 0x93, 0x27, 0x1f, 0x05, 0xb2, 0xc6, 0x16, 0x7a, 0x47, 0x5a, 0xa8,
-	0x9b, 0x01, 0xed, 0xe5, 0x70, 0xe6, 0x12, 0x68, 0x1f, 0x6a, 0x67, 0xbe, 0x67, 0x91, 0x20, 0x18,
-	0x38, 0x41, 0xc8, 0xce, 0xb4, 0x5e, 0x66, 0xcb, 0xde, 0x90, 0x96, 0x3d, 0x90, 0x60, 0xbd, 0x1c,
-	0x4e, 0x51, 0x69, 0xa4, 0xae, 0x67, 0x93, 0x7d, 0x4e, 0xaa, 0x43, 0x66, 0xa4, 0xc3, 0x18, 0x41,
-	0x23, 0x15, 0x08, 0xe9, 0x48, 0xdb, 0xae, 0x75, 0xea, 0xf9, 0x75, 0xf5, 0x12, 0x91, 0x72, 0x68,
-	0x3a, 0x52, 0x6e, 0xef, 0xac, 0x42, 0x89, 0xb1, 0xb5, 0xbf, 0xf2, 0xa0, 0x0a, 0x62, 0x61, 0x6a,
-	0x67, 0x72, 0x63, 0x27, 0x70, 0x91, 0xda, 0x63, 0x04, 0x16, 0xe1, 0xa8, 0x19, 0x69, 0xb3, 0xdf,
-	0xed, 0x99, 0xc1, 0x29, 0x93, 0x7a, 0x05, 0x8b, 0x26, 0x74, 0x1d, 0xca, 0x4c, 0x0c, 0x6c, 0x9e,
-	0x2b, 0x3c, 0x36, 0x20, 0x04, 0xc5, 0xc7, 0x64, 0x6a, 0x33, 0x51, 0x57, 0x30, 0xfb, 0x8d, 0x3e,
-	0x81, 0xf2, 0xb2, 0x50, 0x2c, 0xd5, 0xca, 0x4b, 0x49, 0x6b, 0x51, 0x4a, 0x5a, 0xe3, 0x05, 0x02,
-	0xc7, 0x60, 0x54, 0x87, 0x55, 0xcb, 0x27, 0xb6, 0x13, 0x06, 0x4c, 0xa5, 0x55, 0xbc, 0x18, 0xa2,
-	0x5d, 0xd8, 0xe4, 0x92, 0x66, 0xe3, 0x83, 0xf9, 0x64, 0xea, 0x58, 0xf7, 0xc8, 0x53, 0x26, 0xc6,
-	0x0a, 0xce, 0x9c, 0xa3, 0x9e, 0x07, 0xce, 0x89, 0x6b, 0x86, 0x73, 0x9f, 0x30, 0xb1, 0x55, 0x70,
-	0x6c, 0xa0, 0x7b, 0x9d, 0x13, 0x3f, 0x70, 0x3c, 0x97, 0x29, 0xa6, 0x8a, 0x17, 0x43, 0xed, 0xe7,
-	0x3c, 0xa8, 0xc2, 0x75, 0x7a, 0xc3, 0x0c, 0x27, 0xf2, 0x97, 0x97, 0xf3, 0x97, 0xc8, 0x55, 0xe1,
-	0x35, 0x73, 0x55, 0xbc, 0x5c, 0xae, 0x4a, 0x97, 0xcd, 0xd5, 0xca, 0x0b, 0x72, 0xb5, 0x9a, 0xcc,
-	0xd5, 0x6f, 0x4a, 0x94, 0xab, 0xa8, 0x56, 0xbc, 0x59, 0xae, 0x3e, 0x82, 0x12, 0xf3, 0x8e, 0xe5,
-	0x49, 0xdd, 0x6d, 0x64, 0xd5, 0x28, 0x76, 0x29, 0xf8, 0x96, 0x1c, 0xfc, 0xfa, 0x39, 0xd4, 0xbe,
-	0x57, 0x40, 0x15, 0x0a, 0x17, 0x6a, 0x00, 0x70, 0x77, 0xd8, 0x61, 0x29, 0x2c, 0x0d, 0x82, 0x45,
-	0x8e, 0x2e, 0xff, 0xca, 0x77, 0x6d, 0x42, 0x9d, 0xef, 0x11, 0xe7, 0xe4, 0x34, 0x64, 0x9e, 0x56,
-	0xb1, 0x68, 0xd2, 0x7e, 0x29, 0xc0, 0x66, 0x56, 0xfd, 0x43, 0x06, 0xac, 0x27, 0xab, 0x02, 0x73,
-	0x4e, 0xdd, 0x7d, 0xfb, 0x85, 0x25, 0x05, 0x4b, 0x24, 0xf4, 0x29, 0x40, 0xfc, 0x46, 0x47, 0x49,
-	0xfe, 0xbf, 0xb4, 0x44, 0x7b, 0x09, 0xc0, 0x02, 0x18, 0x7d, 0x09, 0x15, 0xf1, 0xe9, 0x8d, 0xf2,
-	0xfc, 0x96, 0x44, 0xde, 0x13, 0x20, 0x38, 0x41, 0x40, 0xf7, 0xa0, 0x26, 0x28, 0x8f, 0x2f, 0x52,
-	0xcc, 0x2c, 0xd5, 0x86, 0x04, 0xc3, 0x29, 0x22, 0xfa, 0x2c, 0x7a, 0xd2, 0xd8, 0x28, 0xa8, 0x97,
-	0x9a, 0x85, 0x8c, 0x48, 0x62, 0xb9, 0x60, 0x11, 0x8d, 0x06, 0x70, 0x85, 0x24, 0x94, 0xe4, 0x10,
-	0x5a, 0x6f, 0x0a, 0x97, 0x50, 0x5c, 0x9a, 0xa8, 0x3d, 0x53, 0xa0, 0x26, 0x7b, 0x8c, 0xbe, 0x80,
-	0x95, 0x53, 0x62, 0xda, 0xc4, 0x8f, 0xce, 0xe9, 0xdd, 0x97, 0x84, 0xd8, 0x63, 0x60, 0x1c, 0x91,
-	0xd0, 0x6d, 0x58, 0x25, 0x91, 0x5f, 0x79, 0xe6, 0xd7, 0xcd, 0x97, 0xf0, 0xb9, 0x77, 0x0b, 0x92,
-	0xf6, 0xa7, 0x02, 0xd7, 0xb2, 0xb7, 0x40, 0x5b, 0xb0, 0x36, 0xf1, 0x6c, 0x51, 0xe0, 0xcb, 0x31,
-	0x6a, 0x01, 0x3a, 0xf3, 0xc9, 0xb9, 0xe3, 0xcd, 0x03, 0x8e, 0x16, 0x6a, 0x56, 0xc6, 0x0c, 0xda,
-	0xa1, 0xaf, 0x2f, 0xb7, 0xee, 0xcd, 0xa7, 0x53, 0xe1, 0x85, 0x48, 0xd9, 0x65, 0xf1, 0x17, 0x53,
-	0xe2, 0xa7, 0x08, 0x6f, 0xf2, 0x2d, 0xb1, 0x42, 0xdd, 0x9b, 0xbb, 0xbc, 0xcd, 0x29, 0x62, 0xd1,
-	0xa4, 0x3d, 0x2b, 0xc0, 0xd5, 0xcc, 0xc8, 0xe5, 0x16, 0x4b, 0x79, 0xc3, 0x16, 0x2b, 0xff, 0xaa,
-	0x2d, 0xd6, 0x5d, 0xd8, 0x70, 0x5c, 0xcb, 0x27, 0x66, 0x40, 0x3a, 0xe6, 0xd4, 0x74, 0x2d, 0x12,
-	0x5d, 0x10, 0x59, 0x50, 0xfd, 0x24, 0xaa, 0x97, 0xc3, 0x32, 0x11, 0xb5, 0xa1, 0x32, 0x73, 0xdc,
-	0x79, 0x48, 0x86, 0xf3, 0xd9, 0x84, 0xf8, 0xd1, 0x25, 0x91, 0x6f, 0xda, 0xbe, 0x00, 0xe9, 0xe5,
-	0x70, 0x82, 0x82, 0x0e, 0xe0, 0x4a, 0x40, 0xfc, 0x73, 0xe2, 0xf7, 0x5d, 0x9b, 0x3c, 0x89, 0xd6,
-	0xe1, 0x2f, 0x71, 0x53, 0xee, 0xdb, 0x64, 0x5c, 0x2f, 0x87, 0xd3, 0xe4, 0xce, 0xff, 0xe0, 0x2a,
-	0xc9, 0xca, 0xbc, 0xf6, 0xa3, 0x02, 0x1b, 0x52, 0x50, 0x17, 0x3e, 0x40, 0xca, 0x0b, 0x1e, 0xa0,
-	0x9b, 0x50, 0x0d, 0x7d, 0xd3, 0x0d, 0x4c, 0x2b, 0x74, 0x3c, 0xda, 0x4c, 0x73, 0xd9, 0x25, 0x8d,
-	0x68, 0x13, 0x4a, 0x0e, 0xf5, 0x8a, 0x65, 0xb7, 0x88, 0xf9, 0x00, 0x5d, 0x83, 0x15, 0x73, 0xc6,
-	0x44, 0x53, 0x64, 0xe6, 0x68, 0xa4, 0xed, 0x42, 0x45, 0x4c, 0x13, 0xd2, 0xa4, 0xcc, 0x2a, 0x4c,
-	0x84, 0x09, 0x9b, 0xd6, 0x86, 0x2b, 0xa9, 0x94, 0xa0, 0x0f, 0xb2, 0xf2, 0xc9, 0xd9, 0xe9, 0x09,
-	0xed, 0x27, 0x05, 0x54, 0xa1, 0x49, 0x44, 0x5f, 0x81, 0x1a, 0xa5, 0x5b, 0xf7, 0xec, 0xc5, 0x9b,
-	0xd8, 0xb8, 0xb8, 0xab, 0xa4, 0x28, 0x2c, 0x52, 0xd0, 0x0e, 0x94, 0xa6, 0xe4, 0x9c, 0x4c, 0xa3,
-	0x17, 0x67, 0x53, 0xe2, 0x0e, 0xe8, 0x1c, 0xe6, 0x10, 0x7a, 0x8d, 0xa2, 0x89, 0x31, 0x79, 0xc2,
-	0x5f, 0x99, 0x32, 0x16, 0x4d, 0xda, 0xaf, 0x0a, 0xd4, 0xe4, 0x76, 0x18, 0x75, 0xa1, 0xea, 0x92,
-	0xc7, 0xfc, 0x60, 0x59, 0x1b, 0xcd, 0xef, 0xd0, 0x75, 0xd9, 0x4d, 0x11, 0xd3, 0xcb, 0xe1, 0x24,
-	0x09, 0xdd, 0x81, 0x75, 0x97, 0x3c, 0xe6, 0x49, 0xe7, 0xcb, 0xe4, 0x33, 0xdf, 0xa9, 0x61, 0x02,
-	0xd4, 0xcb, 0x61, 0x89, 0xd6, 0x41, 0xe9, 0xc6, 0x5e, 0xfb, 0x18, 0xaa, 0x89, 0xed, 0xe9, 0xa7,
-	0xda, 0x62, 0xfb, 0xa8, 0xac, 0xf0, 0x33, 0x91, 0xac, 0xda, 0x01, 0xac, 0x27, 0x37, 0xa4, 0xed,
-	0xce, 0x72, 0xc3, 0x88, 0x14, 0x1b, 0xe4, 0x5a, 0x95, 0x4f, 0xd5, 0xaa, 0x9d, 0x6d, 0x50, 0x85,
-	0x0f, 0x48, 0xb4, 0x06, 0xc5, 0x41, 0xff, 0x1b, 0xa3, 0x96, 0x43, 0x1b, 0xa0, 0x62, 0xe3, 0x60,
-	0xd0, 0x3e, 0x3a, 0xee, 0x8c, 0x46, 0xe3, 0x9a, 0xb2, 0xf3, 0x90, 0xf5, 0x47, 0xcb, 0x1e, 0xa0,
-	0x0a, 0x65, 0x6c, 0x7c, 0x7d, 0xdf, 0x38, 0x1c, 0x1b, 0xdd, 0x5a, 0x0e, 0x55, 0x60, 0xad, 0xad,
-	0xeb, 0xc6, 0x01, 0x1d, 0x29, 0x74, 0x84, 0x8d, 0xbb, 0x86, 0x4e, 0x47, 0x79, 0xd4, 0x84, 0xeb,
-	0xfa, 0x68, 0x7f, 0xbf, 0x3f, 0x1e, 0x1b, 0xdd, 0xe3, 0xf1, 0xe8, 0xb8, 0xdb, 0xc7, 0x86, 0x3e,
-	0x1e, 0xe1, 0xa3, 0xe3, 0xce, 0x60, 0xa4, 0xdf, 0xab, 0x15, 0x76, 0xde, 0x87, 0x12, 0x3b, 0x7a,
-	0xba, 0x7f, 0x7f, 0xb8, 0x37, 0xaa, 0xe5, 0x90, 0x0a, 0xab, 0x0f, 0xda, 0x78, 0xd8, 0x1f, 0xde,
-	0xa9, 0x29, 0xa8, 0x0c, 0x25, 0x03, 0xe3, 0x11, 0xae, 0xe5, 0x77, 0x0c, 0xd8, 0x90, 0x14, 0x46,
-	0xa1, 0x77, 0x8c, 0xa1, 0x81, 0xdb, 0x03, 0xce, 0x3b, 0x1c, 0xb7, 0x31, 0xf7, 0x03, 0x60, 0xe5,
-	0xf0, 0x68, 0xa8, 0x33, 0x2f, 0x2a, 0xb0, 0x76, 0xd8, 0xbb, 0x3f, 0xee, 0x8e, 0x1e, 0x0c, 0x6b,
-	0x85, 0x4e, 0xfb, 0xf7, 0xe7, 0x0d, 0xe5, 0x8f, 0xe7, 0x0d, 0xe5, 0xef, 0xe7, 0x0d, 0xe5, 0x87,
-	0x7f, 0x1a, 0x39, 0x68, 0x5a, 0xde, 0xac, 0xc5, 0x3f, 0x8d, 0xa3, 0x3f, 0x76, 0xf2, 0xac, 0x1f,
-	0x26, 0xff, 0xa9, 0x30, 0x59, 0x61, 0x2d, 0xd9, 0x87, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0x60,
-	0x04, 0x7d, 0x07, 0x8e, 0x10, 0x00, 0x00,
+	// 1754 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0x4b, 0x73, 0xdb, 0xc8,
+	0x11, 0x26, 0xf8, 0x10, 0xc5, 0x26, 0x29, 0x51, 0x53, 0xb2, 0x82, 0x28, 0x0e, 0xcd, 0x42, 0x9c,
+	0x94, 0xac, 0x4a, 0xc9, 0x29, 0xc5, 0x95, 0x38, 0x2f, 0xc7, 0x7c, 0x40, 0x26, 0x6d, 0x8a, 0x54,
+	0x46, 0xb4, 0x5d, 0xf2, 0x45, 0x05, 0x01, 0x23, 0x09, 0x31, 0x09, 0xa8, 0x00, 0x90, 0xb6, 0x7f,
+	0x44, 0x0e, 0xbe, 0x25, 0x87, 0x1c, 0x72, 0xcc, 0x71, 0x0f, 0x7b, 0xda, 0x3f, 0xb0, 0xc7, 0x3d,
+	0xec, 0x79, 0x77, 0xcb, 0xfb, 0x47, 0xb6, 0xe6, 0x41, 0x72, 0x30, 0x84, 0x2c, 0xd9, 0x3a, 0x49,
+	0xd3, 0xf3, 0x7d, 0x33, 0xd3, 0xdf, 0x74, 0xf7, 0x34, 0x08, 0x35, 0x32, 0x21, 0x5e, 0x34, 0x22,
+	0x61, 0x68, 0x9d, 0x91, 0xf0, 0xfe, 0xa9, 0x65, 0x47, 0xfe, 0xc8, 0xa4, 0xb6, 0x70, 0xe7, 0x22,
+	0xf0, 0x23, 0x1f, 0x95, 0x63, 0x88, 0xcd, 0x3b, 0x67, 0xbe, 0x7f, 0x36, 0x24, 0xf7, 0xd9, 0xe4,
+	0xc9, 0xf8, 0xf4, 0x7e, 0xe4, 0x8e, 0x48, 0x18, 0x59, 0xa3, 0x0b, 0x8e, 0xdf, 0xac, 0xc6, 0x57,
+	0xb4, 0x9c, 0x91, 0xeb, 0x35, 0x86, 0xbe, 0xfd, 0x5a, 0xcc, 0x1b, 0xf1, 0x79, 0xc7, 0x0d, 0x88,
+	0x1d, 0xf9, 0xc1, 0x3b, 0x19, 0xa3, 0xac, 0x41, 0xbc, 0x28, 0x3e, 0x9f, 0x74, 0x6a, 0xd7, 0x91,
+	0x10, 0xc6, 0xff, 0xf2, 0x50, 0xdc, 0x9b, 0x3b, 0x83, 0xfe, 0x0a, 0x45, 0xc6, 0x39, 0xf4, 0xc7,
+	0x81, 0x4d, 0x74, 0xad, 0xa6, 0x6d, 0xad, 0xec, 0x6e, 0xee, 0xc4, 0xd6, 0xd9, 0x31, 0xe7, 0x08,
+	0x2c, 0xc3, 0xd1, 0x6f, 0x60, 0x85, 0x2b, 0xd3, 0xf3, 0x1d, 0xd2, 0xb3, 0x46, 0x44, 0x4f, 0xd7,
+	0xb4, 0xad, 0x02, 0x56, 0xac, 0x68, 0x0b, 0x56, 0x5d, 0x87, 0x78, 0x91, 0x1b, 0xbd, 0x6b, 0x9e,
+	0x5b, 0xae, 0xd7, 0x69, 0xe9, 0x99, 0x9a, 0xb6, 0x55, 0xc2, 0xaa, 0x19, 0x3d, 0x82, 0xa2, 0x4d,
+	0xff, 0x6d, 0xfa, 0xa3, 0x91, 0x1b, 0xe9, 0xd9, 0x9a, 0xb6, 0x55, 0x5c, 0x38, 0x4f, 0x73, 0x8e,
+	0x68, 0xa7, 0xb0, 0x4c, 0xa0, 0x7c, 0xa6, 0x8a, 0xe0, 0xe7, 0x12, 0xf9, 0xe6, 0x1c, 0x41, 0xf9,
+	0x12, 0x61, 0xc6, 0xc7, 0x64, 0x42, 0xac, 0xa1, 0xbe, 0x74, 0x39, 0x9f, 0x23, 0x66, 0x7c, 0x3e,
+	0xa4, 0xfc, 0x30, 0xb2, 0x22, 0xd2, 0x3c, 0xb7, 0xbc, 0x33, 0xa2, 0xe7, 0x13, 0xf9, 0x87, 0x73,
+	0x04, 0xe5, 0x4b, 0x04, 0x74, 0x04, 0xeb, 0xf1, 0x9b, 0x17, 0x8e, 0x2c, 0xb3, 0x85, 0x7e, 0xa5,
+	0x2c, 0xd4, 0x4a, 0x80, 0xb6, 0x53, 0x38, 0x71, 0x09, 0xb4, 0x0f, 0x95, 0x8b, 0xc0, 0xb7, 0x49,
+	0x18, 0x76, 0xdd, 0x30, 0x62, 0x77, 0xaa, 0x17, 0xd8, 0xb2, 0x77, 0x94, 0x65, 0x0f, 0x14, 0x58,
+	0x3b, 0x85, 0x17, 0xa8, 0xd4, 0x53, 0xcf, 0x77, 0xc8, 0x3e, 0x27, 0xe9, 0x90, 0xe8, 0x69, 0x6f,
+	0x8e, 0xa0, 0x9e, 0x4a, 0x84, 0x45, 0x4f, 0xeb, 0x9e, 0x7d, 0xee, 0x07, 0x7a, 0xf1, 0x1a, 0x9e,
+	0x72, 0xe8, 0xa2, 0xa7, 0xdc, 0x8e, 0x5e, 0xc3, 0x66, 0x92, 0x1d, 0x13, 0xdb, 0x0f, 0x1c, 0xbd,
+	0xc4, 0x36, 0xb8, 0x77, 0x8d, 0x0d, 0x38, 0xa1, 0x9d, 0xc2, 0x1f, 0x59, 0x0e, 0x3d, 0x81, 0x15,
+	0x32, 0x24, 0x76, 0xe4, 0xfa, 0x9e, 0xd8, 0xa0, 0xcc, 0x36, 0xf8, 0xa5, 0x1a, 0x34, 0x31, 0x50,
+	0x3b, 0x85, 0x15, 0x5a, 0x23, 0x0f, 0x39, 0xc6, 0x30, 0xbe, 0x4f, 0x43, 0x51, 0x0a, 0x71, 0x96,
+	0xa3, 0x2c, 0x49, 0x58, 0xdc, 0x5c, 0x96, 0xa3, 0x73, 0x04, 0x96, 0xe1, 0xa8, 0x26, 0x32, 0xaa,
+	0xd3, 0x6a, 0x5b, 0xe1, 0x39, 0x4b, 0xd0, 0x12, 0x96, 0x4d, 0xe8, 0x36, 0x14, 0x58, 0x08, 0xb3,
+	0x79, 0x9e, 0x97, 0x73, 0x03, 0x42, 0x90, 0x7d, 0x43, 0x86, 0x0e, 0x4b, 0xc5, 0x12, 0x66, 0xff,
+	0xa3, 0x87, 0x50, 0x98, 0x95, 0xb7, 0x59, 0x8e, 0xf1, 0x02, 0xb8, 0x33, 0x2d, 0x80, 0x3b, 0x83,
+	0x29, 0x02, 0xcf, 0xc1, 0x48, 0x87, 0xbc, 0x1d, 0x10, 0xc7, 0x8d, 0x42, 0x96, 0x5b, 0x65, 0x3c,
+	0x1d, 0xa2, 0x5d, 0x58, 0xe7, 0x89, 0xc8, 0xc6, 0x07, 0xe3, 0x93, 0xa1, 0x6b, 0x3f, 0x23, 0xef,
+	0x58, 0x0a, 0x95, 0x70, 0xe2, 0x1c, 0x3d, 0x79, 0xe8, 0x9e, 0x79, 0x56, 0x34, 0x0e, 0x08, 0x4b,
+	0x91, 0x12, 0x9e, 0x1b, 0xe8, 0x5e, 0x13, 0x12, 0x84, 0xae, 0xef, 0xb1, 0x38, 0x2f, 0xe3, 0xe9,
+	0xd0, 0xf8, 0x7f, 0x1a, 0x8a, 0x52, 0x11, 0xb8, 0xa1, 0xc2, 0x31, 0xfd, 0xd2, 0xaa, 0x7e, 0x31,
+	0xad, 0x32, 0x9f, 0xa9, 0x55, 0xf6, 0x7a, 0x5a, 0xe5, 0xae, 0xab, 0xd5, 0xd2, 0x47, 0xb4, 0xca,
+	0xc7, 0xb5, 0xfa, 0x4a, 0x13, 0x5a, 0x89, 0x0a, 0x77, 0x33, 0xad, 0x1e, 0x40, 0x8e, 0x9d, 0x8e,
+	0xe9, 0x54, 0xdc, 0xad, 0x26, 0x55, 0x56, 0x96, 0x63, 0x7c, 0x4b, 0x0e, 0xfe, 0x7c, 0x0d, 0x8d,
+	0x7f, 0x69, 0x50, 0x94, 0xca, 0x2d, 0xaa, 0x02, 0xf0, 0xe3, 0xb0, 0xcb, 0xd2, 0x98, 0x0c, 0x92,
+	0x45, 0xf5, 0x2e, 0xfd, 0xc9, 0xb9, 0x76, 0x42, 0x0f, 0xdf, 0x26, 0xee, 0xd9, 0x79, 0xc4, 0x4e,
+	0x5a, 0xc6, 0xb2, 0xc9, 0xf8, 0x22, 0x03, 0xeb, 0x49, 0x55, 0x1b, 0x99, 0xb0, 0x12, 0x2f, 0x32,
+	0xba, 0x96, 0x58, 0x46, 0xe2, 0x64, 0xac, 0x90, 0xd0, 0x9f, 0x00, 0xe6, 0x9d, 0x85, 0x10, 0xf9,
+	0xe7, 0xca, 0x12, 0xf5, 0x19, 0x00, 0x4b, 0x60, 0xf4, 0x77, 0x28, 0xc9, 0x0d, 0x83, 0xd0, 0xf9,
+	0x17, 0x0a, 0x79, 0x4f, 0x82, 0xe0, 0x18, 0x01, 0x3d, 0x83, 0x8a, 0x14, 0x79, 0x7c, 0x91, 0x6c,
+	0xe2, 0x03, 0x63, 0x2a, 0x30, 0xbc, 0x40, 0x44, 0x7f, 0x11, 0x0f, 0x31, 0x1b, 0x85, 0x7a, 0xae,
+	0x96, 0x49, 0xf0, 0x64, 0x1e, 0x2e, 0x58, 0x46, 0xa3, 0x2e, 0xac, 0x91, 0x58, 0x24, 0xb9, 0x84,
+	0xd6, 0x9b, 0xcc, 0x35, 0x22, 0x6e, 0x91, 0x68, 0xbc, 0xd7, 0xa0, 0xa2, 0x9e, 0x18, 0xfd, 0x0d,
+	0x96, 0xce, 0x89, 0xe5, 0x90, 0x40, 0xdc, 0xd3, 0xaf, 0xaf, 0x70, 0xb1, 0xcd, 0xc0, 0x58, 0x90,
+	0xd0, 0x23, 0xc8, 0x13, 0x71, 0xae, 0x34, 0x3b, 0xd7, 0xdd, 0x2b, 0xf8, 0xfc, 0x74, 0x53, 0x92,
+	0xf1, 0xad, 0x06, 0x1b, 0xc9, 0x5b, 0xa0, 0x4d, 0x58, 0x3e, 0xf1, 0x1d, 0x39, 0xc0, 0x67, 0x63,
+	0xb4, 0x03, 0xe8, 0x22, 0x20, 0x13, 0xd7, 0x1f, 0x87, 0x1c, 0x2d, 0xd5, 0xac, 0x84, 0x19, 0xb4,
+	0x0d, 0x95, 0xa9, 0x75, 0x6f, 0x3c, 0x1c, 0x4a, 0x2f, 0xc4, 0x82, 0x5d, 0x0d, 0xfe, 0xec, 0x42,
+	0xf0, 0x53, 0x84, 0x7f, 0xf2, 0x4f, 0x62, 0x47, 0x4d, 0x7f, 0xec, 0xf1, 0xe6, 0x2c, 0x8b, 0x65,
+	0x93, 0xf1, 0x3e, 0x03, 0xb7, 0x12, 0x3d, 0x57, 0x1b, 0x43, 0xed, 0x86, 0x8d, 0x61, 0xfa, 0x53,
+	0x1b, 0xc3, 0xa7, 0xb0, 0xea, 0x7a, 0x76, 0x40, 0xac, 0x90, 0x34, 0xac, 0xa1, 0xe5, 0xd9, 0x44,
+	0x24, 0x88, 0x1a, 0x50, 0x9d, 0x38, 0xaa, 0x9d, 0xc2, 0x2a, 0x11, 0xd5, 0xa1, 0x34, 0x72, 0xbd,
+	0x71, 0x44, 0x7a, 0xe3, 0xd1, 0x09, 0x09, 0xf4, 0x6c, 0x62, 0xa6, 0xed, 0x4b, 0x90, 0x76, 0x0a,
+	0xc7, 0x28, 0xe8, 0x00, 0xd6, 0x42, 0x12, 0x4c, 0x48, 0xd0, 0xf1, 0x1c, 0xf2, 0x56, 0xac, 0xc3,
+	0x5f, 0xe2, 0x9a, 0xda, 0x6d, 0xaa, 0xb8, 0x76, 0x0a, 0x2f, 0x92, 0x1b, 0x3f, 0x83, 0x5b, 0x24,
+	0x49, 0x79, 0xe3, 0x3f, 0x1a, 0xac, 0x2a, 0x4e, 0x5d, 0xfa, 0x00, 0x69, 0x1f, 0x79, 0x80, 0xee,
+	0x42, 0x39, 0x0a, 0x2c, 0x2f, 0xb4, 0x58, 0xd3, 0xd3, 0x69, 0x89, 0xb0, 0x8b, 0x1b, 0xd1, 0x3a,
+	0xe4, 0x5c, 0x7a, 0x2a, 0xa6, 0x6e, 0x16, 0xf3, 0x01, 0xda, 0x80, 0x25, 0x6b, 0xc4, 0x82, 0x26,
+	0xcb, 0xcc, 0x62, 0x64, 0xec, 0x42, 0x49, 0x96, 0x09, 0x19, 0x8a, 0xb2, 0x1a, 0x0b, 0xc2, 0x98,
+	0xcd, 0xa8, 0xc3, 0xda, 0x82, 0x24, 0xe8, 0xb7, 0x49, 0x7a, 0x72, 0xf6, 0xe2, 0x84, 0xf1, 0x5f,
+	0x0d, 0x8a, 0x52, 0x6b, 0x8b, 0x1e, 0x43, 0x51, 0xc8, 0xdd, 0xf4, 0x9d, 0xe9, 0x9b, 0x58, 0xbd,
+	0xbc, 0x17, 0xa6, 0x28, 0x2c, 0x53, 0xd0, 0x36, 0xe4, 0x86, 0x64, 0x42, 0x86, 0xe2, 0xc5, 0x59,
+	0x57, 0xb8, 0x5d, 0x3a, 0x87, 0x39, 0x84, 0xa6, 0x91, 0x98, 0x18, 0x90, 0xb7, 0xfc, 0x95, 0x29,
+	0x60, 0xd9, 0x64, 0x7c, 0xa9, 0x41, 0x45, 0x6d, 0xe2, 0x51, 0x0b, 0xca, 0x1e, 0x79, 0xc3, 0x2f,
+	0x96, 0x1a, 0x44, 0x0e, 0xdd, 0x56, 0x8f, 0x29, 0x63, 0xda, 0x29, 0x1c, 0x27, 0xd1, 0x76, 0xd7,
+	0x23, 0x6f, 0xb8, 0xe8, 0x7c, 0x99, 0x74, 0xe2, 0x3b, 0xd5, 0x8b, 0x81, 0x68, 0xbb, 0x1b, 0xa7,
+	0x35, 0xd0, 0xe2, 0xe7, 0x88, 0xf1, 0x47, 0x28, 0xc7, 0xb6, 0xa7, 0x1f, 0x98, 0xd3, 0xed, 0x45,
+	0x59, 0xe1, 0x77, 0xa2, 0x58, 0x8d, 0x03, 0x58, 0x89, 0x6f, 0x48, 0xdb, 0x9d, 0xd9, 0x86, 0x82,
+	0x34, 0x37, 0xa8, 0xb5, 0x2a, 0xbd, 0xf8, 0x50, 0x7f, 0x97, 0x83, 0x95, 0x78, 0xcb, 0xce, 0x7a,
+	0x07, 0x61, 0xe9, 0xb4, 0xd8, 0x9a, 0x59, 0x2c, 0x59, 0xd0, 0x43, 0xc8, 0xfb, 0xe3, 0xc8, 0xf6,
+	0x47, 0xd3, 0xbe, 0xa1, 0x7a, 0xc9, 0x27, 0x40, 0x9f, 0xa3, 0xf0, 0x14, 0x7e, 0x75, 0xdf, 0x40,
+	0x13, 0x80, 0x07, 0x31, 0x4b, 0x80, 0x1c, 0x16, 0x23, 0xd6, 0xb7, 0x8d, 0x58, 0x68, 0xea, 0x39,
+	0xd1, 0xb7, 0xf1, 0x21, 0x4d, 0x85, 0x53, 0x6b, 0x3c, 0x8c, 0x88, 0xc3, 0xa7, 0x79, 0xbb, 0x1d,
+	0xb3, 0xa1, 0x3f, 0xc0, 0xc6, 0x74, 0xac, 0x7c, 0x9e, 0xf3, 0xae, 0xfb, 0x92, 0x59, 0xf4, 0x00,
+	0xf2, 0x61, 0x64, 0x05, 0x11, 0x71, 0xf4, 0xe5, 0x2b, 0xbb, 0xb1, 0x29, 0x14, 0xfd, 0x8e, 0xf6,
+	0x7e, 0x0e, 0x71, 0xf4, 0xc2, 0x95, 0x1c, 0x0e, 0xa4, 0x8a, 0x3b, 0xe3, 0xc0, 0xa2, 0x9a, 0xed,
+	0x87, 0xec, 0x13, 0x33, 0x83, 0x25, 0x0b, 0x55, 0x25, 0xf0, 0xc7, 0x9e, 0x13, 0xb2, 0xaf, 0xc6,
+	0x32, 0x16, 0x23, 0xea, 0x17, 0xbb, 0x97, 0x45, 0xbf, 0x4a, 0xdc, 0xaf, 0xe4, 0x59, 0xfa, 0xdc,
+	0x9d, 0x12, 0x87, 0x04, 0x56, 0x44, 0x1c, 0x5e, 0x23, 0x42, 0xbd, 0x5c, 0xcb, 0xd0, 0xe7, 0x4e,
+	0xb5, 0x53, 0x7d, 0xad, 0xb1, 0xe3, 0x46, 0x53, 0xdc, 0x0a, 0xc3, 0xc5, 0x6c, 0xe8, 0x31, 0xc0,
+	0xc4, 0x1f, 0x8e, 0xbd, 0x88, 0x50, 0xc4, 0x6a, 0x2d, 0x93, 0x50, 0x9e, 0xa7, 0x41, 0xf1, 0x62,
+	0x0a, 0xc4, 0x12, 0x07, 0xed, 0x42, 0x6e, 0xe2, 0x47, 0x24, 0xd4, 0x2b, 0xb5, 0x4c, 0x42, 0xb2,
+	0xce, 0xc9, 0x11, 0x09, 0x31, 0x87, 0xd2, 0x42, 0xcb, 0x0a, 0x85, 0x28, 0x36, 0xa1, 0xbe, 0xc6,
+	0xc4, 0x89, 0x1b, 0x69, 0x67, 0xbc, 0xb6, 0xb0, 0x77, 0xd2, 0x2f, 0x35, 0x5a, 0xf2, 0x2f, 0x35,
+	0xeb, 0x90, 0x63, 0x6a, 0x8b, 0xe4, 0xe1, 0x83, 0x1b, 0x74, 0xea, 0x67, 0x50, 0x8e, 0x79, 0xf3,
+	0x69, 0x47, 0xe1, 0x22, 0x89, 0xa3, 0x70, 0x19, 0x36, 0x60, 0x89, 0xfe, 0x13, 0x84, 0x7a, 0x86,
+	0x5d, 0x8d, 0x18, 0x6d, 0x6f, 0x41, 0x51, 0xfa, 0x41, 0x0b, 0x2d, 0x43, 0xb6, 0xdb, 0x79, 0x61,
+	0x56, 0x52, 0x68, 0x15, 0x8a, 0xd8, 0x3c, 0xe8, 0xd6, 0x8f, 0x8e, 0x1b, 0xfd, 0xfe, 0xa0, 0xa2,
+	0x6d, 0xbf, 0x62, 0x5f, 0x3e, 0xb3, 0xee, 0xbe, 0x0c, 0x05, 0x6c, 0xfe, 0xe3, 0xb9, 0x79, 0x38,
+	0x30, 0x5b, 0x95, 0x14, 0x2a, 0xc1, 0x72, 0xbd, 0xd9, 0x34, 0x0f, 0xe8, 0x48, 0xa3, 0x23, 0x6c,
+	0x3e, 0x35, 0x9b, 0x74, 0x94, 0x46, 0x35, 0xb8, 0xdd, 0xec, 0xef, 0xef, 0x77, 0x06, 0x03, 0xb3,
+	0x75, 0x3c, 0xe8, 0x1f, 0xb7, 0x3a, 0xd8, 0x6c, 0x0e, 0xfa, 0xf8, 0xe8, 0xb8, 0xd1, 0xed, 0x37,
+	0x9f, 0x55, 0x32, 0xdb, 0xf7, 0x20, 0xc7, 0x8a, 0x3a, 0xdd, 0xbf, 0xd3, 0xdb, 0xeb, 0x57, 0x52,
+	0xa8, 0x08, 0xf9, 0x97, 0x75, 0xdc, 0xeb, 0xf4, 0x9e, 0x54, 0x34, 0x54, 0x80, 0x9c, 0x89, 0x71,
+	0x1f, 0x57, 0xd2, 0xdb, 0x26, 0xac, 0x2a, 0x6f, 0x07, 0x85, 0x3e, 0x31, 0x7b, 0x26, 0xae, 0x77,
+	0x39, 0xef, 0x70, 0x50, 0xc7, 0xfc, 0x1c, 0x00, 0x4b, 0x87, 0x47, 0xbd, 0x26, 0x3b, 0x45, 0x09,
+	0x96, 0x0f, 0xdb, 0xcf, 0x07, 0xad, 0xfe, 0xcb, 0x5e, 0x25, 0xb3, 0xfd, 0x67, 0x58, 0x55, 0x0a,
+	0x10, 0x65, 0xe2, 0xe7, 0x3d, 0xb6, 0x23, 0x5b, 0xc6, 0xec, 0x72, 0x07, 0x34, 0xea, 0x6b, 0xbd,
+	0x51, 0xef, 0xb5, 0xfa, 0x3d, 0xba, 0x52, 0xa3, 0xfe, 0xf5, 0x87, 0xaa, 0xf6, 0xcd, 0x87, 0xaa,
+	0xf6, 0xc3, 0x87, 0xaa, 0xf6, 0xef, 0x1f, 0xab, 0x29, 0xa8, 0xd9, 0xfe, 0x68, 0x87, 0xff, 0xcc,
+	0x27, 0xfe, 0x38, 0xf1, 0xd8, 0x7c, 0x15, 0xff, 0x81, 0xf4, 0x64, 0x89, 0x5d, 0xff, 0xef, 0x7f,
+	0x1a, 0x00, 0x2b, 0xa2, 0x00, 0xce, 0x5a, 0x15, 0x00, 0x00,
 }
 
 func (m *FactomEvent) Marshal() (dAtA []byte, err error) {
@@ -1772,6 +2154,48 @@ func (m *FactomEvent_DirectoryBlockAnchor) MarshalToSizedBuffer(dAtA []byte) (in
 	}
 	return len(dAtA) - i, nil
 }
+func (m *FactomEvent_DirectoryBlockAnchorRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FactomEvent_DirectoryBlockAnchorRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.DirectoryBlockAnchorRecord != nil {
+		{
+			size, err := m.DirectoryBlockAnchorRecord.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	return len(dAtA) - i, nil
+}
+func (m *FactomEvent_ElectionRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FactomEvent_ElectionRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.ElectionRecord != nil {
+		{
+			size, err := m.ElectionRecord.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	return len(dAtA) - i, nil
+}
 func (m *ChainCommit) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2697,18 +3121,277 @@ func (m *NewMinuteEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintFactomEvents(dAtA []byte, offset int, v uint64) int {
-	offset -= sovFactomEvents(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ElectionRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *FactomEvent) Size() (n int) {
+
+func (m *ElectionRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ElectionRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.LevelMessages != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.LevelMessages))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
+	}
+	if len(m.Votes) > 0 {
+		for iNdEx := len(m.Votes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Votes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0x82
+		}
+	}
+	if len(m.Volunteers) > 0 {
+		for iNdEx := len(m.Volunteers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Volunteers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x7a
+		}
+	}
+	if len(m.AuditServers) > 0 {
+		for iNdEx := len(m.AuditServers) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AuditServers[iNdEx])
+			copy(dAtA[i:], m.AuditServers[iNdEx])
+			i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.AuditServers[iNdEx])))
+			i--
+			dAtA[i] = 0x72
+		}
+	}
+	if len(m.FederatedServers) > 0 {
+		for iNdEx := len(m.FederatedServers) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.FederatedServers[iNdEx])
+			copy(dAtA[i:], m.FederatedServers[iNdEx])
+			i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.FederatedServers[iNdEx])))
+			i--
+			dAtA[i] = 0x6a
+		}
+	}
+	if len(m.ElectedIdentityChainID) > 0 {
+		i -= len(m.ElectedIdentityChainID)
+		copy(dAtA[i:], m.ElectedIdentityChainID)
+		i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.ElectedIdentityChainID)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.Rounds != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.Rounds))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.DurationMs != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.DurationMs))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.Ended != nil {
+		{
+			size, err := m.Ended.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.Started != nil {
+		{
+			size, err := m.Started.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.FaultedIdentityChainID) > 0 {
+		i -= len(m.FaultedIdentityChainID)
+		copy(dAtA[i:], m.FaultedIdentityChainID)
+		i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.FaultedIdentityChainID)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.FaultedIndex != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.FaultedIndex))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.VmIndex != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.VmIndex))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Minute != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.Minute))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.BlockHeight != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.BlockHeight))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Outcome != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.Outcome))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ElectionID != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.ElectionID))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ElectionVolunteer) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ElectionVolunteer) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ElectionVolunteer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Timestamp != nil {
+		{
+			size, err := m.Timestamp.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Round != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.Round))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.IdentityChainID) > 0 {
+		i -= len(m.IdentityChainID)
+		copy(dAtA[i:], m.IdentityChainID)
+		i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.IdentityChainID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ElectionVotes) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ElectionVotes) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ElectionVotes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Voters) > 0 {
+		for iNdEx := len(m.Voters) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Voters[iNdEx])
+			copy(dAtA[i:], m.Voters[iNdEx])
+			i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.Voters[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Votes != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.Votes))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.IdentityChainID) > 0 {
+		i -= len(m.IdentityChainID)
+		copy(dAtA[i:], m.IdentityChainID)
+		i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.IdentityChainID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintFactomEvents(dAtA []byte, offset int, v uint64) int {
+	offset -= sovFactomEvents(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *FactomEvent) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -2830,6 +3513,30 @@ func (m *FactomEvent_DirectoryBlockAnchor) Size() (n int) {
 	}
 	return n
 }
+func (m *FactomEvent_DirectoryBlockAnchorRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.DirectoryBlockAnchorRecord != nil {
+		l = m.DirectoryBlockAnchorRecord.Size()
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	return n
+}
+func (m *FactomEvent_ElectionRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ElectionRecord != nil {
+		l = m.ElectionRecord.Size()
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	return n
+}
 func (m *ChainCommit) Size() (n int) {
 	if m == nil {
 		return 0
@@ -3274,11 +3981,138 @@ func (m *NewMinuteEvent) Size() (n int) {
 	return n
 }
 
-func sovFactomEvents(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozFactomEvents(x uint64) (n int) {
-	return sovFactomEvents(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+func (m *ElectionRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ElectionID != 0 {
+		n += 1 + sovFactomEvents(uint64(m.ElectionID))
+	}
+	if m.Outcome != 0 {
+		n += 1 + sovFactomEvents(uint64(m.Outcome))
+	}
+	if m.BlockHeight != 0 {
+		n += 1 + sovFactomEvents(uint64(m.BlockHeight))
+	}
+	if m.Minute != 0 {
+		n += 1 + sovFactomEvents(uint64(m.Minute))
+	}
+	if m.VmIndex != 0 {
+		n += 1 + sovFactomEvents(uint64(m.VmIndex))
+	}
+	if m.FaultedIndex != 0 {
+		n += 1 + sovFactomEvents(uint64(m.FaultedIndex))
+	}
+	l = len(m.FaultedIdentityChainID)
+	if l > 0 {
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.Started != nil {
+		l = m.Started.Size()
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.Ended != nil {
+		l = m.Ended.Size()
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.DurationMs != 0 {
+		n += 1 + sovFactomEvents(uint64(m.DurationMs))
+	}
+	if m.Rounds != 0 {
+		n += 1 + sovFactomEvents(uint64(m.Rounds))
+	}
+	l = len(m.ElectedIdentityChainID)
+	if l > 0 {
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if len(m.FederatedServers) > 0 {
+		for _, b := range m.FederatedServers {
+			l = len(b)
+			n += 1 + l + sovFactomEvents(uint64(l))
+		}
+	}
+	if len(m.AuditServers) > 0 {
+		for _, b := range m.AuditServers {
+			l = len(b)
+			n += 1 + l + sovFactomEvents(uint64(l))
+		}
+	}
+	if len(m.Volunteers) > 0 {
+		for _, e := range m.Volunteers {
+			l = e.Size()
+			n += 1 + l + sovFactomEvents(uint64(l))
+		}
+	}
+	if len(m.Votes) > 0 {
+		for _, e := range m.Votes {
+			l = e.Size()
+			n += 2 + l + sovFactomEvents(uint64(l))
+		}
+	}
+	if m.LevelMessages != 0 {
+		n += 2 + sovFactomEvents(uint64(m.LevelMessages))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ElectionVolunteer) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.IdentityChainID)
+	if l > 0 {
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.Round != 0 {
+		n += 1 + sovFactomEvents(uint64(m.Round))
+	}
+	if m.Timestamp != nil {
+		l = m.Timestamp.Size()
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ElectionVotes) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.IdentityChainID)
+	if l > 0 {
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.Votes != 0 {
+		n += 1 + sovFactomEvents(uint64(m.Votes))
+	}
+	if len(m.Voters) > 0 {
+		for _, b := range m.Voters {
+			l = len(b)
+			n += 1 + l + sovFactomEvents(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovFactomEvents(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozFactomEvents(x uint64) (n int) {
+	return sovFactomEvents(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
 func (m *FactomEvent) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
@@ -3674,6 +4508,76 @@ func (m *FactomEvent) Unmarshal(dAtA []byte) error {
 			}
 			m.Event = &FactomEvent_DirectoryBlockAnchor{v}
 			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DirectoryBlockAnchorRecord", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &DirectoryBlockAnchorRecord{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &FactomEvent_DirectoryBlockAnchorRecord{v}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElectionRecord", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &ElectionRecord{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &FactomEvent_ElectionRecord{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFactomEvents(dAtA[iNdEx:])
@@ -6068,6 +6972,785 @@ func (m *NewMinuteEvent) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ElectionRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFactomEvents
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ElectionRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ElectionRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElectionID", wireType)
+			}
+			m.ElectionID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ElectionID |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Outcome", wireType)
+			}
+			m.Outcome = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Outcome |= ElectionOutcome(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeight", wireType)
+			}
+			m.BlockHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeight |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Minute", wireType)
+			}
+			m.Minute = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Minute |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VmIndex", wireType)
+			}
+			m.VmIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.VmIndex |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FaultedIndex", wireType)
+			}
+			m.FaultedIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FaultedIndex |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FaultedIdentityChainID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FaultedIdentityChainID = append(m.FaultedIdentityChainID[:0], dAtA[iNdEx:postIndex]...)
+			if m.FaultedIdentityChainID == nil {
+				m.FaultedIdentityChainID = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Started", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Started == nil {
+				m.Started = &types.Timestamp{}
+			}
+			if err := m.Started.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ended", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Ended == nil {
+				m.Ended = &types.Timestamp{}
+			}
+			if err := m.Ended.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
+			}
+			m.DurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rounds", wireType)
+			}
+			m.Rounds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rounds |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElectedIdentityChainID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ElectedIdentityChainID = append(m.ElectedIdentityChainID[:0], dAtA[iNdEx:postIndex]...)
+			if m.ElectedIdentityChainID == nil {
+				m.ElectedIdentityChainID = []byte{}
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FederatedServers", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FederatedServers = append(m.FederatedServers, make([]byte, postIndex-iNdEx))
+			copy(m.FederatedServers[len(m.FederatedServers)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuditServers", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AuditServers = append(m.AuditServers, make([]byte, postIndex-iNdEx))
+			copy(m.AuditServers[len(m.AuditServers)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Volunteers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Volunteers = append(m.Volunteers, &ElectionVolunteer{})
+			if err := m.Volunteers[len(m.Volunteers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Votes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Votes = append(m.Votes, &ElectionVotes{})
+			if err := m.Votes[len(m.Votes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LevelMessages", wireType)
+			}
+			m.LevelMessages = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LevelMessages |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFactomEvents(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ElectionVolunteer) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFactomEvents
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ElectionVolunteer: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ElectionVolunteer: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IdentityChainID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IdentityChainID = append(m.IdentityChainID[:0], dAtA[iNdEx:postIndex]...)
+			if m.IdentityChainID == nil {
+				m.IdentityChainID = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Round |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Timestamp == nil {
+				m.Timestamp = &types.Timestamp{}
+			}
+			if err := m.Timestamp.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFactomEvents(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ElectionVotes) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFactomEvents
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ElectionVotes: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ElectionVotes: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IdentityChainID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IdentityChainID = append(m.IdentityChainID[:0], dAtA[iNdEx:postIndex]...)
+			if m.IdentityChainID == nil {
+				m.IdentityChainID = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Votes", wireType)
+			}
+			m.Votes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Votes |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Voters", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Voters = append(m.Voters, make([]byte, postIndex-iNdEx))
+			copy(m.Voters[len(m.Voters)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFactomEvents(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipFactomEvents(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
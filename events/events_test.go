@@ -247,6 +247,80 @@ func TestEmitDirectoryBlockAnchorEvent(t *testing.T) {
 	}
 }
 
+func TestEmitDirectoryBlockAnchorRecordEvent(t *testing.T) {
+	eventQueue := make(chan *eventmessages.FactomEvent, 5000)
+	mockSender := &mockEventSender{
+		eventsOutQueue:      eventQueue,
+		replayDuringStartup: true,
+		sendStateChange:     true,
+	}
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.EventService.ConfigSender(s, mockSender)
+
+	db := databaseOverlay.NewOverlayWithState(s.DB.(*databaseOverlay.Overlay), s)
+	db.BitcoinAnchorRecordPublicKeys = []interfaces.Verifier{testHelper.NewPrimitivesPrivateKey(0).Pub}
+	dBlock, err := db.FetchDBlockByHeight(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := testHelper.CreateTestAnchorEnry(dBlock.(*directoryBlock.DirectoryBlock))
+
+	// The record is sent once, when it anchors the block
+	db.SaveAnchorInfoFromEntry(entry, false)
+	db.SaveAnchorInfoFromEntry(entry, false)
+
+	var records []*eventmessages.DirectoryBlockAnchorRecord
+	for len(eventQueue) > 0 {
+		event := <-eventQueue
+		if record := event.GetDirectoryBlockAnchorRecord(); record != nil {
+			records = append(records, record)
+		}
+	}
+	if assert.Equal(t, 1, len(records)) {
+		assert.Equal(t, eventmessages.AnchorChain_BITCOIN, records[0].GetChain())
+		assert.EqualValues(t, 1, records[0].GetBlockHeight())
+		assert.Equal(t, dBlock.DatabasePrimaryIndex().Bytes(), records[0].GetKeyMerkleRoot())
+		assert.Equal(t, entry.GetHash().Bytes(), records[0].GetEntryHash())
+	}
+}
+
+func TestEmitElectionEvent(t *testing.T) {
+	eventQueue := make(chan *eventmessages.FactomEvent, 5000)
+	mockSender := &mockEventSender{
+		eventsOutQueue:      eventQueue,
+		replayDuringStartup: true,
+		sendStateChange:     true,
+	}
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.EventService.ConfigSender(s, mockSender)
+
+	// An event when the election starts, and one when it ends
+	feds := s.GetFedServers(s.GetLLeaderHeight())
+	audit := primitives.Sha([]byte("audit"))
+	s.ElectionLog.Start(10, 2, 0, 0, feds[0].GetChainID(), feds, nil)
+	s.ElectionLog.Volunteer(audit, 1)
+	s.ElectionLog.Elected(audit)
+
+	var records []*eventmessages.ElectionRecord
+	for len(eventQueue) > 0 {
+		event := <-eventQueue
+		if record := event.GetElectionRecord(); record != nil {
+			records = append(records, record)
+		}
+	}
+	if assert.Equal(t, 2, len(records)) {
+		assert.Equal(t, eventmessages.ElectionOutcome_RUNNING, records[0].GetOutcome())
+		assert.Equal(t, eventmessages.ElectionOutcome_ELECTED, records[1].GetOutcome())
+		assert.EqualValues(t, 10, records[1].GetBlockHeight())
+		assert.EqualValues(t, 2, records[1].GetMinute())
+		assert.Equal(t, feds[0].GetChainID().Bytes(), records[1].GetFaultedIdentityChainID())
+		assert.Equal(t, audit.Bytes(), records[1].GetElectedIdentityChainID())
+		assert.Len(t, records[1].GetVolunteers(), 1)
+	}
+}
+
 func TestExecuteMessage(t *testing.T) {
 	testCases := map[string]struct {
 		Message   interfaces.IMsg
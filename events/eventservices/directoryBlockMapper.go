@@ -1,6 +1,8 @@
 package eventservices
 
 import (
+	"encoding/hex"
+
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
 )
@@ -64,3 +66,36 @@ func mapDirectoryBlockInfo(info interfaces.IDirBlockInfo) *eventmessages.FactomE
 	}
 	return event
 }
+
+func mapDirectoryBlockAnchorRecord(anchorRef *interfaces.AnchorRef) *eventmessages.FactomEvent_DirectoryBlockAnchorRecord {
+	chain := eventmessages.AnchorChain_BITCOIN
+	if anchorRef.Chain == interfaces.AnchorChainEthereum {
+		chain = eventmessages.AnchorChain_ETHEREUM
+	}
+	event := &eventmessages.FactomEvent_DirectoryBlockAnchorRecord{
+		DirectoryBlockAnchorRecord: &eventmessages.DirectoryBlockAnchorRecord{
+			Chain:             chain,
+			EntryHash:         hexToBytes(anchorRef.EntryHash),
+			BlockHeight:       anchorRef.DBHeight,
+			KeyMerkleRoot:     hexToBytes(anchorRef.KeyMR),
+			BlockHeightMin:    anchorRef.DBHeightMin,
+			BlockHeightMax:    anchorRef.DBHeightMax,
+			WindowMerkleRoot:  hexToBytes(anchorRef.WindowMR),
+			TxID:              anchorRef.TxID,
+			AnchorBlockHeight: anchorRef.BlockHeight,
+			AnchorBlockHash:   anchorRef.BlockHash,
+			TxIndex:           anchorRef.TxIndex,
+			Address:           anchorRef.Address,
+		},
+	}
+	return event
+}
+
+// hexToBytes decodes a hash of an anchor record, nil if there isn't one
+func hexToBytes(h string) []byte {
+	data, err := hex.DecodeString(h)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,84 @@
+package eventservices
+
+import (
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/gogo/protobuf/types"
+)
+
+func mapElectionRecord(record *interfaces.ElectionRecord) *eventmessages.FactomEvent_ElectionRecord {
+	event := &eventmessages.FactomEvent_ElectionRecord{
+		ElectionRecord: &eventmessages.ElectionRecord{
+			ElectionID:             record.ID,
+			Outcome:                mapElectionOutcome(record.Outcome),
+			BlockHeight:            record.DBHeight,
+			Minute:                 int32(record.Minute),
+			VmIndex:                uint32(record.VMIndex),
+			FaultedIndex:           uint32(record.FaultedIndex),
+			FaultedIdentityChainID: hexToBytes(record.FaultedID),
+			Started:                convertMilliToTimestamp(record.Started),
+			Ended:                  convertMilliToTimestamp(record.Ended),
+			DurationMs:             record.DurationMs,
+			Rounds:                 uint32(record.Rounds),
+			ElectedIdentityChainID: hexToBytes(record.ElectedID),
+			FederatedServers:       mapIdentityChainIDs(record.Federated),
+			AuditServers:           mapIdentityChainIDs(record.Audit),
+			Volunteers:             mapElectionVolunteers(record.Volunteers),
+			Votes:                  mapElectionVotes(record.Votes),
+			LevelMessages:          uint32(record.LevelMessages),
+		},
+	}
+	return event
+}
+
+func mapElectionOutcome(outcome string) eventmessages.ElectionOutcome {
+	switch outcome {
+	case interfaces.ElectionElected:
+		return eventmessages.ElectionOutcome_ELECTED
+	case interfaces.ElectionAbandoned:
+		return eventmessages.ElectionOutcome_ABANDONED
+	}
+	return eventmessages.ElectionOutcome_RUNNING
+}
+
+func mapElectionVolunteers(volunteers []*interfaces.ElectionVolunteer) []*eventmessages.ElectionVolunteer {
+	result := make([]*eventmessages.ElectionVolunteer, len(volunteers))
+	for i, volunteer := range volunteers {
+		result[i] = &eventmessages.ElectionVolunteer{
+			IdentityChainID: hexToBytes(volunteer.ServerID),
+			Round:           uint32(volunteer.Round),
+			Timestamp:       convertMilliToTimestamp(volunteer.Time),
+		}
+	}
+	return result
+}
+
+func mapElectionVotes(votes []*interfaces.ElectionVotes) []*eventmessages.ElectionVotes {
+	result := make([]*eventmessages.ElectionVotes, len(votes))
+	for i, vote := range votes {
+		result[i] = &eventmessages.ElectionVotes{
+			IdentityChainID: hexToBytes(vote.ServerID),
+			Votes:           uint32(vote.Votes),
+			Voters:          mapIdentityChainIDs(vote.Voters),
+		}
+	}
+	return result
+}
+
+func mapIdentityChainIDs(ids []string) [][]byte {
+	result := make([][]byte, len(ids))
+	for i, id := range ids {
+		result[i] = hexToBytes(id)
+	}
+	return result
+}
+
+// convertMilliToTimestamp converts unix milliseconds, nil for 0
+func convertMilliToTimestamp(milli int64) *types.Timestamp {
+	if milli == 0 {
+		return nil
+	}
+	return ConvertTimeToTimestamp(time.Unix(0, milli*int64(time.Millisecond)))
+}
@@ -0,0 +1,60 @@
+package eventservices
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapElectionRecord(t *testing.T) {
+	faulted := primitives.RandomHash().String()
+	audit := primitives.RandomHash().String()
+	record := &interfaces.ElectionRecord{
+		ID:            7,
+		DBHeight:      1000,
+		Minute:        -1,
+		VMIndex:       2,
+		FaultedIndex:  1,
+		FaultedID:     faulted,
+		Started:       1571000000000,
+		Outcome:       interfaces.ElectionRunning,
+		Federated:     []string{faulted},
+		Audit:         []string{audit},
+		Volunteers:    []*interfaces.ElectionVolunteer{{ServerID: audit, Round: 1, Time: 1571000001000}},
+		Votes:         []*interfaces.ElectionVotes{{ServerID: audit, Votes: 1, Voters: []string{faulted}}},
+		LevelMessages: 3,
+	}
+
+	election := mapElectionRecord(record).ElectionRecord
+	assert.EqualValues(t, 7, election.ElectionID)
+	assert.Equal(t, eventmessages.ElectionOutcome_RUNNING, election.Outcome)
+	assert.EqualValues(t, -1, election.Minute)
+	assert.EqualValues(t, 2, election.VmIndex)
+	assert.Len(t, election.FaultedIdentityChainID, 32)
+	assert.EqualValues(t, 1571000000, election.Started.Seconds)
+	assert.Nil(t, election.Ended)
+	assert.Nil(t, election.ElectedIdentityChainID)
+	assert.Len(t, election.AuditServers, 1)
+	if assert.Len(t, election.Volunteers, 1) {
+		assert.EqualValues(t, 1, election.Volunteers[0].Round)
+		assert.EqualValues(t, 1571000001, election.Volunteers[0].Timestamp.Seconds)
+	}
+	if assert.Len(t, election.Votes, 1) {
+		assert.Equal(t, election.AuditServers[0], election.Votes[0].IdentityChainID)
+		assert.Equal(t, election.FederatedServers, election.Votes[0].Voters)
+	}
+	assert.EqualValues(t, 3, election.LevelMessages)
+
+	record.Outcome = interfaces.ElectionElected
+	record.ElectedID = audit
+	record.Ended = 1571000002500
+	record.DurationMs = 2500
+	election = mapElectionRecord(record).ElectionRecord
+	assert.Equal(t, eventmessages.ElectionOutcome_ELECTED, election.Outcome)
+	assert.Equal(t, election.AuditServers[0], election.ElectedIdentityChainID)
+	assert.EqualValues(t, 500000000, election.Ended.Nanos)
+	assert.EqualValues(t, 2500, election.DurationMs)
+}
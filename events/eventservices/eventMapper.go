@@ -32,6 +32,12 @@ func MapToFactomEvent(eventInput eventinput.EventInput, broadcastContent eventco
 	case *eventinput.AnchorEvent:
 		anchorEvent := eventInput.(*eventinput.AnchorEvent)
 		return mapAnchorEvent(anchorEvent)
+	case *eventinput.AnchorRecordEvent:
+		anchorRecordEvent := eventInput.(*eventinput.AnchorRecordEvent)
+		return mapAnchorRecordEvent(anchorRecordEvent)
+	case *eventinput.ElectionEvent:
+		electionEvent := eventInput.(*eventinput.ElectionEvent)
+		return mapElectionEvent(electionEvent)
 	case *eventinput.ProcessListEvent:
 		processMessageEvent := eventInput.(*eventinput.ProcessListEvent)
 		return mapProcessMessageEvent(processMessageEvent)
@@ -143,6 +149,26 @@ func mapAnchorEvent(anchorEvent *eventinput.AnchorEvent) (*eventmessages.FactomE
 	return event, nil
 }
 
+func mapAnchorRecordEvent(anchorRecordEvent *eventinput.AnchorRecordEvent) (*eventmessages.FactomEvent, error) {
+	event := &eventmessages.FactomEvent{}
+	event.EventSource = anchorRecordEvent.GetStreamSource()
+	anchorRef := anchorRecordEvent.GetPayload()
+	if anchorRef != nil {
+		event.Event = mapDirectoryBlockAnchorRecord(anchorRef)
+	}
+	return event, nil
+}
+
+func mapElectionEvent(electionEvent *eventinput.ElectionEvent) (*eventmessages.FactomEvent, error) {
+	event := &eventmessages.FactomEvent{}
+	event.EventSource = electionEvent.GetStreamSource()
+	record := electionEvent.GetPayload()
+	if record != nil {
+		event.Event = mapElectionRecord(record)
+	}
+	return event, nil
+}
+
 func mapProcessMessageEvent(processMessageEvent *eventinput.ProcessListEvent) (*eventmessages.FactomEvent, error) {
 	event := &eventmessages.FactomEvent{
 		EventSource: processMessageEvent.GetStreamSource(),
@@ -252,6 +252,73 @@ func TestAnchorEventMapping(t *testing.T) {
 	assert.True(t, dirBlockAnchorEvent.EthereumConfirmed)
 }
 
+func TestAnchorRecordEventMapping(t *testing.T) {
+	anchorRef := &interfaces.AnchorRef{
+		Chain:       interfaces.AnchorChainEthereum,
+		EntryHash:   primitives.RandomHash().String(),
+		DBHeight:    110,
+		DBHeightMin: 101,
+		DBHeightMax: 110,
+		WindowMR:    primitives.RandomHash().String(),
+		TxID:        "0xb9b3e1b1",
+		BlockHeight: 9000000,
+		TxIndex:     12,
+	}
+	inputEvent := eventinput.NewAnchorRecordEvent(eventmessages.EventSource_LIVE, anchorRef)
+	event, err := eventservices.MapToFactomEvent(inputEvent, eventconfig.BroadcastAlways, true)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.IsType(t, &eventmessages.FactomEvent_DirectoryBlockAnchorRecord{}, event.Event)
+	record := event.GetDirectoryBlockAnchorRecord()
+	assert.Equal(t, eventmessages.AnchorChain_ETHEREUM, record.Chain)
+	assert.Len(t, record.EntryHash, 32)
+	assert.Len(t, record.WindowMerkleRoot, 32)
+	assert.Nil(t, record.KeyMerkleRoot)
+	assert.EqualValues(t, 110, record.BlockHeight)
+	assert.EqualValues(t, 101, record.BlockHeightMin)
+	assert.EqualValues(t, 110, record.BlockHeightMax)
+	assert.Equal(t, "0xb9b3e1b1", record.TxID)
+	assert.EqualValues(t, 9000000, record.AnchorBlockHeight)
+	assert.EqualValues(t, 12, record.TxIndex)
+
+	data, err := event.Marshal()
+	if assert.Nil(t, err) {
+		unmarshalled := new(eventmessages.FactomEvent)
+		assert.Nil(t, unmarshalled.Unmarshal(data))
+		assert.Equal(t, record.TxID, unmarshalled.GetDirectoryBlockAnchorRecord().GetTxID())
+	}
+}
+
+func TestElectionEventMapping(t *testing.T) {
+	record := &interfaces.ElectionRecord{
+		ID:        1,
+		DBHeight:  110,
+		Minute:    4,
+		FaultedID: primitives.RandomHash().String(),
+		Started:   1571000000000,
+		Outcome:   interfaces.ElectionAbandoned,
+	}
+	inputEvent := eventinput.NewElectionEvent(eventmessages.EventSource_LIVE, record)
+	event, err := eventservices.MapToFactomEvent(inputEvent, eventconfig.BroadcastAlways, true)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.IsType(t, &eventmessages.FactomEvent_ElectionRecord{}, event.Event)
+	election := event.GetElectionRecord()
+	assert.Equal(t, eventmessages.ElectionOutcome_ABANDONED, election.Outcome)
+	assert.EqualValues(t, 110, election.BlockHeight)
+	assert.EqualValues(t, 4, election.Minute)
+	assert.Len(t, election.FaultedIdentityChainID, 32)
+
+	data, err := event.Marshal()
+	if assert.Nil(t, err) {
+		unmarshalled := new(eventmessages.FactomEvent)
+		assert.Nil(t, unmarshalled.Unmarshal(data))
+		assert.Equal(t, election.FaultedIdentityChainID, unmarshalled.GetElectionRecord().GetFaultedIdentityChainID())
+	}
+}
+
 func TestMapToFactomEvent(t *testing.T) {
 	testCases := map[string]struct {
 		Input                    eventinput.EventInput
@@ -15,6 +15,22 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "devnet" {
+		os.Exit(engine.RunDevnet(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "genesis" {
+		os.Exit(engine.RunGenesis(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(engine.RunReplay(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "receipt" {
+		os.Exit(engine.RunReceipt(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "identity" {
+		os.Exit(engine.RunIdentity(os.Args[2:]))
+	}
+
 	fmt.Println("Command Line Arguments:")
 
 	for _, v := range os.Args[1:] {
@@ -24,6 +40,10 @@ func main() {
 	params := engine.ParseCmdLine(os.Args[1:])
 	params.PrettyPrint()
 
+	if params.Scenario != "" {
+		os.Exit(engine.RunScenarioFile(params.Scenario))
+	}
+
 	state := engine.Factomd(params)
 	for state.GetRunState() != runstate.Stopped {
 		time.Sleep(time.Second)
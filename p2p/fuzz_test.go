@@ -0,0 +1,102 @@
+package p2p
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io/ioutil"
+	"testing"
+)
+
+// go test -fuzz=FuzzProtocolV11 ./p2p/
+
+// fuzzSeed returns what a peer sends over a protocol: its handshake, a message and a peer share
+func fuzzSeed(f *testing.F, write func(rw *testRW) Protocol) {
+	conf := DefaultP2PConfiguration()
+	buf := new(bytes.Buffer)
+	prot := write(newTestRW(buf, buf))
+	if err := prot.SendHandshake(newHandshake(&conf, 12345)); err != nil {
+		f.Fatal(err)
+	}
+	if err := prot.Send(NewParcel("", []byte("a factom message"))); err != nil {
+		f.Fatal(err)
+	}
+	share, err := prot.MakePeerShare([]Endpoint{{IP: "10.0.0.1", Port: "8108"}, {IP: "10.0.0.2", Port: "8110"}})
+	if err != nil {
+		f.Fatal(err)
+	}
+	if err := prot.Send(newParcel(TypePeerResponse, share)); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buf.Bytes())
+}
+
+// fuzzReceive reads parcels from a peer the way a connection does, till the data runs out
+func fuzzReceive(prot Protocol) {
+	for {
+		p, err := prot.Receive()
+		if err != nil {
+			return
+		}
+		if p.Valid() != nil || p.ptype != TypePeerResponse {
+			continue
+		}
+		if eps, err := prot.ParsePeerShare(p.Payload); err == nil {
+			for _, ep := range eps {
+				ep.Valid()
+			}
+		}
+	}
+}
+
+func FuzzProtocolV9(f *testing.F) {
+	conf := DefaultP2PConfiguration()
+	fuzzSeed(f, func(rw *testRW) Protocol {
+		return newProtocolV9(conf.Network, conf.NodeID, conf.ListenPort, gob.NewDecoder(rw), gob.NewEncoder(rw))
+	})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		prot := newProtocolV9(conf.Network, conf.NodeID, conf.ListenPort, gob.NewDecoder(bytes.NewReader(data)), gob.NewEncoder(ioutil.Discard))
+		hs, err := prot.ReadHandshake()
+		if err != nil {
+			return
+		}
+		hs.Valid(&conf)
+		fuzzReceive(prot)
+	})
+}
+
+func FuzzProtocolV10(f *testing.F) {
+	conf := DefaultP2PConfiguration()
+	fuzzSeed(f, func(rw *testRW) Protocol {
+		return newProtocolV10(gob.NewDecoder(rw), gob.NewEncoder(rw))
+	})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// A V10 peer is told apart by the V9 handshake it sends first
+		decoder := gob.NewDecoder(bytes.NewReader(data))
+		v9 := newProtocolV9(conf.Network, conf.NodeID, conf.ListenPort, decoder, gob.NewEncoder(ioutil.Discard))
+		hs, err := v9.ReadHandshake()
+		if err != nil {
+			return
+		}
+		hs.Valid(&conf)
+		fuzzReceive(newProtocolV10(decoder, gob.NewEncoder(ioutil.Discard)))
+	})
+}
+
+func FuzzProtocolV11(f *testing.F) {
+	conf := DefaultP2PConfiguration()
+	fuzzSeed(f, func(rw *testRW) Protocol {
+		return newProtocolV11(rw)
+	})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		prot := newProtocolV11(newTestRW(bytes.NewReader(data), ioutil.Discard))
+		hs, err := prot.ReadHandshake()
+		if err != nil {
+			return
+		}
+		hs.Valid(&conf)
+		fuzzReceive(prot)
+	})
+}
@@ -0,0 +1,226 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package receipts
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// How many directory blocks past the one of a receipt are searched for its anchors
+const AnchorSearchDepth = 1000
+
+// AnchorJSON is an anchor record of the directory block of a receipt, with the
+// signed anchor chain entry it was read from.  An anchor for a window of blocks has
+// the branch from the KeyMR of the directory block to the WindowMR of the record.
+type AnchorJSON struct {
+	ChainID      string                   `json:"chainid"`
+	Entry        string                   `json:"entry"`
+	Record       *anchor.AnchorRecord     `json:"record"`
+	WindowBranch []*primitives.MerkleNode `json:"windowbranch,omitempty"`
+}
+
+func (e *AnchorJSON) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *AnchorJSON) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (e *AnchorJSON) String() string {
+	str, _ := e.JSONString()
+	return str
+}
+
+func (e *AnchorJSON) IsSameAs(r *AnchorJSON) bool {
+	if r == nil {
+		return false
+	}
+	return e.ChainID == r.ChainID && e.Entry == r.Entry
+}
+
+// AddAnchors searches the Bitcoin and Ethereum anchor chains for the first record
+// of each that anchors the directory block of the receipt, and is signed by one of
+// the keys of its chain
+func AddAnchors(dbo interfaces.DBOverlaySimple, receipt *Receipt, bitcoinKeys, ethereumKeys []interfaces.Verifier) error {
+	if receipt.DirectoryBlockKeyMR == nil {
+		return fmt.Errorf("Receipt has no DirectoryBlockKeyMR")
+	}
+	receipt.Anchors = nil
+
+	keys := map[string][]interfaces.Verifier{
+		databaseOverlay.BitcoinAnchorChainID:  bitcoinKeys,
+		databaseOverlay.EthereumAnchorChainID: ethereumKeys,
+	}
+	found := make(map[string]bool)
+	height := receipt.DirectoryBlockHeight
+	for i := height + 1; i <= height+AnchorSearchDepth && len(found) < len(keys); i++ {
+		dBlock, err := dbo.FetchDBlockByHeight(i)
+		if err != nil {
+			return err
+		} else if dBlock == nil {
+			break
+		}
+
+		for _, dbEntry := range dBlock.GetDBEntries() {
+			chainID := dbEntry.GetChainID().String()
+			if _, ok := keys[chainID]; !ok || found[chainID] {
+				continue
+			}
+			eBlock, err := dbo.FetchEBlock(dbEntry.GetKeyMR())
+			if err != nil {
+				return err
+			} else if eBlock == nil {
+				continue
+			}
+			for _, entryHash := range eBlock.GetEntryHashes() {
+				if entryHash.IsMinuteMarker() {
+					continue
+				}
+				entry, err := dbo.FetchEntry(entryHash)
+				if err != nil {
+					return err
+				} else if entry == nil {
+					continue
+				}
+				a, err := newAnchorJSON(dbo, receipt, entry, keys[chainID])
+				if err != nil {
+					return err
+				} else if a != nil {
+					receipt.Anchors = append(receipt.Anchors, a)
+					found[chainID] = true
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// newAnchorJSON returns the anchor of the entry if it is a signed record that
+// anchors the directory block of the receipt, or nil
+func newAnchorJSON(dbo interfaces.DBOverlaySimple, receipt *Receipt, entry interfaces.IEBEntry, keys []interfaces.Verifier) (*AnchorJSON, error) {
+	record, valid, _ := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, keys)
+	if !valid || record == nil {
+		return nil, nil
+	}
+
+	a := new(AnchorJSON)
+	a.ChainID = entry.GetChainID().String()
+	a.Record = record
+	if record.WindowMR == "" {
+		if record.DBHeight != receipt.DirectoryBlockHeight || record.KeyMR != receipt.DirectoryBlockKeyMR.String() {
+			return nil, nil
+		}
+	} else {
+		height := receipt.DirectoryBlockHeight
+		if height < record.DBHeightMin || height > record.DBHeightMax {
+			return nil, nil
+		}
+		var window []interfaces.IHash
+		for i := record.DBHeightMin; i <= record.DBHeightMax; i++ {
+			keyMR, err := dbo.FetchDBKeyMRByHeight(i)
+			if err != nil {
+				return nil, err
+			} else if keyMR == nil {
+				return nil, nil
+			}
+			window = append(window, keyMR)
+		}
+		if primitives.ComputeMerkleRoot(window).String() != record.WindowMR {
+			return nil, nil
+		}
+		a.WindowBranch = primitives.BuildMerkleBranch(window, int(height-record.DBHeightMin), true)
+	}
+
+	raw, err := entry.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	a.Entry = hex.EncodeToString(raw)
+	return a, nil
+}
+
+// ValidateAnchors checks each anchor of the receipt is a record signed by one of
+// the keys of its chain, that anchors the directory block of the receipt
+func (e *Receipt) ValidateAnchors(bitcoinKeys, ethereumKeys []interfaces.Verifier) error {
+	if e.DirectoryBlockKeyMR == nil {
+		return fmt.Errorf("Receipt has no DirectoryBlockKeyMR")
+	}
+	for i, a := range e.Anchors {
+		var keys []interfaces.Verifier
+		switch a.ChainID {
+		case databaseOverlay.BitcoinAnchorChainID:
+			keys = bitcoinKeys
+		case databaseOverlay.EthereumAnchorChainID:
+			keys = ethereumKeys
+		default:
+			return fmt.Errorf("Anchor %v/%v is on unknown chain %v", i, len(e.Anchors), a.ChainID)
+		}
+
+		raw, err := hex.DecodeString(a.Entry)
+		if err != nil {
+			return err
+		}
+		entry := entryBlock.NewEntry()
+		if err := entry.UnmarshalBinary(raw); err != nil {
+			return err
+		}
+		if entry.GetChainID().String() != a.ChainID {
+			return fmt.Errorf("Anchor %v/%v entry is not on chain %v", i, len(e.Anchors), a.ChainID)
+		}
+		record, valid, err := anchor.UnmarshalAndValidateAnchorEntryAnyVersion(entry, keys)
+		if err != nil {
+			return err
+		} else if !valid || record == nil {
+			return fmt.Errorf("Anchor %v/%v is not signed by a key of its chain", i, len(e.Anchors))
+		}
+		if a.Record != nil && record.IsSame(a.Record) == false {
+			return fmt.Errorf("Anchor %v/%v record is not the one in its entry", i, len(e.Anchors))
+		}
+
+		if record.WindowMR == "" {
+			if record.DBHeight != e.DirectoryBlockHeight || record.KeyMR != e.DirectoryBlockKeyMR.String() {
+				return fmt.Errorf("Anchor %v/%v is of directory block %v", i, len(e.Anchors), record.KeyMR)
+			}
+			continue
+		}
+		if e.DirectoryBlockHeight < record.DBHeightMin || e.DirectoryBlockHeight > record.DBHeightMax {
+			return fmt.Errorf("Anchor %v/%v is of directory blocks %v to %v", i, len(e.Anchors), record.DBHeightMin, record.DBHeightMax)
+		}
+		tops, err := climbBranch(e.DirectoryBlockKeyMR, a.WindowBranch)
+		if err != nil {
+			return err
+		}
+		top := interfaces.IHash(e.DirectoryBlockKeyMR)
+		if len(tops) > 0 {
+			top = tops[len(tops)-1]
+		}
+		if top.String() != record.WindowMR {
+			return fmt.Errorf("Anchor %v/%v WindowMR not found in branch", i, len(e.Anchors))
+		}
+	}
+	return nil
+}
+
+// AnchorKeysFromHex parses the hex public keys anchor records are signed with
+func AnchorKeysFromHex(publicKeys []string) ([]interfaces.Verifier, error) {
+	var keys []interfaces.Verifier
+	for _, v := range publicKeys {
+		publicKey := new(primitives.PublicKey)
+		if err := publicKey.UnmarshalText([]byte(v)); err != nil {
+			return nil, err
+		}
+		keys = append(keys, publicKey)
+	}
+	return keys, nil
+}
@@ -9,23 +9,58 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 )
 
+// A Receipt proves an entry, or a factoid or entry credit transaction, is in a
+// directory block.  The MerkleBranch climbs from the entry to the KeyMR of its
+// entry block, or from the factoid transaction to the KeyMR of its factoid block,
+// and on to the KeyMR of the directory block.  An entry credit block isn't a merkle
+// tree, so a receipt for a commit carries the whole block, and the branch starts
+// at the hash of its header.  The Anchors, if any, are the signed records of the
+// directory block being written to Bitcoin and Ethereum.
 type Receipt struct {
 	Entry                *EntryJSON               `json:"entry,omitempty"`
+	Transaction          *TransactionJSON         `json:"transaction,omitempty"`
 	MerkleBranch         []*primitives.MerkleNode `json:"merklebranch,omitempty"`
 	EntryBlockKeyMR      *primitives.Hash         `json:"entryblockkeymr,omitempty"`
+	FactoidBlockKeyMR    *primitives.Hash         `json:"factoidblockkeymr,omitempty"`
+	EntryCreditBlock     string                   `json:"entrycreditblock,omitempty"`
 	DirectoryBlockKeyMR  *primitives.Hash         `json:"directoryblockkeymr,omitempty"`
 	DirectoryBlockHeight uint32                   `json:"directoryblockheight,omitempty"`
+	Anchors              []*AnchorJSON            `json:"anchors,omitempty"`
+}
+
+// leaf returns the hash the MerkleBranch starts from
+func (e *Receipt) leaf() (interfaces.IHash, error) {
+	if e.Entry != nil {
+		return primitives.NewShaHashFromStr(e.Entry.EntryHash)
+	}
+	if e.Transaction == nil {
+		return nil, fmt.Errorf("Receipt has no entry or transaction")
+	}
+	if e.EntryCreditBlock == "" {
+		return primitives.NewShaHashFromStr(e.Transaction.Hash)
+	}
+
+	ecBlock, err := e.entryCreditBlock()
+	if err != nil {
+		return nil, err
+	}
+	return ecBlock.HeaderHash()
 }
 
 func (e *Receipt) TrimReceipt() {
 	if e == nil {
 		return
 	}
-	entry, _ := primitives.NewShaHashFromStr(e.Entry.EntryHash)
+	entry, err := e.leaf()
+	if err != nil {
+		return
+	}
 	for i := range e.MerkleBranch {
 		if entry.IsSameAs(e.MerkleBranch[i].Left) {
 			e.MerkleBranch[i].Left = nil
@@ -43,34 +78,77 @@ func (e *Receipt) Validate() error {
 	if e == nil {
 		return fmt.Errorf("No receipt provided")
 	}
-	if e.Entry == nil {
-		return fmt.Errorf("Receipt has no entry")
+	if e.Entry == nil && e.Transaction == nil {
+		return fmt.Errorf("Receipt has no entry or transaction")
 	}
 	if e.MerkleBranch == nil {
 		return fmt.Errorf("Receipt has no MerkleBranch")
 	}
-	if e.EntryBlockKeyMR == nil {
-		return fmt.Errorf("Receipt has no EntryBlockKeyMR")
+	// The KeyMR of the block the entry or transaction is in, if it is a merkle tree
+	var blockKeyMR *primitives.Hash
+	switch {
+	case e.Entry != nil:
+		if e.EntryBlockKeyMR == nil {
+			return fmt.Errorf("Receipt has no EntryBlockKeyMR")
+		}
+		blockKeyMR = e.EntryBlockKeyMR
+	case e.EntryCreditBlock == "":
+		if e.FactoidBlockKeyMR == nil {
+			return fmt.Errorf("Receipt has no FactoidBlockKeyMR")
+		}
+		blockKeyMR = e.FactoidBlockKeyMR
 	}
 	if e.DirectoryBlockKeyMR == nil {
 		return fmt.Errorf("Receipt has no DirectoryBlockKeyMR")
 	}
-	entryHash, err := primitives.NewShaHashFromStr(e.Entry.EntryHash)
-	//TODO: validate entry hashes into EntryHash
+	if err := e.validateRaw(); err != nil {
+		return err
+	}
+	leaf, err := e.leaf()
+	if err != nil {
+		return err
+	}
 
+	tops, err := climbBranch(leaf, e.MerkleBranch)
 	if err != nil {
 		return err
 	}
+	blockFound := blockKeyMR == nil
+	dBlockFound := false
+	for _, top := range tops {
+		if blockKeyMR != nil && top.IsSameAs(blockKeyMR) {
+			blockFound = true
+		}
+		if top.IsSameAs(e.DirectoryBlockKeyMR) {
+			dBlockFound = true
+		}
+	}
+
+	if blockFound == false {
+		if e.Entry != nil {
+			return fmt.Errorf("EntryBlockKeyMR not found in branch")
+		}
+		return fmt.Errorf("FactoidBlockKeyMR not found in branch")
+	}
+
+	if dBlockFound == false {
+		return fmt.Errorf("DirectoryBlockKeyMR not found in branch")
+	}
+
+	return nil
+}
+
+// climbBranch hashes its way up the branch from the leaf, and returns the top of
+// each node
+func climbBranch(leaf interfaces.IHash, branch []*primitives.MerkleNode) ([]interfaces.IHash, error) {
 	var left interfaces.IHash
 	var right interfaces.IHash
-	var currentEntry interfaces.IHash
-	currentEntry = entryHash
-	eBlockFound := false
-	dBlockFound := false
-	for i, node := range e.MerkleBranch {
+	var tops []interfaces.IHash
+	currentEntry := leaf
+	for i, node := range branch {
 		if node.Left == nil {
 			if node.Right == nil {
-				return fmt.Errorf("Node %v/%v has two nil sides", i, len(e.MerkleBranch))
+				return nil, fmt.Errorf("Node %v/%v has two nil sides", i, len(branch))
 			}
 			left = currentEntry
 			right = node.Right
@@ -82,32 +160,53 @@ func (e *Receipt) Validate() error {
 				right = node.Right
 			}
 		}
-		if left.IsSameAs(currentEntry) == false && left.IsSameAs(currentEntry) {
-			return fmt.Errorf("Entry %v not found in node %v/%v", currentEntry, i, len(e.MerkleBranch))
+		if left.IsSameAs(currentEntry) == false && right.IsSameAs(currentEntry) == false {
+			return nil, fmt.Errorf("Entry %v not found in node %v/%v", currentEntry, i, len(branch))
 		}
 		top := primitives.HashMerkleBranches(left, right)
 		if node.Top != nil {
 			if top.IsSameAs(node.Top) == false {
-				return fmt.Errorf("Derived top %v is not the same as saved top in node %v/%v", top, i, len(e.MerkleBranch))
+				return nil, fmt.Errorf("Derived top %v is not the same as saved top in node %v/%v", top, i, len(branch))
 			}
 		}
-		if top.IsSameAs(e.EntryBlockKeyMR) == true {
-			eBlockFound = true
-		}
-		if top.IsSameAs(e.DirectoryBlockKeyMR) == true {
-			dBlockFound = true
-		}
+		tops = append(tops, top)
 		currentEntry = top
 	}
+	return tops, nil
+}
 
-	if eBlockFound == false {
-		return fmt.Errorf("EntryBlockKeyMR not found in branch")
+// validateRaw checks the raw entry or transaction, if the receipt has one, hashes
+// to what the receipt proves
+func (e *Receipt) validateRaw() error {
+	if e.Entry != nil && e.Entry.Raw != "" {
+		raw, err := hex.DecodeString(e.Entry.Raw)
+		if err != nil {
+			return err
+		}
+		entry := entryBlock.NewEntry()
+		if err := entry.UnmarshalBinary(raw); err != nil {
+			return err
+		}
+		if entry.GetHash().String() != e.Entry.EntryHash {
+			return fmt.Errorf("Raw entry does not hash to %v", e.Entry.EntryHash)
+		}
 	}
-
-	if dBlockFound == false {
-		return fmt.Errorf("DirectoryBlockKeyMR not found in branch")
+	if e.Transaction != nil && e.Transaction.Raw != "" {
+		raw, err := hex.DecodeString(e.Transaction.Raw)
+		if err != nil {
+			return err
+		}
+		if e.EntryCreditBlock != "" {
+			return e.validateRawCommit(raw)
+		}
+		tx := new(factoid.Transaction)
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return err
+		}
+		if tx.GetHash().String() != e.Transaction.Hash {
+			return fmt.Errorf("Raw transaction does not hash to %v", e.Transaction.Hash)
+		}
 	}
-
 	return nil
 }
 
@@ -181,6 +280,39 @@ func (e *Receipt) IsSameAs(r *Receipt) bool {
 		}
 	}
 
+	if e.Transaction == nil {
+		if r.Transaction != nil {
+			return false
+		}
+	} else {
+		if e.Transaction.IsSameAs(r.Transaction) == false {
+			return false
+		}
+	}
+
+	if e.FactoidBlockKeyMR == nil {
+		if r.FactoidBlockKeyMR != nil {
+			return false
+		}
+	} else {
+		if e.FactoidBlockKeyMR.IsSameAs(r.FactoidBlockKeyMR) == false {
+			return false
+		}
+	}
+
+	if e.EntryCreditBlock != r.EntryCreditBlock {
+		return false
+	}
+
+	if len(e.Anchors) != len(r.Anchors) {
+		return false
+	}
+	for i := range e.Anchors {
+		if e.Anchors[i].IsSameAs(r.Anchors[i]) == false {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -329,24 +461,10 @@ func CreateReceipt(dbo interfaces.DBOverlaySimple, entryHash interfaces.IHash, i
 	} else if dBlock == nil {
 		return nil, fmt.Errorf("DBlock not found")
 	}
-
-	dBlockEntries := dBlock.GetEntryHashesForBranch()
-	branch = primitives.BuildMerkleBranchForHash(dBlockEntries, receipt.EntryBlockKeyMR, true)
-	blockNode = new(primitives.MerkleNode)
-	left, err = dBlock.GetHeaderHash()
+	err = receipt.addDirectoryBlock(dBlock, receipt.EntryBlockKeyMR)
 	if err != nil {
 		return nil, err
 	}
-	blockNode.Left = left.(*primitives.Hash)
-	blockNode.Right = dBlock.BodyKeyMR().(*primitives.Hash)
-	blockNode.Top = hash.(*primitives.Hash)
-	branch = append(branch, blockNode)
-	receipt.MerkleBranch = append(receipt.MerkleBranch, branch...)
-
-	// Directory Block Info
-	hash = dBlock.DatabasePrimaryIndex()
-	receipt.DirectoryBlockKeyMR = hash.(*primitives.Hash)
-	receipt.DirectoryBlockHeight = dBlock.GetDatabaseHeight()
 
 	// Now that we have enough info available, find entry timestamp
 	mins := make(map[string]uint8) // create a map of possible minute markers
@@ -370,6 +488,32 @@ func CreateReceipt(dbo interfaces.DBOverlaySimple, entryHash interfaces.IHash, i
 	return receipt, nil
 }
 
+// addDirectoryBlock climbs the branch from the KeyMR of a block in the directory
+// block to the KeyMR of the directory block
+func (e *Receipt) addDirectoryBlock(dBlock interfaces.IDirectoryBlock, blockKeyMR interfaces.IHash) error {
+	dBlockEntries := dBlock.GetEntryHashesForBranch()
+	branch := primitives.BuildMerkleBranchForHash(dBlockEntries, blockKeyMR, true)
+	if branch == nil {
+		return fmt.Errorf("Block %v not found in DBlock", blockKeyMR)
+	}
+	blockNode := new(primitives.MerkleNode)
+	left, err := dBlock.GetHeaderHash()
+	if err != nil {
+		return err
+	}
+	hash := dBlock.DatabasePrimaryIndex()
+	blockNode.Left = left.(*primitives.Hash)
+	blockNode.Right = dBlock.BodyKeyMR().(*primitives.Hash)
+	blockNode.Top = hash.(*primitives.Hash)
+	branch = append(branch, blockNode)
+	e.MerkleBranch = append(e.MerkleBranch, branch...)
+
+	// Directory Block Info
+	e.DirectoryBlockKeyMR = hash.(*primitives.Hash)
+	e.DirectoryBlockHeight = dBlock.GetDatabaseHeight()
+	return nil
+}
+
 func VerifyFullReceipt(dbo interfaces.DBOverlaySimple, receiptStr string) error {
 	receipt, err := DecodeReceiptString(receiptStr)
 	if err != nil {
@@ -5,9 +5,15 @@
 package receipts_test
 
 import (
+	"encoding/hex"
 	"testing"
 
+	"github.com/FactomProject/factomd/anchor"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
 	. "github.com/FactomProject/factomd/receipts"
 	. "github.com/FactomProject/factomd/testHelper"
 )
@@ -64,7 +70,7 @@ func TestReceipts(t *testing.T) {
 }
 
 func TestDecodeReceiptString(t *testing.T) {
-	receiptStr := `{"directoryblockkeymr":"bdadd16c5335c369a1b784212f80764e1f47805c89d39141bd40d05153edcdf5","entry":{"key":"cf9503fad6a6cf3cf6d7a5a491e23d84f9dee6dacb8c12f428633995655bd0d0"},"entryblockkeymr":"905740850540f1d17fcb1fc7fd0c61a33150b2cdc0f88334f6a891ec34bd1cfc","merklebranch":[{"left":"0a2f96c96ea89ee82908be9f5aef2be4b533a32ffb3855aeb3b8327f9e989f3a","right":"cf9503fad6a6cf3cf6d7a5a491e23d84f9dee6dacb8c12f428633995655bd0d0","top":"905740850540f1d17fcb1fc7fd0c61a33150b2cdc0f88334f6a891ec34bd1cfc"},{"left":"6e7e64ac45ff57edbf8537a0c99fba2e9ee351ef3d3f4abd93af9f01107e592c","right":"905740850540f1d17fcb1fc7fd0c61a33150b2cdc0f88334f6a891ec34bd1cfc","top":"4f477201a150694ed0f85fee17c41282542f976fae479a4de553a37747b09f41"},{"left":"4f477201a150694ed0f85fee17c41282542f976fae479a4de553a37747b09f41","right":"18ab692a40f370e9529c180f2476684ccde4937b9a4b4605805e3f51e592f632","top":"890003f0db6cceca94031a70745fd83845726987cffa6fc95ddb0e2f6c64b499"},{"left":"1857570da9a1c93dac4993d3048faa80d1d1d939f4fc44a38e61781fdc123165","right":"890003f0db6cceca94031a70745fd83845726987cffa6fc95ddb0e2f6c64b499","top":"4d8ed632f7852a07055a0592c341b957815bdd46e82d2da7bdf58be54fc60bf9"},{"left":"4d8ed632f7852a07055a0592c341b957815bdd46e82d2da7bdf58be54fc60bf9","right":"f955a2709628086d656257885bf27b7c054a6acd0b3ebf5b769b3cf036ab04ee","top":"d6bd24e979e81feddb319483878c678865a80175d1954e5429f2d799eadd1bc9"},{"left":"49a5c28516f3c4d5e44f5cf0b2e5f5f00ca1187714dd9ee914e7df1eb7702972","right":"d6bd24e979e81feddb319483878c678865a80175d1954e5429f2d799eadd1bc9","top":"bdadd16c5335c369a1b784212f80764e1f47805c89d39141bd40d05153edcdf5"}]}`
+	receiptStr := `{"directoryblockkeymr":"bdadd16c5335c369a1b784212f80764e1f47805c89d39141bd40d05153edcdf5","entry":{"entryhash":"cf9503fad6a6cf3cf6d7a5a491e23d84f9dee6dacb8c12f428633995655bd0d0"},"entryblockkeymr":"905740850540f1d17fcb1fc7fd0c61a33150b2cdc0f88334f6a891ec34bd1cfc","merklebranch":[{"left":"0a2f96c96ea89ee82908be9f5aef2be4b533a32ffb3855aeb3b8327f9e989f3a","right":"cf9503fad6a6cf3cf6d7a5a491e23d84f9dee6dacb8c12f428633995655bd0d0","top":"905740850540f1d17fcb1fc7fd0c61a33150b2cdc0f88334f6a891ec34bd1cfc"},{"left":"6e7e64ac45ff57edbf8537a0c99fba2e9ee351ef3d3f4abd93af9f01107e592c","right":"905740850540f1d17fcb1fc7fd0c61a33150b2cdc0f88334f6a891ec34bd1cfc","top":"4f477201a150694ed0f85fee17c41282542f976fae479a4de553a37747b09f41"},{"left":"4f477201a150694ed0f85fee17c41282542f976fae479a4de553a37747b09f41","right":"18ab692a40f370e9529c180f2476684ccde4937b9a4b4605805e3f51e592f632","top":"890003f0db6cceca94031a70745fd83845726987cffa6fc95ddb0e2f6c64b499"},{"left":"1857570da9a1c93dac4993d3048faa80d1d1d939f4fc44a38e61781fdc123165","right":"890003f0db6cceca94031a70745fd83845726987cffa6fc95ddb0e2f6c64b499","top":"4d8ed632f7852a07055a0592c341b957815bdd46e82d2da7bdf58be54fc60bf9"},{"left":"4d8ed632f7852a07055a0592c341b957815bdd46e82d2da7bdf58be54fc60bf9","right":"f955a2709628086d656257885bf27b7c054a6acd0b3ebf5b769b3cf036ab04ee","top":"d6bd24e979e81feddb319483878c678865a80175d1954e5429f2d799eadd1bc9"},{"left":"49a5c28516f3c4d5e44f5cf0b2e5f5f00ca1187714dd9ee914e7df1eb7702972","right":"d6bd24e979e81feddb319483878c678865a80175d1954e5429f2d799eadd1bc9","top":"bdadd16c5335c369a1b784212f80764e1f47805c89d39141bd40d05153edcdf5"}]}`
 	receipt, err := DecodeReceiptString(receiptStr)
 	if err != nil {
 		t.Error(err)
@@ -75,3 +81,170 @@ func TestDecodeReceiptString(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestTransactionReceipts(t *testing.T) {
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+	blocks := CreateFullTestBlockSet()
+	for _, block := range blocks[:len(blocks)-2] {
+		for _, tx := range block.FBlock.GetTransactions() {
+			receipt, err := CreateTransactionReceipt(dbo, tx.GetSigHash(), true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if receipt.Transaction.Hash != tx.GetHash().String() {
+				t.Errorf("Receipt of %v is of %v", tx.GetHash(), receipt.Transaction.Hash)
+			}
+			if receipt.DirectoryBlockKeyMR.IsSameAs(block.DBlock.DatabasePrimaryIndex()) == false {
+				t.Errorf("Receipt of %v is in directory block %v", tx.GetHash(), receipt.DirectoryBlockKeyMR)
+			}
+			err = VerifyFullReceipt(dbo, receipt.CustomMarshalString())
+			if err != nil {
+				t.Error(err)
+			}
+
+			receipt.TrimReceipt()
+			err = VerifyMinimalReceipt(dbo, receipt.CustomMarshalString())
+			if err != nil {
+				t.Error(err)
+			}
+
+			receipt.FactoidBlockKeyMR = primitives.NewZeroHash().(*primitives.Hash)
+			if receipt.Validate() == nil {
+				t.Errorf("Receipt of %v is valid for another factoid block", tx.GetHash())
+			}
+		}
+
+		for _, entry := range block.ECBlock.GetEntries() {
+			if entry.ECID() != constants.ECIDChainCommit && entry.ECID() != constants.ECIDEntryCommit {
+				continue
+			}
+			receipt, err := CreateTransactionReceipt(dbo, entry.Hash(), true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if receipt.EntryCreditBlock == "" {
+				t.Errorf("Receipt of %v has no EntryCreditBlock", entry.Hash())
+			}
+			err = VerifyFullReceipt(dbo, receipt.CustomMarshalString())
+			if err != nil {
+				t.Error(err)
+			}
+
+			receipt.Transaction.Hash = primitives.NewZeroHash().String()
+			if receipt.Validate() == nil {
+				t.Errorf("Receipt of %v is valid for another commit", entry.Hash())
+			}
+		}
+	}
+
+	_, err := CreateTransactionReceipt(dbo, primitives.NewZeroHash(), false)
+	if err == nil {
+		t.Errorf("Created a receipt for a transaction that doesn't exist")
+	}
+}
+
+func TestReceiptAnchors(t *testing.T) {
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+	blocks := CreateFullTestBlockSet()
+	keys := []interfaces.Verifier{NewPrimitivesPrivateKey(0).Pub}
+	other := []interfaces.Verifier{NewPrimitivesPrivateKey(1).Pub}
+
+	entry := blocks[3].Entries[0]
+	receipt, err := CreateFullReceipt(dbo, entry.GetHash(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = AddAnchors(dbo, receipt, keys, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipt.Anchors) != 1 {
+		t.Fatalf("Expected 1 anchor, found %v", len(receipt.Anchors))
+	}
+	if receipt.Anchors[0].Record.DBHeight != receipt.DirectoryBlockHeight {
+		t.Errorf("Anchor is of height %v, not %v", receipt.Anchors[0].Record.DBHeight, receipt.DirectoryBlockHeight)
+	}
+
+	decoded, err := DecodeReceiptString(receipt.CustomMarshalString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.IsSameAs(receipt) == false {
+		t.Errorf("Decoded receipt is not the same")
+	}
+	err = decoded.ValidateAnchors(keys, keys)
+	if err != nil {
+		t.Error(err)
+	}
+	if decoded.ValidateAnchors(other, other) == nil {
+		t.Errorf("Anchor is valid with keys it was not signed by")
+	}
+
+	// An anchor of another block doesn't prove this one
+	receipt, err = CreateFullReceipt(dbo, blocks[2].Entries[0].GetHash(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt.Anchors = decoded.Anchors
+	if receipt.ValidateAnchors(keys, keys) == nil {
+		t.Errorf("Anchor of block %v is valid for block %v", decoded.DirectoryBlockHeight, receipt.DirectoryBlockHeight)
+	}
+
+	// Records not signed by the keys are not anchors
+	err = AddAnchors(dbo, receipt, other, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipt.Anchors) != 0 {
+		t.Errorf("Found %v anchors not signed by the keys", len(receipt.Anchors))
+	}
+}
+
+func TestReceiptWindowAnchor(t *testing.T) {
+	dbo := CreateAndPopulateTestDatabaseOverlay()
+	blocks := CreateFullTestBlockSet()
+	keys := []interfaces.Verifier{NewPrimitivesPrivateKey(0).Pub}
+
+	receipt, err := CreateFullReceipt(dbo, blocks[3].Entries[0].GetHash(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var window []interfaces.IHash
+	for i := 1; i <= 5; i++ {
+		window = append(window, blocks[i].DBlock.DatabasePrimaryIndex())
+	}
+	ar := new(anchor.AnchorRecord)
+	ar.AnchorRecordVer = 2
+	ar.DBHeightMin = 1
+	ar.DBHeightMax = 5
+	ar.DBHeight = 5
+	ar.WindowMR = primitives.ComputeMerkleRoot(window).String()
+	ar.Ethereum = new(anchor.EthereumStruct)
+	data, sig, err := ar.MarshalAndSignV2(NewPrimitivesPrivateKey(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := entryBlock.NewEntry()
+	entry.ChainID, _ = primitives.HexToHash(databaseOverlay.EthereumAnchorChainID)
+	entry.Content = primitives.ByteSlice{Bytes: data}
+	entry.ExtIDs = []primitives.ByteSlice{{Bytes: sig}}
+	raw, err := entry.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &AnchorJSON{ChainID: databaseOverlay.EthereumAnchorChainID, Entry: hex.EncodeToString(raw), Record: ar}
+	a.WindowBranch = primitives.BuildMerkleBranch(window, 2, true)
+	receipt.Anchors = []*AnchorJSON{a}
+	err = receipt.ValidateAnchors(keys, keys)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// The branch of another block of the window
+	a.WindowBranch = primitives.BuildMerkleBranch(window, 1, true)
+	if receipt.ValidateAnchors(keys, keys) == nil {
+		t.Errorf("Window branch of another block is valid")
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package receipts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// TransactionJSON is the factoid transaction or entry credit commit a receipt is
+// for.  The Hash is what the block holds, TxID the hash the transaction is known by.
+type TransactionJSON struct {
+	Raw       string `json:"raw,omitempty"`
+	TxID      string `json:"txid,omitempty"`
+	Hash      string `json:"hash,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+}
+
+func (e *TransactionJSON) JSONByte() ([]byte, error) {
+	return primitives.EncodeJSON(e)
+}
+
+func (e *TransactionJSON) JSONString() (string, error) {
+	return primitives.EncodeJSONString(e)
+}
+
+func (e *TransactionJSON) String() string {
+	str, _ := e.JSONString()
+	return str
+}
+
+func (e *TransactionJSON) IsSameAs(r *TransactionJSON) bool {
+	if r == nil {
+		return false
+	}
+	return *e == *r
+}
+
+// CreateTransactionReceipt creates the receipt of a factoid transaction or entry
+// credit commit, found by its TxID or its hash
+func CreateTransactionReceipt(dbo interfaces.DBOverlaySimple, txHash interfaces.IHash, includeRawTransaction bool) (*Receipt, error) {
+	hash, err := dbo.FetchIncludedIn(txHash)
+	if err != nil {
+		return nil, err
+	} else if hash == nil {
+		return nil, fmt.Errorf("Block containing transaction not found")
+	}
+
+	fBlock, err := dbo.FetchFBlock(hash)
+	if err != nil {
+		return nil, err
+	} else if fBlock != nil {
+		return createFactoidReceipt(dbo, fBlock, txHash, includeRawTransaction)
+	}
+
+	ecBlock, err := dbo.FetchECBlock(hash)
+	if err != nil {
+		return nil, err
+	} else if ecBlock != nil {
+		return createEntryCreditReceipt(dbo, ecBlock, txHash, includeRawTransaction)
+	}
+	return nil, fmt.Errorf("Block containing transaction not found")
+}
+
+func createFactoidReceipt(dbo interfaces.DBOverlaySimple, fBlock interfaces.IFBlock, txHash interfaces.IHash, includeRawTransaction bool) (*Receipt, error) {
+	tx := fBlock.GetTransactionByHash(txHash)
+	if tx == nil {
+		return nil, fmt.Errorf("Transaction not found in FBlock")
+	}
+
+	receipt := new(Receipt)
+	receipt.Transaction = new(TransactionJSON)
+	receipt.Transaction.TxID = tx.GetSigHash().String()
+	receipt.Transaction.Hash = tx.GetHash().String()
+	receipt.Transaction.Timestamp = tx.GetTimestamp().GetTimeSeconds()
+	if includeRawTransaction {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		receipt.Transaction.Raw = hex.EncodeToString(raw)
+	}
+
+	// Factoid Block
+	branch := primitives.BuildMerkleBranchForHash(factoidBlockLeaves(fBlock), tx.GetHash(), true)
+	header, err := fBlock.MarshalHeader()
+	if err != nil {
+		return nil, err
+	}
+	keyMR := fBlock.GetKeyMR()
+	blockNode := new(primitives.MerkleNode)
+	blockNode.Left = primitives.Sha(header).(*primitives.Hash)
+	blockNode.Right = fBlock.GetBodyMR().(*primitives.Hash)
+	blockNode.Top = keyMR.(*primitives.Hash)
+	branch = append(branch, blockNode)
+	receipt.MerkleBranch = append(receipt.MerkleBranch, branch...)
+	receipt.FactoidBlockKeyMR = keyMR.(*primitives.Hash)
+
+	// Directory Block
+	dBlock, err := dbo.FetchDBlockByHeight(fBlock.GetDatabaseHeight())
+	if err != nil {
+		return nil, err
+	} else if dBlock == nil {
+		return nil, fmt.Errorf("DBlock not found")
+	}
+	err = receipt.addDirectoryBlock(dBlock, keyMR)
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// factoidBlockLeaves returns the hashes the body MR of the factoid block is the
+// merkle root of: its transactions, with a marker at the end of each minute
+func factoidBlockLeaves(fBlock interfaces.IFBlock) []interfaces.IHash {
+	transactions := fBlock.GetTransactions()
+	endOfPeriod := fBlock.GetEndOfPeriod()
+	hashes := make([]interfaces.IHash, 0, len(transactions)+len(endOfPeriod))
+	marker := 0
+	for i, trans := range transactions {
+		for marker < len(endOfPeriod) && i != 0 && i == endOfPeriod[marker] {
+			marker++
+			hashes = append(hashes, primitives.Sha(constants.ZERO))
+		}
+		hashes = append(hashes, trans.GetHash())
+	}
+	for marker < len(endOfPeriod) {
+		marker++
+		hashes = append(hashes, primitives.Sha(constants.ZERO))
+	}
+	return hashes
+}
+
+func createEntryCreditReceipt(dbo interfaces.DBOverlaySimple, ecBlock interfaces.IEntryCreditBlock, txHash interfaces.IHash, includeRawTransaction bool) (*Receipt, error) {
+	tx := ecBlock.GetEntryByHash(txHash)
+	if tx == nil {
+		return nil, fmt.Errorf("Transaction not found in ECBlock")
+	}
+
+	receipt := new(Receipt)
+	receipt.Transaction = new(TransactionJSON)
+	receipt.Transaction.Hash = tx.Hash().String()
+	if sigHash := tx.GetSigHash(); sigHash != nil {
+		receipt.Transaction.TxID = sigHash.String()
+	}
+	if ts := tx.GetTimestamp(); ts != nil {
+		receipt.Transaction.Timestamp = ts.GetTimeSeconds()
+	}
+	if includeRawTransaction {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		receipt.Transaction.Raw = hex.EncodeToString(raw)
+	}
+
+	// Entry Credit Block, which is proven whole
+	raw, err := ecBlock.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	receipt.EntryCreditBlock = hex.EncodeToString(raw)
+
+	// Directory Block
+	dBlock, err := dbo.FetchDBlockByHeight(ecBlock.GetDatabaseHeight())
+	if err != nil {
+		return nil, err
+	} else if dBlock == nil {
+		return nil, fmt.Errorf("DBlock not found")
+	}
+	err = receipt.addDirectoryBlock(dBlock, ecBlock.DatabasePrimaryIndex())
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// entryCreditBlock unmarshals the entry credit block of the receipt, and checks
+// the commit is in it
+func (e *Receipt) entryCreditBlock() (interfaces.IEntryCreditBlock, error) {
+	raw, err := hex.DecodeString(e.EntryCreditBlock)
+	if err != nil {
+		return nil, err
+	}
+	ecBlock := entryCreditBlock.NewECBlock()
+	if err := ecBlock.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	hash, err := primitives.NewShaHashFromStr(e.Transaction.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx := ecBlock.GetEntryByHash(hash); tx == nil || tx.Hash().IsSameAs(hash) == false {
+		return nil, fmt.Errorf("Transaction %v not found in EntryCreditBlock", e.Transaction.Hash)
+	}
+	return ecBlock, nil
+}
+
+// validateRawCommit checks the raw commit is the one in the entry credit block
+func (e *Receipt) validateRawCommit(raw []byte) error {
+	ecBlock, err := e.entryCreditBlock()
+	if err != nil {
+		return err
+	}
+	hash, err := primitives.NewShaHashFromStr(e.Transaction.Hash)
+	if err != nil {
+		return err
+	}
+	data, err := ecBlock.GetEntryByHash(hash).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, raw) == false {
+		return fmt.Errorf("Raw transaction is not %v", e.Transaction.Hash)
+	}
+	return nil
+}
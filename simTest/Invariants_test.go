@@ -0,0 +1,25 @@
+package simtest
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/engine"
+
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+// TestInvariants runs a network with the invariant checker, which is off for the other SetupSim
+// tests.  A violation panics, failing the test.
+func TestInvariants(t *testing.T) {
+	state0 := SetupSim("LLLAAF", map[string]string{"--invariants": "true"}, 8, 0, 0, t)
+
+	RunCmd("g5")
+	WaitBlocks(state0, 5)
+	if engine.GetInvariantChecker() == nil {
+		t.Fatal("the invariant checker is not running")
+	}
+	if v := engine.GetInvariantChecker().Violation(); v != nil {
+		t.Fatal(v.String())
+	}
+	ShutDownEverything(t)
+}
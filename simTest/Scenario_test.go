@@ -0,0 +1,62 @@
+package simtest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/engine"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+/*
+Run the scenario files in the scenarios folder.  Only one simulation can run in a process,
+so each file is run by running this test again for just that file.
+
+Run one file with:
+
+	FACTOMD_SCENARIO=scenarios/brainswap.yaml go test -v -run TestScenarios ./simTest/
+*/
+func TestScenarios(t *testing.T) {
+	if file := os.Getenv("FACTOMD_SCENARIO"); file != "" {
+		runScenario(t, file)
+		return
+	}
+
+	var files []string
+	for _, pattern := range []string{"scenarios/*.yaml", "scenarios/*.yml", "scenarios/*.json"} {
+		matches, _ := filepath.Glob(pattern)
+		files = append(files, matches...)
+	}
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestScenarios$", "-test.v", "-test.timeout=0")
+			cmd.Env = append(os.Environ(), "FACTOMD_SCENARIO="+file)
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			if err := cmd.Run(); err != nil {
+				t.Errorf("scenario %s failed: %v", file, err)
+			}
+		})
+	}
+}
+
+func runScenario(t *testing.T, file string) {
+	sc, err := engine.ReadScenario(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	home := filepath.Join(dir, ".sim", "TestScenarios", sc.Name)
+	ResetTestHome(home, t)
+
+	if err := engine.RunScenario(sc, home, t); err != nil {
+		t.Fatal(err)
+	}
+}
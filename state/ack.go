@@ -73,6 +73,9 @@ func (s *State) GetEntryCommitAckByTXID(hash interfaces.IHash) (status int, blkt
 			// TODO: Is this thread safe?
 			for _, v := range pl.VMs {
 				for i, m := range v.List {
+					if m == nil { // A hole waiting for a missing message
+						continue
+					}
 					switch m.Type() {
 					case constants.COMMIT_CHAIN_MSG:
 						cc, ok := m.(*messages.CommitChainMsg)
@@ -159,6 +162,9 @@ func (s *State) GetEntryCommitAckByEntryHash(hash interfaces.IHash) (status int,
 			// TODO: Is this thread safe?
 			for _, v := range pl.VMs {
 				for _, m := range v.List {
+					if m == nil {
+						continue
+					}
 					switch m.Type() {
 					case constants.COMMIT_CHAIN_MSG:
 						cc, ok := m.(*messages.CommitChainMsg)
@@ -213,6 +213,25 @@ func TestUnknownAcks(t *testing.T) {
 	}
 }
 
+func TestAcksSkipProcessListHoles(t *testing.T) {
+	// A process list with a hole where a message is still missing
+	s := CreateAndPopulateTestState()
+	pl := s.ProcessLists.Get(s.PLProcessHeight)
+	if pl == nil {
+		t.Fatalf("no process list at %d", s.PLProcessHeight)
+	}
+	pl.VMs[0].List = append(pl.VMs[0].List, nil)
+
+	status, _, _, _ := s.GetEntryCommitAckByTXID(primitives.RandomHash())
+	if status != constants.AckStatusUnknown {
+		t.Error("Should be unknown")
+	}
+	status, _ = s.GetEntryCommitAckByEntryHash(primitives.RandomHash())
+	if status != constants.AckStatusUnknown {
+		t.Error("Should be unknown")
+	}
+}
+
 func TestDblockConf(t *testing.T) {
 	// All random unknown hashes
 	s := CreateAndPopulateTestStateAndStartValidator()
@@ -0,0 +1,220 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// AddressIndexMaxPage is the most transactions returned by one call to History
+var AddressIndexMaxPage uint32 = 1000
+
+// AddressIndex keeps the history of every factoid and entry credit address in the database:
+// each transaction that changed its balance, by how much, and the balance after it.
+//
+// Blocks are added as they are saved once the index has caught up.  Until then Backfill
+// builds the index from the blocks already in the database.  Factoid balances come from the
+// fblocks, entry credit balances from the ecblocks (purchases are the balance increases, so
+// the exchange rate of the time is already applied).
+type AddressIndex struct {
+	*blockIndexer
+}
+
+// NewAddressIndex creates an AddressIndex for the state's database
+func NewAddressIndex(s *State) *AddressIndex {
+	ai := new(AddressIndex)
+	ai.blockIndexer = newBlockIndexer(s, "addressindex")
+	ai.load = ai.loadHeight
+	ai.add = ai.addHeight
+	return ai
+}
+
+func (ai *AddressIndex) overlay() (*databaseOverlay.Overlay, error) {
+	dbo, ok := ai.s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return nil, fmt.Errorf("the address index needs a database overlay, not %T", ai.s.DB)
+	}
+	return dbo, nil
+}
+
+func (ai *AddressIndex) loadHeight() (uint32, error) {
+	dbo, err := ai.overlay()
+	if err != nil {
+		return 0, err
+	}
+	return dbo.FetchAddressHistoryHeight()
+}
+
+// addHeight adds the transactions of a height to the index
+func (ai *AddressIndex) addHeight(dbheight uint32, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) error {
+	dbo, err := ai.overlay()
+	if err != nil {
+		return err
+	}
+
+	updates := make(map[[33]byte]*databaseOverlay.AddressHistoryUpdate)
+	var order []*databaseOverlay.AddressHistoryUpdate // keep the batch in a deterministic order
+
+	record := func(ec bool, address [32]byte, txid interfaces.IHash, delta int64) error {
+		var key [33]byte
+		if ec {
+			key[0] = 1
+		}
+		copy(key[1:], address[:])
+
+		u := updates[key]
+		if u == nil {
+			u = &databaseOverlay.AddressHistoryUpdate{EC: ec, Address: address}
+			u.Summary, err = dbo.FetchAddressHistorySummary(ec, address)
+			if err != nil {
+				return err
+			}
+			if u.Summary == nil {
+				u.Summary = new(databaseOverlay.AddressHistorySummary)
+			}
+			updates[key] = u
+			order = append(order, u)
+		}
+		u.Summary.Count++
+		u.Summary.Balance += delta
+		u.Records = append(u.Records, &databaseOverlay.AddressHistoryRecord{DBHeight: dbheight, TxID: txid, Delta: delta, Balance: u.Summary.Balance})
+		return nil
+	}
+
+	for _, tx := range fblock.GetTransactions() {
+		// One record per address per transaction, even if the address is both an input and an output
+		deltas := make(map[[32]byte]int64)
+		var addresses [][32]byte
+		add := func(address [32]byte, delta int64) {
+			if _, ok := deltas[address]; !ok {
+				addresses = append(addresses, address)
+			}
+			deltas[address] += delta
+		}
+		for _, in := range tx.GetInputs() {
+			add(in.GetAddress().Fixed(), -int64(in.GetAmount()))
+		}
+		for _, out := range tx.GetOutputs() {
+			add(out.GetAddress().Fixed(), int64(out.GetAmount()))
+		}
+		for _, address := range addresses {
+			if err := record(false, address, tx.GetSigHash(), deltas[address]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, e := range ecblock.GetEntries() {
+		switch e.ECID() {
+		case constants.ECIDBalanceIncrease:
+			ib := e.(*entryCreditBlock.IncreaseBalance)
+			err = record(true, ib.ECPubKey.Fixed(), ib.TXID, int64(ib.NumEC))
+		case constants.ECIDChainCommit:
+			c := e.(*entryCreditBlock.CommitChain)
+			err = record(true, c.ECPubKey.Fixed(), c.GetSigHash(), -int64(c.Credits))
+		case constants.ECIDEntryCommit:
+			c := e.(*entryCreditBlock.CommitEntry)
+			err = record(true, c.ECPubKey.Fixed(), c.GetSigHash(), -int64(c.Credits))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := dbo.SaveAddressHistory(order, dbheight+1); err != nil {
+		return err
+	}
+	AddressIndexHeight.Set(float64(dbheight))
+	return nil
+}
+
+// History returns up to limit of the transactions touching an address, starting from the
+// offset-th (oldest first)
+func (ai *AddressIndex) History(ec bool, address [32]byte, offset uint32, limit uint32) (*interfaces.AddressHistory, error) {
+	next, err := ai.Height()
+	if err != nil {
+		return nil, err
+	}
+	if next == 0 {
+		return nil, fmt.Errorf("the address index is empty, it is still being built")
+	}
+	dbo, err := ai.overlay()
+	if err != nil {
+		return nil, err
+	}
+	if limit > AddressIndexMaxPage {
+		limit = AddressIndexMaxPage
+	}
+
+	h := new(interfaces.AddressHistory)
+	h.IndexHeight = next - 1
+	h.Transactions = []*interfaces.AddressTxRef{}
+	summary, err := dbo.FetchAddressHistorySummary(ec, address)
+	if err != nil || summary == nil {
+		return h, err
+	}
+	h.Total = summary.Count
+
+	for n := offset; n < summary.Count && n-offset < limit; n++ {
+		r, err := dbo.FetchAddressHistoryRecord(ec, address, n)
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			return nil, fmt.Errorf("record %d of %x is missing", n, address[:4])
+		}
+		// The summary can get ahead of the records we read, if a height is added meanwhile
+		if r.DBHeight > h.IndexHeight {
+			break
+		}
+		h.Transactions = append(h.Transactions, &interfaces.AddressTxRef{DBHeight: r.DBHeight, TxID: r.TxID.String(), Delta: r.Delta, Balance: r.Balance})
+	}
+	return h, nil
+}
+
+// BalanceAt returns the balance of an address at the end of the block at dbheight
+func (ai *AddressIndex) BalanceAt(ec bool, address [32]byte, dbheight uint32) (int64, error) {
+	next, err := ai.Height()
+	if err != nil {
+		return 0, err
+	}
+	if dbheight >= next {
+		return 0, fmt.Errorf("height %d is not in the address index yet, it holds heights up to %d", dbheight, int64(next)-1)
+	}
+	dbo, err := ai.overlay()
+	if err != nil {
+		return 0, err
+	}
+	summary, err := dbo.FetchAddressHistorySummary(ec, address)
+	if err != nil || summary == nil {
+		return 0, err
+	}
+
+	// Find the last record at or below the height.  Records are in height order.
+	var balance int64
+	lo, hi := uint32(0), summary.Count // the answer is in [lo, hi)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		r, err := dbo.FetchAddressHistoryRecord(ec, address, mid)
+		if err != nil {
+			return 0, err
+		}
+		if r == nil {
+			return 0, fmt.Errorf("record %d of %x is missing", mid, address[:4])
+		}
+		if r.DBHeight <= dbheight {
+			balance = r.Balance
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return balance, nil
+}
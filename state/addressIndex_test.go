@@ -0,0 +1,131 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAddressIndex(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	ai := NewAddressIndex(s)
+
+	fa := testHelper.NewFactoidAddress(0).Fixed()
+	if _, err := ai.History(false, fa, 0, 10); err == nil {
+		t.Errorf("expected an error from an empty index")
+	}
+
+	last := uint32(testHelper.BlockCount - 1)
+	if err := ai.IndexTo(last - 1); err != nil {
+		t.Fatal(err)
+	}
+	// Blocks that are behind or ahead of the index are left to the backfill
+	ai.BlockSaved(last+1, nil, nil)
+	if next, _ := ai.Height(); next != last {
+		t.Fatalf("expected index height %d, got %d", last, next)
+	}
+	if err := ai.IndexTo(last); err != nil {
+		t.Fatal(err)
+	}
+
+	// The history ends at the balances summed from the blocks
+	fct, ec := blockBalances(t, s, last)
+	if len(fct) == 0 || len(ec) == 0 {
+		t.Fatalf("no balances to check")
+	}
+	for fixed, balance := range fct {
+		h, err := ai.History(false, fixed, 0, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.IndexHeight != last || int(h.Total) != len(h.Transactions) {
+			t.Errorf("wrong history page %d %d %d", h.IndexHeight, h.Total, len(h.Transactions))
+		}
+		if h.Total > 0 && h.Transactions[h.Total-1].Balance != balance {
+			t.Errorf("factoid balance %d, history says %d", balance, h.Transactions[h.Total-1].Balance)
+		}
+		if b, err := ai.BalanceAt(false, fixed, last); err != nil || b != balance {
+			t.Errorf("factoid balance %d, BalanceAt says %d %v", balance, b, err)
+		}
+	}
+	for fixed, balance := range ec {
+		if b, err := ai.BalanceAt(true, fixed, last); err != nil || b != balance {
+			t.Errorf("entry credit balance %d, BalanceAt says %d %v", balance, b, err)
+		}
+	}
+
+	// Pages, and balances in the past
+	h, err := ai.History(false, fa, 0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Total < 3 {
+		t.Fatalf("expected a longer history, got %d", h.Total)
+	}
+	page, err := ai.History(false, fa, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != h.Total || len(page.Transactions) != 2 || *page.Transactions[0] != *h.Transactions[1] {
+		t.Errorf("wrong page %+v", page)
+	}
+	if page, _ = ai.History(false, fa, h.Total, 10); len(page.Transactions) != 0 {
+		t.Errorf("expected an empty page past the end")
+	}
+	for _, tx := range h.Transactions {
+		b, err := ai.BalanceAt(false, fa, tx.DBHeight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The balance at the end of the block is that after its last transaction
+		var want int64
+		for _, o := range h.Transactions {
+			if o.DBHeight <= tx.DBHeight {
+				want = o.Balance
+			}
+		}
+		if b != want {
+			t.Errorf("balance at %d is %d, expected %d", tx.DBHeight, b, want)
+		}
+	}
+	if _, err := ai.BalanceAt(false, fa, last+1); err == nil {
+		t.Errorf("expected an error past the index height")
+	}
+}
+
+// blockBalances sums the balance changes in the blocks up to the given height
+func blockBalances(t *testing.T, s *State, last uint32) (fct map[[32]byte]int64, ec map[[32]byte]int64) {
+	fct = make(map[[32]byte]int64)
+	ec = make(map[[32]byte]int64)
+	for h := uint32(0); h <= last; h++ {
+		fblock, err := s.DB.FetchFBlockByHeight(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tx := range fblock.GetTransactions() {
+			for _, in := range tx.GetInputs() {
+				fct[in.GetAddress().Fixed()] -= int64(in.GetAmount())
+			}
+			for _, out := range tx.GetOutputs() {
+				fct[out.GetAddress().Fixed()] += int64(out.GetAmount())
+			}
+		}
+		ecblock, err := s.DB.FetchECBlockByHeight(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range ecblock.GetEntries() {
+			switch e := e.(type) {
+			case *entryCreditBlock.IncreaseBalance:
+				ec[e.ECPubKey.Fixed()] += int64(e.NumEC)
+			case *entryCreditBlock.CommitChain:
+				ec[e.ECPubKey.Fixed()] -= int64(e.Credits)
+			case *entryCreditBlock.CommitEntry:
+				ec[e.ECPubKey.Fixed()] -= int64(e.Credits)
+			}
+		}
+	}
+	return fct, ec
+}
@@ -0,0 +1,396 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	. "github.com/FactomProject/factomd/common/identity"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// AuthorityChangesMaxPage is the most changes returned by one call to Changes
+var AuthorityChangesMaxPage uint32 = 1000
+
+// AuthorityHistory is the authority set of every height, rebuilt from the admin blocks in the
+// database.  Every entry that changes an authority (adding, promoting, demoting and removing
+// servers, and their keys, efficiency and coinbase address) is kept in memory in block order,
+// and the set at a height is found by applying the ones before it to an IdentityManager
+// holding just the bootstrap identity.
+//
+// Nothing is read until the history is first asked for, then it catches up with the saved
+// blocks on every call.
+type AuthorityHistory struct {
+	s *State
+
+	mtx     sync.Mutex
+	next    uint32                        // The next admin block to read
+	im      *IdentityManager              // The authorities after the admin blocks read so far, nil until the first is
+	entries []*authorityHistoryEntry      // Every entry that changed an authority, in block order
+	changes []*interfaces.AuthorityChange // and what it changed
+}
+
+type authorityHistoryEntry struct {
+	dbheight uint32 // Admin block holding the entry, which is applied from the next height on
+	entry    interfaces.IABEntry
+}
+
+// NewAuthorityHistory creates an AuthorityHistory for the state's database
+func NewAuthorityHistory(s *State) *AuthorityHistory {
+	ah := new(AuthorityHistory)
+	ah.s = s
+	return ah
+}
+
+// newIdentityManager returns an IdentityManager holding the authorities of the genesis block
+func (ah *AuthorityHistory) newIdentityManager() *IdentityManager {
+	im := NewIdentityManager()
+	im.SetBootstrapIdentity(ah.s.GetNetworkBootStrapIdentity(), ah.s.GetNetworkBootStrapKey())
+	return im
+}
+
+// Height returns the next admin block to be read, so the history holds every height up to it
+func (ah *AuthorityHistory) Height() uint32 {
+	ah.mtx.Lock()
+	defer ah.mtx.Unlock()
+	return ah.next
+}
+
+// catchUp reads the admin blocks saved since the last call.  Must hold the mutex.
+func (ah *AuthorityHistory) catchUp() error {
+	end := ah.s.GetHighestSavedBlk()
+	for ah.next <= end {
+		ablock, err := ah.s.DB.FetchABlockByHeight(ah.next)
+		if err != nil {
+			return err
+		}
+		if ablock == nil {
+			return fmt.Errorf("admin block %d is missing", ah.next)
+		}
+		if err := ah.addABlock(ablock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddABlock adds the next admin block to the history, ahead of it being read from the database
+func (ah *AuthorityHistory) AddABlock(ablock interfaces.IAdminBlock) error {
+	ah.mtx.Lock()
+	defer ah.mtx.Unlock()
+	return ah.addABlock(ablock)
+}
+
+// addABlock logs the authority changes of the next admin block.  Must hold the mutex.
+func (ah *AuthorityHistory) addABlock(ablock interfaces.IAdminBlock) error {
+	dbheight := ablock.GetDatabaseHeight()
+	if dbheight != ah.next {
+		return fmt.Errorf("expected admin block %d, not %d", ah.next, dbheight)
+	}
+	if ah.im == nil {
+		ah.im = ah.newIdentityManager()
+	}
+
+	for i, entry := range ablock.GetABEntries() {
+		entry = ah.bootstrapEntry(entry)
+		chainID := authorityEntryChainID(entry)
+		if chainID == nil {
+			continue
+		}
+
+		change := new(interfaces.AuthorityChange)
+		change.DBHeight = dbheight
+		change.ActiveHeight = dbheight + 1
+		change.EntryIndex = i
+		change.Entry = authorityEntryName(entry)
+		change.ChainID = chainID.String()
+		change.Change, change.Previous, change.Value = authorityChange(ah.im.GetAuthority(chainID), entry)
+
+		if err := applyAuthorityEntry(ah.im, entry); err != nil {
+			continue // The entry did nothing, as it did when the block was processed
+		}
+		if auth := ah.im.GetAuthority(chainID); auth != nil && change.Change != interfaces.AuthorityRemoved {
+			_, _, change.Value = authorityChange(auth, entry)
+		}
+		ah.entries = append(ah.entries, &authorityHistoryEntry{dbheight: dbheight, entry: entry})
+		ah.changes = append(ah.changes, change)
+	}
+	ah.next++
+	return nil
+}
+
+// bootstrapEntry replaces the placeholder server of the TEST and LOCAL genesis blocks with the
+// bootstrap identity, as the AddFederatedServer entry does when it updates the state
+func (ah *AuthorityHistory) bootstrapEntry(entry interfaces.IABEntry) interfaces.IABEntry {
+	e, ok := entry.(*adminBlock.AddFederatedServer)
+	if !ok || e.DBHeight != 1 {
+		return entry
+	}
+	if e.IdentityChainID.IsZero() || e.IdentityChainID.IsSameAs(primitives.Sha([]byte("FNode0"))) {
+		return adminBlock.NewAddFederatedServer(ah.s.GetNetworkBootStrapIdentity(), e.DBHeight)
+	}
+	return entry
+}
+
+// authorityEntryChainID returns the authority an admin block entry changes, nil for entries
+// that don't change an authority
+func authorityEntryChainID(entry interfaces.IABEntry) interfaces.IHash {
+	switch e := entry.(type) {
+	case *adminBlock.AddFederatedServer:
+		return e.IdentityChainID
+	case *adminBlock.AddAuditServer:
+		return e.IdentityChainID
+	case *adminBlock.RemoveFederatedServer:
+		return e.IdentityChainID
+	case *adminBlock.AddFederatedServerSigningKey:
+		return e.IdentityChainID
+	case *adminBlock.AddFederatedServerBitcoinAnchorKey:
+		return e.IdentityChainID
+	case *adminBlock.AddEfficiency:
+		return e.IdentityChainID
+	case *adminBlock.AddFactoidAddress:
+		return e.IdentityChainID
+	case *adminBlock.AddReplaceMatryoshkaHash:
+		return e.IdentityChainID
+	}
+	return nil
+}
+
+func authorityEntryName(entry interfaces.IABEntry) string {
+	switch entry.(type) {
+	case *adminBlock.AddFederatedServer:
+		return "AddFederatedServer"
+	case *adminBlock.AddAuditServer:
+		return "AddAuditServer"
+	case *adminBlock.RemoveFederatedServer:
+		return "RemoveFederatedServer"
+	case *adminBlock.AddFederatedServerSigningKey:
+		return "AddFederatedServerSigningKey"
+	case *adminBlock.AddFederatedServerBitcoinAnchorKey:
+		return "AddFederatedServerBitcoinAnchorKey"
+	case *adminBlock.AddEfficiency:
+		return "AddEfficiency"
+	case *adminBlock.AddFactoidAddress:
+		return "AddFactoidAddress"
+	case *adminBlock.AddReplaceMatryoshkaHash:
+		return "AddReplaceMatryoshkaHash"
+	}
+	return fmt.Sprintf("%T", entry)
+}
+
+// authorityChange returns the kind of change an entry makes to an authority, nil if it isn't
+// one yet, and the value it changes as the authority has it.  Entries that don't add a server
+// change nothing for an authority that isn't one.
+func authorityChange(auth *Authority, entry interfaces.IABEntry) (change string, previous string, value string) {
+	status := ""
+	if auth != nil {
+		status = authorityStatus(auth.Status)
+	}
+
+	switch entry.(type) {
+	case *adminBlock.AddFederatedServer:
+		change = interfaces.AuthorityAddedFederated
+		if status == interfaces.AuthorityStatusAudit {
+			change = interfaces.AuthorityPromoted
+		}
+		return change, status, status
+	case *adminBlock.AddAuditServer:
+		change = interfaces.AuthorityAddedAudit
+		if status == interfaces.AuthorityStatusFederated {
+			change = interfaces.AuthorityDemoted
+		}
+		return change, status, status
+	case *adminBlock.RemoveFederatedServer:
+		return interfaces.AuthorityRemoved, status, ""
+	}
+
+	if auth == nil {
+		return "", "", ""
+	}
+	switch e := entry.(type) {
+	case *adminBlock.AddFederatedServerSigningKey:
+		return interfaces.AuthoritySigningKey, auth.SigningKey.String(), auth.SigningKey.String()
+	case *adminBlock.AddFederatedServerBitcoinAnchorKey:
+		key := ""
+		for _, k := range auth.AnchorKeys {
+			if k.KeyLevel == e.KeyPriority && k.KeyType == e.KeyType {
+				key = anchorKeyString(k)
+			}
+		}
+		return interfaces.AuthorityAnchorKey, key, key
+	case *adminBlock.AddEfficiency:
+		efficiency := fmt.Sprintf("%d", auth.Efficiency)
+		return interfaces.AuthorityEfficiency, efficiency, efficiency
+	case *adminBlock.AddFactoidAddress:
+		address := coinbaseAddressString(auth.CoinbaseAddress)
+		return interfaces.AuthorityCoinbaseAddress, address, address
+	case *adminBlock.AddReplaceMatryoshkaHash:
+		return interfaces.AuthorityMatryoshkaHash, auth.MatryoshkaHash.String(), auth.MatryoshkaHash.String()
+	}
+	return "", "", ""
+}
+
+func authorityStatus(status uint8) string {
+	switch status {
+	case constants.IDENTITY_FEDERATED_SERVER, constants.IDENTITY_PENDING_FEDERATED_SERVER:
+		return interfaces.AuthorityStatusFederated
+	case constants.IDENTITY_AUDIT_SERVER, constants.IDENTITY_PENDING_AUDIT_SERVER:
+		return interfaces.AuthorityStatusAudit
+	}
+	return ""
+}
+
+func anchorKeyString(k AnchorSigningKey) string {
+	return fmt.Sprintf("%s:%d:%d:%x", k.BlockChain, k.KeyLevel, k.KeyType, k.SigningKey[:])
+}
+
+func coinbaseAddressString(address interfaces.IAddress) string {
+	if address == nil || address.IsZero() {
+		return ""
+	}
+	return primitives.ConvertFctAddressToUserStr(address)
+}
+
+// applyAuthorityEntry applies an admin block entry to an IdentityManager of the history.  The
+// identities of new servers are added as placeholders, rather than read from their chains.
+func applyAuthorityEntry(im *IdentityManager, entry interfaces.IABEntry) error {
+	var chainID interfaces.IHash
+	switch e := entry.(type) {
+	case *adminBlock.AddFederatedServer:
+		chainID = e.IdentityChainID
+	case *adminBlock.AddAuditServer:
+		chainID = e.IdentityChainID
+	}
+	if chainID != nil && im.GetIdentity(chainID) == nil {
+		id := NewIdentity()
+		id.IdentityChainID = chainID
+		im.SetIdentity(chainID, id)
+	}
+
+	if e, ok := entry.(*adminBlock.RemoveFederatedServer); ok && im.GetAuthority(e.IdentityChainID) == nil {
+		return fmt.Errorf("Authority %v not found", e.IdentityChainID.String())
+	}
+	switch e := entry.(type) {
+	case *adminBlock.AddFactoidAddress:
+		return im.ApplyAddFactoidAddress(e)
+	case *adminBlock.AddEfficiency:
+		return im.ApplyAddEfficiency(e)
+	}
+	return im.ProcessABlockEntry(entry, nil)
+}
+
+// AuthoritiesAt returns the federated and audit servers that built the block at a height, which
+// may be the height being built now
+func (ah *AuthorityHistory) AuthoritiesAt(dbheight uint32) (*interfaces.AuthoritySet, error) {
+	ah.mtx.Lock()
+	defer ah.mtx.Unlock()
+	if err := ah.catchUp(); err != nil {
+		return nil, err
+	}
+	if dbheight > ah.next {
+		return nil, fmt.Errorf("height %d is past the next block, %d", dbheight, ah.next)
+	}
+
+	im := ah.newIdentityManager()
+	for _, e := range ah.entries {
+		if e.dbheight >= dbheight {
+			break
+		}
+		applyAuthorityEntry(im, e.entry)
+	}
+
+	set := new(interfaces.AuthoritySet)
+	set.DBHeight = dbheight
+	set.Federated = make([]*interfaces.AuthorityAtHeight, 0)
+	set.Audit = make([]*interfaces.AuthorityAtHeight, 0)
+	for _, a := range im.GetSortedAuthorities() {
+		auth := a.(*Authority)
+		at := ah.authorityAtHeight(auth)
+		switch at.Status {
+		case interfaces.AuthorityStatusFederated:
+			set.Federated = append(set.Federated, at)
+		case interfaces.AuthorityStatusAudit:
+			set.Audit = append(set.Audit, at)
+		}
+	}
+	return set, nil
+}
+
+func (ah *AuthorityHistory) authorityAtHeight(auth *Authority) *interfaces.AuthorityAtHeight {
+	at := new(interfaces.AuthorityAtHeight)
+	at.ChainID = auth.AuthorityChainID.String()
+	if id := ah.s.IdentityControl.GetIdentity(auth.AuthorityChainID); id != nil && !id.ManagementChainID.IsZero() {
+		at.ManagementChainID = id.ManagementChainID.String()
+	} else if !auth.ManagementChainID.IsZero() {
+		at.ManagementChainID = auth.ManagementChainID.String()
+	}
+	at.Status = authorityStatus(auth.Status)
+	at.SigningKey = auth.SigningKey.String()
+	at.MatryoshkaHash = auth.MatryoshkaHash.String()
+	at.AnchorKeys = make([]string, 0)
+	keys := append([]AnchorSigningKey{}, auth.AnchorKeys...)
+	sort.Slice(keys, func(i, j int) bool { return keys[i].KeyLevel < keys[j].KeyLevel })
+	for _, k := range keys {
+		at.AnchorKeys = append(at.AnchorKeys, anchorKeyString(k))
+	}
+	at.Efficiency = auth.Efficiency
+	at.CoinbaseAddress = coinbaseAddressString(auth.CoinbaseAddress)
+	return at
+}
+
+// Changes returns a page of the authority changes, oldest first.  The changes can be limited
+// to one authority, and to the admin blocks from start to end (0 for no end).
+func (ah *AuthorityHistory) Changes(chainID interfaces.IHash, start uint32, end uint32, offset uint32, limit uint32) (*interfaces.AuthorityChanges, error) {
+	ah.mtx.Lock()
+	defer ah.mtx.Unlock()
+	if err := ah.catchUp(); err != nil {
+		return nil, err
+	}
+	if limit > AuthorityChangesMaxPage {
+		limit = AuthorityChangesMaxPage
+	}
+
+	page := new(interfaces.AuthorityChanges)
+	if ah.next > 0 {
+		page.IndexHeight = ah.next - 1
+	}
+	page.Changes = make([]*interfaces.AuthorityChange, 0)
+	for _, c := range ah.changes {
+		if c.DBHeight < start || (end != 0 && c.DBHeight > end) {
+			continue
+		}
+		if chainID != nil && c.ChainID != chainID.String() {
+			continue
+		}
+		if page.Total >= offset && uint32(len(page.Changes)) < limit {
+			page.Changes = append(page.Changes, c)
+		}
+		page.Total++
+	}
+	return page, nil
+}
+
+// LastChange returns the last change of a kind to an authority held by an admin block below a
+// height, which is the one the block at the height was built with, or nil if there is none
+func (ah *AuthorityHistory) LastChange(chainID interfaces.IHash, change string, dbheight uint32) (*interfaces.AuthorityChange, error) {
+	ah.mtx.Lock()
+	defer ah.mtx.Unlock()
+	if err := ah.catchUp(); err != nil {
+		return nil, err
+	}
+
+	for i := len(ah.changes) - 1; i >= 0; i-- {
+		c := ah.changes[i]
+		if c.DBHeight < dbheight && c.Change == change && c.ChainID == chainID.String() {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,148 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func authorityIDs(list []*interfaces.AuthorityAtHeight) map[string]*interfaces.AuthorityAtHeight {
+	ids := make(map[string]*interfaces.AuthorityAtHeight)
+	for _, a := range list {
+		ids[a.ChainID] = a
+	}
+	return ids
+}
+
+func TestAuthorityHistory(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	ah := s.AuthorityHistory
+	boot := s.GetNetworkBootStrapIdentity().String()
+
+	set, err := ah.AuthoritiesAt(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Federated) != 1 || set.Federated[0].ChainID != boot || len(set.Audit) != 0 {
+		t.Fatalf("expected only the bootstrap identity at height 0, found %v %v", set.Federated, set.Audit)
+	}
+
+	// Add some admin blocks changing the authorities after the ones in the database
+	top := s.GetHighestSavedBlk()
+	prev, err := s.DB.FetchABlockByHeight(top)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := primitives.Sha([]byte("authority a"))
+	b := primitives.Sha([]byte("authority b"))
+	key := primitives.RandomPrivateKey().Pub
+	address := primitives.Sha([]byte("coinbase address"))
+
+	ab1 := adminBlock.NewAdminBlock(prev)
+	ab1.AddAuditServer(a)
+	ab1.AddFedServer(b)
+	ab1.AddEfficiency(b, 4000)
+	ab1.AddCoinbaseAddress(b, address)
+	ab1.AddFederatedServerSigningKey(b, key.Fixed())
+	ab1.AddEfficiency(primitives.Sha([]byte("not an authority")), 4000)
+	ab1.InsertIdentityABEntries()
+
+	ab2 := adminBlock.NewAdminBlock(ab1)
+	ab2.AddFedServer(a)
+	ab2.AddAuditServer(b)
+
+	ab3 := adminBlock.NewAdminBlock(ab2)
+	ab3.RemoveFederatedServer(a)
+
+	for _, ab := range []interfaces.IAdminBlock{ab1, ab2, ab3} {
+		if err := ah.AddABlock(ab); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ah.AddABlock(ab1); err == nil {
+		t.Errorf("expected an error adding an admin block twice")
+	}
+
+	set, err = ah.AuthoritiesAt(top + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Audit) != 0 || authorityIDs(set.Federated)[b.String()] != nil {
+		t.Errorf("the admin block at %d isn't in force until %d", top+1, top+2)
+	}
+
+	set, _ = ah.AuthoritiesAt(top + 2)
+	if auth := authorityIDs(set.Audit)[a.String()]; auth == nil || len(set.Audit) != 1 {
+		t.Errorf("expected audit server %s at %d, found %v", a, top+2, set.Audit)
+	}
+	auth := authorityIDs(set.Federated)[b.String()]
+	if auth == nil {
+		t.Fatalf("expected federated server %s at %d, found %v", b, top+2, set.Federated)
+	}
+	if auth.Efficiency != 4000 || auth.SigningKey != key.String() || auth.CoinbaseAddress != primitives.ConvertFctAddressToUserStr(address) {
+		t.Errorf("authority changes not applied: %+v", auth)
+	}
+
+	set, _ = ah.AuthoritiesAt(top + 3)
+	if authorityIDs(set.Federated)[a.String()] == nil || authorityIDs(set.Audit)[b.String()] == nil {
+		t.Errorf("expected %s promoted and %s demoted at %d", a, b, top+3)
+	}
+
+	set, _ = ah.AuthoritiesAt(top + 4)
+	if authorityIDs(set.Federated)[a.String()] != nil || authorityIDs(set.Audit)[b.String()] == nil {
+		t.Errorf("expected %s removed at %d", a, top+4)
+	}
+
+	if _, err := ah.AuthoritiesAt(top + 5); err == nil {
+		t.Errorf("expected an error past the next block")
+	}
+
+	// The change log
+	expected := []struct {
+		height uint32
+		chain  interfaces.IHash
+		change string
+		value  string
+	}{
+		{top + 1, a, interfaces.AuthorityAddedAudit, interfaces.AuthorityStatusAudit},
+		{top + 1, b, interfaces.AuthorityAddedFederated, interfaces.AuthorityStatusFederated},
+		{top + 1, b, interfaces.AuthoritySigningKey, key.String()}, // The identity entries are sorted by type
+		{top + 1, b, interfaces.AuthorityCoinbaseAddress, primitives.ConvertFctAddressToUserStr(address)},
+		{top + 1, b, interfaces.AuthorityEfficiency, "4000"},
+		{top + 2, a, interfaces.AuthorityPromoted, interfaces.AuthorityStatusFederated},
+		{top + 2, b, interfaces.AuthorityDemoted, interfaces.AuthorityStatusAudit},
+		{top + 3, a, interfaces.AuthorityRemoved, ""},
+	}
+	page, err := ah.Changes(nil, top+1, 0, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(page.Total) != len(expected) || len(page.Changes) != len(expected) {
+		t.Fatalf("expected %d changes, found %d", len(expected), page.Total)
+	}
+	for i, e := range expected {
+		c := page.Changes[i]
+		if c.DBHeight != e.height || c.ActiveHeight != e.height+1 || c.ChainID != e.chain.String() || c.Change != e.change || c.Value != e.value {
+			t.Errorf("change %d: expected %d %s %s %q, found %+v", i, e.height, e.chain, e.change, e.value, c)
+		}
+	}
+	if page.Changes[4].Previous != "10000" {
+		t.Errorf("expected the efficiency to change from 10000, found %q", page.Changes[4].Previous)
+	}
+	if page.IndexHeight != top+3 {
+		t.Errorf("expected the log to hold heights to %d, found %d", top+3, page.IndexHeight)
+	}
+
+	// Paging and filters
+	page, _ = ah.Changes(a, 0, 0, 1, 1)
+	if page.Total != 3 || len(page.Changes) != 1 || page.Changes[0].Change != interfaces.AuthorityPromoted {
+		t.Errorf("expected the second of 3 changes of %s, found %d %v", a, page.Total, page.Changes)
+	}
+	page, _ = ah.Changes(nil, top+2, top+2, 0, 100)
+	if page.Total != 2 {
+		t.Errorf("expected 2 changes at %d, found %d", top+2, page.Total)
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// BalanceCheckpoint is every factoid and entry credit balance at the end of a height
+type BalanceCheckpoint struct {
+	FCT map[[32]byte]int64
+	EC  map[[32]byte]int64
+}
+
+var _ interfaces.BinaryMarshallable = (*BalanceCheckpoint)(nil)
+
+func (c *BalanceCheckpoint) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if err := PushBalanceMap(buf, c.FCT); err != nil {
+		return nil, err
+	}
+	if err := PushBalanceMap(buf, c.EC); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (c *BalanceCheckpoint) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	if c.FCT, err = PopBalanceMap(buf); err != nil {
+		return nil, err
+	}
+	if c.EC, err = PopBalanceMap(buf); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (c *BalanceCheckpoint) UnmarshalBinary(data []byte) error {
+	_, err := c.UnmarshalBinaryData(data)
+	return err
+}
+
+// BalanceDelta is the new balances of the addresses changed by a height, and the height of the
+// checkpoint the balances of the height are rebuilt from
+type BalanceDelta struct {
+	Base uint32
+	BalanceCheckpoint
+}
+
+var _ interfaces.BinaryMarshallable = (*BalanceDelta)(nil)
+
+func (d *BalanceDelta) MarshalBinary() ([]byte, error) {
+	buf := primitives.NewBuffer(nil)
+	if err := buf.PushUInt32(d.Base); err != nil {
+		return nil, err
+	}
+	if err := buf.PushBinaryMarshallable(&d.BalanceCheckpoint); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (d *BalanceDelta) UnmarshalBinaryData(data []byte) ([]byte, error) {
+	buf := primitives.NewBuffer(data)
+	var err error
+	if d.Base, err = buf.PopUInt32(); err != nil {
+		return nil, err
+	}
+	if err = buf.PopBinaryMarshallable(&d.BalanceCheckpoint); err != nil {
+		return nil, err
+	}
+	return buf.DeepCopyBytes(), nil
+}
+
+func (d *BalanceDelta) UnmarshalBinary(data []byte) error {
+	_, err := d.UnmarshalBinaryData(data)
+	return err
+}
+
+// BalanceHistory keeps the balances of every factoid and entry credit address at each height,
+// as a full checkpoint every Interval heights and the changed balances at every height.  The
+// balances at a height are rebuilt from the checkpoint before it and the deltas since.
+//
+// Like the AddressIndex, it is built from the blocks in the database by Backfill, and then kept
+// up as blocks are saved.
+type BalanceHistory struct {
+	*blockIndexer
+	Interval uint32 // Heights between checkpoints
+
+	// The balances at the end of the last height added, only touched while adding a height
+	fct  map[[32]byte]int64
+	ec   map[[32]byte]int64
+	base uint32 // The checkpoint of the last height added
+
+	cacheMtx    sync.Mutex
+	cache       *BalanceCheckpoint // The last balances rebuilt, as the balance calls look up several addresses at a time
+	cacheHeight uint32
+}
+
+// NewBalanceHistory creates a BalanceHistory for the state's database, with a checkpoint every interval heights
+func NewBalanceHistory(s *State, interval uint32) *BalanceHistory {
+	bh := new(BalanceHistory)
+	bh.blockIndexer = newBlockIndexer(s, "balancehistory")
+	bh.Interval = interval
+	if bh.Interval == 0 {
+		bh.Interval = 1
+	}
+	bh.load = bh.loadHeight
+	bh.add = bh.addHeight
+	return bh
+}
+
+func (bh *BalanceHistory) overlay() (*databaseOverlay.Overlay, error) {
+	dbo, ok := bh.s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return nil, fmt.Errorf("the balance history needs a database overlay, not %T", bh.s.DB)
+	}
+	return dbo, nil
+}
+
+// loadHeight reads the height of the history, and the balances at it to carry on from
+func (bh *BalanceHistory) loadHeight() (uint32, error) {
+	dbo, err := bh.overlay()
+	if err != nil {
+		return 0, err
+	}
+	next, err := dbo.FetchBalanceHistoryHeight()
+	if err != nil {
+		return 0, err
+	}
+	bh.fct = make(map[[32]byte]int64)
+	bh.ec = make(map[[32]byte]int64)
+	if next == 0 {
+		return 0, nil
+	}
+	balances, base, err := bh.rebuild(next - 1)
+	if err != nil {
+		return 0, err
+	}
+	bh.fct, bh.ec, bh.base = balances.FCT, balances.EC, base
+	return next, nil
+}
+
+// addHeight works out the balances changed by a height, and saves them along with a checkpoint if one is due
+func (bh *BalanceHistory) addHeight(dbheight uint32, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) error {
+	dbo, err := bh.overlay()
+	if err != nil {
+		return err
+	}
+
+	delta := new(BalanceDelta)
+	delta.FCT, delta.EC = blockBalanceChanges(fblock, ecblock)
+
+	// Turn the changes into new balances.  The running balances are only updated once the
+	// height is saved, so a failed write can be tried again.
+	for address, change := range delta.FCT {
+		delta.FCT[address] = bh.fct[address] + change
+	}
+	for address, change := range delta.EC {
+		delta.EC[address] = bh.ec[address] + change
+	}
+
+	var checkpoint *BalanceCheckpoint
+	delta.Base = bh.base
+	if dbheight%bh.Interval == 0 {
+		checkpoint = new(BalanceCheckpoint)
+		checkpoint.FCT = copyBalanceMap(bh.fct, delta.FCT)
+		checkpoint.EC = copyBalanceMap(bh.ec, delta.EC)
+		delta.Base = dbheight
+	}
+
+	if checkpoint != nil {
+		err = dbo.SaveBalanceHistory(dbheight, delta, checkpoint)
+	} else {
+		err = dbo.SaveBalanceHistory(dbheight, delta, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	for address, balance := range delta.FCT {
+		bh.fct[address] = balance
+	}
+	for address, balance := range delta.EC {
+		bh.ec[address] = balance
+	}
+	bh.base = delta.Base
+	return nil
+}
+
+// blockBalanceChanges adds up the changes to the factoid and entry credit balances made by the
+// transactions in a height's blocks
+func blockBalanceChanges(fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) (fct map[[32]byte]int64, ec map[[32]byte]int64) {
+	fct = make(map[[32]byte]int64)
+	ec = make(map[[32]byte]int64)
+	for _, tx := range fblock.GetTransactions() {
+		for _, in := range tx.GetInputs() {
+			fct[in.GetAddress().Fixed()] -= int64(in.GetAmount())
+		}
+		for _, out := range tx.GetOutputs() {
+			fct[out.GetAddress().Fixed()] += int64(out.GetAmount())
+		}
+	}
+	for _, e := range ecblock.GetEntries() {
+		switch e.ECID() {
+		case constants.ECIDBalanceIncrease:
+			ib := e.(*entryCreditBlock.IncreaseBalance)
+			ec[ib.ECPubKey.Fixed()] += int64(ib.NumEC)
+		case constants.ECIDChainCommit:
+			c := e.(*entryCreditBlock.CommitChain)
+			ec[c.ECPubKey.Fixed()] -= int64(c.Credits)
+		case constants.ECIDEntryCommit:
+			c := e.(*entryCreditBlock.CommitEntry)
+			ec[c.ECPubKey.Fixed()] -= int64(c.Credits)
+		}
+	}
+	return fct, ec
+}
+
+// copyBalanceMap returns a copy of the balances, with the changes applied
+func copyBalanceMap(balances map[[32]byte]int64, changes map[[32]byte]int64) map[[32]byte]int64 {
+	m := make(map[[32]byte]int64, len(balances))
+	for address, balance := range balances {
+		m[address] = balance
+	}
+	for address, balance := range changes {
+		m[address] = balance
+	}
+	return m
+}
+
+// rebuild reads the balances at the end of a height from the database, and the height of the
+// checkpoint they are based on
+func (bh *BalanceHistory) rebuild(dbheight uint32) (*BalanceCheckpoint, uint32, error) {
+	dbo, err := bh.overlay()
+	if err != nil {
+		return nil, 0, err
+	}
+	delta, err := dbo.FetchBalanceDelta(dbheight, new(BalanceDelta))
+	if err != nil {
+		return nil, 0, err
+	}
+	if delta == nil {
+		return nil, 0, fmt.Errorf("the balance history is missing height %d", dbheight)
+	}
+	base := delta.(*BalanceDelta).Base
+
+	checkpoint, err := dbo.FetchBalanceCheckpoint(base, new(BalanceCheckpoint))
+	if err != nil {
+		return nil, 0, err
+	}
+	if checkpoint == nil {
+		return nil, 0, fmt.Errorf("the balance history is missing the checkpoint at height %d", base)
+	}
+	balances := checkpoint.(*BalanceCheckpoint)
+
+	for h := base + 1; h <= dbheight; h++ {
+		delta, err := dbo.FetchBalanceDelta(h, new(BalanceDelta))
+		if err != nil {
+			return nil, 0, err
+		}
+		if delta == nil {
+			return nil, 0, fmt.Errorf("the balance history is missing height %d", h)
+		}
+		for address, balance := range delta.(*BalanceDelta).FCT {
+			balances.FCT[address] = balance
+		}
+		for address, balance := range delta.(*BalanceDelta).EC {
+			balances.EC[address] = balance
+		}
+	}
+	return balances, base, nil
+}
+
+// Balances returns every balance at the end of a height.  The maps must not be changed.
+func (bh *BalanceHistory) Balances(dbheight uint32) (*BalanceCheckpoint, error) {
+	next, err := bh.Height()
+	if err != nil {
+		return nil, err
+	}
+	if dbheight >= next {
+		return nil, fmt.Errorf("height %d is not in the balance history yet, it holds heights up to %d", dbheight, int64(next)-1)
+	}
+
+	bh.cacheMtx.Lock()
+	defer bh.cacheMtx.Unlock()
+	if bh.cache != nil && bh.cacheHeight == dbheight {
+		return bh.cache, nil
+	}
+	balances, _, err := bh.rebuild(dbheight)
+	if err != nil {
+		return nil, err
+	}
+	bh.cache, bh.cacheHeight = balances, dbheight
+	return balances, nil
+}
@@ -0,0 +1,53 @@
+package state_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func sameBalances(t *testing.T, kind string, dbheight uint32, expected map[[32]byte]int64, found map[[32]byte]int64) {
+	if len(expected) != len(found) {
+		t.Errorf("%s at %d: expected %d balances, found %d", kind, dbheight, len(expected), len(found))
+	}
+	for address, balance := range expected {
+		if found[address] != balance {
+			t.Errorf("%s at %d: expected balance %d, found %d", kind, dbheight, balance, found[address])
+		}
+	}
+}
+
+func TestBalanceHistory(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	last := uint32(testHelper.BlockCount - 1)
+
+	if _, _, err := s.GetFactoidState().GetBalancesAt(0); err == nil {
+		t.Errorf("expected an error without a balance history")
+	}
+
+	bh := NewBalanceHistory(s, 4)
+	if err := bh.IndexTo(5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bh.Balances(6); err == nil {
+		t.Errorf("expected an error past the history height")
+	}
+
+	// A new history picks up the balances where the last one stopped
+	bh = NewBalanceHistory(s, 4)
+	if err := bh.IndexTo(last); err != nil {
+		t.Fatal(err)
+	}
+	s.BalanceHistory = bh
+
+	for h := uint32(0); h <= last; h++ {
+		fct, ec, err := s.GetFactoidState().GetBalancesAt(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedFCT, expectedEC := blockBalances(t, s, h)
+		sameBalances(t, "factoid", h, expectedFCT, fct)
+		sameBalances(t, "entry credit", h, expectedEC, ec)
+	}
+}
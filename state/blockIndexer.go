@@ -0,0 +1,166 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// blockIndexer feeds the factoid and entry credit blocks to an index in height order.  Backfill
+// reads them from the database until the index has caught up with the saved height, after that
+// BlockSaved adds them as they are saved.
+type blockIndexer struct {
+	s    *State
+	name string // Name of the log file
+
+	// load reads the next height to add from the database
+	load func() (next uint32, err error)
+	// add adds a height to the index, which must then be ready for the next height
+	add func(dbheight uint32, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) error
+
+	mtx  sync.Mutex // Held while adding a height, so Backfill and BlockSaved don't both add it
+	next uint32     // The next height to add
+	open bool       // next has been loaded from the database
+
+	closer chan interface{}
+}
+
+func newBlockIndexer(s *State, name string) *blockIndexer {
+	bi := new(blockIndexer)
+	bi.s = s
+	bi.name = name
+	bi.closer = make(chan interface{})
+	return bi
+}
+
+// Stop ends the backfill
+func (bi *blockIndexer) Stop() {
+	close(bi.closer)
+}
+
+// loaded makes sure next has been read from the database.  Must hold the mutex.
+func (bi *blockIndexer) loaded() error {
+	if bi.open {
+		return nil
+	}
+	next, err := bi.load()
+	if err != nil {
+		return err
+	}
+	bi.next = next
+	bi.open = true
+	return nil
+}
+
+// Height returns the next height to be added to the index, which is 0 if the index is empty
+func (bi *blockIndexer) Height() (uint32, error) {
+	bi.mtx.Lock()
+	defer bi.mtx.Unlock()
+	err := bi.loaded()
+	return bi.next, err
+}
+
+// Backfill adds the blocks already in the database to the index, until it has caught up
+// with the saved height.  It waits for the database to be loaded first.
+func (bi *blockIndexer) Backfill() {
+	for !bi.s.DBFinished {
+		select {
+		case <-bi.closer:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+
+	start := time.Now()
+	from, _ := bi.Height()
+	for {
+		select {
+		case <-bi.closer:
+			return
+		default:
+		}
+
+		caughtUp, err := bi.backfillHeight(bi.s.GetHighestSavedBlk())
+		if err != nil {
+			bi.s.LogPrintf(bi.name, "backfill failed: %v", err)
+			select {
+			case <-bi.closer:
+				return
+			case <-time.After(time.Minute):
+			}
+			continue
+		}
+		if caughtUp {
+			next, _ := bi.Height()
+			bi.s.LogPrintf(bi.name, "backfill of heights %d to %d done in %s", from, next, time.Since(start))
+			return
+		}
+	}
+}
+
+// backfillHeight adds the next height from the database, unless the index is past end
+func (bi *blockIndexer) backfillHeight(end uint32) (caughtUp bool, err error) {
+	bi.mtx.Lock()
+	defer bi.mtx.Unlock()
+	if err := bi.loaded(); err != nil {
+		return false, err
+	}
+	if bi.next > end {
+		return true, nil
+	}
+
+	fblock, err := bi.s.DB.FetchFBlockByHeight(bi.next)
+	if err != nil {
+		return false, err
+	}
+	ecblock, err := bi.s.DB.FetchECBlockByHeight(bi.next)
+	if err != nil {
+		return false, err
+	}
+	if fblock == nil || ecblock == nil {
+		return false, fmt.Errorf("blocks at height %d are missing", bi.next)
+	}
+	return false, bi.addNext(fblock, ecblock)
+}
+
+// IndexTo adds the blocks in the database to the index, up to the given height.  Backfill does
+// the same in the background; this is for tools and tests.
+func (bi *blockIndexer) IndexTo(end uint32) error {
+	for {
+		caughtUp, err := bi.backfillHeight(end)
+		if caughtUp || err != nil {
+			return err
+		}
+	}
+}
+
+// BlockSaved adds the blocks just saved at a height, if the index has caught up to it
+func (bi *blockIndexer) BlockSaved(dbheight uint32, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) {
+	bi.mtx.Lock()
+	defer bi.mtx.Unlock()
+	if err := bi.loaded(); err != nil {
+		bi.s.LogPrintf(bi.name, "failed to load the index: %v", err)
+		return
+	}
+	if bi.next != dbheight {
+		return // Behind, Backfill will get to it.  Or a repeat we already have.
+	}
+	if err := bi.addNext(fblock, ecblock); err != nil {
+		bi.s.LogPrintf(bi.name, "failed to add height %d: %v", dbheight, err)
+	}
+}
+
+// addNext adds the blocks of the next height.  Must hold the mutex.
+func (bi *blockIndexer) addNext(fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) error {
+	if err := bi.add(bi.next, fblock, ecblock); err != nil {
+		return err
+	}
+	bi.next++
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The messages a ChaosCrash can take a leader down before sending
+const (
+	ChaosBeforeEOM   = "eom"
+	ChaosBeforeDBSig = "dbsig"
+)
+
+// Chaos is the misbehaviour a simulated node has been told to show, so the election and fault
+// code can be exercised on purpose rather than by luck.  The zero value is a node that behaves.
+type Chaos struct {
+	Crash       *ChaosCrash // Go off the network just before sending an EOM or DBSig
+	Equivocate  bool        // Send a second, conflicting ack for every ack this node sends as a leader
+	DBSigDelay  int64       // Milliseconds to hold up the DBSigs this node sends
+	CorruptAcks int         // Acks this node sends corrupted, out of every thousand
+	ClockSkew   int64       // Milliseconds this node's clock is ahead, or behind if negative
+}
+
+// ChaosCrash takes the leader of a VM off the network, as if it had crashed, just before it
+// sends its EOM or DBSig.  The same crash can be given to many nodes; it goes off once, on the
+// first of them to get there.
+type ChaosCrash struct {
+	Before string // ChaosBeforeEOM or ChaosBeforeDBSig
+	VM     int    // The VM whose leader crashes, -1 for any
+	fired  int32
+}
+
+// NewChaosCrash returns a crash for the leader of the VM, -1 for any VM
+func NewChaosCrash(before string, vm int) (*ChaosCrash, error) {
+	if before != ChaosBeforeEOM && before != ChaosBeforeDBSig {
+		return nil, fmt.Errorf("a crash is before an %s or a %s, not %q", ChaosBeforeEOM, ChaosBeforeDBSig, before)
+	}
+	if vm < -1 {
+		return nil, fmt.Errorf("there is no VM %d", vm)
+	}
+	return &ChaosCrash{Before: before, VM: vm}, nil
+}
+
+// Fired returns true once the crash has gone off
+func (c *ChaosCrash) Fired() bool {
+	return atomic.LoadInt32(&c.fired) != 0
+}
+
+func (c Chaos) String() string {
+	var s []string
+	if c.Crash != nil && !c.Crash.Fired() {
+		vm := "any VM"
+		if c.Crash.VM >= 0 {
+			vm = fmt.Sprintf("VM %d", c.Crash.VM)
+		}
+		s = append(s, fmt.Sprintf("crash the leader of %s before its %s", vm, c.Crash.Before))
+	}
+	if c.Equivocate {
+		s = append(s, "equivocate")
+	}
+	if c.DBSigDelay > 0 {
+		s = append(s, fmt.Sprintf("delay DBSigs %dms", c.DBSigDelay))
+	}
+	if c.CorruptAcks > 0 {
+		s = append(s, fmt.Sprintf("corrupt %d/1000 acks", c.CorruptAcks))
+	}
+	if c.ClockSkew != 0 {
+		s = append(s, fmt.Sprintf("clock skewed %+dms", c.ClockSkew))
+	}
+	if len(s) == 0 {
+		return "behaving"
+	}
+	return strings.Join(s, ", ")
+}
+
+// Behaves returns true if there is no misbehaviour to show
+func (c Chaos) Behaves() bool {
+	return c.Crash == nil && !c.Equivocate && c.DBSigDelay <= 0 && c.CorruptAcks <= 0 && c.ClockSkew == 0
+}
+
+// ChaosOn returns true if this node has been told to misbehave.  The hooks check it before
+// taking the lock, as nodes outside simulations never are.
+func (s *State) ChaosOn() bool {
+	return atomic.LoadInt32(&s.chaosOn) != 0
+}
+
+// GetChaos returns the misbehaviour this node has been told to show
+func (s *State) GetChaos() Chaos {
+	s.chaosMutex.Lock()
+	defer s.chaosMutex.Unlock()
+	return s.chaos
+}
+
+// SetChaos tells this node how to misbehave.  Only meant for simulations.
+func (s *State) SetChaos(c Chaos) {
+	s.chaosMutex.Lock()
+	defer s.chaosMutex.Unlock()
+	s.LogPrintf("faulting", "Chaos: %s", c.String())
+	s.chaos = c
+	on := int32(1)
+	if c.Behaves() {
+		on = 0
+	}
+	atomic.StoreInt32(&s.chaosOn, on)
+}
+
+// chaosCrash takes this node off the network if it has been told to crash before sending the
+// message as the leader of the VM
+func (s *State) chaosCrash(before string, vmIndex int) {
+	if !s.ChaosOn() {
+		return
+	}
+	c := s.GetChaos().Crash
+	if c == nil || c.Before != before || (c.VM >= 0 && c.VM != vmIndex) {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&c.fired, 0, 1) {
+		return
+	}
+	s.LogPrintf("faulting", "Chaos: crashing before the %s of VM %d at %d-:-%d", before, vmIndex, s.LLeaderHeight, s.CurrentMinute)
+	s.SetNetStateOff(true)
+}
+
+// chaosEquivocate sends a second ack for the same height of the process list as the ack this
+// leader just sent, acknowledging a message that doesn't exist
+func (s *State) chaosEquivocate(p *ProcessList, ack *messages.Ack) {
+	if !s.ChaosOn() || !s.GetChaos().Equivocate {
+		return
+	}
+	other := new(messages.Ack)
+	other.DBHeight = ack.DBHeight
+	other.VMIndex = ack.VMIndex
+	other.Minute = ack.Minute
+	other.Timestamp = ack.Timestamp
+	other.SaltNumber = ack.SaltNumber
+	other.Salt = ack.Salt
+	other.MessageHash = primitives.Sha(ack.MessageHash.Bytes())
+	other.LeaderChainID = ack.LeaderChainID
+	other.BalanceHash = ack.BalanceHash
+	other.Height = ack.Height
+	other.SerialHash = other.MessageHash
+	if ack.Height > 0 {
+		vm := p.VMs[ack.VMIndex]
+		if int(ack.Height) > len(vm.ListAck) || vm.ListAck[ack.Height-1] == nil {
+			return
+		}
+		other.SerialHash, _ = primitives.CreateHash(vm.ListAck[ack.Height-1].MessageHash, other.MessageHash)
+	}
+	other.Sign(s)
+	s.LogMessage("faulting", "Chaos: equivocate", other)
+	other.SendOut(s, other)
+}
+
+// chaosTimestamp returns the time by this node's clock, which may have been skewed
+func (s *State) chaosTimestamp() interfaces.Timestamp {
+	if !s.ChaosOn() {
+		return primitives.NewTimestampNow()
+	}
+	skew := s.GetChaos().ClockSkew
+	if skew == 0 {
+		return primitives.NewTimestampNow()
+	}
+	return primitives.NewTimestampFromMilliseconds(uint64(int64(primitives.GetTimeMilli()) + skew))
+}
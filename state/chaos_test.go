@@ -0,0 +1,51 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func TestChaos(t *testing.T) {
+	s := new(State)
+	if c := s.GetChaos(); c.String() != "behaving" {
+		t.Errorf("expected a new node to behave, got %s", c.String())
+	}
+
+	if s.ChaosOn() {
+		t.Error("expected the hooks to be off for a new node")
+	}
+
+	s.SetChaos(Chaos{ClockSkew: -60000})
+	if !s.ChaosOn() {
+		t.Error("expected the hooks to be on for a skewed clock")
+	}
+	skew := s.GetTimestamp().GetTimeMilli() - primitives.NewTimestampNow().GetTimeMilli()
+	if skew > -59000 || skew < -61000 {
+		t.Errorf("expected the clock to be a minute behind, got %dms", skew)
+	}
+
+	crash, err := NewChaosCrash(ChaosBeforeEOM, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetChaos(Chaos{Crash: crash, CorruptAcks: 5})
+	if str := s.GetChaos().String(); str != "crash the leader of VM 2 before its eom, corrupt 5/1000 acks" {
+		t.Errorf("got %s", str)
+	}
+	if crash.Fired() {
+		t.Error("expected the crash not to have gone off")
+	}
+	s.SetChaos(Chaos{})
+	if s.ChaosOn() {
+		t.Error("expected the hooks to be off once the node behaves again")
+	}
+	if _, err := NewChaosCrash("ack", -1); err == nil {
+		t.Error("expected a crash before an ack to be refused")
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// GetCoinbasePayout returns the coinbase payout of a block.  A saved payout is read from the
+// descriptor and the cancel entries in the admin blocks.  The outputs of a descriptor that isn't
+// saved yet are projected from the current authorities, with the efficiency and coinbase
+// address changes still waiting for the next admin block, or from the grants.
+func (s *State) GetCoinbasePayout(dbheight uint32) (*interfaces.CoinbasePayout, error) {
+	if !constants.IsCoinbasePayoutHeight(dbheight) {
+		return nil, fmt.Errorf("block %d doesn't pay a coinbase", dbheight)
+	}
+	if s.AuthorityHistory == nil {
+		return nil, fmt.Errorf("the authority history is not loaded")
+	}
+
+	saved := s.GetHighestSavedBlk()
+	p := new(interfaces.CoinbasePayout)
+	p.PayoutHeight = dbheight
+	p.DescriptorHeight = dbheight - constants.COINBASE_DECLARATION
+	p.Kind = interfaces.CoinbaseAuthorities
+	if dbheight%constants.COINBASE_PAYOUT_FREQUENCY == 1 {
+		p.Kind = interfaces.CoinbaseGrants
+	}
+	p.Projected = dbheight > saved
+	p.DescriptorProjected = p.DescriptorHeight > saved
+	p.Outputs = make([]*interfaces.CoinbaseOutput, 0)
+	p.PendingChanges = make([]*interfaces.CoinbasePendingChange, 0)
+
+	var err error
+	if p.DescriptorProjected {
+		if p.PendingChanges, err = s.pendingCoinbaseChanges(saved + 1); err != nil {
+			return nil, err
+		}
+		err = s.projectCoinbaseDescriptor(p, saved+1)
+	} else {
+		err = s.readCoinbaseDescriptor(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !p.DescriptorProjected {
+		if err := s.addCoinbaseCancels(p, saved); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range p.Outputs {
+		if o.Cancelled == nil {
+			p.Total += o.Amount
+		}
+	}
+
+	if !p.Projected {
+		fblock, err := s.DB.FetchFBlockByHeight(dbheight)
+		if err != nil {
+			return nil, err
+		}
+		if fblock == nil {
+			return nil, fmt.Errorf("factoid block %d is missing", dbheight)
+		}
+		if txs := fblock.GetTransactions(); len(txs) > 0 {
+			p.TransactionID = txs[0].GetSigHash().String()
+		}
+	}
+	return p, nil
+}
+
+// readCoinbaseDescriptor adds the outputs of a saved descriptor, and the authorities they pay
+func (s *State) readCoinbaseDescriptor(p *interfaces.CoinbasePayout) error {
+	ablock, err := s.DB.FetchABlockByHeight(p.DescriptorHeight)
+	if err != nil {
+		return err
+	}
+	if ablock == nil {
+		return fmt.Errorf("admin block %d is missing", p.DescriptorHeight)
+	}
+
+	for i, e := range ablock.GetABEntries() {
+		desc, ok := e.(*adminBlock.CoinbaseDescriptor)
+		if !ok {
+			continue
+		}
+		p.Descriptor = &interfaces.AdminEntryLink{DBHeight: p.DescriptorHeight, EntryIndex: i, Entry: "CoinbaseDescriptor"}
+		for j, o := range desc.Outputs {
+			out := new(interfaces.CoinbaseOutput)
+			out.Index = uint32(j)
+			out.Address = primitives.ConvertFctAddressToUserStr(o.GetAddress())
+			out.Amount = o.GetAmount()
+			p.Outputs = append(p.Outputs, out)
+		}
+		break
+	}
+	if p.Descriptor == nil || p.Kind != interfaces.CoinbaseAuthorities {
+		return nil
+	}
+
+	set, err := s.AuthorityHistory.AuthoritiesAt(p.DescriptorHeight)
+	if err != nil {
+		return err
+	}
+	paid := coinbasePaidAuthorities(set)
+	if len(paid) != len(p.Outputs) {
+		return nil // Not the descriptor the authorities make, so the outputs can't be matched to them
+	}
+	for i, a := range paid {
+		if p.Outputs[i].Address != a.CoinbaseAddress || p.Outputs[i].Amount != primitives.CalculateCoinbasePayout(a.Efficiency) {
+			return nil
+		}
+	}
+	for i, a := range paid {
+		if p.Outputs[i].Authority, err = s.coinbaseOutputAuthority(a, p.DescriptorHeight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectCoinbaseDescriptor adds the outputs of a descriptor that isn't saved yet.  next is the
+// block being built, which holds the pending changes.
+func (s *State) projectCoinbaseDescriptor(p *interfaces.CoinbasePayout, next uint32) error {
+	if p.DescriptorHeight <= constants.COINBASE_ACTIVATION {
+		return nil
+	}
+	if p.Kind == interfaces.CoinbaseGrants {
+		for i, o := range GetGrantPayoutsFor(p.DescriptorHeight) {
+			out := new(interfaces.CoinbaseOutput)
+			out.Index = uint32(i)
+			out.Address = primitives.ConvertFctAddressToUserStr(o.GetAddress())
+			out.Amount = o.GetAmount()
+			p.Outputs = append(p.Outputs, out)
+		}
+		return nil
+	}
+
+	set, err := s.AuthorityHistory.AuthoritiesAt(next)
+	if err != nil {
+		return err
+	}
+	for _, c := range p.PendingChanges {
+		if c.ActiveHeight > p.DescriptorHeight {
+			continue
+		}
+		for _, a := range append(set.Federated, set.Audit...) {
+			if a.ChainID != c.ChainID {
+				continue
+			}
+			switch c.Change {
+			case interfaces.AuthorityEfficiency:
+				fmt.Sscanf(c.Value, "%d", &a.Efficiency)
+			case interfaces.AuthorityCoinbaseAddress:
+				a.CoinbaseAddress = c.Value
+			}
+		}
+	}
+
+	for i, a := range coinbasePaidAuthorities(set) {
+		out := new(interfaces.CoinbaseOutput)
+		out.Index = uint32(i)
+		out.Address = a.CoinbaseAddress
+		out.Amount = primitives.CalculateCoinbasePayout(a.Efficiency)
+		if out.Authority, err = s.coinbaseOutputAuthority(a, p.DescriptorHeight); err != nil {
+			return err
+		}
+		p.Outputs = append(p.Outputs, out)
+	}
+	return nil
+}
+
+// coinbasePaidAuthorities returns the authorities of a set that a descriptor pays, in the order
+// of its outputs
+func coinbasePaidAuthorities(set *interfaces.AuthoritySet) []*interfaces.AuthorityAtHeight {
+	all := append(append([]*interfaces.AuthorityAtHeight{}, set.Federated...), set.Audit...)
+	sort.Slice(all, func(i, j int) bool { return all[i].ChainID < all[j].ChainID })
+
+	paid := make([]*interfaces.AuthorityAtHeight, 0)
+	for _, a := range all {
+		if a.CoinbaseAddress == "" || primitives.CalculateCoinbasePayout(a.Efficiency) == 0 {
+			continue
+		}
+		paid = append(paid, a)
+	}
+	return paid
+}
+
+// coinbaseOutputAuthority links an output to the authority it pays, and to the entries that set
+// the efficiency and coinbase address a descriptor at a height was built with
+func (s *State) coinbaseOutputAuthority(a *interfaces.AuthorityAtHeight, dbheight uint32) (*interfaces.CoinbaseOutputAuthority, error) {
+	chainID, err := primitives.HexToHash(a.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	oa := new(interfaces.CoinbaseOutputAuthority)
+	oa.ChainID = a.ChainID
+	oa.Efficiency = a.Efficiency
+
+	link := func(change string, value string) (*interfaces.AdminEntryLink, error) {
+		c, err := s.AuthorityHistory.LastChange(chainID, change, dbheight)
+		if err != nil || c == nil || c.Value != value {
+			return nil, err // A pending change isn't in an admin block yet
+		}
+		return &interfaces.AdminEntryLink{DBHeight: c.DBHeight, EntryIndex: c.EntryIndex, Entry: c.Entry}, nil
+	}
+	if oa.EfficiencyEntry, err = link(interfaces.AuthorityEfficiency, fmt.Sprintf("%d", a.Efficiency)); err != nil {
+		return nil, err
+	}
+	if oa.AddressEntry, err = link(interfaces.AuthorityCoinbaseAddress, a.CoinbaseAddress); err != nil {
+		return nil, err
+	}
+	return oa, nil
+}
+
+// pendingCoinbaseChanges returns the efficiencies and coinbase addresses in the identities of
+// the authorities that differ from the ones the admin blocks set.  The block being built adds
+// them to its admin block, so they apply to the descriptors from the block after it.
+func (s *State) pendingCoinbaseChanges(next uint32) ([]*interfaces.CoinbasePendingChange, error) {
+	set, err := s.AuthorityHistory.AuthoritiesAt(next)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*interfaces.CoinbasePendingChange, 0)
+	for _, a := range append(set.Federated, set.Audit...) {
+		chainID, err := primitives.HexToHash(a.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		id := s.IdentityControl.GetIdentity(chainID)
+		if id == nil {
+			continue
+		}
+		add := func(change string, value string, previous string) {
+			c := new(interfaces.CoinbasePendingChange)
+			c.ChainID = a.ChainID
+			c.Change = change
+			c.Previous = previous
+			c.Value = value
+			c.ActiveHeight = next + 1
+			changes = append(changes, c)
+		}
+		if id.Efficiency != a.Efficiency {
+			add(interfaces.AuthorityEfficiency, fmt.Sprintf("%d", id.Efficiency), fmt.Sprintf("%d", a.Efficiency))
+		}
+		if address := coinbaseAddressString(factoid.NewAddress(id.CoinbaseAddress.Bytes())); address != "" && address != a.CoinbaseAddress {
+			add(interfaces.AuthorityCoinbaseAddress, address, a.CoinbaseAddress)
+		}
+	}
+	return changes, nil
+}
+
+// addCoinbaseCancels marks the outputs of a saved descriptor cancelled by the admin blocks up to
+// the payout, and adds the votes to cancel the others that no admin block records yet
+func (s *State) addCoinbaseCancels(p *interfaces.CoinbasePayout, saved uint32) error {
+	end := p.PayoutHeight
+	if end > saved {
+		end = saved
+	}
+	for h := p.DescriptorHeight; h <= end; h++ {
+		ablock, err := s.DB.FetchABlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		if ablock == nil {
+			return fmt.Errorf("admin block %d is missing", h)
+		}
+		for i, e := range ablock.GetABEntries() {
+			c, ok := e.(*adminBlock.CancelCoinbaseDescriptor)
+			if !ok || c.DescriptorHeight != p.DescriptorHeight || c.DescriptorIndex >= uint32(len(p.Outputs)) {
+				continue
+			}
+			if o := p.Outputs[c.DescriptorIndex]; o.Cancelled == nil {
+				o.Cancelled = &interfaces.AdminEntryLink{DBHeight: h, EntryIndex: i, Entry: "CancelCoinbaseDescriptor"}
+			}
+		}
+	}
+
+	if !p.Projected {
+		return nil
+	}
+	needed := s.IdentityControl.AuthorityServerCount()/2 + 1
+	for index, votes := range s.IdentityControl.CancelManager.Votes(p.DescriptorHeight) {
+		if index >= uint32(len(p.Outputs)) || p.Outputs[index].Cancelled != nil {
+			continue
+		}
+		v := new(interfaces.CoinbaseCancelVotes)
+		v.Needed = needed
+		for _, chainID := range votes {
+			v.Votes = append(v.Votes, chainID.String())
+		}
+		p.Outputs[index].Votes = v
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestCoinbasePayout(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	for _, bs := range testHelper.CreateFullTestBlockSet() {
+		s.DBStates.NewDBState(false, bs.DBlock, bs.ABlock, bs.FBlock, bs.ECBlock, nil, nil)
+	}
+	for _, d := range s.DBStates.DBStates {
+		d.Saved = true
+	}
+	top := s.GetHighestSavedBlk()
+	boot := s.GetNetworkBootStrapIdentity()
+
+	// Small enough for the test blocks to hold payouts
+	declaration, frequency, activation := constants.COINBASE_DECLARATION, constants.COINBASE_PAYOUT_FREQUENCY, constants.COINBASE_ACTIVATION
+	defer func() {
+		constants.COINBASE_DECLARATION, constants.COINBASE_PAYOUT_FREQUENCY, constants.COINBASE_ACTIVATION = declaration, frequency, activation
+	}()
+	constants.COINBASE_DECLARATION, constants.COINBASE_PAYOUT_FREQUENCY, constants.COINBASE_ACTIVATION = 2, 3, 0
+
+	for _, h := range []uint32{5, 8, top + 2} {
+		if _, err := s.GetCoinbasePayout(h); err == nil {
+			t.Errorf("expected an error for block %d, which doesn't pay a coinbase", h)
+		}
+	}
+
+	// A saved payout holds the coinbase transaction, of a descriptor without outputs here
+	p, err := s.GetCoinbasePayout(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Projected || p.DescriptorProjected || p.DescriptorHeight != 7 || p.Kind != interfaces.CoinbaseAuthorities {
+		t.Errorf("wrong payout %+v", p)
+	}
+	fblock, _ := s.DB.FetchFBlockByHeight(9)
+	if p.TransactionID != fblock.GetTransactions()[0].GetSigHash().String() {
+		t.Errorf("expected coinbase transaction %s, found %s", fblock.GetTransactions()[0].GetSigHash(), p.TransactionID)
+	}
+	if p.Descriptor != nil || len(p.Outputs) != 0 || p.Total != 0 {
+		t.Errorf("expected no descriptor, found %+v", p)
+	}
+	if p, _ := s.GetCoinbasePayout(10); p.Kind != interfaces.CoinbaseGrants {
+		t.Errorf("expected block 10 to pay the grants, found %s", p.Kind)
+	}
+
+	// The bootstrap identity sets a coinbase address and an efficiency in its chains, which the
+	// block being built adds to its admin block
+	id := s.IdentityControl.GetIdentity(boot)
+	if id == nil {
+		t.Fatalf("no identity for %s", boot)
+	}
+	address := primitives.Sha([]byte("coinbase address"))
+	fa := primitives.ConvertFctAddressToUserStr(factoid.NewAddress(address.Bytes()))
+	id.CoinbaseAddress = address
+	id.Efficiency = 4000
+	s.IdentityControl.SetIdentity(boot, id)
+
+	// So the descriptor of the block being built is made without them
+	next := top + 1
+	p, err = s.GetCoinbasePayout(next + constants.COINBASE_DECLARATION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Projected || !p.DescriptorProjected || p.DescriptorHeight != next || len(p.Outputs) != 0 {
+		t.Errorf("expected a projected descriptor at %d without outputs, found %+v", next, p)
+	}
+	if len(p.PendingChanges) != 2 {
+		t.Fatalf("expected 2 pending changes, found %v", p.PendingChanges)
+	}
+	for _, c := range p.PendingChanges {
+		if c.ChainID != boot.String() || c.ActiveHeight != next+1 {
+			t.Errorf("wrong pending change %+v", c)
+		}
+		if c.Change == interfaces.AuthorityEfficiency && (c.Previous != "10000" || c.Value != "4000") {
+			t.Errorf("wrong efficiency change %+v", c)
+		}
+		if c.Change == interfaces.AuthorityCoinbaseAddress && (c.Previous != "" || c.Value != fa) {
+			t.Errorf("wrong coinbase address change %+v", c)
+		}
+	}
+
+	// and the descriptors after it pay the address
+	payout := next + 2 + constants.COINBASE_DECLARATION
+	for payout%constants.COINBASE_PAYOUT_FREQUENCY != 0 {
+		payout++
+	}
+	p, err = s.GetCoinbasePayout(payout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Outputs) != 1 {
+		t.Fatalf("expected one output, found %v", p.Outputs)
+	}
+	o := p.Outputs[0]
+	if o.Address != fa || o.Amount != primitives.CalculateCoinbasePayout(4000) || p.Total != o.Amount {
+		t.Errorf("wrong output %+v", o)
+	}
+	if o.Authority == nil || o.Authority.ChainID != boot.String() || o.Authority.Efficiency != 4000 || o.Authority.EfficiencyEntry != nil {
+		t.Errorf("wrong authority %+v", o.Authority)
+	}
+}
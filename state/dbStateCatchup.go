@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/messages"
 )
 
@@ -205,7 +206,7 @@ func (list *DBStateList) Catchup() {
 				}
 			}
 
-			time.Sleep(requestTimeout)
+			clock.Sleep(requestTimeout)
 		}
 	}()
 
@@ -432,7 +433,7 @@ type WaitingState struct {
 func NewWaitingState(height uint32) *WaitingState {
 	s := new(WaitingState)
 	s.height = height
-	s.requestedTime = time.Now()
+	s.requestedTime = clock.Now()
 	return s
 }
 
@@ -441,11 +442,11 @@ func (s *WaitingState) Height() uint32 {
 }
 
 func (s *WaitingState) RequestAge() time.Duration {
-	return time.Since(s.requestedTime)
+	return clock.Since(s.requestedTime)
 }
 
 func (s *WaitingState) ResetRequestAge() {
-	s.requestedTime = time.Now()
+	s.requestedTime = clock.Now()
 }
 
 type StatesWaiting struct {
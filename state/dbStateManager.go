@@ -1059,6 +1059,16 @@ func (list *DBStateList) ProcessBlocks(d *DBState) (progress bool) {
 	pln.SetStartingAuthoritySet()
 	pln2.SetStartingAuthoritySet()
 
+	// A node can move to height 1 before it processes the genesis block, so check again if it
+	// leads now the servers the genesis names are known
+	if dbht == 0 && s.LLeaderHeight == 1 {
+		s.Leader, s.LeaderVMIndex = s.LeaderPL.GetVirtualServers(s.CurrentMinute, s.IdentityChainID)
+		s.LogPrintf("dbstateprocess", "ProcessBlocks(0) set leader=%v, vmIndex = %v", s.Leader, s.LeaderVMIndex)
+		if s.Leader && !s.LeaderPL.DBSigAlreadySent && s.LLeaderHeight > s.DBHeightAtBoot {
+			s.SendDBSig(s.LLeaderHeight, s.LeaderVMIndex) // ProcessBlocks()
+		}
+	}
+
 	// *******************
 	// Factoid Block Processing
 	// *******************
@@ -1600,6 +1610,16 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 	list.State.ECBalancesPapi = nil
 	list.State.ECBalancesPMutex.Unlock()
 
+	if list.State.ExportPipeline != nil {
+		list.State.ExportPipeline.Notify()
+	}
+	if list.State.AddressIndex != nil {
+		list.State.AddressIndex.BlockSaved(uint32(dbheight), d.FactoidBlock, d.EntryCreditBlock)
+	}
+	if list.State.BalanceHistory != nil {
+		list.State.BalanceHistory.BlockSaved(uint32(dbheight), d.FactoidBlock, d.EntryCreditBlock)
+	}
+
 	return
 }
 
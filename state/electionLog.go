@@ -0,0 +1,310 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// ElectionLogSize is the number of finished elections kept in the election log
+var ElectionLogSize uint64 = 100
+
+// ElectionLogMaxLevels is the most level messages kept in the timeline of one election, the
+// ones past it are only counted
+var ElectionLogMaxLevels = 1000
+
+// ElectionLog records the timeline of every election this node takes part in: the fault that
+// started it, the rounds of audit volunteers, the votes and level messages received for them,
+// and how it ended.  The last ElectionLogSize elections are saved to the database as they end.
+//
+// The election messages call it from the elections goroutine, so all the methods lock, and
+// do nothing on a nil log.
+type ElectionLog struct {
+	s *State
+
+	mtx     sync.Mutex
+	loaded  bool                         // The saved elections have been read from the database
+	records []*interfaces.ElectionRecord // The last elections that ended, oldest first
+	current *interfaces.ElectionRecord   // The running election, nil if there isn't one
+	nextID  uint64
+}
+
+// NewElectionLog creates an ElectionLog saving to the state's database
+func NewElectionLog(s *State) *ElectionLog {
+	el := new(ElectionLog)
+	el.s = s
+	return el
+}
+
+func (el *ElectionLog) overlay() *databaseOverlay.Overlay {
+	if el.s == nil {
+		return nil
+	}
+	dbo, _ := el.s.DB.(*databaseOverlay.Overlay)
+	return dbo
+}
+
+// load reads the elections saved by earlier runs, so ids carry on from them
+func (el *ElectionLog) load() error {
+	if el.loaded {
+		return nil
+	}
+	dbo := el.overlay()
+	if dbo == nil {
+		el.loaded = true
+		return nil
+	}
+	saved, err := dbo.FetchElectionRecords()
+	if err != nil {
+		return err
+	}
+	el.loaded = true
+	el.records = append(saved, el.records...)
+	if uint64(len(el.records)) > ElectionLogSize {
+		el.records = el.records[uint64(len(el.records))-ElectionLogSize:]
+	}
+	for _, r := range el.records {
+		if r.ID >= el.nextID {
+			el.nextID = r.ID + 1
+		}
+	}
+	return nil
+}
+
+func serverIDs(servers []interfaces.IServer) []string {
+	ids := make([]string, 0, len(servers))
+	for _, s := range servers {
+		ids = append(ids, s.GetChainID().String())
+	}
+	return ids
+}
+
+// Start records the start of an election to replace the federated server fedIndex, which
+// faulted on the EOM of a minute, or on the DBSig if the minute is -1.  A running election
+// is abandoned.
+func (el *ElectionLog) Start(dbheight uint32, minute int, vmIndex int, fedIndex int, fedID interfaces.IHash, federated []interfaces.IServer, audit []interfaces.IServer) {
+	if el == nil {
+		return
+	}
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	if el.current != nil {
+		el.finish(interfaces.ElectionAbandoned, nil)
+	}
+	if err := el.load(); err != nil {
+		el.s.LogPrintf("elections", "ElectionLog: failed to read the saved elections: %v", err)
+	}
+
+	r := new(interfaces.ElectionRecord)
+	r.ID = el.nextID
+	el.nextID++
+	r.DBHeight = dbheight
+	r.Minute = minute
+	r.VMIndex = vmIndex
+	r.FaultedIndex = fedIndex
+	r.FaultedID = fedID.String()
+	r.Started = primitives.NewTimestampNow().GetTimeMilli()
+	r.Outcome = interfaces.ElectionRunning
+	r.Federated = serverIDs(federated)
+	r.Audit = serverIDs(audit)
+	el.current = r
+
+	ElectionsStarted.Inc()
+	el.emit(r)
+}
+
+// Round records the round the running election got to
+func (el *ElectionLog) Round(round int) {
+	if el == nil {
+		return
+	}
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	if el.current != nil && round > el.current.Rounds {
+		el.current.Rounds = round
+	}
+}
+
+// Volunteer records an audit server volunteering in a round of the running election
+func (el *ElectionLog) Volunteer(serverID interfaces.IHash, round int) {
+	if el == nil || serverID == nil {
+		return
+	}
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	if el.current == nil {
+		return
+	}
+	id := serverID.String()
+	// Proposals carry the volunteer message, so the same one is seen many times
+	for _, v := range el.current.Volunteers {
+		if v.ServerID == id && v.Round == round {
+			return
+		}
+	}
+	v := new(interfaces.ElectionVolunteer)
+	v.ServerID = id
+	v.Round = round
+	v.Time = primitives.NewTimestampNow().GetTimeMilli()
+	el.current.Volunteers = append(el.current.Volunteers, v)
+}
+
+// Vote records the vote of a federated server for a volunteer of the running election
+func (el *ElectionLog) Vote(signer interfaces.IHash, volunteer interfaces.IHash) {
+	if el == nil || signer == nil || volunteer == nil {
+		return
+	}
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	if el.current == nil {
+		return
+	}
+	id, voter := volunteer.String(), signer.String()
+	var votes *interfaces.ElectionVotes
+	for _, v := range el.current.Votes {
+		if v.ServerID == id {
+			votes = v
+			break
+		}
+	}
+	if votes == nil {
+		votes = new(interfaces.ElectionVotes)
+		votes.ServerID = id
+		el.current.Votes = append(el.current.Votes, votes)
+	}
+	for _, v := range votes.Voters {
+		if v == voter {
+			return
+		}
+	}
+	votes.Voters = append(votes.Voters, voter)
+	votes.Votes = len(votes.Voters)
+}
+
+// Level records a level message of the running election
+func (el *ElectionLog) Level(signer interfaces.IHash, volunteer interfaces.IHash, level uint32, rank uint32, committed bool) {
+	if el == nil || signer == nil || volunteer == nil {
+		return
+	}
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	if el.current == nil {
+		return
+	}
+	el.current.LevelMessages++
+	if len(el.current.Levels) >= ElectionLogMaxLevels {
+		return
+	}
+	l := new(interfaces.ElectionLevel)
+	l.Signer = signer.String()
+	l.Volunteer = volunteer.String()
+	l.Level = level
+	l.Rank = rank
+	l.Committed = committed
+	l.Time = primitives.NewTimestampNow().GetTimeMilli()
+	el.current.Levels = append(el.current.Levels, l)
+}
+
+// Elected ends the running election with the audit server that replaces the faulted leader
+func (el *ElectionLog) Elected(serverID interfaces.IHash) {
+	if el == nil {
+		return
+	}
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	if el.current != nil {
+		el.finish(interfaces.ElectionElected, serverID)
+	}
+}
+
+// Abandon ends the running election, if there is one, without a replacement
+func (el *ElectionLog) Abandon() {
+	if el == nil {
+		return
+	}
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	if el.current != nil {
+		el.finish(interfaces.ElectionAbandoned, nil)
+	}
+}
+
+// finish ends the running election, saves it and drops the oldest past ElectionLogSize
+func (el *ElectionLog) finish(outcome string, elected interfaces.IHash) {
+	r := el.current
+	el.current = nil
+
+	r.Ended = primitives.NewTimestampNow().GetTimeMilli()
+	r.DurationMs = r.Ended - r.Started
+	r.Outcome = outcome
+	if elected != nil {
+		r.ElectedID = elected.String()
+	}
+
+	el.records = append(el.records, r)
+	if uint64(len(el.records)) > ElectionLogSize {
+		el.records = el.records[uint64(len(el.records))-ElectionLogSize:]
+	}
+	if dbo := el.overlay(); dbo != nil {
+		if err := dbo.SaveElectionRecord(r, ElectionLogSize); err != nil {
+			el.s.LogPrintf("elections", "ElectionLog: failed to save election %d: %v", r.ID, err)
+		}
+	}
+
+	ElectionsFinished.WithLabelValues(outcome).Inc()
+	ElectionDuration.Observe(float64(r.DurationMs) / 1000)
+	ElectionRounds.Observe(float64(r.Rounds))
+	el.emit(r)
+}
+
+func (el *ElectionLog) emit(r *interfaces.ElectionRecord) {
+	if el.s != nil && el.s.EventService != nil {
+		el.s.EventService.EmitElectionEvent(r)
+	}
+}
+
+// Records returns the last elections that ended and the running one, oldest first
+func (el *ElectionLog) Records() ([]*interfaces.ElectionRecord, error) {
+	if el == nil {
+		return nil, nil
+	}
+	el.mtx.Lock()
+	defer el.mtx.Unlock()
+
+	if err := el.load(); err != nil {
+		return nil, err
+	}
+	records := make([]*interfaces.ElectionRecord, 0, len(el.records)+1)
+	records = append(records, el.records...)
+	if el.current != nil {
+		records = append(records, copyElectionRecord(el.current))
+	}
+	return records, nil
+}
+
+// copyElectionRecord copies the running election, which is still changing
+func copyElectionRecord(r *interfaces.ElectionRecord) *interfaces.ElectionRecord {
+	c := *r
+	c.Volunteers = append([]*interfaces.ElectionVolunteer(nil), r.Volunteers...)
+	c.Levels = append([]*interfaces.ElectionLevel(nil), r.Levels...)
+	c.Votes = make([]*interfaces.ElectionVotes, 0, len(r.Votes))
+	for _, v := range r.Votes {
+		votes := *v
+		votes.Voters = append([]string(nil), v.Voters...)
+		c.Votes = append(c.Votes, &votes)
+	}
+	return &c
+}
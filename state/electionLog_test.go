@@ -0,0 +1,96 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestElectionLog(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	el := s.ElectionLog
+	feds := s.GetFedServers(s.GetLLeaderHeight())
+	faulted := feds[0].GetChainID()
+	audit := primitives.Sha([]byte("audit"))
+	other := primitives.Sha([]byte("other"))
+
+	el.Start(10, 3, 0, 0, faulted, feds, nil)
+	el.Round(1)
+	el.Volunteer(audit, 1)
+	el.Volunteer(audit, 1) // Seen again in a proposal
+	el.Vote(faulted, audit)
+	el.Vote(faulted, audit)
+	el.Vote(other, audit)
+	el.Level(faulted, audit, 1, 0, false)
+	el.Level(faulted, audit, 2, 1, true)
+
+	records, err := s.GetElectionLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Outcome != interfaces.ElectionRunning {
+		t.Fatalf("expected a running election, found %v", records)
+	}
+	r := records[0]
+	if r.DBHeight != 10 || r.Minute != 3 || r.FaultedID != faulted.String() || r.Rounds != 1 || len(r.Federated) != len(feds) {
+		t.Errorf("wrong election start: %+v", r)
+	}
+	if len(r.Volunteers) != 1 || r.Volunteers[0].ServerID != audit.String() {
+		t.Errorf("expected one volunteer, found %v", r.Volunteers)
+	}
+	if len(r.Votes) != 1 || r.Votes[0].Votes != 2 {
+		t.Errorf("expected 2 votes for the volunteer, found %v", r.Votes)
+	}
+	if r.LevelMessages != 2 || len(r.Levels) != 2 || !r.Levels[1].Committed {
+		t.Errorf("expected 2 level messages, found %v", r.Levels)
+	}
+
+	el.Elected(audit)
+	el.Start(11, -1, 1, 1, faulted, feds, nil)
+	el.Start(12, -1, 1, 1, faulted, feds, nil) // Abandons the one before
+	el.Abandon()
+	el.Abandon()
+
+	records, _ = s.GetElectionLog()
+	if len(records) != 3 {
+		t.Fatalf("expected 3 elections, found %d", len(records))
+	}
+	if records[0].Outcome != interfaces.ElectionElected || records[0].ElectedID != audit.String() || records[0].Ended == 0 {
+		t.Errorf("expected the first election to elect %s, found %+v", audit, records[0])
+	}
+	for _, r := range records[1:] {
+		if r.Outcome != interfaces.ElectionAbandoned || r.ElectedID != "" {
+			t.Errorf("expected election %d abandoned, found %+v", r.ID, r)
+		}
+	}
+	if records[2].ID != records[0].ID+2 {
+		t.Errorf("expected consecutive ids, found %d and %d", records[0].ID, records[2].ID)
+	}
+
+	// A new log reads the elections saved, and keeps only the last ElectionLogSize
+	defer func(size uint64) { ElectionLogSize = size }(ElectionLogSize)
+	ElectionLogSize = 2
+	el = NewElectionLog(s)
+	el.Start(13, 0, 0, 0, faulted, feds, nil)
+	el.Abandon()
+	records, _ = el.Records()
+	if len(records) != 2 || records[1].ID != records[0].ID+1 || records[1].DBHeight != 13 {
+		t.Errorf("expected the last 2 elections, found %v", records)
+	}
+
+	// Nothing is recorded outside of an election, or on a nil log
+	el.Vote(faulted, audit)
+	el.Level(faulted, audit, 1, 0, false)
+	el.Elected(audit)
+	var nilLog *ElectionLog
+	nilLog.Start(14, 0, 0, 0, faulted, feds, nil)
+	if records, err := nilLog.Records(); err != nil || records != nil {
+		t.Errorf("expected nothing from a nil log, found %v %v", records, err)
+	}
+	if after, _ := el.Records(); len(after) != 2 || len(after[1].Votes) != 0 {
+		t.Errorf("expected no change outside an election, found %v", after)
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+var (
+	// ExportPollInterval is how often the exporters look for new blocks if they are not told
+	// about them.  Entries can arrive well after their dblock is saved.
+	ExportPollInterval = 10 * time.Second
+	// ExportRetryDelay is the pause after a sink fails before it is tried again
+	ExportRetryDelay = time.Minute
+)
+
+// ExportBlockSet is everything saved at one directory block height, as handed to the exporters
+type ExportBlockSet struct {
+	DBHeight uint32
+	DBlock   interfaces.IDirectoryBlock
+	ABlock   interfaces.IAdminBlock
+	FBlock   interfaces.IFBlock
+	ECBlock  interfaces.IEntryCreditBlock
+	EBlocks  []interfaces.IEntryBlock
+	Entries  [][]interfaces.IEBEntry // The entries of each eblock, in the same order as EBlocks
+}
+
+// BlockExporter is a sink for the blocks the node saves.  The pipeline hands every sink each
+// height in order, starting at the height the sink asks for in Open, so a sink added to an
+// already synced node catches up from the database.
+type BlockExporter interface {
+	// Name of the sink, for logs and metrics
+	Name() string
+	// Open prepares the sink, and returns its high-water mark: the next height it needs
+	Open() (next uint32, err error)
+	// Export writes the blocks at a height.  Once it returns nil the sink must not ask
+	// for that height again.  If it fails the sink is closed and opened again, so it can
+	// go back to its high-water mark.
+	Export(set *ExportBlockSet) error
+	Close() error
+}
+
+// LoadExportBlockSet reads all the blocks and entries at a height from the database.  It
+// fails if anything is missing, so the exporters never write a partial height.
+func LoadExportBlockSet(db interfaces.DBOverlaySimple, dbheight uint32) (*ExportBlockSet, error) {
+	set := new(ExportBlockSet)
+	set.DBHeight = dbheight
+
+	dblock, err := db.FetchDBlockByHeight(dbheight)
+	if err != nil {
+		return nil, err
+	}
+	if dblock == nil {
+		return nil, fmt.Errorf("dblock %d not found", dbheight)
+	}
+	set.DBlock = dblock
+
+	for _, e := range dblock.GetDBEntries() {
+		var block interface{}
+		switch {
+		case e.GetChainID().IsSameAs(primitives.NewHash(constants.ADMIN_CHAINID)):
+			set.ABlock, err = db.FetchABlock(e.GetKeyMR())
+			block = set.ABlock
+		case e.GetChainID().IsSameAs(primitives.NewHash(constants.FACTOID_CHAINID)):
+			set.FBlock, err = db.FetchFBlock(e.GetKeyMR())
+			block = set.FBlock
+		case e.GetChainID().IsSameAs(primitives.NewHash(constants.EC_CHAINID)):
+			set.ECBlock, err = db.FetchECBlock(e.GetKeyMR())
+			block = set.ECBlock
+		default:
+			var eblock interfaces.IEntryBlock
+			eblock, err = db.FetchEBlock(e.GetKeyMR())
+			if err == nil && eblock != nil {
+				var entries []interfaces.IEBEntry
+				entries, err = loadExportEntries(db, eblock)
+				set.EBlocks = append(set.EBlocks, eblock)
+				set.Entries = append(set.Entries, entries)
+			}
+			block = eblock
+		}
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %x of chain %x at height %d not found", e.GetKeyMR().Bytes()[:4], e.GetChainID().Bytes()[:4], dbheight)
+		}
+	}
+	return set, nil
+}
+
+func loadExportEntries(db interfaces.DBOverlaySimple, eblock interfaces.IEntryBlock) ([]interfaces.IEBEntry, error) {
+	var entries []interfaces.IEBEntry
+	for _, hash := range eblock.GetEntryHashes() {
+		if hash.IsMinuteMarker() {
+			continue
+		}
+		entry, err := db.FetchEntry(hash)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return nil, fmt.Errorf("entry %x not found", hash.Bytes()[:4])
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// NewBlockExporter creates one of the built in sinks from the config
+func NewBlockExporter(s *State, format string) (BlockExporter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "files":
+		return NewFileExporter(s.ExportDataSubpath), nil
+	case "ndjson":
+		return NewNDJSONExporter(filepath.Join(s.ExportDataSubpath, "ndjson")), nil
+	case "parquet":
+		return NewParquetExporter(filepath.Join(s.ExportDataSubpath, "parquet"), s.ExportParquetRange), nil
+	case "sql":
+		return NewSQLExporter(s.ExportSQLDriver, s.ExportSQLSource), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// ExportPipeline hands the blocks the node saves to a set of BlockExporters.  Every sink
+// runs in its own goroutine and keeps its own high-water mark, so a slow or broken sink
+// doesn't hold up the others.
+type ExportPipeline struct {
+	s      *State
+	sinks  []*exportSink
+	closer chan interface{}
+}
+
+type exportSink struct {
+	BlockExporter
+	open bool
+	next uint32
+	wake chan interface{}
+}
+
+// NewExportPipeline creates a pipeline with the sinks listed in the ExportFormats config
+func NewExportPipeline(s *State) (*ExportPipeline, error) {
+	p := new(ExportPipeline)
+	p.s = s
+	p.closer = make(chan interface{})
+	for _, format := range strings.Split(s.ExportFormats, ",") {
+		if strings.TrimSpace(format) == "" {
+			continue
+		}
+		e, err := NewBlockExporter(s, format)
+		if err != nil {
+			return nil, err
+		}
+		p.Add(e)
+	}
+	return p, nil
+}
+
+// Add adds a sink to the pipeline.  Sinks must be added before Run is called.
+func (p *ExportPipeline) Add(e BlockExporter) {
+	p.sinks = append(p.sinks, &exportSink{BlockExporter: e, wake: make(chan interface{}, 1)})
+}
+
+// Notify tells the sinks another block was saved
+func (p *ExportPipeline) Notify() {
+	for _, sink := range p.sinks {
+		select {
+		case sink.wake <- nil:
+		default:
+		}
+	}
+}
+
+// Stop ends the pipeline, closing the sinks
+func (p *ExportPipeline) Stop() {
+	close(p.closer)
+}
+
+// exportableHeight is the highest height that has all its blocks and entries in the database
+func (p *ExportPipeline) exportableHeight() uint32 {
+	end := p.s.GetHighestSavedBlk()
+	if complete := p.s.GetEntryBlockDBHeightComplete(); complete < end {
+		end = complete
+	}
+	return end
+}
+
+// Run starts a goroutine for each sink.  It waits for the database to be loaded first.
+func (p *ExportPipeline) Run() {
+	for !p.s.DBFinished {
+		select {
+		case <-p.closer:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, sink := range p.sinks {
+		wg.Add(1)
+		go func(sink *exportSink) {
+			defer wg.Done()
+			p.runSink(sink)
+		}(sink)
+	}
+	wg.Wait()
+}
+
+func (p *ExportPipeline) runSink(sink *exportSink) {
+	for {
+		wait := ExportPollInterval
+		if err := p.export(sink, p.exportableHeight()); err != nil {
+			p.s.LogPrintf("export", "%s failed at height %d: %v", sink.Name(), sink.next, err)
+			ExportErrors.WithLabelValues(sink.Name()).Inc()
+			wait = ExportRetryDelay
+		}
+
+		select {
+		case <-p.closer:
+			if sink.open {
+				if err := sink.Close(); err != nil {
+					p.s.LogPrintf("export", "%s failed to close: %v", sink.Name(), err)
+				}
+			}
+			return
+		case <-sink.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// ExportTo brings every sink up to the given height, one sink after another.  Run does the
+// same in the background; this is for tools and tests.
+func (p *ExportPipeline) ExportTo(end uint32) error {
+	for _, sink := range p.sinks {
+		if err := p.export(sink, end); err != nil {
+			return fmt.Errorf("%s: %v", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Close closes all the sinks that were opened
+func (p *ExportPipeline) Close() error {
+	var first error
+	for _, sink := range p.sinks {
+		if sink.open {
+			if err := sink.Close(); err != nil && first == nil {
+				first = err
+			}
+			sink.open = false
+		}
+	}
+	return first
+}
+
+// export brings a sink up to the given height
+func (p *ExportPipeline) export(sink *exportSink, end uint32) error {
+	if !sink.open {
+		next, err := sink.Open()
+		if err != nil {
+			return err
+		}
+		sink.open = true
+		sink.next = next
+		p.s.LogPrintf("export", "%s opened, next height %d", sink.Name(), next)
+	}
+
+	for sink.next <= end {
+		select {
+		case <-p.closer:
+			return nil
+		default:
+		}
+
+		set, err := LoadExportBlockSet(p.s.DB, sink.next)
+		if err != nil {
+			return err
+		}
+		if err := sink.Export(set); err != nil {
+			sink.open = false
+			if cerr := sink.Close(); cerr != nil {
+				return fmt.Errorf("%v, then failed to close: %v", err, cerr)
+			}
+			return err
+		}
+		ExportHeight.WithLabelValues(sink.Name()).Set(float64(sink.next))
+		sink.next++
+	}
+	return nil
+}
+
+// exportMark is the high-water mark of a file based sink, kept in a small JSON file in its
+// output directory.  Sizes holds the length of each output file that is appended to, so
+// anything written after the mark can be cut off after a crash.
+type exportMark struct {
+	Next  uint32           `json:"next"`
+	Sizes map[string]int64 `json:"sizes,omitempty"`
+}
+
+const exportMarkFile = "export.mark"
+
+func readExportMark(dir string) (*exportMark, error) {
+	mark := new(exportMark)
+	mark.Sizes = make(map[string]int64)
+	data, err := ioutil.ReadFile(filepath.Join(dir, exportMarkFile))
+	if os.IsNotExist(err) {
+		return mark, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, mark); err != nil {
+		return nil, fmt.Errorf("%s: %v", exportMarkFile, err)
+	}
+	if mark.Sizes == nil {
+		mark.Sizes = make(map[string]int64)
+	}
+	return mark, nil
+}
+
+// write replaces the mark on disk in one step, so it is never left half written
+func (m *exportMark) write(dir string) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, exportMarkFile), data)
+}
+
+// writeFileAtomic writes a file by writing a temporary file and renaming it
+func writeFileAtomic(name string, data []byte) error {
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
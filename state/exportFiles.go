@@ -0,0 +1,232 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/blockExtractor"
+)
+
+// FileExporter writes every block and entry to its own file, in the format the ExportData
+// config option always has: binary and JSON files in a directory per chain.
+type FileExporter struct {
+	Path string
+
+	extractor blockExtractor.BlockExtractor
+}
+
+var _ BlockExporter = (*FileExporter)(nil)
+
+// NewFileExporter creates a FileExporter writing under path
+func NewFileExporter(path string) *FileExporter {
+	e := new(FileExporter)
+	e.Path = path
+	// The block extractor builds the paths by concatenation
+	if len(path) > 0 && !os.IsPathSeparator(path[len(path)-1]) {
+		path += string(os.PathSeparator)
+	}
+	e.extractor.DataStorePath = path
+	return e
+}
+
+func (e *FileExporter) Name() string {
+	return "files"
+}
+
+func (e *FileExporter) Open() (uint32, error) {
+	if err := os.MkdirAll(e.Path, 0755); err != nil {
+		return 0, err
+	}
+	mark, err := readExportMark(e.Path)
+	if err != nil {
+		return 0, err
+	}
+	return mark.Next, nil
+}
+
+// Export writes the blocks at a height.  The files are named by height, so a height that is
+// written again after a crash just overwrites the same files.
+func (e *FileExporter) Export(set *ExportBlockSet) error {
+	// Making sure Hash and KeyMR are set for the JSON export
+	set.DBlock.GetFullHash()
+	set.DBlock.GetKeyMR()
+
+	blocks := []interfaces.DatabaseBatchable{set.DBlock, set.ABlock, set.FBlock, set.ECBlock}
+	for _, block := range blocks {
+		if err := e.extractor.ExportBlock(block); err != nil {
+			return err
+		}
+	}
+	for i, eblock := range set.EBlocks {
+		if err := e.extractor.ExportBlock(eblock); err != nil {
+			return err
+		}
+		for _, entry := range set.Entries[i] {
+			if err := e.extractor.ExportEntry(entry, set.DBHeight); err != nil {
+				return err
+			}
+		}
+	}
+	return (&exportMark{Next: set.DBHeight + 1}).write(e.Path)
+}
+
+func (e *FileExporter) Close() error {
+	return nil
+}
+
+// The files the NDJSONExporter writes, one per kind of block
+var ndjsonFiles = []string{"dblocks.ndjson", "ablocks.ndjson", "fblocks.ndjson", "ecblocks.ndjson", "eblocks.ndjson", "entries.ndjson"}
+
+// NDJSONExporter appends every block to a newline delimited JSON file per type of block.
+// Each line is an object holding the height and the block (or entry) as the API returns it.
+type NDJSONExporter struct {
+	Path string
+
+	files map[string]*os.File
+	mark  *exportMark
+}
+
+var _ BlockExporter = (*NDJSONExporter)(nil)
+
+// NewNDJSONExporter creates a NDJSONExporter writing into the directory path
+func NewNDJSONExporter(path string) *NDJSONExporter {
+	e := new(NDJSONExporter)
+	e.Path = path
+	return e
+}
+
+func (e *NDJSONExporter) Name() string {
+	return "ndjson"
+}
+
+// Open opens the files for appending.  Anything written past the high-water mark, by a node
+// that stopped or failed part way through a height, is cut off.
+func (e *NDJSONExporter) Open() (uint32, error) {
+	if err := os.MkdirAll(e.Path, 0755); err != nil {
+		return 0, err
+	}
+	mark, err := readExportMark(e.Path)
+	if err != nil {
+		return 0, err
+	}
+
+	e.files = make(map[string]*os.File)
+	for _, name := range ndjsonFiles {
+		f, err := os.OpenFile(filepath.Join(e.Path, name), os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			e.Close()
+			return 0, err
+		}
+		e.files[name] = f
+	}
+	e.mark = mark
+	if err := e.rewind(); err != nil {
+		e.Close()
+		return 0, err
+	}
+	return mark.Next, nil
+}
+
+// rewind cuts the files back to the sizes they had at the high-water mark
+func (e *NDJSONExporter) rewind() error {
+	for _, name := range ndjsonFiles {
+		if err := e.files[name].Truncate(e.mark.Sizes[name]); err != nil {
+			return err
+		}
+		if _, err := e.files[name].Seek(e.mark.Sizes[name], 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ndjsonLine struct {
+	DBHeight uint32          `json:"dbheight"`
+	Block    json.RawMessage `json:"block,omitempty"`
+	Entry    json.RawMessage `json:"entry,omitempty"`
+	EBlock   string          `json:"ebkeymr,omitempty"` // The keymr of the eblock an entry is in
+}
+
+func (e *NDJSONExporter) Export(set *ExportBlockSet) error {
+	// Making sure Hash and KeyMR are set for the JSON export
+	set.DBlock.GetFullHash()
+	set.DBlock.GetKeyMR()
+
+	out := make(map[string]*bytes.Buffer)
+	for _, name := range ndjsonFiles {
+		out[name] = new(bytes.Buffer)
+	}
+
+	write := func(name string, line *ndjsonLine, p interfaces.Printable) error {
+		data, err := p.JSONByte()
+		if err != nil {
+			return err
+		}
+		if line.EBlock == "" {
+			line.Block = data
+		} else {
+			line.Entry = data
+		}
+		data, err = json.Marshal(line)
+		if err != nil {
+			return err
+		}
+		out[name].Write(data)
+		out[name].WriteByte('\n')
+		return nil
+	}
+
+	blocks := []interfaces.Printable{set.DBlock, set.ABlock, set.FBlock, set.ECBlock}
+	for i, block := range blocks {
+		if err := write(ndjsonFiles[i], &ndjsonLine{DBHeight: set.DBHeight}, block); err != nil {
+			return err
+		}
+	}
+	for i, eblock := range set.EBlocks {
+		if err := write("eblocks.ndjson", &ndjsonLine{DBHeight: set.DBHeight}, eblock); err != nil {
+			return err
+		}
+		keymr, err := eblock.KeyMR()
+		if err != nil {
+			return err
+		}
+		for _, entry := range set.Entries[i] {
+			if err := write("entries.ndjson", &ndjsonLine{DBHeight: set.DBHeight, EBlock: keymr.String()}, entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	mark := &exportMark{Next: set.DBHeight + 1, Sizes: make(map[string]int64)}
+	for _, name := range ndjsonFiles {
+		n, err := e.files[name].Write(out[name].Bytes())
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		mark.Sizes[name] = e.mark.Sizes[name] + int64(n)
+	}
+	if err := mark.write(e.Path); err != nil {
+		return err
+	}
+	e.mark = mark
+	return nil
+}
+
+func (e *NDJSONExporter) Close() error {
+	var first error
+	for _, f := range e.files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	e.files = nil
+	return first
+}
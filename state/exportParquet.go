@@ -0,0 +1,117 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/FactomProject/factomd/util/parquet"
+)
+
+// ParquetExporter writes the exportTables as Parquet files, one file per table for every
+// Range heights: <path>/<table>/<first height>-<last height>.parquet
+//
+// A partition is only written once all its heights are saved, so the most recent blocks
+// show up in batches of Range.  If the node stops part way through a partition, the
+// partition is exported again from its start.
+type ParquetExporter struct {
+	Path  string
+	Range uint32
+
+	tables map[string]*parquet.Writer
+	start  uint32 // first height of the partition being collected
+}
+
+var _ BlockExporter = (*ParquetExporter)(nil)
+
+// NewParquetExporter creates a ParquetExporter writing into the directory path, with
+// heights partitioned into files of heightRange blocks
+func NewParquetExporter(path string, heightRange uint32) *ParquetExporter {
+	e := new(ParquetExporter)
+	e.Path = path
+	e.Range = heightRange
+	if e.Range == 0 {
+		e.Range = 1000
+	}
+	return e
+}
+
+func (e *ParquetExporter) Name() string {
+	return "parquet"
+}
+
+func (e *ParquetExporter) Open() (uint32, error) {
+	for _, t := range exportTables {
+		if err := os.MkdirAll(filepath.Join(e.Path, t.Name), 0755); err != nil {
+			return 0, err
+		}
+	}
+	mark, err := readExportMark(e.Path)
+	if err != nil {
+		return 0, err
+	}
+	// The mark is always the start of a partition, unless the range was changed
+	e.start = mark.Next - mark.Next%e.Range
+	e.reset()
+	return e.start, nil
+}
+
+func (e *ParquetExporter) reset() {
+	e.tables = make(map[string]*parquet.Writer)
+	for _, t := range exportTables {
+		e.tables[t.Name] = parquet.NewWriter(t.Columns)
+	}
+}
+
+func (e *ParquetExporter) Export(set *ExportBlockSet) error {
+	if set.DBHeight < e.start || set.DBHeight >= e.start+e.Range {
+		return fmt.Errorf("height %d is outside the partition starting at %d", set.DBHeight, e.start)
+	}
+	rows, err := exportRows(set)
+	if err != nil {
+		return err
+	}
+	for _, t := range exportTables {
+		for _, row := range rows[t.Name] {
+			if err := e.tables[t.Name].Append(row...); err != nil {
+				return err
+			}
+		}
+	}
+
+	if set.DBHeight == e.start+e.Range-1 {
+		return e.flush()
+	}
+	return nil
+}
+
+// flush writes the files of the current partition, and starts the next one
+func (e *ParquetExporter) flush() error {
+	end := e.start + e.Range - 1
+	for _, t := range exportTables {
+		var buf bytes.Buffer
+		if _, err := e.tables[t.Name].WriteTo(&buf); err != nil {
+			return err
+		}
+		name := filepath.Join(e.Path, t.Name, fmt.Sprintf("%09d-%09d.parquet", e.start, end))
+		if err := writeFileAtomic(name, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := (&exportMark{Next: end + 1}).write(e.Path); err != nil {
+		return err
+	}
+	e.start = end + 1
+	e.reset()
+	return nil
+}
+
+func (e *ParquetExporter) Close() error {
+	e.tables = nil
+	return nil
+}
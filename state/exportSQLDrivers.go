@@ -0,0 +1,12 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+// The database/sql drivers the sql sink can use: "postgres" and "sqlite3".  The SQLite
+// driver needs cgo; without it opening a sqlite3 database fails.
+import (
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
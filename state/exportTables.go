@@ -0,0 +1,161 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/util/parquet"
+)
+
+// exportTable is a table written by the Parquet and SQL exporters
+type exportTable struct {
+	Name    string
+	Columns []parquet.Column
+}
+
+// The tables the Parquet and SQL exporters write.  Hashes are hex strings, times are
+// unix seconds for blocks and unix milliseconds for transactions and commits.
+var exportTables = []exportTable{
+	{"dblocks", []parquet.Column{
+		{Name: "height", Type: parquet.Int64},
+		{Name: "keymr", Type: parquet.String},
+		{Name: "full_hash", Type: parquet.String},
+		{Name: "prev_keymr", Type: parquet.String},
+		{Name: "timestamp", Type: parquet.Int64},
+		{Name: "block_count", Type: parquet.Int64},
+	}},
+	{"eblocks", []parquet.Column{
+		{Name: "height", Type: parquet.Int64},
+		{Name: "keymr", Type: parquet.String},
+		{Name: "chain_id", Type: parquet.String},
+		{Name: "sequence", Type: parquet.Int64},
+		{Name: "prev_keymr", Type: parquet.String},
+		{Name: "entry_count", Type: parquet.Int64},
+	}},
+	{"entries", []parquet.Column{
+		{Name: "height", Type: parquet.Int64},
+		{Name: "entry_hash", Type: parquet.String},
+		{Name: "chain_id", Type: parquet.String},
+		{Name: "eblock_keymr", Type: parquet.String},
+		{Name: "ext_ids", Type: parquet.String}, // JSON array of hex strings
+		{Name: "content", Type: parquet.ByteArray},
+	}},
+	{"fct_transactions", []parquet.Column{
+		{Name: "height", Type: parquet.Int64},
+		{Name: "tx_id", Type: parquet.String},
+		{Name: "timestamp", Type: parquet.Int64},
+		{Name: "input_count", Type: parquet.Int64},
+		{Name: "output_count", Type: parquet.Int64},
+		{Name: "ec_output_count", Type: parquet.Int64},
+		{Name: "input_total", Type: parquet.Int64},
+		{Name: "output_total", Type: parquet.Int64},
+		{Name: "ec_output_total", Type: parquet.Int64},
+	}},
+	{"ec_commits", []parquet.Column{
+		{Name: "height", Type: parquet.Int64},
+		{Name: "type", Type: parquet.String}, // "chain" or "entry"
+		{Name: "entry_hash", Type: parquet.String},
+		{Name: "ec_pubkey", Type: parquet.String},
+		{Name: "credits", Type: parquet.Int64},
+		{Name: "timestamp", Type: parquet.Int64},
+	}},
+}
+
+// exportRows flattens the blocks at a height into the rows of each of the exportTables
+func exportRows(set *ExportBlockSet) (map[string][][]interface{}, error) {
+	rows := make(map[string][][]interface{})
+	h := int64(set.DBHeight)
+
+	header := set.DBlock.GetHeader()
+	rows["dblocks"] = append(rows["dblocks"], []interface{}{
+		h,
+		set.DBlock.GetKeyMR().String(),
+		set.DBlock.GetFullHash().String(),
+		header.GetPrevKeyMR().String(),
+		header.GetTimestamp().GetTimeSeconds(),
+		int64(header.GetBlockCount()),
+	})
+
+	for i, eblock := range set.EBlocks {
+		keymr, err := eblock.KeyMR()
+		if err != nil {
+			return nil, err
+		}
+		eh := eblock.GetHeader()
+		rows["eblocks"] = append(rows["eblocks"], []interface{}{
+			h,
+			keymr.String(),
+			eh.GetChainID().String(),
+			int64(eh.GetEBSequence()),
+			eh.GetPrevKeyMR().String(),
+			int64(eh.GetEntryCount()),
+		})
+
+		for _, entry := range set.Entries[i] {
+			extIDs := []string{}
+			for _, id := range entry.ExternalIDs() {
+				extIDs = append(extIDs, hex.EncodeToString(id))
+			}
+			ids, err := json.Marshal(extIDs)
+			if err != nil {
+				return nil, err
+			}
+			rows["entries"] = append(rows["entries"], []interface{}{
+				h,
+				entry.GetHash().String(),
+				entry.GetChainID().String(),
+				keymr.String(),
+				string(ids),
+				entry.GetContent(),
+			})
+		}
+	}
+
+	for _, tx := range set.FBlock.GetTransactions() {
+		in, err := tx.TotalInputs()
+		if err != nil {
+			return nil, err
+		}
+		out, err := tx.TotalOutputs()
+		if err != nil {
+			return nil, err
+		}
+		ecs, err := tx.TotalECs()
+		if err != nil {
+			return nil, err
+		}
+		rows["fct_transactions"] = append(rows["fct_transactions"], []interface{}{
+			h,
+			tx.GetSigHash().String(),
+			tx.GetTimestamp().GetTimeMilli(),
+			int64(len(tx.GetInputs())),
+			int64(len(tx.GetOutputs())),
+			int64(len(tx.GetECOutputs())),
+			int64(in),
+			int64(out),
+			int64(ecs),
+		})
+	}
+
+	for _, e := range set.ECBlock.GetEntries() {
+		switch e.ECID() {
+		case constants.ECIDChainCommit:
+			c := e.(*entryCreditBlock.CommitChain)
+			rows["ec_commits"] = append(rows["ec_commits"], []interface{}{
+				h, "chain", c.EntryHash.String(), c.ECPubKey.String(), int64(c.Credits), c.GetTimestamp().GetTimeMilli(),
+			})
+		case constants.ECIDEntryCommit:
+			c := e.(*entryCreditBlock.CommitEntry)
+			rows["ec_commits"] = append(rows["ec_commits"], []interface{}{
+				h, "entry", c.EntryHash.String(), c.ECPubKey.String(), int64(c.Credits), c.GetTimestamp().GetTimeMilli(),
+			})
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,204 @@
+package state_test
+
+import (
+	"bufio"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func countLines(t *testing.T, name string) int {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		n++
+	}
+	return n
+}
+
+func TestExportPipeline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.ExportDataSubpath = dir + "/"
+	s.ExportFormats = "files, ndjson,parquet"
+	s.ExportParquetRange = 4
+
+	p, err := NewExportPipeline(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := uint32(testHelper.BlockCount - 1)
+	if err := p.ExportTo(last); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// files: the same layout as the old ExportData
+	blocks := testHelper.CreateFullTestBlockSet()
+	for _, name := range []string{
+		"000000000000000000000000000000000000000000000000000000000000000d/store.000000009.block",
+		"000000000000000000000000000000000000000000000000000000000000000f/storeJSON.000000005.block",
+		blocks[3].EBlock.GetChainID().String() + "/store.000000003.block",
+		blocks[3].EBlock.GetChainID().String() + "/entries/store.000000003." + blocks[3].Entries[0].GetHash().String() + ".entry",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("files: %v", err)
+		}
+	}
+
+	// ndjson: a line per block
+	entries := 0
+	for _, b := range blocks {
+		entries += len(b.Entries)
+	}
+	if n := countLines(t, filepath.Join(dir, "ndjson", "dblocks.ndjson")); n != testHelper.BlockCount {
+		t.Errorf("ndjson: expected %d dblocks, found %d", testHelper.BlockCount, n)
+	}
+	if n := countLines(t, filepath.Join(dir, "ndjson", "entries.ndjson")); n != entries {
+		t.Errorf("ndjson: expected %d entries, found %d", entries, n)
+	}
+
+	// parquet: only the complete partitions are written
+	for _, name := range []string{"000000000-000000003.parquet", "000000004-000000007.parquet"} {
+		if _, err := os.Stat(filepath.Join(dir, "parquet", "entries", name)); err != nil {
+			t.Errorf("parquet: %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "parquet", "dblocks", "000000008-000000011.parquet")); err == nil {
+		t.Errorf("parquet: incomplete partition was written")
+	}
+
+	// A half written height is cut off when the sink is opened again, and then written again
+	f, err := os.OpenFile(filepath.Join(dir, "ndjson", "dblocks.ndjson"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{\"dbheight\":10,")
+	f.Close()
+
+	ndjson := NewNDJSONExporter(filepath.Join(dir, "ndjson"))
+	next, err := ndjson.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != last+1 {
+		t.Errorf("ndjson: expected next height %d, got %d", last+1, next)
+	}
+	ndjson.Close()
+	if n := countLines(t, filepath.Join(dir, "ndjson", "dblocks.ndjson")); n != testHelper.BlockCount {
+		t.Errorf("ndjson: expected the partial line to be cut off, found %d lines", n)
+	}
+
+	parquet := NewParquetExporter(filepath.Join(dir, "parquet"), 4)
+	if next, err = parquet.Open(); err != nil || next != 8 {
+		t.Errorf("parquet: expected next height 8, got %d %v", next, err)
+	}
+}
+
+func TestExportPipelineCatchUp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.ExportFormats = ""
+	p, _ := NewExportPipeline(s)
+	p.Add(NewNDJSONExporter(dir))
+	if err := p.ExportTo(4); err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+
+	// A new pipeline picks up where the last one stopped
+	p, _ = NewExportPipeline(s)
+	p.Add(NewNDJSONExporter(dir))
+	if err := p.ExportTo(uint32(testHelper.BlockCount - 1)); err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+	if n := countLines(t, filepath.Join(dir, "dblocks.ndjson")); n != testHelper.BlockCount {
+		t.Errorf("expected %d dblocks, found %d", testHelper.BlockCount, n)
+	}
+}
+
+func TestSQLExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, "export.sqlite")
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.ExportFormats = ""
+	p, _ := NewExportPipeline(s)
+	p.Add(NewSQLExporter("sqlite3", source))
+	if err := p.ExportTo(uint32(testHelper.BlockCount - 1)); err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+
+	db, err := sql.Open("sqlite3", source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	count := func(table string) int {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	entries := 0
+	for _, b := range testHelper.CreateFullTestBlockSet() {
+		entries += len(b.Entries)
+	}
+	if n := count("dblocks"); n != testHelper.BlockCount {
+		t.Errorf("expected %d dblocks, found %d", testHelper.BlockCount, n)
+	}
+	if n := count("entries"); n != entries {
+		t.Errorf("expected %d entries, found %d", entries, n)
+	}
+	for _, table := range []string{"eblocks", "fct_transactions", "ec_commits"} {
+		if count(table) == 0 {
+			t.Errorf("no rows in %s", table)
+		}
+	}
+
+	// The chain of an entry is found by its hash
+	var chainID string
+	entry := testHelper.CreateFullTestBlockSet()[1].Entries[0]
+	err = db.QueryRow("SELECT chain_id FROM entries WHERE entry_hash = ?", entry.GetHash().String()).Scan(&chainID)
+	if err != nil || chainID != entry.GetChainID().String() {
+		t.Errorf("expected the entry in chain %s, got %q %v", entry.GetChainID().String(), chainID, err)
+	}
+
+	// The high-water mark is read back when the sink is opened again
+	e := NewSQLExporter("sqlite3", source)
+	if next, err := e.Open(); err != nil || next != uint32(testHelper.BlockCount) {
+		t.Errorf("expected next height %d, got %d %v", testHelper.BlockCount, next, err)
+	}
+	e.Close()
+}
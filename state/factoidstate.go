@@ -389,6 +389,12 @@ func (fs *FactoidState) ProcessEndOfBlock(state interfaces.IState) {
 // Returns an error message about what is wrong with the transaction if it is
 // invalid, otherwise you are good to go.
 func (fs *FactoidState) Validate(index int, trans interfaces.ITransaction) (err error, holdAddr [32]byte) {
+	for _, rcd := range trans.GetRCDs() { // Multisig inputs are only good once activated
+		if _, ok := rcd.(*factoid.RCD_2); ok && !fs.State.IsActive(activations.RCD_2_MULTISIG) {
+			return fmt.Errorf("Multisig (RCD type 2) inputs are not active at height %d", fs.DBHeight), holdAddr
+		}
+	}
+
 	var sums = make(map[[32]byte]uint64, 10)  // Look at the sum of an address's inputs
 	for _, input := range trans.GetInputs() { //    to a transaction.
 		bal, err := factoid.ValidateAmounts(sums[input.GetAddress().Fixed()], input.GetAmount())
@@ -421,11 +427,7 @@ func (fs *FactoidState) GetCoinbaseTransaction(dbheight uint32, ftime interfaces
 
 	// Coinbases only have outputs on payout blocks.
 	//	Payout blocks are every n blocks, where n is the coinbase frequency
-	if dbheight > constants.COINBASE_ACTIVATION && // Coinbase code must be above activation
-		dbheight != 0 && // Does not affect gensis
-		(dbheight%constants.COINBASE_PAYOUT_FREQUENCY == 0 || dbheight%constants.COINBASE_PAYOUT_FREQUENCY == 1) && // Frequency of payouts
-		// Cannot payout before a declaration (cannot grab below height 0)
-		dbheight > constants.COINBASE_DECLARATION+constants.COINBASE_PAYOUT_FREQUENCY {
+	if constants.IsCoinbasePayoutHeight(dbheight) {
 		// Grab the admin block 1000 blocks earlier
 		descriptorHeight := dbheight - constants.COINBASE_DECLARATION
 		ablock, err := fs.State.DB.FetchABlockByHeight(descriptorHeight)
@@ -580,3 +582,16 @@ func (fs *FactoidState) GetMultipleFactoidBalances(singleAdd [32]byte) (uint32,
 //
 //	return height, list
 //}
+
+// GetBalancesAt returns all the factoid and entry credit balances at the end of the block at
+// dbheight, rebuilt from the balance history.  The maps must not be changed.
+func (fs *FactoidState) GetBalancesAt(dbheight uint32) (map[[32]byte]int64, map[[32]byte]int64, error) {
+	if fs.State.BalanceHistory == nil {
+		return nil, nil, fmt.Errorf("the balance history is not kept on this node")
+	}
+	balances, err := fs.State.BalanceHistory.Balances(dbheight)
+	if err != nil {
+		return nil, nil, err
+	}
+	return balances.FCT, balances.EC, nil
+}
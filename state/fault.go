@@ -7,8 +7,8 @@ package state
 import (
 	"encoding/binary"
 	"fmt"
-	"time"
 
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 
@@ -86,7 +86,7 @@ func markFault(pl *ProcessList, vmIndex int, faultReason int) {
 		return
 	}
 
-	now := time.Now().Unix()
+	now := clock.Now().Unix()
 	vm := pl.VMs[vmIndex]
 
 	if vm.WhenFaulted == 0 {
@@ -129,7 +129,7 @@ func markNoFault(pl *ProcessList, vmIndex int) {
 }
 
 func FaultCheck(pl *ProcessList) {
-	now := time.Now().Unix()
+	now := clock.Now().Unix()
 
 	for i := 0; i < len(pl.FedServers); i++ {
 		if i == pl.State.LeaderVMIndex {
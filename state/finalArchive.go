@@ -0,0 +1,579 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/activations"
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The final archive is a record of the state of Factom at the end of its era (the height of the
+// FactomMaxHeight activation): every factoid and entry credit balance, the head of every chain,
+// and the authority set.  It is built from the blocks alone, so every node with the same blocks
+// builds the same archive.  The records are hashed into a Merkle root, which the node signs
+// with its identity key, so operators can attest to the final state by comparing roots.
+//
+// Each section has its own root, over the hashes of its records in the order they are listed.
+// The Merkle root is over the header hash and the four section roots:
+//   header:      'H' [dbheight uint32] [dblock keymr]
+//   factoid:     'F' [address] [balance int64]
+//   ec:          'E' [address] [balance int64]
+//   chain head:  'C' [chainid] [keymr] [dbheight uint32]
+//   authority:   'A' [chainid] [status byte] [signing key] [matryoshka hash] [coinbase address]
+//                    [efficiency uint16] { [priority byte] [type byte] [20 byte key] }
+// Integers are big endian, and everything else is 32 bytes.
+
+const FinalArchiveVersion = 1
+
+// FinalArchivePollInterval is how often the node checks whether the final height has been saved
+var FinalArchivePollInterval = 10 * time.Second
+
+// FinalArchive is the final state of Factom, as written to the archive file
+type FinalArchive struct {
+	Version     int    `json:"version"`
+	Network     string `json:"network"` // For information, it isn't covered by the Merkle root
+	DBHeight    uint32 `json:"dbheight"`
+	DBlockKeyMR string `json:"dblockkeymr"`
+
+	FactoidBalances     []*ArchiveBalance   `json:"factoidbalances"`     // Sorted by address, zero balances are left out
+	EntryCreditBalances []*ArchiveBalance   `json:"entrycreditbalances"` // Sorted by address, zero balances are left out
+	ChainHeads          []*ArchiveChainHead `json:"chainheads"`          // Sorted by chain id
+	Authorities         []*ArchiveAuthority `json:"authorities"`         // Sorted by chain id
+
+	Roots      ArchiveRoots      `json:"roots"`
+	MerkleRoot string            `json:"merkleroot"`
+	Signature  *ArchiveSignature `json:"signature,omitempty"`
+}
+
+type ArchiveBalance struct {
+	Address string `json:"address"` // FA... or EC... address
+	Balance int64  `json:"balance"` // In factoshis or entry credits
+}
+
+type ArchiveChainHead struct {
+	ChainID  string `json:"chainid"`
+	KeyMR    string `json:"keymr"`
+	DBHeight uint32 `json:"dbheight"` // Height of the directory block holding the head
+}
+
+type ArchiveAuthority struct {
+	ChainID         string              `json:"chainid"`
+	Status          string              `json:"status"` // "federated" or "audit"
+	SigningKey      string              `json:"signingkey"`
+	MatryoshkaHash  string              `json:"matryoshkahash"`
+	CoinbaseAddress string              `json:"coinbaseaddress"` // Hex of the address, zeros if not set
+	Efficiency      uint16              `json:"efficiency"`
+	AnchorKeys      []*ArchiveAnchorKey `json:"anchorkeys"` // Sorted by priority and type
+}
+
+type ArchiveAnchorKey struct {
+	Priority byte   `json:"priority"`
+	Type     byte   `json:"type"`
+	Key      string `json:"key"`
+}
+
+// ArchiveRoots are the Merkle roots of each section, to find where two archives disagree
+type ArchiveRoots struct {
+	Header              string `json:"header"`
+	FactoidBalances     string `json:"factoidbalances"`
+	EntryCreditBalances string `json:"entrycreditbalances"`
+	ChainHeads          string `json:"chainheads"`
+	Authorities         string `json:"authorities"`
+}
+
+// ArchiveSignature is the signature of a node's identity over the Merkle root
+type ArchiveSignature struct {
+	IdentityChainID string `json:"identitychainid"`
+	PublicKey       string `json:"publickey"`
+	Signature       string `json:"signature"`
+}
+
+// fixedHex decodes a hex string that must be 32 bytes
+func fixedHex(name string, s string) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		return nil, fmt.Errorf("bad %s %q", name, s)
+	}
+	return b, nil
+}
+
+func (b *ArchiveBalance) leaf(kind byte) (interfaces.IHash, error) {
+	if (kind == 'F' && !primitives.ValidateFUserStr(b.Address)) || (kind == 'E' && !primitives.ValidateECUserStr(b.Address)) {
+		return nil, fmt.Errorf("bad address %q", b.Address)
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteByte(kind)
+	buf.Write(primitives.ConvertUserStrToAddress(b.Address))
+	binary.Write(buf, binary.BigEndian, b.Balance)
+	return primitives.Sha(buf.Bytes()), nil
+}
+
+func (c *ArchiveChainHead) leaf() (interfaces.IHash, error) {
+	chainID, err := fixedHex("chain id", c.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	keyMR, err := fixedHex("keymr", c.KeyMR)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteByte('C')
+	buf.Write(chainID)
+	buf.Write(keyMR)
+	binary.Write(buf, binary.BigEndian, c.DBHeight)
+	return primitives.Sha(buf.Bytes()), nil
+}
+
+func (a *ArchiveAuthority) leaf() (interfaces.IHash, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('A')
+	chainID, err := fixedHex("chain id", a.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(chainID)
+	switch a.Status {
+	case "federated":
+		buf.WriteByte(constants.IDENTITY_FEDERATED_SERVER)
+	case "audit":
+		buf.WriteByte(constants.IDENTITY_AUDIT_SERVER)
+	default:
+		return nil, fmt.Errorf("bad authority status %q", a.Status)
+	}
+	for _, f := range []struct{ name, value string }{
+		{"signing key", a.SigningKey},
+		{"matryoshka hash", a.MatryoshkaHash},
+		{"coinbase address", a.CoinbaseAddress},
+	} {
+		b, err := fixedHex(f.name, f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	binary.Write(buf, binary.BigEndian, a.Efficiency)
+	for _, k := range a.AnchorKeys {
+		key, err := hex.DecodeString(k.Key)
+		if err != nil || len(key) != 20 {
+			return nil, fmt.Errorf("bad anchor key %q", k.Key)
+		}
+		buf.WriteByte(k.Priority)
+		buf.WriteByte(k.Type)
+		buf.Write(key)
+	}
+	return primitives.Sha(buf.Bytes()), nil
+}
+
+// ComputeRoots hashes the records of the archive into the section roots and the Merkle root
+func (a *FinalArchive) ComputeRoots() error {
+	keyMR, err := fixedHex("dblock keymr", a.DBlockKeyMR)
+	if err != nil {
+		return err
+	}
+	header := new(bytes.Buffer)
+	header.WriteByte('H')
+	binary.Write(header, binary.BigEndian, a.DBHeight)
+	header.Write(keyMR)
+
+	var sections [4][]interfaces.IHash
+	for _, b := range a.FactoidBalances {
+		h, err := b.leaf('F')
+		if err != nil {
+			return err
+		}
+		sections[0] = append(sections[0], h)
+	}
+	for _, b := range a.EntryCreditBalances {
+		h, err := b.leaf('E')
+		if err != nil {
+			return err
+		}
+		sections[1] = append(sections[1], h)
+	}
+	for _, c := range a.ChainHeads {
+		h, err := c.leaf()
+		if err != nil {
+			return err
+		}
+		sections[2] = append(sections[2], h)
+	}
+	for _, auth := range a.Authorities {
+		h, err := auth.leaf()
+		if err != nil {
+			return err
+		}
+		sections[3] = append(sections[3], h)
+	}
+
+	roots := []interfaces.IHash{primitives.Sha(header.Bytes())}
+	for _, leaves := range sections {
+		roots = append(roots, primitives.ComputeMerkleRoot(leaves))
+	}
+	a.Roots.Header = roots[0].String()
+	a.Roots.FactoidBalances = roots[1].String()
+	a.Roots.EntryCreditBalances = roots[2].String()
+	a.Roots.ChainHeads = roots[3].String()
+	a.Roots.Authorities = roots[4].String()
+	a.MerkleRoot = primitives.ComputeMerkleRoot(roots).String()
+	return nil
+}
+
+// Sign signs the Merkle root with the key of a node's identity
+func (a *FinalArchive) Sign(identityChainID interfaces.IHash, key *primitives.PrivateKey) error {
+	root, err := fixedHex("merkle root", a.MerkleRoot)
+	if err != nil {
+		return err
+	}
+	sig := key.Sign(root)
+	a.Signature = new(ArchiveSignature)
+	a.Signature.IdentityChainID = identityChainID.String()
+	a.Signature.PublicKey = hex.EncodeToString(sig.GetKey())
+	a.Signature.Signature = hex.EncodeToString(sig.Bytes())
+	return nil
+}
+
+// CheckSignature checks the archive is signed, and that the signature is over its Merkle root
+func (a *FinalArchive) CheckSignature() error {
+	if a.Signature == nil {
+		return fmt.Errorf("the archive is not signed")
+	}
+	root, err := fixedHex("merkle root", a.MerkleRoot)
+	if err != nil {
+		return err
+	}
+	pub, err := hex.DecodeString(a.Signature.PublicKey)
+	if err != nil {
+		return fmt.Errorf("bad public key %q", a.Signature.PublicKey)
+	}
+	sig, err := hex.DecodeString(a.Signature.Signature)
+	if err != nil {
+		return fmt.Errorf("bad signature %q", a.Signature.Signature)
+	}
+	return primitives.VerifySignature(root, pub, sig)
+}
+
+// SignerAuthority returns the authority in the archive that signed it, nil if the signer
+// is not an authority with the key used
+func (a *FinalArchive) SignerAuthority() *ArchiveAuthority {
+	if a.Signature == nil {
+		return nil
+	}
+	for _, auth := range a.Authorities {
+		if auth.ChainID == a.Signature.IdentityChainID && auth.SigningKey == a.Signature.PublicKey {
+			return auth
+		}
+	}
+	return nil
+}
+
+// BuildFinalArchive builds the archive of the state at the end of dbheight from the blocks in
+// a database.  The archive is not signed.
+func BuildFinalArchive(db interfaces.DBOverlaySimple, dbheight uint32) (*FinalArchive, error) {
+	a := new(FinalArchive)
+	a.Version = FinalArchiveVersion
+	a.DBHeight = dbheight
+
+	fct := make(map[[32]byte]int64)
+	ec := make(map[[32]byte]int64)
+	heads := make(map[[32]byte]*ArchiveChainHead)
+	auths := newArchiveAuthorities()
+
+	for h := uint32(0); h <= dbheight; h++ {
+		dblock, err := db.FetchDBlockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		fblock, err := db.FetchFBlockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		ecblock, err := db.FetchECBlockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		ablock, err := db.FetchABlockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		if dblock == nil || fblock == nil || ecblock == nil || ablock == nil {
+			return nil, fmt.Errorf("blocks at height %d are missing", h)
+		}
+
+		fctChanges, ecChanges := blockBalanceChanges(fblock, ecblock)
+		for address, change := range fctChanges {
+			fct[address] += change
+		}
+		for address, change := range ecChanges {
+			ec[address] += change
+		}
+
+		for _, e := range dblock.GetDBEntries() {
+			heads[e.GetChainID().Fixed()] = &ArchiveChainHead{ChainID: e.GetChainID().String(), KeyMR: e.GetKeyMR().String(), DBHeight: h}
+		}
+
+		for _, e := range ablock.GetABEntries() {
+			if err := auths.apply(e); err != nil {
+				return nil, fmt.Errorf("admin block %d: %v", h, err)
+			}
+		}
+
+		if h == dbheight {
+			a.DBlockKeyMR = dblock.GetKeyMR().String()
+		}
+	}
+
+	a.FactoidBalances = archiveBalances(fct, func(address [32]byte) string {
+		return primitives.ConvertFctAddressToUserStr(factoid.NewAddress(address[:]))
+	})
+	a.EntryCreditBalances = archiveBalances(ec, func(address [32]byte) string {
+		return primitives.ConvertECAddressToUserStr(factoid.NewAddress(address[:]))
+	})
+	for _, chainID := range sortedKeys(heads) {
+		a.ChainHeads = append(a.ChainHeads, heads[chainID])
+	}
+	a.Authorities = auths.list()
+
+	if err := a.ComputeRoots(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func sortedKeys(m map[[32]byte]*ArchiveChainHead) [][32]byte {
+	keys := make([][32]byte, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Sort(ByKey(keys))
+	return keys
+}
+
+func archiveBalances(balances map[[32]byte]int64, userStr func([32]byte) string) []*ArchiveBalance {
+	keys := make([][32]byte, 0, len(balances))
+	for k, v := range balances {
+		if v != 0 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Sort(ByKey(keys))
+	list := []*ArchiveBalance{}
+	for _, k := range keys {
+		list = append(list, &ArchiveBalance{Address: userStr(k), Balance: balances[k]})
+	}
+	return list
+}
+
+// archiveAuthorities follows the authority set through the admin blocks, the way the
+// IdentityManager does, without needing the identity chains
+type archiveAuthorities map[[32]byte]*ArchiveAuthority
+
+func newArchiveAuthorities() archiveAuthorities {
+	return make(archiveAuthorities)
+}
+
+func (as archiveAuthorities) get(chainID interfaces.IHash) (*ArchiveAuthority, error) {
+	auth, ok := as[chainID.Fixed()]
+	if !ok {
+		return nil, fmt.Errorf("authority %s not found", chainID.String())
+	}
+	return auth, nil
+}
+
+func (as archiveAuthorities) add(chainID interfaces.IHash, status string) {
+	auth, ok := as[chainID.Fixed()]
+	if !ok {
+		zero := primitives.NewZeroHash().String()
+		auth = &ArchiveAuthority{ChainID: chainID.String(), SigningKey: zero, MatryoshkaHash: zero, CoinbaseAddress: zero, Efficiency: 10000}
+		as[chainID.Fixed()] = auth
+	}
+	auth.Status = status
+}
+
+func (as archiveAuthorities) apply(entry interfaces.IABEntry) error {
+	switch e := entry.(type) {
+	case *adminBlock.AddFederatedServer:
+		as.add(e.IdentityChainID, "federated")
+	case *adminBlock.AddAuditServer:
+		as.add(e.IdentityChainID, "audit")
+	case *adminBlock.RemoveFederatedServer:
+		delete(as, e.IdentityChainID.Fixed())
+	case *adminBlock.AddFederatedServerSigningKey:
+		auth, err := as.get(e.IdentityChainID)
+		if err != nil {
+			return err
+		}
+		auth.SigningKey = hex.EncodeToString(e.PublicKey[:])
+	case *adminBlock.AddReplaceMatryoshkaHash:
+		auth, err := as.get(e.IdentityChainID)
+		if err != nil {
+			return err
+		}
+		auth.MatryoshkaHash = e.MHash.String()
+	case *adminBlock.AddFactoidAddress:
+		auth, err := as.get(e.IdentityChainID)
+		if err != nil {
+			return err
+		}
+		auth.CoinbaseAddress = hex.EncodeToString(e.FactoidAddress.Bytes())
+	case *adminBlock.AddEfficiency:
+		auth, err := as.get(e.IdentityChainID)
+		if err != nil {
+			return err
+		}
+		auth.Efficiency = e.Efficiency
+	case *adminBlock.AddFederatedServerBitcoinAnchorKey:
+		auth, err := as.get(e.IdentityChainID)
+		if err != nil {
+			return err
+		}
+		key := &ArchiveAnchorKey{Priority: e.KeyPriority, Type: e.KeyType, Key: hex.EncodeToString(e.ECDSAPublicKey[:])}
+		for i, k := range auth.AnchorKeys {
+			if k.Priority == key.Priority && k.Type == key.Type {
+				auth.AnchorKeys[i] = key
+				return nil
+			}
+		}
+		auth.AnchorKeys = append(auth.AnchorKeys, key)
+		sort.Slice(auth.AnchorKeys, func(i, j int) bool {
+			if auth.AnchorKeys[i].Priority != auth.AnchorKeys[j].Priority {
+				return auth.AnchorKeys[i].Priority < auth.AnchorKeys[j].Priority
+			}
+			return auth.AnchorKeys[i].Type < auth.AnchorKeys[j].Type
+		})
+	}
+	return nil
+}
+
+func (as archiveAuthorities) list() []*ArchiveAuthority {
+	keys := make([][32]byte, 0, len(as))
+	for k := range as {
+		keys = append(keys, k)
+	}
+	sort.Sort(ByKey(keys))
+	list := []*ArchiveAuthority{}
+	for _, k := range keys {
+		if as[k].AnchorKeys == nil {
+			as[k].AnchorKeys = []*ArchiveAnchorKey{}
+		}
+		list = append(list, as[k])
+	}
+	return list
+}
+
+// VerifyFinalArchive checks an archive against the blocks in a database: the records in the
+// archive must hash to its roots, and the database must give the same roots.  The signature
+// is checked separately by CheckSignature.
+func VerifyFinalArchive(db interfaces.DBOverlaySimple, a *FinalArchive) error {
+	if a.Version != FinalArchiveVersion {
+		return fmt.Errorf("unknown archive version %d", a.Version)
+	}
+
+	// The records must match the roots of the archive
+	claimed := *a
+	if err := claimed.ComputeRoots(); err != nil {
+		return err
+	}
+	if claimed.MerkleRoot != a.MerkleRoot || claimed.Roots != a.Roots {
+		return fmt.Errorf("the records of the archive do not hash to its Merkle root: %s", strings.Join(differentRoots(&claimed.Roots, &a.Roots), ", "))
+	}
+
+	built, err := BuildFinalArchive(db, a.DBHeight)
+	if err != nil {
+		return err
+	}
+	if built.MerkleRoot != a.MerkleRoot {
+		return fmt.Errorf("the database does not agree with the archive at height %d: %s", a.DBHeight, strings.Join(differentRoots(&built.Roots, &a.Roots), ", "))
+	}
+	return nil
+}
+
+func differentRoots(x *ArchiveRoots, y *ArchiveRoots) []string {
+	var diff []string
+	if x.Header != y.Header {
+		diff = append(diff, "header")
+	}
+	if x.FactoidBalances != y.FactoidBalances {
+		diff = append(diff, "factoid balances")
+	}
+	if x.EntryCreditBalances != y.EntryCreditBalances {
+		diff = append(diff, "entry credit balances")
+	}
+	if x.ChainHeads != y.ChainHeads {
+		diff = append(diff, "chain heads")
+	}
+	if x.Authorities != y.Authorities {
+		diff = append(diff, "authorities")
+	}
+	return diff
+}
+
+// WriteFinalArchive writes an archive as JSON
+func WriteFinalArchive(name string, a *FinalArchive) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(name, append(data, '\n'))
+}
+
+// ReadFinalArchive reads an archive written by WriteFinalArchive
+func ReadFinalArchive(name string) (*FinalArchive, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	a := new(FinalArchive)
+	if err := json.NewDecoder(f).Decode(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RunFinalArchive waits for the final height of the Factom era to be saved, then writes the
+// archive of the final state to FinalArchivePath, signed by the node's identity
+func (s *State) RunFinalArchive() {
+	final, ok := activations.ActivationHeight(activations.MAX_FACTOM_HEIGHT)
+	if !ok {
+		s.LogPrintf("finalarchive", "network %s has no final height, no archive will be written", s.GetNetworkName())
+		return
+	}
+
+	for !s.DBFinished || s.GetHighestSavedBlk() < uint32(final) {
+		time.Sleep(FinalArchivePollInterval)
+	}
+
+	start := time.Now()
+	a, err := BuildFinalArchive(s.DB, uint32(final))
+	if err == nil {
+		a.Network = s.GetNetworkName()
+		err = a.Sign(s.GetIdentityChainID(), s.ServerPrivKey)
+	}
+	if err == nil {
+		err = WriteFinalArchive(s.FinalArchivePath, a)
+	}
+	if err != nil {
+		s.LogPrintf("finalarchive", "failed to write the final archive: %v", err)
+		fmt.Fprintf(os.Stderr, "Failed to write the final archive: %v\n", err)
+		return
+	}
+	s.LogPrintf("finalarchive", "final archive of height %d written to %s in %s, Merkle root %s", final, s.FinalArchivePath, time.Since(start), a.MerkleRoot)
+	fmt.Printf("Final archive of height %d written to %s, Merkle root %s\n", final, s.FinalArchivePath, a.MerkleRoot)
+}
@@ -0,0 +1,102 @@
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestFinalArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := testHelper.CreateAndPopulateTestState()
+	last := uint32(testHelper.BlockCount - 1)
+
+	a, err := BuildFinalArchive(s.DB, last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fct, ec := blockBalances(t, s, last)
+	nonZero := func(balances map[[32]byte]int64) (n int) {
+		for _, b := range balances {
+			if b != 0 {
+				n++
+			}
+		}
+		return n
+	}
+	if len(a.FactoidBalances) != nonZero(fct) || len(a.EntryCreditBalances) != nonZero(ec) {
+		t.Errorf("expected %d and %d balances, found %d and %d", nonZero(fct), nonZero(ec), len(a.FactoidBalances), len(a.EntryCreditBalances))
+	}
+	if len(a.ChainHeads) < 3 {
+		t.Errorf("expected the chain heads of the admin, ec, factoid and entry chains, found %d", len(a.ChainHeads))
+	}
+	for i := 1; i < len(a.ChainHeads); i++ {
+		if a.ChainHeads[i-1].ChainID >= a.ChainHeads[i].ChainID {
+			t.Errorf("chain heads are not sorted")
+		}
+	}
+	if len(a.Authorities) != 1 || a.Authorities[0].Status != "federated" {
+		t.Errorf("expected the one federated server of the test blocks, found %d authorities", len(a.Authorities))
+	}
+
+	// The same blocks give the same root, and a different height a different one
+	again, _ := BuildFinalArchive(s.DB, last)
+	if again.MerkleRoot != a.MerkleRoot {
+		t.Errorf("the archive is not deterministic")
+	}
+	earlier, _ := BuildFinalArchive(s.DB, last-1)
+	if earlier.MerkleRoot == a.MerkleRoot {
+		t.Errorf("archives of different heights have the same root")
+	}
+
+	if err := a.CheckSignature(); err == nil {
+		t.Errorf("expected an error for an unsigned archive")
+	}
+	if err := a.Sign(s.GetIdentityChainID(), s.ServerPrivKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.CheckSignature(); err != nil {
+		t.Errorf("signature: %v", err)
+	}
+
+	name := filepath.Join(dir, "final.json")
+	if err := WriteFinalArchive(name, a); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadFinalArchive(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFinalArchive(s.DB, read); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+	if err := read.CheckSignature(); err != nil {
+		t.Errorf("signature after reading: %v", err)
+	}
+
+	// A changed balance no longer hashes to the root
+	read.FactoidBalances[0].Balance++
+	if err := VerifyFinalArchive(s.DB, read); err == nil || !strings.Contains(err.Error(), "factoid balances") {
+		t.Errorf("expected the factoid balances to differ, got %v", err)
+	}
+	// Nor does the database agree with it, once the roots are made to match
+	if err := read.ComputeRoots(); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFinalArchive(s.DB, read); err == nil || !strings.Contains(err.Error(), "database does not agree") {
+		t.Errorf("expected the database to disagree, got %v", err)
+	}
+	if err := read.CheckSignature(); err == nil {
+		t.Errorf("expected the signature to fail with a new root")
+	}
+}
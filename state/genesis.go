@@ -0,0 +1,359 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// GenesisSpec describes the starting state of a custom network
+type GenesisSpec struct {
+	Network      string              `json:"network" yaml:"network"`           // The name given to -customnet
+	BlockTime    int                 `json:"blocktime" yaml:"blocktime"`       // Seconds per directory block, 600 if not set
+	ExchangeRate uint64              `json:"exchangerate" yaml:"exchangerate"` // Factoshis per entry credit
+	Bootstrap    GenesisServer       `json:"bootstrap" yaml:"bootstrap"`       // Leads the network from the genesis block
+	Authorities  []GenesisServer     `json:"authorities" yaml:"authorities"`   // More leaders and audit servers from the genesis block
+	Factoids     []GenesisAllocation `json:"factoids" yaml:"factoids"`
+	EntryCredits []GenesisAllocation `json:"entrycredits" yaml:"entrycredits"`
+}
+
+// GenesisServer is a server of the genesis block, named by its identity chain and signing key
+type GenesisServer struct {
+	Identity   string `json:"identity" yaml:"identity"`
+	Key        string `json:"key" yaml:"key"`               // Public key, found from the private key if not set
+	PrivateKey string `json:"privatekey" yaml:"privatekey"` // Optional, only used to write the config of the server
+	Role       string `json:"role" yaml:"role"`             // Authorities only: leader or audit
+}
+
+// GenesisAllocation is a balance of the genesis block
+type GenesisAllocation struct {
+	Address string        `json:"address" yaml:"address"` // FA... or EC... public address
+	Amount  GenesisAmount `json:"amount" yaml:"amount"`   // Factoids, or entry credits
+}
+
+// GenesisAmount is the decimal amount of an allocation, kept as it is written in the spec so
+// it is never rounded through a float.  The spec can give it as a number or a string.
+type GenesisAmount string
+
+// UnmarshalJSON reads the amount from a JSON number or string
+func (a *GenesisAmount) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		var str string
+		if json.Unmarshal(data, &str) != nil {
+			return fmt.Errorf("the amount %s is not a number", data)
+		}
+		n = json.Number(str)
+	}
+	*a = GenesisAmount(n)
+	return nil
+}
+
+// Factoshis returns an amount of factoids in factoshis.  It can't be negative, have more than
+// 8 decimals, or be more than a balance holds.
+func (a GenesisAmount) Factoshis() (uint64, error) {
+	amount := strings.TrimSpace(string(a))
+	whole, fraction := amount, ""
+	if i := strings.Index(amount, "."); i >= 0 {
+		whole, fraction = amount[:i], amount[i+1:]
+	}
+	if len(fraction) > 8 {
+		return 0, fmt.Errorf("%s has more than 8 decimals", amount)
+	}
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("%q is not a positive decimal number", amount)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	fraction += strings.Repeat("0", 8-len(fraction))
+	if !decimalDigits(whole) || !decimalDigits(fraction) {
+		return 0, fmt.Errorf("%q is not a positive decimal number", amount)
+	}
+
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil || w > math.MaxInt64/100000000 {
+		return 0, fmt.Errorf("%s is more than a balance holds", amount)
+	}
+	f, _ := strconv.ParseUint(fraction, 10, 64)
+	factoshis := w*100000000 + f
+	if factoshis > math.MaxInt64 {
+		return 0, fmt.Errorf("%s is more than a balance holds", amount)
+	}
+	return factoshis, nil
+}
+
+// EntryCredits returns an amount of entry credits, which must be a whole number
+func (a GenesisAmount) EntryCredits() (uint64, error) {
+	amount := strings.TrimSpace(string(a))
+	if !decimalDigits(amount) {
+		return 0, fmt.Errorf("%q is not a whole number", amount)
+	}
+	ec, err := strconv.ParseUint(amount, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s is more than a balance holds", amount)
+	}
+	return ec, nil
+}
+
+// decimalDigits is true if a string is only made of the digits 0 to 9, and isn't empty
+func decimalDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// GenesisFile holds the blocks of a custom genesis, as written by WriteGenesisFile
+type GenesisFile struct {
+	Network   string `json:"network"`
+	NetworkID uint32 `json:"networkid"`
+	KeyMR     string `json:"keymr"` // Of the directory block
+	DBlock    string `json:"dblock"`
+	ABlock    string `json:"ablock"`
+	FBlock    string `json:"fblock"`
+	ECBlock   string `json:"ecblock"`
+}
+
+// CustomNetworkID is the network ID of a custom network, as -customnet makes it
+func CustomNetworkID(network string) uint32 {
+	return binary.BigEndian.Uint32(primitives.Sha([]byte(network)).Bytes()[:4])
+}
+
+// Check validates a genesis spec, filling in the block time and the public keys given by private keys
+func (g *GenesisSpec) Check() error {
+	if g.Network == "" {
+		return fmt.Errorf("the genesis needs the name of its network")
+	}
+	if g.BlockTime == 0 {
+		g.BlockTime = 600
+	}
+	if g.BlockTime < 0 {
+		return fmt.Errorf("the block time can't be negative")
+	}
+	if g.ExchangeRate == 0 {
+		return fmt.Errorf("the genesis needs an exchange rate in factoshis per entry credit")
+	}
+
+	ids := make(map[string]bool)
+	checkServer := func(name string, s *GenesisServer) error {
+		if _, err := primitives.HexToHash(s.Identity); err != nil {
+			return fmt.Errorf("%s: the identity %q is not a chain ID", name, s.Identity)
+		}
+		if ids[s.Identity] {
+			return fmt.Errorf("%s: the identity %s is used more than once", name, s.Identity)
+		}
+		ids[s.Identity] = true
+		if s.PrivateKey != "" {
+			pub, err := primitives.PrivateKeyStringToPublicKeyString(s.PrivateKey)
+			if err != nil {
+				return fmt.Errorf("%s: the private key is not valid: %v", name, err)
+			}
+			if s.Key != "" && s.Key != pub {
+				return fmt.Errorf("%s: the key does not match the private key", name)
+			}
+			s.Key = pub
+		}
+		if _, err := primitives.HexToHash(s.Key); err != nil {
+			return fmt.Errorf("%s: the key %q is not a public key", name, s.Key)
+		}
+		return nil
+	}
+	if err := checkServer("bootstrap", &g.Bootstrap); err != nil {
+		return err
+	}
+	if g.Bootstrap.Role != "" && g.Bootstrap.Role != "leader" {
+		return fmt.Errorf("bootstrap: the bootstrap identity is always a leader")
+	}
+	for i := range g.Authorities {
+		a := &g.Authorities[i]
+		name := fmt.Sprintf("authority %d", i)
+		if err := checkServer(name, a); err != nil {
+			return err
+		}
+		if a.Role != "leader" && a.Role != "audit" {
+			return fmt.Errorf("%s: the role must be leader or audit, not %q", name, a.Role)
+		}
+	}
+
+	for _, f := range g.Factoids {
+		if !primitives.ValidateFUserStr(f.Address) {
+			return fmt.Errorf("%q is not a factoid address", f.Address)
+		}
+		amount, err := f.Amount.Factoshis()
+		if err != nil {
+			return fmt.Errorf("the factoids of %s: %v", f.Address, err)
+		}
+		if amount == 0 {
+			return fmt.Errorf("the factoids of %s must be more than 0", f.Address)
+		}
+	}
+	for _, e := range g.EntryCredits {
+		if !primitives.ValidateECUserStr(e.Address) {
+			return fmt.Errorf("%q is not an entry credit address", e.Address)
+		}
+		amount, err := e.Amount.EntryCredits()
+		if err != nil {
+			return fmt.Errorf("the entry credits of %s: %v", e.Address, err)
+		}
+		if amount == 0 {
+			return fmt.Errorf("the entry credits of %s must be more than 0", e.Address)
+		}
+		if amount > math.MaxInt64/g.ExchangeRate {
+			return fmt.Errorf("the entry credits of %s cost more factoshis than a balance holds", e.Address)
+		}
+	}
+	return nil
+}
+
+// GenerateCustomGenesisBlocks builds the genesis blocks of a custom network from a spec, which
+// must have been checked.  The coinbase of the factoid block pays the factoid allocations and
+// buys the entry credits at the exchange rate of the spec, and the admin block makes the
+// bootstrap identity and the authorities servers, with their signing keys.
+func GenerateCustomGenesisBlocks(g *GenesisSpec) (interfaces.IDirectoryBlock, interfaces.IAdminBlock, interfaces.IFBlock, interfaces.IEntryCreditBlock, error) {
+	networkID := CustomNetworkID(g.Network)
+
+	ablk := adminBlock.NewAdminBlock(nil)
+	servers := append([]GenesisServer{g.Bootstrap}, g.Authorities...)
+	for _, s := range servers {
+		id, _ := primitives.HexToHash(s.Identity)
+		var err error
+		if s.Role == "audit" {
+			err = ablk.AddAuditServer(id)
+		} else {
+			err = ablk.AddFedServer(id)
+		}
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	for _, s := range servers {
+		id, _ := primitives.HexToHash(s.Identity)
+		key, _ := primitives.HexToHash(s.Key)
+		if err := ablk.AddFederatedServerSigningKey(id, key.Fixed()); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	if err := ablk.InsertIdentityABEntries(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	coinbase := new(factoid.Transaction)
+	coinbase.SetTimestamp(genesisTimestamp())
+	for _, f := range g.Factoids {
+		amount, err := f.Amount.Factoshis()
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		coinbase.AddOutput(factoid.NewAddress(primitives.ConvertUserStrToAddress(f.Address)), amount)
+	}
+	for _, e := range g.EntryCredits {
+		amount, err := e.Amount.EntryCredits()
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		coinbase.AddECOutput(factoid.NewAddress(primitives.ConvertUserStrToAddress(e.Address)), amount*g.ExchangeRate)
+	}
+	fblk := factoid.NewFBlock(nil).(*factoid.FBlock)
+	fblk.SetExchRate(g.ExchangeRate)
+	// AddCoinbase refuses entry credit outputs, which only the genesis coinbase may have
+	fblk.Transactions = append(fblk.Transactions, coinbase)
+	fblk.GetBodyMR()
+
+	ecblk := entryCreditBlock.NewECBlock()
+
+	dblk := genesisDBlock(networkID, ablk, fblk, ecblk)
+	return dblk, ablk, fblk, ecblk, nil
+}
+
+// WriteGenesisFile writes the genesis blocks of a checked spec to a file, returning the
+// KeyMR of the directory block
+func WriteGenesisFile(filename string, g *GenesisSpec) (string, error) {
+	dblk, ablk, fblk, ecblk, err := GenerateCustomGenesisBlocks(g)
+	if err != nil {
+		return "", err
+	}
+	f := new(GenesisFile)
+	f.Network = g.Network
+	f.NetworkID = CustomNetworkID(g.Network)
+	f.KeyMR = dblk.GetKeyMR().String()
+	for _, b := range []struct {
+		block interfaces.BinaryMarshallable
+		hex   *string
+	}{{dblk, &f.DBlock}, {ablk, &f.ABlock}, {fblk, &f.FBlock}, {ecblk, &f.ECBlock}} {
+		data, err := b.block.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		*b.hex = hex.EncodeToString(data)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return f.KeyMR, ioutil.WriteFile(filename, data, 0644)
+}
+
+// ReadGenesisFile reads the genesis blocks written by WriteGenesisFile, checking they are of the
+// network and that the directory block is the one of the other blocks
+func ReadGenesisFile(filename string, networkID uint32) (interfaces.IDirectoryBlock, interfaces.IAdminBlock, interfaces.IFBlock, interfaces.IEntryCreditBlock, error) {
+	fail := func(err error) (interfaces.IDirectoryBlock, interfaces.IAdminBlock, interfaces.IFBlock, interfaces.IEntryCreditBlock, error) {
+		return nil, nil, nil, nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	f := new(GenesisFile)
+	if err := json.Unmarshal(data, f); err != nil {
+		return fail(err)
+	}
+	if f.NetworkID != networkID {
+		return fail(fmt.Errorf("the genesis is of network %q (%08x), not %08x", f.Network, f.NetworkID, networkID))
+	}
+
+	var blocks [4][]byte
+	for i, h := range []string{f.DBlock, f.ABlock, f.FBlock, f.ECBlock} {
+		if blocks[i], err = hex.DecodeString(h); err != nil {
+			return fail(err)
+		}
+	}
+	dblk, err := directoryBlock.UnmarshalDBlock(blocks[0])
+	if err != nil {
+		return fail(err)
+	}
+	ablk, err := adminBlock.UnmarshalABlock(blocks[1])
+	if err != nil {
+		return fail(err)
+	}
+	fblk, err := factoid.UnmarshalFBlock(blocks[2])
+	if err != nil {
+		return fail(err)
+	}
+	ecblk, err := entryCreditBlock.UnmarshalECBlock(blocks[3])
+	if err != nil {
+		return fail(err)
+	}
+	if !genesisDBlock(networkID, ablk, fblk, ecblk).GetKeyMR().IsSameAs(dblk.GetKeyMR()) || dblk.GetKeyMR().String() != f.KeyMR {
+		return fail(fmt.Errorf("the directory block does not match the other blocks"))
+	}
+	return dblk, ablk, fblk, ecblk, nil
+}
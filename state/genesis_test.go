@@ -0,0 +1,192 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+)
+
+func testGenesisSpec() *GenesisSpec {
+	g := new(GenesisSpec)
+	g.Network = "genesistest"
+	g.ExchangeRate = 5000
+	g.Bootstrap = GenesisServer{
+		Identity:   "73dfd781235078c0a7c8feeffc88c07a1dc821c17361371f29bf0eb409655cba",
+		PrivateKey: "c23131ba92b2ed5dc3516fffe2afa85689b5ef2ca0735b42279c139707ac7989",
+	}
+	g.Authorities = []GenesisServer{
+		{Identity: "888888d68f00a41cf8caddd8d3d37f22507c39d9beddb037a222effa522d1485", Key: "b52ee1f9e51cb2c667353c2a6beb1f4eb835cfbfeeade905c7c1b12e44fcf9d3", Role: "leader"},
+		{Identity: "888888ea276276adb7b6b9e7644ade8c68a18875cc609668810a4e15d96a4b0e", Key: "d993081a62ea7eac00a1575e86c5bb23e2e3e4c35e1f7ada7a71a85ec06ad499", Role: "audit"},
+	}
+	g.Factoids = []GenesisAllocation{{Address: "FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q", Amount: "1234.5"}}
+	g.EntryCredits = []GenesisAllocation{{Address: "EC1zGzM78psHhs5xVdv6jgVGmswvUaN6R3VgmTquGsdyx9W67Cqy", Amount: "777"}}
+	return g
+}
+
+func TestGenesisSpecCheck(t *testing.T) {
+	g := testGenesisSpec()
+	if err := g.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if g.BlockTime != 600 {
+		t.Errorf("expected the block time to default to 600, got %d", g.BlockTime)
+	}
+	if g.Bootstrap.Key != "c314b0181aeab97adc21ab6215bc0d3f74ea03f3ac6a9a85e5e562a2ddf206a4" {
+		t.Errorf("expected the bootstrap key from its private key, got %s", g.Bootstrap.Key)
+	}
+
+	bad := map[string]func(g *GenesisSpec){
+		"name of its network":    func(g *GenesisSpec) { g.Network = "" },
+		"exchange rate":          func(g *GenesisSpec) { g.ExchangeRate = 0 },
+		"is not a chain ID":      func(g *GenesisSpec) { g.Authorities[0].Identity = "88" },
+		"used more than once":    func(g *GenesisSpec) { g.Authorities[1].Identity = g.Authorities[0].Identity },
+		"does not match":         func(g *GenesisSpec) { g.Bootstrap.Key = g.Authorities[0].Key },
+		"role must be":           func(g *GenesisSpec) { g.Authorities[0].Role = "follower" },
+		"always a leader":        func(g *GenesisSpec) { g.Bootstrap.Role = "audit" },
+		"not a factoid address":  func(g *GenesisSpec) { g.Factoids[0].Address = g.EntryCredits[0].Address },
+		"must be more than 0":    func(g *GenesisSpec) { g.Factoids[0].Amount = "0" },
+		"positive decimal":       func(g *GenesisSpec) { g.Factoids[0].Amount = "-1" },
+		"more than 8 decimals":   func(g *GenesisSpec) { g.Factoids[0].Amount = "1.000000001" },
+		"balance holds":          func(g *GenesisSpec) { g.Factoids[0].Amount = "92233720368.54775808" },
+		"whole number":           func(g *GenesisSpec) { g.EntryCredits[0].Amount = "1.5" },
+		"cost more factoshis":    func(g *GenesisSpec) { g.EntryCredits[0].Amount = "1844674407370956" },
+		"not an entry credit ad": func(g *GenesisSpec) { g.EntryCredits[0].Address = "EC" },
+	}
+	for want, change := range bad {
+		g := testGenesisSpec()
+		change(g)
+		if err := g.Check(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected an error with %q, got %v", want, err)
+		}
+	}
+}
+
+func TestGenerateCustomGenesisBlocks(t *testing.T) {
+	g := testGenesisSpec()
+	if err := g.Check(); err != nil {
+		t.Fatal(err)
+	}
+	dblk, ablk, fblk, _, err := GenerateCustomGenesisBlocks(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dblk.GetHeader().GetNetworkID() != CustomNetworkID("genesistest") || dblk.GetHeader().GetDBHeight() != 0 {
+		t.Errorf("expected a genesis directory block of the network, got %s", dblk.String())
+	}
+
+	types := make(map[byte]int)
+	for _, e := range ablk.GetABEntries() {
+		types[e.Type()]++
+	}
+	if types[constants.TYPE_ADD_FED_SERVER] != 2 || types[constants.TYPE_ADD_AUDIT_SERVER] != 1 || types[constants.TYPE_ADD_FED_SERVER_KEY] != 3 {
+		t.Errorf("expected 2 leaders, an audit server and 3 keys, got %v", types)
+	}
+
+	if fblk.GetExchRate() != 5000 || len(fblk.GetTransactions()) != 1 {
+		t.Fatalf("expected a rate of 5000 and only a coinbase, got %d and %d transactions", fblk.GetExchRate(), len(fblk.GetTransactions()))
+	}
+	coinbase := fblk.GetTransactions()[0]
+	outs, ecs := coinbase.GetOutputs(), coinbase.GetECOutputs()
+	if len(outs) != 1 || outs[0].GetAmount() != 123450000000 || primitives.ConvertFctAddressToUserStr(outs[0].GetAddress()) != g.Factoids[0].Address {
+		t.Errorf("expected 1234.5 factoids for %s, got %s", g.Factoids[0].Address, coinbase.String())
+	}
+	if len(ecs) != 1 || ecs[0].GetAmount() != 777*5000 || primitives.ConvertECAddressToUserStr(ecs[0].GetAddress()) != g.EntryCredits[0].Address {
+		t.Errorf("expected 777 entry credits for %s, got %s", g.EntryCredits[0].Address, coinbase.String())
+	}
+
+	// The same spec always makes the same genesis
+	again, _, _, _, _ := GenerateCustomGenesisBlocks(g)
+	if !again.GetKeyMR().IsSameAs(dblk.GetKeyMR()) {
+		t.Error("expected the genesis to be deterministic")
+	}
+}
+
+func TestGenesisFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "genesis.json")
+
+	g := testGenesisSpec()
+	if err := g.Check(); err != nil {
+		t.Fatal(err)
+	}
+	keyMR, err := WriteGenesisFile(filename, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dblk, _, fblk, _, err := ReadGenesisFile(filename, CustomNetworkID(g.Network))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dblk.GetKeyMR().String() != keyMR || fblk.GetExchRate() != 5000 {
+		t.Errorf("expected to read back the genesis %s", keyMR)
+	}
+
+	if _, _, _, _, err := ReadGenesisFile(filename, CustomNetworkID("other")); err == nil || !strings.Contains(err.Error(), "not") {
+		t.Errorf("expected the genesis of another network to be refused, got %v", err)
+	}
+
+	// Swap in the factoid block of another genesis
+	f := new(GenesisFile)
+	data, _ := ioutil.ReadFile(filename)
+	json.Unmarshal(data, f)
+	g.Factoids[0].Amount = "1234.6"
+	WriteGenesisFile(filename, g)
+	other := new(GenesisFile)
+	data, _ = ioutil.ReadFile(filename)
+	json.Unmarshal(data, other)
+	f.FBlock = other.FBlock
+	data, _ = json.Marshal(f)
+	ioutil.WriteFile(filename, data, 0644)
+	if _, _, _, _, err := ReadGenesisFile(filename, CustomNetworkID(g.Network)); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected a changed block to be refused, got %v", err)
+	}
+}
+
+func TestGenesisAmount(t *testing.T) {
+	var a GenesisAllocation
+	for data, want := range map[string]GenesisAmount{
+		`{"amount": 92233720368.54775807}`: "92233720368.54775807",
+		`{"amount": "0.00000001"}`:         "0.00000001",
+		`{"amount": 10}`:                   "10",
+	} {
+		if err := json.Unmarshal([]byte(data), &a); err != nil || a.Amount != want {
+			t.Errorf("%s: expected %s, got %s %v", data, want, a.Amount, err)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"amount": true}`), &a); err == nil {
+		t.Error("expected a bool amount to be refused")
+	}
+
+	// The largest balance doesn't lose any factoshis on the way
+	for amount, want := range map[GenesisAmount]uint64{
+		"92233720368.54775807": math.MaxInt64,
+		"1000.5":               100050000000,
+		".00000001":            1,
+		"7":                    700000000,
+	} {
+		if f, err := amount.Factoshis(); err != nil || f != want {
+			t.Errorf("%s: expected %d factoshis, got %d %v", amount, want, f, err)
+		}
+	}
+	for _, amount := range []GenesisAmount{"", ".", "1e3", "+1", "1.2.3", "18446744073709551616"} {
+		if _, err := amount.Factoshis(); err == nil {
+			t.Errorf("expected %q to be refused", amount)
+		}
+	}
+}
@@ -281,6 +281,60 @@ var (
 			"If there is a delay, it means the ack+msg would benefit from being " +
 			"coupled. The delay is measured in seconds.",
 	}, []string{"leader"})
+
+	// Integrity Checker
+	IntegrityPasses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_integrity_passes_total",
+		Help: "Number of passes the integrity checker has started over the database",
+	})
+	IntegrityPosition = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_integrity_position",
+		Help: "Directory block height the integrity checker is checking",
+	})
+	IntegrityFindings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_integrity_findings_total",
+		Help: "Problems found in the database by the integrity checker",
+	}, []string{"kind"})
+	IntegrityRepairs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_integrity_repairs_total",
+		Help: "Problems repaired, or requested from peers, by the integrity checker",
+	}, []string{"kind"})
+
+	// Block export
+	ExportHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "factomd_state_export_height",
+		Help: "Last height written by each export sink",
+	}, []string{"sink"})
+	ExportErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_export_errors_total",
+		Help: "Failures of each export sink",
+	}, []string{"sink"})
+
+	// Address history index
+	AddressIndexHeight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_state_address_index_height",
+		Help: "Last height added to the address history index",
+	})
+
+	// Elections
+	ElectionsStarted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "factomd_state_elections_started_total",
+		Help: "Elections started to replace a faulted leader",
+	})
+	ElectionsFinished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_state_elections_finished_total",
+		Help: "Elections that ended, by outcome (elected or abandoned)",
+	}, []string{"outcome"})
+	ElectionDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "factomd_state_election_duration_seconds",
+		Help:    "Time from the fault to the end of an election",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	})
+	ElectionRounds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "factomd_state_election_rounds",
+		Help:    "Rounds of audit volunteers an election took",
+		Buckets: prometheus.LinearBuckets(0, 1, 10),
+	})
 )
 
 var registered bool = false
@@ -376,4 +430,23 @@ func RegisterPrometheus() {
 	prometheus.MustRegister(LeaderSyncMsgDelay)
 	prometheus.MustRegister(LeaderSyncAckDelay)
 	prometheus.MustRegister(LeaderSyncAckPairDelay)
+
+	// Integrity Checker
+	prometheus.MustRegister(IntegrityPasses)
+	prometheus.MustRegister(IntegrityPosition)
+	prometheus.MustRegister(IntegrityFindings)
+	prometheus.MustRegister(IntegrityRepairs)
+
+	// Block export
+	prometheus.MustRegister(ExportHeight)
+	prometheus.MustRegister(ExportErrors)
+
+	// Address history index
+	prometheus.MustRegister(AddressIndexHeight)
+
+	// Elections
+	prometheus.MustRegister(ElectionsStarted)
+	prometheus.MustRegister(ElectionsFinished)
+	prometheus.MustRegister(ElectionDuration)
+	prometheus.MustRegister(ElectionRounds)
 }
@@ -0,0 +1,554 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// The kinds of problems the integrity checker reports
+const (
+	IntegrityMissingBlock   = "missing-block"   // dblock, ablock, fblock or ecblock missing at a saved height
+	IntegrityBlockLinkage   = "block-linkage"   // dblock, ablock, fblock or ecblock does not link to the block before it
+	IntegrityMissingEBlock  = "missing-eblock"  // eblock listed in a dblock is not in the database
+	IntegrityEBlockLinkage  = "eblock-linkage"  // eblock does not link to the previous eblock of its chain
+	IntegrityChainHead      = "chain-head"      // CHAIN_HEAD does not point to the latest eblock of a chain
+	IntegrityIncludedIn     = "included-in"     // INCLUDED_IN index is missing or wrong
+	IntegrityPaidFor        = "paid-for"        // PAID_FOR index is missing for a commit
+	IntegrityMissingEntry   = "missing-entry"   // entry listed in an eblock is not in the database
+	IntegrityCheckerFailure = "checker-failure" // the checker could not read from the database
+)
+
+var (
+	// IntegrityCheckDelay is the pause between two heights, which keeps the checker from
+	// competing with consensus for the database.
+	IntegrityCheckDelay = 100 * time.Millisecond
+	// IntegrityCheckInterval is the pause between two complete passes over the database
+	IntegrityCheckInterval = time.Hour
+	// IntegrityMaxFindings is the number of findings kept for the debug API
+	IntegrityMaxFindings = 1000
+)
+
+// IntegrityChecker walks the database in the background checking the same things the offline
+// utilities (DatabaseIntegrityCheck, CorrectChainHeads, chainheadfix) do, without stopping the node.
+//
+// For every height up to the height where we have all the entries it:
+// 1. Checks the dblock, ablock, fblock and ecblock exist and link to the previous height
+// 2. Checks the INCLUDED_IN index of the dblock, fblock and ecblock contents
+// 3. Checks the PAID_FOR index of every commit in the ecblock
+// 4. Checks every eblock exists, links to the previous eblock in its chain, and has all its entries
+// At the end of a pass the chain heads gathered in 4. are compared to CHAIN_HEAD.
+//
+// If Repair is set, indexes are rewritten from the blocks, chain heads are corrected, and
+// missing eblocks and entries are requested from our peers.  A missing dblock, ablock, fblock
+// or ecblock is requested with the DBState of its height, through the catch-up.
+type IntegrityChecker struct {
+	s      *State
+	Repair bool
+
+	mtx           sync.RWMutex
+	running       bool
+	pass          int
+	position      uint32
+	end           uint32
+	passStarted   time.Time
+	lastPassEnded time.Time
+	total         int
+	counts        map[string]int
+	findings      []*interfaces.IntegrityFinding
+
+	// the latest eblock seen for every chain in this pass
+	heads map[[32]byte]*integrityHead
+	// the blocks at the previous height, to check the linkage
+	prev integrityBlockSet
+
+	askMtx sync.Mutex
+	asked  map[[32]byte]uint32 // eblock keymr => dbheight we asked for it at
+
+	closer chan interface{}
+}
+
+type integrityHead struct {
+	keymr  interfaces.IHash
+	height uint32
+}
+
+type integrityBlockSet struct {
+	dblock  interfaces.IDirectoryBlock
+	ablock  interfaces.IAdminBlock
+	fblock  interfaces.IFBlock
+	ecblock interfaces.IEntryCreditBlock
+}
+
+// NewIntegrityChecker creates a new IntegrityChecker for the state
+func NewIntegrityChecker(s *State, repair bool) *IntegrityChecker {
+	ic := new(IntegrityChecker)
+	ic.s = s
+	ic.Repair = repair
+	ic.counts = make(map[string]int)
+	ic.asked = make(map[[32]byte]uint32)
+	ic.closer = make(chan interface{})
+	return ic
+}
+
+// Stop ends the background checking
+func (ic *IntegrityChecker) Stop() {
+	close(ic.closer)
+}
+
+// stopped sleeps for the given duration, returning true if the checker was stopped meanwhile
+func (ic *IntegrityChecker) stopped(d time.Duration) bool {
+	select {
+	case <-ic.closer:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// Run checks the database over and over until stopped. It waits for the database to
+// be loaded before it starts.
+func (ic *IntegrityChecker) Run() {
+	for !ic.s.DBFinished {
+		if ic.stopped(time.Second) {
+			return
+		}
+	}
+
+	for {
+		end := ic.s.GetHighestSavedBlk()
+		if complete := ic.s.GetEntryBlockDBHeightComplete(); complete < end {
+			end = complete
+		}
+		if !ic.CheckRange(0, end) {
+			return
+		}
+		if ic.stopped(IntegrityCheckInterval) {
+			return
+		}
+	}
+}
+
+// CheckRange makes one pass over the heights start to end inclusive. Chain heads are only
+// compared to the database if the pass starts at zero. Returns false if the checker was stopped.
+func (ic *IntegrityChecker) CheckRange(start uint32, end uint32) bool {
+	ic.mtx.Lock()
+	ic.running = true
+	ic.pass++
+	ic.position = start
+	ic.end = end
+	ic.passStarted = time.Now()
+	ic.mtx.Unlock()
+	IntegrityPasses.Inc()
+
+	defer func() {
+		ic.mtx.Lock()
+		ic.running = false
+		ic.mtx.Unlock()
+	}()
+
+	ic.heads = make(map[[32]byte]*integrityHead)
+	ic.prev = integrityBlockSet{}
+	if start > 0 {
+		ic.prev = ic.loadBlockSet(start - 1)
+	}
+
+	for h := start; h <= end; h++ {
+		ic.mtx.Lock()
+		ic.position = h
+		ic.mtx.Unlock()
+		IntegrityPosition.Set(float64(h))
+
+		ic.checkHeight(h)
+
+		if ic.stopped(IntegrityCheckDelay) {
+			return false
+		}
+	}
+
+	if start == 0 {
+		ic.checkChainHeads()
+	}
+
+	ic.mtx.Lock()
+	ic.lastPassEnded = time.Now()
+	ic.mtx.Unlock()
+	return true
+}
+
+// Report returns a snapshot of the checker's progress and most recent findings
+func (ic *IntegrityChecker) Report() *interfaces.IntegrityReport {
+	ic.mtx.RLock()
+	defer ic.mtx.RUnlock()
+
+	r := new(interfaces.IntegrityReport)
+	r.Enabled = true
+	r.Repair = ic.Repair
+	r.Running = ic.running
+	r.Pass = ic.pass
+	r.Position = ic.position
+	r.End = ic.end
+	if !ic.passStarted.IsZero() {
+		r.PassStarted = ic.passStarted.Unix()
+	}
+	if !ic.lastPassEnded.IsZero() {
+		r.LastPassEnded = ic.lastPassEnded.Unix()
+	}
+	r.TotalFindings = ic.total
+	r.Counts = make(map[string]int)
+	for k, v := range ic.counts {
+		r.Counts[k] = v
+	}
+	r.Findings = append(r.Findings, ic.findings...)
+	return r
+}
+
+// report records a finding, logs it, and updates the prometheus counters
+func (ic *IntegrityChecker) report(kind string, dbheight uint32, hash interfaces.IHash, repaired bool, format string, args ...interface{}) {
+	f := new(interfaces.IntegrityFinding)
+	f.Kind = kind
+	f.DBHeight = dbheight
+	if hash != nil {
+		f.Hash = hash.String()
+	}
+	f.Detail = fmt.Sprintf(format, args...)
+	f.Repaired = repaired
+	f.Time = time.Now().Unix()
+
+	ic.mtx.Lock()
+	f.Pass = ic.pass
+	ic.total++
+	ic.counts[kind]++
+	ic.findings = append(ic.findings, f)
+	if len(ic.findings) > IntegrityMaxFindings {
+		ic.findings = ic.findings[len(ic.findings)-IntegrityMaxFindings:]
+	}
+	ic.mtx.Unlock()
+
+	IntegrityFindings.WithLabelValues(kind).Inc()
+	if repaired {
+		IntegrityRepairs.WithLabelValues(kind).Inc()
+	}
+	ic.s.LogPrintf("integrity", "%s at %d %s: %s repaired=%v", kind, dbheight, f.Hash, f.Detail, repaired)
+}
+
+// overlay returns the full database overlay needed for repairs, or nil if repairs are off
+func (ic *IntegrityChecker) overlay() *databaseOverlay.Overlay {
+	if !ic.Repair {
+		return nil
+	}
+	dbo, _ := ic.s.DB.(*databaseOverlay.Overlay)
+	return dbo
+}
+
+// loadBlockSet loads the dblock, ablock, fblock and ecblock at a height. Missing blocks are nil.
+func (ic *IntegrityChecker) loadBlockSet(h uint32) (set integrityBlockSet) {
+	set.dblock, _ = ic.s.DB.FetchDBlockByHeight(h)
+	if set.dblock == nil {
+		return
+	}
+	for _, e := range set.dblock.GetDBEntries() {
+		switch {
+		case e.GetChainID().IsSameAs(primitives.NewHash(constants.ADMIN_CHAINID)):
+			set.ablock, _ = ic.s.DB.FetchABlock(e.GetKeyMR())
+		case e.GetChainID().IsSameAs(primitives.NewHash(constants.FACTOID_CHAINID)):
+			set.fblock, _ = ic.s.DB.FetchFBlock(e.GetKeyMR())
+		case e.GetChainID().IsSameAs(primitives.NewHash(constants.EC_CHAINID)):
+			set.ecblock, _ = ic.s.DB.FetchECBlock(e.GetKeyMR())
+		}
+	}
+	return
+}
+
+// checkHeight runs all the per height checks
+func (ic *IntegrityChecker) checkHeight(h uint32) {
+	set := ic.loadBlockSet(h)
+	prev := ic.prev
+	ic.prev = set
+
+	if set.dblock == nil {
+		ic.report(IntegrityMissingBlock, h, nil, ic.askForDBState(h), "dblock missing")
+		return
+	}
+	dbKeyMR := set.dblock.GetKeyMR()
+
+	if h == 0 || prev.dblock != nil {
+		if err := directoryBlock.CheckBlockPairIntegrity(set.dblock, prev.dblock); err != nil {
+			ic.report(IntegrityBlockLinkage, h, dbKeyMR, false, "dblock: %v", err)
+		}
+	}
+
+	if set.ablock == nil {
+		ic.report(IntegrityMissingBlock, h, dbKeyMR, ic.askForDBState(h), "ablock missing")
+	} else if h == 0 || prev.ablock != nil {
+		if err := adminBlock.CheckBlockPairIntegrity(set.ablock, prev.ablock); err != nil {
+			ic.report(IntegrityBlockLinkage, h, set.ablock.DatabasePrimaryIndex(), false, "ablock: %v", err)
+		}
+	}
+
+	if set.fblock == nil {
+		ic.report(IntegrityMissingBlock, h, dbKeyMR, ic.askForDBState(h), "fblock missing")
+	} else {
+		if h == 0 || prev.fblock != nil {
+			if err := factoid.CheckBlockPairIntegrity(set.fblock, prev.fblock); err != nil {
+				ic.report(IntegrityBlockLinkage, h, set.fblock.DatabasePrimaryIndex(), false, "fblock: %v", err)
+			}
+		}
+		ic.checkIncludedIn(h, set.fblock.GetEntryHashes(), set.fblock.DatabasePrimaryIndex(), false)
+	}
+
+	if set.ecblock == nil {
+		ic.report(IntegrityMissingBlock, h, dbKeyMR, ic.askForDBState(h), "ecblock missing")
+	} else {
+		if h == 0 || prev.ecblock != nil {
+			if err := entryCreditBlock.CheckBlockPairIntegrity(set.ecblock, prev.ecblock); err != nil {
+				ic.report(IntegrityBlockLinkage, h, set.ecblock.DatabasePrimaryIndex(), false, "ecblock: %v", err)
+			}
+		}
+		ic.checkIncludedIn(h, set.ecblock.GetEntryHashes(), set.ecblock.DatabasePrimaryIndex(), false)
+		ic.checkPaidFor(h, set.ecblock)
+	}
+
+	ic.checkIncludedIn(h, set.dblock.GetEntryHashes(), dbKeyMR, true)
+
+	for _, e := range set.dblock.GetEBlockDBEntries() {
+		ic.checkEBlock(h, e.GetChainID(), e.GetKeyMR())
+	}
+}
+
+// checkIncludedIn checks every hash is indexed to a block. If exact is set, it must be
+// indexed to the given block; otherwise (entries and transactions may be repeated) any block will do.
+func (ic *IntegrityChecker) checkIncludedIn(h uint32, hashes []interfaces.IHash, block interfaces.IHash, exact bool) {
+	for _, hash := range hashes {
+		if hash.IsMinuteMarker() {
+			continue
+		}
+		in, err := ic.s.DB.FetchIncludedIn(hash)
+		if err != nil {
+			ic.report(IntegrityCheckerFailure, h, hash, false, "FetchIncludedIn: %v", err)
+			continue
+		}
+		if in != nil && (!exact || in.IsSameAs(block)) {
+			continue
+		}
+
+		repaired := false
+		if dbo := ic.overlay(); dbo != nil {
+			repaired = dbo.SaveIncludedIn(hash, block) == nil
+		}
+		if in == nil {
+			ic.report(IntegrityIncludedIn, h, hash, repaired, "missing, expected %x", block.Bytes()[:6])
+		} else {
+			ic.report(IntegrityIncludedIn, h, hash, repaired, "points to %x, expected %x", in.Bytes()[:6], block.Bytes()[:6])
+		}
+	}
+}
+
+// checkPaidFor checks every chain and entry commit in the ecblock is indexed in PAID_FOR
+func (ic *IntegrityChecker) checkPaidFor(h uint32, ecblock interfaces.IEntryCreditBlock) {
+	for _, entry := range ecblock.GetEntries() {
+		var entryHash interfaces.IHash
+		switch entry.ECID() {
+		case constants.ECIDChainCommit:
+			entryHash = entry.(*entryCreditBlock.CommitChain).EntryHash
+		case constants.ECIDEntryCommit:
+			entryHash = entry.(*entryCreditBlock.CommitEntry).EntryHash
+		default:
+			continue
+		}
+
+		paid, err := ic.s.DB.FetchPaidFor(entryHash)
+		if err != nil {
+			ic.report(IntegrityCheckerFailure, h, entryHash, false, "FetchPaidFor: %v", err)
+			continue
+		}
+		if paid != nil {
+			continue
+		}
+
+		repaired := false
+		if dbo := ic.overlay(); dbo != nil {
+			repaired = dbo.SavePaidFor(entryHash, entry.GetSigHash()) == nil
+		}
+		ic.report(IntegrityPaidFor, h, entryHash, repaired, "commit %x not indexed", entry.GetSigHash().Bytes()[:6])
+	}
+}
+
+// checkEBlock checks an eblock listed in the dblock at height h, its linkage, and its entries
+func (ic *IntegrityChecker) checkEBlock(h uint32, chainID interfaces.IHash, keymr interfaces.IHash) {
+	head := ic.heads[chainID.Fixed()]
+	ic.heads[chainID.Fixed()] = &integrityHead{keymr: keymr, height: h}
+
+	eblock, err := ic.s.DB.FetchEBlock(keymr)
+	if err != nil {
+		ic.report(IntegrityCheckerFailure, h, keymr, false, "FetchEBlock: %v", err)
+		return
+	}
+	if eblock == nil {
+		ic.report(IntegrityMissingEBlock, h, keymr, ic.askForEBlock(h, keymr), "chain %x", chainID.Bytes()[:6])
+		return
+	}
+
+	prevKeyMR := eblock.GetHeader().GetPrevKeyMR()
+	switch {
+	case head == nil && !prevKeyMR.IsZero():
+		// First eblock of the chain in this pass, so all we can do is check the previous one exists
+		if has, _ := ic.s.DB.DoesKeyExist(databaseOverlay.ENTRYBLOCK, prevKeyMR.Bytes()); !has {
+			ic.report(IntegrityEBlockLinkage, h, keymr, false, "previous eblock %x not found", prevKeyMR.Bytes()[:6])
+		}
+	case head != nil && !prevKeyMR.IsSameAs(head.keymr):
+		ic.report(IntegrityEBlockLinkage, h, keymr, false, "previous eblock is %x, expected %x from height %d",
+			prevKeyMR.Bytes()[:6], head.keymr.Bytes()[:6], head.height)
+	}
+	if eblock.GetHeader().GetDBHeight() != h {
+		ic.report(IntegrityEBlockLinkage, h, keymr, false, "eblock claims height %d", eblock.GetHeader().GetDBHeight())
+	}
+
+	ic.checkIncludedIn(h, eblock.GetEntryHashes(), keymr, false)
+
+	for _, entryHash := range eblock.GetEntryHashes() {
+		if entryHash.IsMinuteMarker() {
+			continue
+		}
+		has, err := ic.s.DB.DoesKeyExist(databaseOverlay.ENTRY, entryHash.Bytes())
+		if err != nil {
+			ic.report(IntegrityCheckerFailure, h, entryHash, false, "DoesKeyExist: %v", err)
+			continue
+		}
+		if !has {
+			ic.report(IntegrityMissingEntry, h, entryHash, ic.askForEntry(entryHash), "in eblock %x", keymr.Bytes()[:6])
+		}
+	}
+}
+
+// checkChainHeads compares the chain heads gathered in the pass with CHAIN_HEAD. A chain
+// may have grown since the pass started, so a head pointing to a newer eblock is fine.
+func (ic *IntegrityChecker) checkChainHeads() {
+	for _, head := range ic.heads {
+		eblock, err := ic.s.DB.FetchEBlock(head.keymr)
+		if err != nil || eblock == nil {
+			continue // reported as a missing eblock already
+		}
+		chainID := eblock.GetChainID()
+
+		dbHead, err := ic.s.DB.FetchHeadIndexByChainID(chainID)
+		if err != nil {
+			ic.report(IntegrityCheckerFailure, head.height, chainID, false, "FetchHeadIndexByChainID: %v", err)
+			continue
+		}
+		if dbHead != nil && dbHead.IsSameAs(head.keymr) {
+			continue
+		}
+		if dbHead != nil {
+			if newer, _ := ic.s.DB.FetchEBlock(dbHead); newer != nil && newer.GetChainID().IsSameAs(chainID) &&
+				newer.GetHeader().GetDBHeight() > head.height {
+				continue
+			}
+		}
+
+		repaired := false
+		if dbo := ic.overlay(); dbo != nil {
+			// look again right before writing, as the chain may have just grown
+			if now, _ := ic.s.DB.FetchHeadIndexByChainID(chainID); now == nil || now.IsSameAs(dbHead) {
+				repaired = dbo.SetChainHeads([]interfaces.IHash{head.keymr}, []interfaces.IHash{chainID}) == nil
+			}
+		}
+		if dbHead == nil {
+			ic.report(IntegrityChainHead, head.height, chainID, repaired, "no chain head, expected %x", head.keymr.Bytes()[:6])
+		} else {
+			ic.report(IntegrityChainHead, head.height, chainID, repaired, "chain head is %x, expected %x", dbHead.Bytes()[:6], head.keymr.Bytes()[:6])
+		}
+	}
+}
+
+// askForEntry queues a missing entry with EntrySync, returns true if it was queued
+func (ic *IntegrityChecker) askForEntry(hash interfaces.IHash) bool {
+	if !ic.Repair || ic.s.EntrySync == nil {
+		return false
+	}
+	ic.s.EntrySync.syncEntryHash(hash)
+	return true
+}
+
+// askForDBState queues the DBState at a height with the catch-up, which requests it from our
+// peers, returns true if it was queued.  Queuing a height twice asks for it once.
+func (ic *IntegrityChecker) askForDBState(h uint32) bool {
+	if !ic.Repair || ic.s.NetStateOff || ic.s.StatesMissing == nil {
+		return false
+	}
+	ic.s.StatesMissing.Add(h)
+	return true
+}
+
+// askForEBlock requests a missing eblock from our peers, returns true if it was requested
+func (ic *IntegrityChecker) askForEBlock(h uint32, keymr interfaces.IHash) bool {
+	if !ic.Repair || ic.s.NetStateOff {
+		return false
+	}
+	ic.askMtx.Lock()
+	ic.asked[keymr.Fixed()] = h
+	ic.askMtx.Unlock()
+
+	request := messages.NewMissingData(primitives.NewTimestampNow(), keymr)
+	request.SendOut(ic.s, request)
+	return true
+}
+
+// AskedFor returns true if the checker requested the eblock with this keymr and has not
+// received it yet
+func (ic *IntegrityChecker) AskedFor(keymr interfaces.IHash) bool {
+	ic.askMtx.Lock()
+	defer ic.askMtx.Unlock()
+	_, ok := ic.asked[keymr.Fixed()]
+	return ok
+}
+
+// SaveEBlock writes an eblock we asked our peers for, and queues any of its entries we
+// do not have. The chain head is left alone; the next pass will correct it if need be.
+func (ic *IntegrityChecker) SaveEBlock(eblock interfaces.IEntryBlock) error {
+	keymr, err := eblock.KeyMR()
+	if err != nil {
+		return err
+	}
+
+	ic.askMtx.Lock()
+	h, ok := ic.asked[keymr.Fixed()]
+	delete(ic.asked, keymr.Fixed())
+	ic.askMtx.Unlock()
+	if !ok {
+		return fmt.Errorf("eblock %x was not requested", keymr.Bytes()[:6])
+	}
+
+	dbo := ic.overlay()
+	if dbo == nil {
+		return fmt.Errorf("repairs are not enabled")
+	}
+	if err := dbo.ProcessEBlockBatchWithoutHead(eblock, true); err != nil {
+		return err
+	}
+	ic.s.LogPrintf("integrity", "saved eblock %x at %d", keymr.Bytes()[:6], h)
+
+	for _, entryHash := range eblock.GetEntryHashes() {
+		if entryHash.IsMinuteMarker() {
+			continue
+		}
+		if has, _ := dbo.DoesKeyExist(databaseOverlay.ENTRY, entryHash.Bytes()); !has {
+			ic.askForEntry(entryHash)
+		}
+	}
+	return nil
+}
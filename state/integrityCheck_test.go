@@ -0,0 +1,121 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+// noIntegrityCheckDelay lets the checker run without pausing between heights, and returns a
+// func restoring the delay
+func noIntegrityCheckDelay() func() {
+	delay := state.IntegrityCheckDelay
+	state.IntegrityCheckDelay = 0
+	return func() {
+		state.IntegrityCheckDelay = delay
+	}
+}
+
+func TestIntegrityCheckerCleanDatabase(t *testing.T) {
+	defer noIntegrityCheckDelay()()
+	s := testHelper.CreateAndPopulateTestState()
+
+	ic := state.NewIntegrityChecker(s, false)
+	if !ic.CheckRange(0, uint32(testHelper.BlockCount-1)) {
+		t.Fatal("CheckRange stopped early")
+	}
+
+	r := ic.Report()
+	if r.Pass != 1 || r.Running {
+		t.Errorf("unexpected pass state %d %v", r.Pass, r.Running)
+	}
+	if r.TotalFindings != 0 {
+		for _, f := range r.Findings {
+			t.Errorf("unexpected finding %s at %d: %s", f.Kind, f.DBHeight, f.Detail)
+		}
+	}
+}
+
+func TestIntegrityCheckerFindsAndRepairs(t *testing.T) {
+	defer noIntegrityCheckDelay()()
+	s := testHelper.CreateAndPopulateTestState()
+	dbo := s.DB.(*databaseOverlay.Overlay)
+
+	blocks := testHelper.CreateFullTestBlockSet()
+	bs := blocks[5]
+	entry := bs.Entries[0]
+	chainID := bs.EBlock.GetChainID()
+
+	// break an entry, its INCLUDED_IN index and the chain head
+	if err := dbo.Delete(databaseOverlay.ENTRY, entry.GetHash().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbo.Delete(databaseOverlay.INCLUDED_IN, entry.GetHash().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	keymr, _ := blocks[2].EBlock.KeyMR()
+	if err := dbo.SetChainHeads([]interfaces.IHash{keymr}, []interfaces.IHash{chainID}); err != nil {
+		t.Fatal(err)
+	}
+
+	ic := state.NewIntegrityChecker(s, true)
+	ic.CheckRange(0, uint32(testHelper.BlockCount-1))
+
+	r := ic.Report()
+	for _, kind := range []string{state.IntegrityMissingEntry, state.IntegrityIncludedIn, state.IntegrityChainHead} {
+		if r.Counts[kind] != 1 {
+			t.Errorf("expected 1 %s finding, found %d", kind, r.Counts[kind])
+		}
+	}
+
+	// the index and the chain head are repaired from the blocks, so a second pass only finds the entry
+	ic.CheckRange(0, uint32(testHelper.BlockCount-1))
+	r = ic.Report()
+	if r.Counts[state.IntegrityMissingEntry] != 2 || r.Counts[state.IntegrityIncludedIn] != 1 || r.Counts[state.IntegrityChainHead] != 1 {
+		t.Errorf("repairs did not stick: %v", r.Counts)
+	}
+	head, err := s.DB.FetchHeadIndexByChainID(chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := blocks[testHelper.BlockCount-1].EBlock.KeyMR()
+	if !head.IsSameAs(want) {
+		t.Errorf("chain head not repaired, got %v want %v", head, want)
+	}
+}
+
+func TestIntegrityCheckerRequestsMissingBlocks(t *testing.T) {
+	defer noIntegrityCheckDelay()()
+	s := testHelper.CreateAndPopulateTestState()
+	dbo := s.DB.(*databaseOverlay.Overlay)
+
+	// delete the fblock at height 5
+	fblock := testHelper.CreateFullTestBlockSet()[5].FBlock
+	if err := dbo.Delete(databaseOverlay.FACTOIDBLOCK, fblock.DatabasePrimaryIndex().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	// without repairs it is only reported
+	ic := state.NewIntegrityChecker(s, false)
+	ic.CheckRange(0, uint32(testHelper.BlockCount-1))
+	if r := ic.Report(); r.Counts[state.IntegrityMissingBlock] != 1 {
+		t.Errorf("expected 1 %s finding, found %v", state.IntegrityMissingBlock, r.Counts)
+	}
+	if s.StatesMissing.Get(5) != nil {
+		t.Error("the DBState was requested without repairs")
+	}
+
+	// with repairs the DBState at its height is handed to the catch-up
+	ic = state.NewIntegrityChecker(s, true)
+	ic.CheckRange(0, uint32(testHelper.BlockCount-1))
+	r := ic.Report()
+	if r.Counts[state.IntegrityMissingBlock] != 1 || !r.Findings[0].Repaired || r.Findings[0].DBHeight != 5 {
+		t.Errorf("expected a requested missing block at 5, found %v", r.Findings)
+	}
+	if s.StatesMissing.Get(5) == nil || s.StatesMissing.Len() != 1 {
+		t.Errorf("expected the DBState at 5 to be missing, found %d missing", s.StatesMissing.Len())
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// JournalVersion is the version of the journal format written by JournalWriter
+const JournalVersion = 1
+
+// journalMagic starts every journal file, followed by its version
+var journalMagic = []byte("FJNL")
+
+const journalHeaderSize = 6
+
+// The queues of a State that messages are journaled entering
+const (
+	JournalInMsgQueue  = "inmsg"  // InMsgQueue, most messages from peers and the API
+	JournalInMsgQueue2 = "inmsg2" // InMsgQueue2, commits and reveals
+	JournalDataQueue   = "data"   // DataMsgQueue, requests for missing data
+)
+
+// JournalRecord is a message entering a queue of a State.  DBHeight and Minute are where the
+// recording node was when the message arrived, so a replay can stop at a given block.
+//
+// In a journal a record is the length of the rest of the record followed by the time in unix
+// nanoseconds, the height, the minute, the queue and the source each prefixed by their length,
+// then the marshalled message.
+type JournalRecord struct {
+	Time     time.Time
+	Queue    string
+	Source   string
+	DBHeight uint32
+	Minute   int
+	Message  interfaces.IMsg
+}
+
+// MarshalBinary marshals the record without its length
+func (r *JournalRecord) MarshalBinary() (data []byte, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("could not marshal the message: %v", e)
+		}
+	}()
+	msg, err := r.Message.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Queue) > 255 || len(r.Source) > 65535 {
+		return nil, fmt.Errorf("the queue or source of the record is too long")
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, r.Time.UnixNano())
+	binary.Write(buf, binary.BigEndian, r.DBHeight)
+	buf.WriteByte(byte(r.Minute))
+	buf.WriteByte(byte(len(r.Queue)))
+	buf.WriteString(r.Queue)
+	binary.Write(buf, binary.BigEndian, uint16(len(r.Source)))
+	buf.WriteString(r.Source)
+	buf.Write(msg)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary unmarshals a record marshalled by MarshalBinary
+func (r *JournalRecord) UnmarshalBinary(data []byte) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("bad journal record: %v", e)
+		}
+	}()
+	buf := bytes.NewBuffer(data)
+	var nanos int64
+	if err := binary.Read(buf, binary.BigEndian, &nanos); err != nil {
+		return err
+	}
+	r.Time = time.Unix(0, nanos)
+	if err := binary.Read(buf, binary.BigEndian, &r.DBHeight); err != nil {
+		return err
+	}
+	minute, err := buf.ReadByte()
+	if err != nil {
+		return err
+	}
+	r.Minute = int(minute)
+	n, err := buf.ReadByte()
+	if err != nil {
+		return err
+	}
+	r.Queue = string(buf.Next(int(n)))
+	var m uint16
+	if err := binary.Read(buf, binary.BigEndian, &m); err != nil {
+		return err
+	}
+	r.Source = string(buf.Next(int(m)))
+	if messages.General == nil {
+		return fmt.Errorf("no message factory to unmarshal the journal with")
+	}
+	r.Message, err = messages.General.UnmarshalMessage(buf.Bytes())
+	return err
+}
+
+// JournalWriter writes a journal, rotating it when it grows past MaxSize bytes.  The journal is
+// renamed to filename.1, the one before that to filename.2 and so on, keeping MaxFiles of them.
+type JournalWriter struct {
+	Filename string
+	MaxSize  int64 // 0 never rotates
+	MaxFiles int
+
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+// NewJournalWriter starts a journal, rotating out the one already there so a restart keeps it
+func NewJournalWriter(filename string, maxSize int64, maxFiles int) (*JournalWriter, error) {
+	w := new(JournalWriter)
+	w.Filename = filename
+	w.MaxSize = maxSize
+	w.MaxFiles = maxFiles
+	if info, err := os.Stat(filename); err == nil && info.Size() > 0 {
+		w.rotate()
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *JournalWriter) open() error {
+	f, err := os.Create(w.Filename)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, journalHeaderSize)
+	copy(header, journalMagic)
+	binary.BigEndian.PutUint16(header[4:], JournalVersion)
+	if _, err := f.Write(header); err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = int64(len(header))
+	return nil
+}
+
+// rotate shifts the old journals up by one, dropping the oldest
+func (w *JournalWriter) rotate() {
+	if w.MaxFiles <= 0 {
+		os.Remove(w.Filename)
+		return
+	}
+	os.Remove(fmt.Sprintf("%s.%d", w.Filename, w.MaxFiles))
+	for i := w.MaxFiles - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.Filename, i), fmt.Sprintf("%s.%d", w.Filename, i+1))
+	}
+	os.Rename(w.Filename, w.Filename+".1")
+}
+
+// Write adds a record to the journal
+func (w *JournalWriter) Write(r *JournalRecord) error {
+	data, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	record := make([]byte, 4, 4+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	record = append(record, data...)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file == nil {
+		return fmt.Errorf("the journal %s is closed", w.Filename)
+	}
+	if w.MaxSize > 0 && w.size > journalHeaderSize && w.size+int64(len(record)) > w.MaxSize {
+		w.file.Close()
+		w.file = nil
+		w.rotate()
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(record)
+	w.size += int64(n)
+	return err
+}
+
+// Close closes the journal
+func (w *JournalWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// JournalReader reads the records of a journal
+type JournalReader struct {
+	Version uint16
+	r       *bufio.Reader
+}
+
+// IsJournal returns true if the data starts like a journal
+func IsJournal(data []byte) bool {
+	return bytes.HasPrefix(data, journalMagic)
+}
+
+// NewJournalReader reads the header of a journal
+func NewJournalReader(r io.Reader) (*JournalReader, error) {
+	j := new(JournalReader)
+	j.r = bufio.NewReaderSize(r, 64*1024)
+	header := make([]byte, journalHeaderSize)
+	if _, err := io.ReadFull(j.r, header); err != nil || !IsJournal(header) {
+		return nil, fmt.Errorf("not a journal")
+	}
+	j.Version = binary.BigEndian.Uint16(header[4:])
+	if j.Version != JournalVersion {
+		return nil, fmt.Errorf("journal version %d is not supported, only %d", j.Version, JournalVersion)
+	}
+	return j, nil
+}
+
+// Next returns the next record, or io.EOF at the end of the journal.  A record cut short, as
+// the last one of a node that was killed can be, is also the end of the journal.
+func (j *JournalReader) Next() (*JournalRecord, error) {
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(j.r, size); err != nil {
+		return nil, io.EOF
+	}
+	n := binary.BigEndian.Uint32(size)
+	if n > 1<<24 {
+		return nil, fmt.Errorf("bad journal record of %d bytes", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(j.r, data); err != nil {
+		return nil, io.EOF
+	}
+	r := new(JournalRecord)
+	if err := r.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// JournalFiles returns the journal and the journals rotated out of it, oldest first
+func JournalFiles(filename string) []string {
+	var rotated []int
+	matches, _ := filepath.Glob(filename + ".*")
+	for _, m := range matches {
+		if n, err := strconv.Atoi(strings.TrimPrefix(m, filename+".")); err == nil && n > 0 {
+			rotated = append(rotated, n)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(rotated)))
+	var files []string
+	for _, n := range rotated {
+		files = append(files, fmt.Sprintf("%s.%d", filename, n))
+	}
+	if _, err := os.Stat(filename); err == nil {
+		files = append(files, filename)
+	}
+	return files
+}
@@ -5,11 +5,18 @@
 package state_test
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
 	. "github.com/FactomProject/factomd/testHelper"
 )
 
@@ -19,21 +26,118 @@ func TestJournal(t *testing.T) {
 	s := CreateAndPopulateTestStateAndStartValidator()
 	filename := "journaltest.log"
 	s.JournalFile = filename
-	_, err := os.Create(s.JournalFile)
-	if err != nil {
+	defer os.Remove(filename)
+	if err := s.StartJournaling(); err != nil {
 		t.Errorf("%v", err)
 	}
-	s.Journaling = true
 
 	msg := new(messages.Ack)
 	msg.MsgHash = primitives.NewZeroHash()
 	msg.MessageHash = primitives.NewZeroHash()
 	msg.SerialHash = primitives.NewZeroHash()
+	msg.LeaderChainID = primitives.NewZeroHash()
+	msg.Timestamp = primitives.NewTimestampNow()
 
-	s.JournalMessage(msg)
+	s.JournalMessage(JournalInMsgQueue, "peer-0", msg)
 
 	msgs := s.GetJournalMessages()
-	if msgs == nil {
+	if len(msgs) != 1 {
 		t.Error("No messages returned from journal")
 	}
 }
+
+func TestJournalRecords(t *testing.T) {
+	var records []*JournalRecord
+	for i := 0; i < 3; i++ {
+		msg := new(messages.EOM)
+		msg.Timestamp = primitives.NewTimestampNow()
+		msg.ChainID = primitives.NewZeroHash()
+		msg.DBHeight = uint32(i)
+		msg.Minute = byte(i)
+
+		r := new(JournalRecord)
+		r.Time = time.Unix(1500000000, int64(i))
+		r.Queue = JournalInMsgQueue
+		r.Source = fmt.Sprintf("peer-%d", i)
+		r.DBHeight = uint32(100 + i)
+		r.Minute = i
+		r.Message = msg
+		records = append(records, r)
+	}
+
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "journal")
+
+	w, err := NewJournalWriter(filename, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	j, err := NewJournalReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range records {
+		r, err := j.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.Time.Equal(want.Time) || r.Queue != want.Queue || r.Source != want.Source || r.DBHeight != want.DBHeight || r.Minute != want.Minute {
+			t.Errorf("record %d: got %+v, expected %+v", i, r, want)
+		}
+		if !r.Message.GetHash().IsSameAs(want.Message.GetHash()) {
+			t.Errorf("record %d: got the message %s, expected %s", i, r.Message.String(), want.Message.String())
+		}
+	}
+	if _, err := j.Next(); err != io.EOF {
+		t.Errorf("expected the end of the journal, got %v", err)
+	}
+
+	if _, err := NewJournalReader(bytes.NewReader([]byte("MsgHex: 00"))); err == nil {
+		t.Error("expected a text journal to be refused")
+	}
+	if _, err := NewJournalReader(bytes.NewReader([]byte{'F', 'J', 'N', 'L', 0, 9})); err == nil {
+		t.Error("expected an unknown version to be refused")
+	}
+
+	// Rotate after every record, keeping 2 old journals
+	w, err = NewJournalWriter(filename, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range records {
+		w.Write(r)
+	}
+	w.Close()
+	files := JournalFiles(filename)
+	if len(files) != 3 || files[0] != filename+".2" || files[2] != filename {
+		t.Fatalf("expected 2 rotated journals, got %v", files)
+	}
+	for i, file := range files {
+		f, _ := os.Open(file)
+		j, err := NewJournalReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The first journal, rotated out when the writer started, is gone
+		if r, err := j.Next(); err != nil || r.Source != records[i].Source {
+			t.Errorf("expected %s to start with the record of %s, got %v", file, records[i].Source, err)
+		}
+		f.Close()
+	}
+}
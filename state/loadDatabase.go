@@ -113,14 +113,27 @@ func LoadDatabase(s *State) {
 		s.Println("******* New Database **************")
 		s.Println("***********************************\n")
 
-		var customIdentity interfaces.IHash
-		if s.Network == "CUSTOM" {
-			customIdentity, err = primitives.HexToHash(s.CustomBootstrapIdentity)
+		var dblk interfaces.IDirectoryBlock
+		var ablk interfaces.IAdminBlock
+		var fblk interfaces.IFBlock
+		var ecblk interfaces.IEntryCreditBlock
+		if s.Network == "CUSTOM" && s.CustomGenesis != "" {
+			dblk, ablk, fblk, ecblk, err = ReadGenesisFile(s.CustomGenesis, s.GetNetworkID())
 			if err != nil {
-				panic(fmt.Sprintf("Could not decode Custom Bootstrap Identity (likely in config file) found: %s\n", s.CustomBootstrapIdentity))
+				panic(fmt.Sprintf("Could not read the Custom Genesis (likely in config file): %v\n", err))
 			}
+			// The entry credits of the genesis are bought at its own rate
+			s.FactoshisPerEC = fblk.GetExchRate()
+		} else {
+			var customIdentity interfaces.IHash
+			if s.Network == "CUSTOM" {
+				customIdentity, err = primitives.HexToHash(s.CustomBootstrapIdentity)
+				if err != nil {
+					panic(fmt.Sprintf("Could not decode Custom Bootstrap Identity (likely in config file) found: %s\n", s.CustomBootstrapIdentity))
+				}
+			}
+			dblk, ablk, fblk, ecblk = GenerateGenesisBlocks(s.GetNetworkID(), customIdentity)
 		}
-		dblk, ablk, fblk, ecblk := GenerateGenesisBlocks(s.GetNetworkID(), customIdentity)
 
 		messages.LogPrintf("marshalsizes.txt", "FBlock unmarshaled transaction count: %d", len(fblk.GetTransactions()))
 
@@ -137,7 +150,7 @@ func LoadDatabase(s *State) {
 }
 
 func GenerateGenesisBlocks(networkID uint32, bootstrapIdentity interfaces.IHash) (interfaces.IDirectoryBlock, interfaces.IAdminBlock, interfaces.IFBlock, interfaces.IEntryCreditBlock) {
-	dblk := directoryBlock.NewDirectoryBlock(nil)
+	var dblk interfaces.IDirectoryBlock
 	ablk := adminBlock.NewAdminBlock(nil)
 	fblk := factoid.GetGenesisFBlock(networkID)
 	ecblk := entryCreditBlock.NewECBlock()
@@ -167,13 +180,24 @@ n:
 		}
 	}
 
+	dblk = genesisDBlock(networkID, ablk, fblk, ecblk)
+	return dblk, ablk, fblk, ecblk
+}
+
+// genesisDBlock makes the directory block of a genesis from its other blocks
+func genesisDBlock(networkID uint32, ablk interfaces.IAdminBlock, fblk interfaces.IFBlock, ecblk interfaces.IEntryCreditBlock) interfaces.IDirectoryBlock {
+	dblk := directoryBlock.NewDirectoryBlock(nil)
 	dblk.SetABlockHash(ablk)
 	dblk.SetECBlockHash(ecblk)
 	dblk.SetFBlockHash(fblk)
 	dblk.GetHeader().SetNetworkID(networkID)
 
-	dblk.GetHeader().SetTimestamp(primitives.NewTimestampFromMinutes(24018960))
+	dblk.GetHeader().SetTimestamp(genesisTimestamp())
 	dblk.BuildBodyMR()
+	return dblk
+}
 
-	return dblk, ablk, fblk, ecblk
+// genesisTimestamp is when every genesis block was made
+func genesisTimestamp() interfaces.Timestamp {
+	return primitives.NewTimestampFromMinutes(24018960)
 }
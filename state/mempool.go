@@ -0,0 +1,108 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+)
+
+// isMempoolType is true for the messages the mempool api lists
+func isMempoolType(msg interfaces.IMsg) bool {
+	switch msg.Type() {
+	case constants.COMMIT_CHAIN_MSG, constants.COMMIT_ENTRY_MSG, constants.REVEAL_ENTRY_MSG, constants.FACTOID_TRANSACTION_MSG:
+		return true
+	}
+	return false
+}
+
+// GetMempool returns the commits, reveals and factoid transactions acked in the
+// process lists past the last complete block, then those in the dependent holding,
+// then those in holding.  A message is only listed the first place it is found.
+func (s *State) GetMempool() []interfaces.IMempoolMessage {
+	rval := make([]interfaces.IMempoolMessage, 0)
+	seen := make(map[[32]byte]bool)
+	add := func(m interfaces.IMempoolMessage) {
+		h := m.Msg.GetMsgHash().Fixed()
+		if !seen[h] {
+			seen[h] = true
+			rval = append(rval, m)
+		}
+	}
+
+	lastComplete := s.GetDBHeightComplete()
+	for _, pl := range s.ProcessLists.Lists {
+		if pl == nil || pl.DBHeight <= lastComplete {
+			continue
+		}
+		for _, vm := range pl.VMs {
+			for _, msg := range vm.List {
+				if msg != nil && isMempoolType(msg) {
+					add(interfaces.IMempoolMessage{Msg: msg, Status: constants.MempoolStatusAcked, DBHeight: pl.DBHeight})
+				}
+			}
+		}
+	}
+
+	for h, list := range s.LoadDependentHoldingMap() {
+		for _, msg := range list {
+			if isMempoolType(msg) {
+				add(s.heldMempoolMessage(h, msg))
+			}
+		}
+	}
+
+	for _, msg := range s.LoadHoldingMap() {
+		if isMempoolType(msg) {
+			add(interfaces.IMempoolMessage{Msg: msg, Status: constants.MempoolStatusHolding})
+		}
+	}
+	return rval
+}
+
+// heldMempoolMessage works out why a message in the dependent holding waits on h,
+// from what h is to the message
+func (s *State) heldMempoolMessage(h [32]byte, msg interfaces.IMsg) interfaces.IMempoolMessage {
+	m := interfaces.IMempoolMessage{Msg: msg, Status: constants.MempoolStatusHeldForDependency, DependsOn: h}
+
+	if height, ok := hashToHeight(h); ok {
+		m.Status = constants.MempoolStatusHeldForHeight
+		m.Reason = constants.MempoolReasonFutureHeight
+		m.DBHeight = height
+		return m
+	}
+
+	m.Reason = constants.MempoolReasonDependency
+	switch msg := msg.(type) {
+	case *messages.RevealEntryMsg:
+		switch {
+		case h == msg.Entry.GetChainID().Fixed():
+			m.Reason = constants.MempoolReasonMissingChain
+		case s.Commits.Get(h) != nil:
+			m.Reason = constants.MempoolReasonUnderpaidCommit
+		default:
+			m.Reason = constants.MempoolReasonMissingCommit
+		}
+	case *messages.CommitChainMsg, *messages.CommitEntryMsg:
+		m.Reason = constants.MempoolReasonInsufficientEC
+	case *messages.FactoidTransaction:
+		m.Reason = constants.MempoolReasonInsufficientFactoid
+	}
+	return m
+}
+
+// hashToHeight undoes HeightToHash, for a hash that is a height
+func hashToHeight(h [32]byte) (uint32, bool) {
+	if h == [32]byte{} {
+		return 0, false
+	}
+	for _, b := range h[5:] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return uint32(h[0])<<24 | uint32(h[1])<<16 | uint32(h[2])<<8 | uint32(h[3]), true
+}
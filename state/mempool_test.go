@@ -0,0 +1,97 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestGetMempool(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	entry := testHelper.CreateTestEntry(100)
+	reveal := new(messages.RevealEntryMsg)
+	reveal.Entry = entry
+	reveal.Timestamp = primitives.NewTimestampNow()
+
+	other := testHelper.CreateTestEntry(101)
+	revealNoChain := new(messages.RevealEntryMsg)
+	revealNoChain.Entry = other
+	revealNoChain.Timestamp = primitives.NewTimestampNow()
+
+	commit := entryCreditBlock.NewCommitEntry()
+	commit.EntryHash = entryBlock.NewEntry().GetHash()
+	commit.Credits = 1
+	testHelper.SignCommit(5, commit)
+	commitMsg := &messages.CommitEntryMsg{CommitEntry: commit}
+
+	future := entryCreditBlock.NewCommitEntry()
+	future.EntryHash = other.GetHash()
+	future.Credits = 2
+	testHelper.SignCommit(5, future)
+	futureMsg := &messages.CommitEntryMsg{CommitEntry: future}
+
+	tx := new(factoid.Transaction)
+	tx.AddInput(testHelper.NewFactoidAddress(5), 1000)
+	tx.AddOutput(testHelper.NewFactoidAddress(1), 990)
+	testHelper.SignFactoidTransaction(5, tx)
+	txMsg := &messages.FactoidTransaction{Transaction: tx}
+
+	s.Add(entry.GetHash().Fixed(), reveal)
+	s.Add(other.GetChainID().Fixed(), revealNoChain)
+	s.Add(commit.ECPubKey.Fixed(), commitMsg)
+	s.Add(HeightToHash(100, 3), futureMsg)
+	s.Add(tx.Inputs[0].GetAddress().Fixed(), txMsg)
+	s.HoldingLast = 0
+	s.UpdateState()
+
+	held := make(map[[32]byte]interfaces.IMempoolMessage)
+	for _, m := range s.GetMempool() {
+		if m.Status != constants.MempoolStatusAcked {
+			held[m.Msg.GetMsgHash().Fixed()] = m
+		}
+	}
+	if len(held) != 5 {
+		t.Fatalf("Expected 5 held messages, found %d", len(held))
+	}
+
+	for _, c := range []struct {
+		msg    interfaces.IMsg
+		status string
+		reason string
+	}{
+		{reveal, constants.MempoolStatusHeldForDependency, constants.MempoolReasonMissingCommit},
+		{revealNoChain, constants.MempoolStatusHeldForDependency, constants.MempoolReasonMissingChain},
+		{commitMsg, constants.MempoolStatusHeldForDependency, constants.MempoolReasonInsufficientEC},
+		{futureMsg, constants.MempoolStatusHeldForHeight, constants.MempoolReasonFutureHeight},
+		{txMsg, constants.MempoolStatusHeldForDependency, constants.MempoolReasonInsufficientFactoid},
+	} {
+		m := held[c.msg.GetMsgHash().Fixed()]
+		if m.Status != c.status || m.Reason != c.reason {
+			t.Errorf("%s: expected %s %s, found %s %s", c.msg.String(), c.status, c.reason, m.Status, m.Reason)
+		}
+	}
+	if m := held[futureMsg.GetMsgHash().Fixed()]; m.DBHeight != 100 {
+		t.Errorf("Expected to be held for height 100, found %d", m.DBHeight)
+	}
+
+	// With a commit that pays too little, the reveal is underpaid
+	s.Commits.Put(entry.GetHash().Fixed(), commitMsg)
+	for _, m := range s.GetMempool() {
+		if m.Msg == reveal && m.Reason != constants.MempoolReasonUnderpaidCommit {
+			t.Errorf("Expected %s, found %s", constants.MempoolReasonUnderpaidCommit, m.Reason)
+		}
+	}
+}
@@ -1157,6 +1157,7 @@ func (p *ProcessList) AddToProcessList(s *State, ack *messages.Ack, m interfaces
 	//p.processVM(p.VMs[ack.VMIndex])
 
 	// Both the ack and the message hash to the same GetHash()
+	leader := ack.IsLocal()
 	ack.SetLocal(false)
 	ack.SetPeer2Peer(false)
 	m.SetPeer2Peer(false)
@@ -1164,6 +1165,9 @@ func (p *ProcessList) AddToProcessList(s *State, ack *messages.Ack, m interfaces
 
 	m.SendOut(s, m)
 	ack.SendOut(s, ack)
+	if leader {
+		s.chaosEquivocate(p, ack)
+	}
 
 	// also add the msg and ack to our missing msg request handler
 	s.MissingMessageResponseHandler.NotifyNewMsgPair(ack, m)
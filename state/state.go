@@ -5,7 +5,6 @@
 package state
 
 import (
-	"bufio"
 	"bytes"
 	"crypto/rand"
 	"encoding/binary"
@@ -22,6 +21,7 @@ import (
 
 	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/constants/runstate"
 	"github.com/FactomProject/factomd/common/globals"
@@ -72,9 +72,20 @@ type State struct {
 		CheckChainHeads bool
 		Fix             bool
 	}
-	CloneDBType       string
-	ExportData        bool
-	ExportDataSubpath string
+	IntegrityCheck struct {
+		Enabled bool // Check the database in the background while running
+		Repair  bool // Repair what the check finds
+	}
+	CloneDBType        string
+	ExportData         bool
+	ExportDataSubpath  string
+	ExportFormats      string // Comma separated list of export sinks: files, ndjson, parquet, sql
+	ExportParquetRange uint32 // Number of heights in each Parquet file
+	ExportSQLDriver    string // database/sql driver name for the sql sink
+	ExportSQLSource    string // database/sql data source for the sql sink
+	AddressIndexOn     bool   // Keep the history of every factoid and entry credit address
+	BalanceCheckpoints uint32 // Keep the balances at every height, with a full copy every so many heights.  0 is off
+	FinalArchivePath   string // Where to write the archive of the final state of the Factom era, if not ""
 
 	LogBits int64 // Bit zero is for logging the Directory Block on DBSig [5]
 
@@ -115,6 +126,7 @@ type State struct {
 	CustomNetworkID         []byte
 	CustomBootstrapIdentity string
 	CustomBootstrapKey      string
+	CustomGenesis           string // File of the genesis blocks of a custom network, see WriteGenesisFile
 
 	IdentityChainID interfaces.IHash // If this node has an identity, this is it
 	//Identities      []*Identity      // Identities of all servers in management chain
@@ -140,6 +152,8 @@ type State struct {
 	HoldingMutex sync.RWMutex
 	HoldingLast  int64
 	HoldingMap   map[[32]byte]interfaces.IMsg
+	// and of the dependent holding, by what the messages wait on
+	DependentHoldingMap map[[32]byte][]interfaces.IMsg
 
 	// Elections are managed through the Elections Structure
 	EFactory  interfaces.IElectionsFactory
@@ -189,6 +203,10 @@ type State struct {
 	ShutdownChan chan int // For gracefully halting Factom
 	JournalFile  string
 	Journaling   bool
+	// Journals are rotated when they grow past JournalMaxSize bytes, keeping JournalMaxFiles old ones
+	JournalMaxSize  int64
+	JournalMaxFiles int
+	journal         *JournalWriter
 
 	ServerPrivKey         *primitives.PrivateKey
 	ServerPubKey          *primitives.PublicKey
@@ -354,6 +372,34 @@ type State struct {
 	// EntrySync handles the downloading of entries
 	EntrySync *EntrySync
 
+	// IntegrityChecker checks the database in the background, nil if not enabled
+	IntegrityChecker *IntegrityChecker
+
+	// ExportPipeline exports the saved blocks, nil if ExportData is off
+	ExportPipeline *ExportPipeline
+
+	// AddressIndex holds the history of every address, nil if AddressIndexOn is false
+	AddressIndex *AddressIndex
+
+	// BalanceHistory holds the balances at every height, nil if BalanceCheckpoints is 0
+	BalanceHistory *BalanceHistory
+
+	// AuthorityHistory holds the authority set at every height
+	AuthorityHistory *AuthorityHistory
+
+	// ElectionLog holds the timelines of the last elections
+	ElectionLog *ElectionLog
+
+	// SimTopology is the network between the nodes of a simulation, nil if this node isn't simulated
+	SimTopology interfaces.ISimTopology
+	// LoadGenerator is the simulator's load generator, nil if there isn't one
+	LoadGenerator interfaces.ILoadGenerator
+	// chaos is the misbehaviour the simulator has told this node to show.  chaosOn is set while
+	// there is any, so the hooks cost a node that behaves an atomic load and no lock.
+	chaos      Chaos
+	chaosMutex sync.Mutex
+	chaosOn    int32
+
 	MissingEntryBlockRepeat interfaces.Timestamp
 	// DBlock Height at which node has a complete set of eblocks+entries
 	EntryBlockDBHeightComplete uint32
@@ -495,8 +541,10 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.RunState = runstate.New // reset runstate since this clone will be started by sim node
 	newState.DropRate = s.DropRate
 	newState.LdbPath = s.LdbPath + "/Sim" + number
-	newState.JournalFile = s.LogPath + "/journal" + number + ".log"
+	newState.JournalFile = s.LogPath + "/journal" + number + ".journal"
 	newState.Journaling = s.Journaling
+	newState.JournalMaxSize = s.JournalMaxSize
+	newState.JournalMaxFiles = s.JournalMaxFiles
 	newState.BoltDBPath = s.BoltDBPath + "/Sim" + number
 	newState.LogLevel = s.LogLevel
 	newState.ConsoleLogLevel = s.ConsoleLogLevel
@@ -504,8 +552,15 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.CloneDBType = s.CloneDBType
 	newState.DBType = s.CloneDBType
 	newState.CheckChainHeads = s.CheckChainHeads
+	newState.IntegrityCheck = s.IntegrityCheck
 	newState.ExportData = s.ExportData
 	newState.ExportDataSubpath = s.ExportDataSubpath + "sim-" + number
+	newState.ExportFormats = s.ExportFormats
+	newState.ExportParquetRange = s.ExportParquetRange
+	newState.ExportSQLDriver = s.ExportSQLDriver
+	newState.ExportSQLSource = s.ExportSQLSource
+	newState.AddressIndexOn = s.AddressIndexOn
+	newState.BalanceCheckpoints = s.BalanceCheckpoints
 	newState.Network = s.Network
 	newState.MainNetworkPort = s.MainNetworkPort
 	newState.PeersFile = s.PeersFile
@@ -524,6 +579,7 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.CustomNetworkID = s.CustomNetworkID
 	newState.CustomBootstrapIdentity = s.CustomBootstrapIdentity
 	newState.CustomBootstrapKey = s.CustomBootstrapKey
+	newState.CustomGenesis = s.CustomGenesis
 
 	newState.DirectoryBlockInSeconds = s.DirectoryBlockInSeconds
 	newState.PortNumber = s.PortNumber
@@ -754,7 +810,7 @@ func (s *State) GetPreviousMinuteStartTime() int64 {
 }
 
 func (s *State) GetCurrentTime() int64 {
-	return time.Now().UnixNano()
+	return clock.Now().UnixNano()
 }
 
 func (s *State) IsSyncing() bool {
@@ -834,10 +890,17 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.BoltDBPath = cfg.App.BoltDBPath + s.Prefix
 		s.LogLevel = cfg.Log.LogLevel
 		s.ConsoleLogLevel = cfg.Log.ConsoleLogLevel
+		s.Journaling = cfg.Log.Journaling
+		s.JournalMaxSize = int64(cfg.Log.JournalMaxSize) << 20
+		s.JournalMaxFiles = cfg.Log.JournalMaxFiles
 		s.NodeMode = cfg.App.NodeMode
 		s.DBType = cfg.App.DBType
 		s.ExportData = cfg.App.ExportData // bool
 		s.ExportDataSubpath = cfg.App.ExportDataSubpath
+		s.ExportFormats = cfg.App.ExportFormats
+		s.ExportParquetRange = cfg.App.ExportParquetRange
+		s.ExportSQLDriver = cfg.App.ExportSQLDriver
+		s.ExportSQLSource = cfg.App.ExportSQLSource
 		s.MainNetworkPort = cfg.App.MainNetworkPort
 		s.PeersFile = cfg.App.PeersFile
 		s.MainSeedURL = cfg.App.MainSeedURL
@@ -847,6 +910,7 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.TestSpecialPeers = cfg.App.TestSpecialPeers
 		s.CustomBootstrapIdentity = cfg.App.CustomBootstrapIdentity
 		s.CustomBootstrapKey = cfg.App.CustomBootstrapKey
+		s.CustomGenesis = cfg.App.CustomGenesis
 		s.LocalNetworkPort = cfg.App.LocalNetworkPort
 		s.LocalSeedURL = cfg.App.LocalSeedURL
 		s.LocalSpecialPeers = cfg.App.LocalSpecialPeers
@@ -950,6 +1014,8 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.DBType = "Map"
 		s.ExportData = false
 		s.ExportDataSubpath = "data/export"
+		s.ExportFormats = "files"
+		s.ExportParquetRange = 1000
 		s.Network = "TEST"
 		s.MainNetworkPort = "8108"
 		s.PeersFile = "peers.json"
@@ -976,7 +1042,7 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.LogPrintf("AckChange", "Default IdentityChainID %v", s.IdentityChainID.String())
 
 	}
-	s.JournalFile = s.LogPath + "/journal0" + ".log"
+	s.JournalFile = s.LogPath + "/journal0" + ".journal"
 
 	s.updateNetworkControllerConfig()
 }
@@ -1058,12 +1124,10 @@ func (s *State) Init() {
 	s.RecentMessage.NewMsgs = make(chan interfaces.IMsg, 100)
 
 	if s.Journaling {
-		f, err := os.Create(s.JournalFile)
-		if err != nil {
-			fmt.Println("Could not create the journal file:", s.JournalFile)
+		if err := s.StartJournaling(); err != nil {
+			fmt.Println("Could not create the journal file:", s.JournalFile, err)
 			s.JournalFile = ""
 		}
-		f.Close()
 	}
 	// Set up struct to stop replay attacks
 	s.Replay = new(Replay)
@@ -1160,8 +1224,21 @@ func (s *State) Init() {
 		}
 	}
 	if s.ExportData {
-		s.DB.SetExportData(s.ExportDataSubpath)
+		pipeline, err := NewExportPipeline(s)
+		if err != nil {
+			panic(fmt.Sprintf("bad export config: %v", err))
+		}
+		s.ExportPipeline = pipeline
+	}
+
+	if s.AddressIndexOn {
+		s.AddressIndex = NewAddressIndex(s)
+	}
+	if s.BalanceCheckpoints > 0 {
+		s.BalanceHistory = NewBalanceHistory(s, s.BalanceCheckpoints)
 	}
+	s.AuthorityHistory = NewAuthorityHistory(s)
+	s.ElectionLog = NewElectionLog(s)
 
 	// Cross Boot Replay
 	switch s.DBType {
@@ -1303,6 +1380,129 @@ func (s *State) GetLLeaderHeight() uint32 {
 	return s.LLeaderHeight
 }
 
+// GetIntegrityReport returns the progress and findings of the background integrity checker
+func (s *State) GetIntegrityReport() *interfaces.IntegrityReport {
+	if s.IntegrityChecker == nil {
+		return new(interfaces.IntegrityReport)
+	}
+	return s.IntegrityChecker.Report()
+}
+
+// GetAddressHistory returns a page of the transactions that touched a factoid or entry credit address
+func (s *State) GetAddressHistory(ec bool, address [32]byte, offset uint32, limit uint32) (*interfaces.AddressHistory, error) {
+	if s.AddressIndex == nil {
+		return nil, fmt.Errorf("the address index is not enabled on this node")
+	}
+	return s.AddressIndex.History(ec, address, offset, limit)
+}
+
+// GetBalanceAtHeight returns the balance of a factoid or entry credit address at the end of a block
+func (s *State) GetBalanceAtHeight(ec bool, address [32]byte, dbheight uint32) (int64, error) {
+	if s.AddressIndex == nil {
+		return 0, fmt.Errorf("the address index is not enabled on this node")
+	}
+	return s.AddressIndex.BalanceAt(ec, address, dbheight)
+}
+
+func (s *State) anchorIndex() (*databaseOverlay.Overlay, error) {
+	dbo, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return nil, fmt.Errorf("the anchor index needs a database overlay, not %T", s.DB)
+	}
+	return dbo, nil
+}
+
+// GetAnchorStatus returns the Bitcoin and Ethereum anchor records of a directory block, nil
+// if there is no block at that height
+func (s *State) GetAnchorStatus(dbheight uint32) (*interfaces.AnchorStatus, error) {
+	dbo, err := s.anchorIndex()
+	if err != nil {
+		return nil, err
+	}
+	keyMR, err := dbo.FetchDBKeyMRByHeight(dbheight)
+	if err != nil {
+		return nil, err
+	} else if keyMR == nil {
+		return nil, nil
+	}
+
+	status := new(interfaces.AnchorStatus)
+	status.DBHeight = dbheight
+	status.KeyMR = keyMR.String()
+	bitcoin, err := dbo.FetchAnchorIndexRecord(false, dbheight)
+	if err != nil {
+		return nil, err
+	} else if bitcoin != nil {
+		status.Bitcoin = bitcoin.Ref()
+	}
+	ethereum, err := dbo.FetchAnchorIndexRecord(true, dbheight)
+	if err != nil {
+		return nil, err
+	} else if ethereum != nil {
+		status.Ethereum = ethereum.Ref()
+	}
+	return status, nil
+}
+
+// GetLatestAnchors returns the records anchoring the highest directory blocks anchored in
+// Bitcoin and in Ethereum
+func (s *State) GetLatestAnchors() (*interfaces.LatestAnchors, error) {
+	dbo, err := s.anchorIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := new(interfaces.LatestAnchors)
+	bitcoin, err := dbo.FetchLatestAnchorIndexRecord(false)
+	if err != nil {
+		return nil, err
+	} else if bitcoin != nil {
+		latest.Bitcoin = bitcoin.Ref()
+	}
+	ethereum, err := dbo.FetchLatestAnchorIndexRecord(true)
+	if err != nil {
+		return nil, err
+	} else if ethereum != nil {
+		latest.Ethereum = ethereum.Ref()
+	}
+	return latest, nil
+}
+
+// GetAuthoritiesAtHeight returns the federated and audit servers that built the block at a height
+func (s *State) GetAuthoritiesAtHeight(dbheight uint32) (*interfaces.AuthoritySet, error) {
+	if s.AuthorityHistory == nil {
+		return nil, fmt.Errorf("the authority history is not loaded")
+	}
+	return s.AuthorityHistory.AuthoritiesAt(dbheight)
+}
+
+// GetAuthorityChanges returns a page of the admin block entries that changed the authorities,
+// optionally of one authority and between two heights
+func (s *State) GetAuthorityChanges(chainID interfaces.IHash, start uint32, end uint32, offset uint32, limit uint32) (*interfaces.AuthorityChanges, error) {
+	if s.AuthorityHistory == nil {
+		return nil, fmt.Errorf("the authority history is not loaded")
+	}
+	return s.AuthorityHistory.Changes(chainID, start, end, offset, limit)
+}
+
+// GetElectionLog returns the timelines of the last elections and of the running one, oldest first
+func (s *State) GetElectionLog() ([]*interfaces.ElectionRecord, error) {
+	if s.ElectionLog == nil {
+		return nil, fmt.Errorf("the election log is not loaded")
+	}
+	return s.ElectionLog.Records()
+}
+
+// GetLoadGenerator returns the simulator's load generator, or nil if there isn't one
+func (s *State) GetLoadGenerator() interfaces.ILoadGenerator {
+	return s.LoadGenerator
+}
+
+// GetSimTopology returns the network between the nodes of a simulation, or nil if this node isn't simulated
+func (s *State) GetSimTopology() interfaces.ISimTopology {
+	return s.SimTopology
+}
+
 func (s *State) GetFaultTimeout() int {
 	return s.FaultTimeout
 }
@@ -1576,21 +1776,38 @@ func (s *State) LoadHoldingMap() map[[32]byte]interfaces.IMsg {
 	return localMap
 }
 
+// LoadDependentHoldingMap returns the snapshot of the dependent holding, the messages
+// held by what they wait on
+func (s *State) LoadDependentHoldingMap() map[[32]byte][]interfaces.IMsg {
+	s.HoldingMutex.RLock()
+	defer s.HoldingMutex.RUnlock()
+	return s.DependentHoldingMap
+}
+
 // this is executed in the state maintenance processes where the holding queue is in scope and can be queried
 //  This is what fills the HoldingMap while locking it against a read while building
 func (s *State) fillHoldingMap() {
 	// once a second is often enough to rebuild the Ack list exposed to api
 
-	if s.HoldingLast < time.Now().Unix() {
+	if s.HoldingLast < clock.Now().Unix() {
 
 		localMap := make(map[[32]byte]interfaces.IMsg)
 		for i, msg := range s.Holding {
 			localMap[i] = msg
 		}
-		s.HoldingLast = time.Now().Unix()
+		dependentMap := make(map[[32]byte][]interfaces.IMsg)
+		for h, list := range s.Hold.Messages() {
+			for _, msg := range list {
+				if msg != nil {
+					dependentMap[h] = append(dependentMap[h], msg)
+				}
+			}
+		}
+		s.HoldingLast = clock.Now().Unix()
 		s.HoldingMutex.Lock()
 		defer s.HoldingMutex.Unlock()
 		s.HoldingMap = localMap
+		s.DependentHoldingMap = dependentMap
 
 	}
 }
@@ -1610,12 +1827,12 @@ func (s *State) LoadAcksMap() map[[32]byte]interfaces.IMsg {
 //  This is what fills the AcksMap requested in LoadAcksMap
 func (s *State) fillAcksMap() {
 	// once a second is often enough to rebuild the Ack list exposed to api
-	if s.AcksLast < time.Now().Unix() {
+	if s.AcksLast < clock.Now().Unix() {
 		localMap := make(map[[32]byte]interfaces.IMsg)
 		for i, msg := range s.Acks {
 			localMap[i] = msg
 		}
-		s.AcksLast = time.Now().Unix()
+		s.AcksLast = clock.Now().Unix()
 		s.AcksMutex.Lock()
 		defer s.AcksMutex.Unlock()
 		s.AcksMap = localMap
@@ -1930,7 +2147,7 @@ func (s *State) IsStalled() bool {
 	stalltime = stalltime * 1.5 * 1e9
 	//fmt.Println("STALL 2", s.CurrentMinuteStartTime/1e9, time.Now().UnixNano()/1e9, stalltime/1e9, (float64(time.Now().UnixNano())-stalltime)/1e9)
 
-	if float64(s.CurrentMinuteStartTime) < float64(time.Now().UnixNano())-stalltime { //-90 seconds was arbitrary
+	if float64(s.CurrentMinuteStartTime) < float64(clock.Now().UnixNano())-stalltime { //-90 seconds was arbitrary
 		return true
 	}
 
@@ -1945,56 +2162,75 @@ func (s *State) DatabaseContains(hash interfaces.IHash) bool {
 	return false
 }
 
-// JournalMessage writes the message to the message journal for debugging
-func (s *State) JournalMessage(msg interfaces.IMsg) {
-	type journalentry struct {
-		Type    byte
-		Message interfaces.IMsg
+// StartJournaling starts a new journal in JournalFile, rotating out the last one
+func (s *State) StartJournaling() error {
+	if s.journal != nil {
+		s.journal.Close()
 	}
+	journal, err := NewJournalWriter(s.JournalFile, s.JournalMaxSize, s.JournalMaxFiles)
+	if err != nil {
+		return err
+	}
+	s.journal = journal
+	s.Journaling = true
+	return nil
+}
 
-	if s.Journaling && len(s.JournalFile) != 0 {
-		f, err := os.OpenFile(s.JournalFile, os.O_APPEND+os.O_WRONLY, 0666)
-		if err != nil {
-			s.JournalFile = ""
-			return
-		}
-		defer f.Close()
-
-		e := new(journalentry)
-		e.Type = msg.Type()
-		e.Message = msg
-
-		p, err := json.Marshal(e)
-		if err != nil {
-			return
-		}
-		fmt.Fprintln(f, string(p))
+// JournalMessage writes a message entering a queue to the message journal for debugging
+func (s *State) JournalMessage(queue string, source string, msg interfaces.IMsg) {
+	if !s.Journaling || s.journal == nil {
+		return
+	}
+	r := new(JournalRecord)
+	r.Time = clock.Now()
+	r.Queue = queue
+	r.Source = source
+	r.DBHeight = s.LLeaderHeight
+	r.Minute = s.CurrentMinute
+	r.Message = msg
+	if err := s.journal.Write(r); err != nil {
+		s.LogPrintf("journal", "could not journal %s: %v", msg.String(), err)
 	}
 }
 
-// GetJournalMessages gets all messages from the message journal
+// GetJournalMessages gets all messages from the message journal, as JSON
 func (s *State) GetJournalMessages() [][]byte {
-	ret := make([][]byte, 0)
+	type journalentry struct {
+		Time     time.Time
+		Queue    string
+		Source   string
+		DBHeight uint32
+		Minute   int
+		Type     byte
+		Message  interfaces.IMsg
+	}
+
 	if !s.Journaling || len(s.JournalFile) == 0 {
 		return nil
 	}
 
 	f, err := os.Open(s.JournalFile)
 	if err != nil {
-		s.JournalFile = ""
 		return nil
 	}
 	defer f.Close()
 
-	r := bufio.NewReader(f)
+	ret := make([][]byte, 0)
+	j, err := NewJournalReader(f)
+	if err != nil {
+		return ret
+	}
 	for {
-		p, err := r.ReadBytes('\n')
+		r, err := j.Next()
 		if err != nil {
 			break
 		}
+		p, err := json.Marshal(&journalentry{r.Time, r.Queue, r.Source, r.DBHeight, r.Minute, r.Message.Type(), r.Message})
+		if err != nil {
+			continue
+		}
 		ret = append(ret, p)
 	}
-
 	return ret
 }
 
@@ -2267,7 +2503,7 @@ func (s *State) GetTimestamp() interfaces.Timestamp {
 		fmt.Println("^^^^^^^^ IsReplying is true")
 		return s.ReplayTimestamp
 	}
-	return primitives.NewTimestampNow()
+	return s.chaosTimestamp()
 }
 
 func (s *State) GetTimeOffset() interfaces.Timestamp {
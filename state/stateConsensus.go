@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/FactomProject/factomd/activations"
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/entryBlock"
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
@@ -827,7 +828,7 @@ func (s *State) MoveStateToHeight(dbheight uint32, newMinute int) {
 		// update cached values that change with height
 		s.dbheights <- int(dbheight) // Notify MMR process we have moved on...
 
-		s.CurrentMinuteStartTime = time.Now().UnixNano()
+		s.CurrentMinuteStartTime = clock.Now().UnixNano()
 		s.CurrentBlockStartTime = s.CurrentMinuteStartTime
 
 		// If an we added or removed servers or elections tool place in minute 9, our lists will be unsorted. Fix that
@@ -874,7 +875,7 @@ func (s *State) MoveStateToHeight(dbheight uint32, newMinute int) {
 		// there might be a circumstance where we get here in a weird state
 		// so make it the normal starting state
 
-		s.CurrentMinuteStartTime = time.Now().UnixNano()
+		s.CurrentMinuteStartTime = clock.Now().UnixNano()
 		// If an election took place, our lists will be unsorted. Fix that
 		s.LeaderPL.SortAuditServers()
 		s.LeaderPL.SortFedServers()
@@ -977,14 +978,14 @@ func (s *State) FollowerExecuteMsg(m interfaces.IMsg) {
 // execute a msg with an optional delay (in factom seconds)
 func (s *State) repost(m interfaces.IMsg, delay int) {
 	//whereAmI := atomic.WhereAmIString(1)
-	go func() { // This is a trigger to issue the EOM, but we are still syncing.  Wait to retry.
+	clock.Go(func() { // This is a trigger to issue the EOM, but we are still syncing.  Wait to retry.
 		if delay > 0 {
-			time.Sleep(time.Duration(delay) * s.FactomSecond()) // delay in Factom seconds
+			clock.Sleep(time.Duration(delay) * s.FactomSecond()) // delay in Factom seconds
 		}
 		//s.LogMessage("MsgQueue", fmt.Sprintf("enqueue_%s(%d)", whereAmI, len(s.msgQueue)), m)
 		s.LogMessage("MsgQueue", fmt.Sprintf("repost enqueue (%d)", len(s.msgQueue)), m)
 		s.msgQueue <- m // Goes in the "do this really fast" queue so we are prompt about EOM's while syncing
-	}()
+	})
 }
 
 // FactomSecond finds the time duration of 1 second relative to 10min blocks.
@@ -1325,8 +1326,14 @@ func (s *State) FollowerExecuteDataResponse(m interfaces.IMsg) {
 
 	switch msg.DataType {
 	case 1: // Data is an entryBlock
-		s.LogMessage("executeMsg", "unprompted eblock response was sent", msg)
-		return
+		eblock, ok := msg.DataObject.(interfaces.IEntryBlock)
+		if !ok || s.IntegrityChecker == nil || !s.IntegrityChecker.AskedFor(msg.DataHash) {
+			s.LogMessage("executeMsg", "unprompted eblock response was sent", msg)
+			return
+		}
+		if err := s.IntegrityChecker.SaveEBlock(eblock); err != nil {
+			s.LogMessage("executeMsg", fmt.Sprintf("failed to save requested eblock: %v", err), msg)
+		}
 	case 0: // Data is an entry
 		entry, ok := msg.DataObject.(interfaces.IEBEntry)
 		if !ok {
@@ -1496,7 +1503,7 @@ func (s *State) LeaderExecuteEOM(m interfaces.IMsg) {
 		fix = true
 	}
 
-	s.EOMIssueTime = time.Now().UnixNano() // Time we issue the EOM
+	s.EOMIssueTime = clock.Now().UnixNano() // Time we issue the EOM
 
 	// make sure EOM has the right data
 	eom.DBHeight = s.LLeaderHeight
@@ -1515,6 +1522,8 @@ func (s *State) LeaderExecuteEOM(m interfaces.IMsg) {
 		s.LogMessage("executeMsg", "matching ACK", ack)
 	}
 
+	s.chaosCrash(ChaosBeforeEOM, s.LeaderVMIndex)
+
 	TotalAcksInputs.Inc()
 	s.Acks[eom.GetMsgHash().Fixed()] = ack
 	ack.SendOut(s, ack)
@@ -1949,6 +1958,7 @@ func (s *State) SendDBSig(dbheight uint32, vmIndex int) {
 			}
 
 			dbslog.WithFields(dbs.LogFields()).WithFields(log.Fields{"lheight": s.GetLeaderHeight(), "node-name": s.GetFactomNodeName()}).Infof("Generate DBSig")
+			s.chaosCrash(ChaosBeforeDBSig, vmIndex)
 			dbs.LeaderExecute(s)
 			vm.Signed = true
 			pl.DBSigAlreadySent = true
@@ -2036,7 +2046,7 @@ func (s *State) ProcessEOM(dbheight uint32, msg interfaces.IMsg) bool {
 			s.EOMDone = false  // ProcessEOM (EOM complete)
 			s.EOMProcessed = 0 // ProcessEOM (EOM complete)
 
-			s.EOMSyncEnd = time.Now().UnixNano()
+			s.EOMSyncEnd = clock.Now().UnixNano()
 
 			for _, vm := range pl.VMs {
 				vm.Synced = false // ProcessEOM (EOM complete)
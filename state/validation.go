@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/FactomProject/factomd/common/clock"
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
 
 	"github.com/FactomProject/factomd/common/constants"
@@ -126,11 +127,11 @@ func (s *State) ValidatorLoop() {
 					c = 8 // Send 8 retries on a 1/10 of the normal minute period
 				}
 				if c > 0 {
-					go func() {
+					clock.Go(func() {
 						// We sleep for 1/10 of a minute, and try again
-						time.Sleep(s.GetMinuteDuration() / 10)
+						clock.Sleep(s.GetMinuteDuration() / 10)
 						s.tickerQueue <- c - 1
-					}()
+					})
 				}
 				s.LogPrintf("timer", "retry %d", c)
 				s.LogPrintf("validator", "retry %d  %d-:-%d %d", c, s.LLeaderHeight, currentMinute, s.LeaderVMIndex)
@@ -0,0 +1,261 @@
+package testHelper
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/messages/electionMsgs"
+	"github.com/FactomProject/factomd/common/messages/msgsupport"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+)
+
+// CreateTestMessages returns a message of each type a peer can send, signed where the type is
+// signed, built from the test blocks.  They make a seed corpus for fuzzing the decoders.
+func CreateTestMessages() []interfaces.IMsg {
+	s := CreateEmptyTestState()
+	key := NewPrimitivesPrivateKey(1)
+	sign := func(m interfaces.IMsg) interfaces.IMsg {
+		if signable, ok := m.(interface{ Sign(interfaces.Signer) error }); ok {
+			if err := signable.Sign(key); err != nil {
+				panic(err)
+			}
+		}
+		return m
+	}
+	set := CreateTestBlockSet(nil)
+	set = CreateTestBlockSet(set)
+	ts := primitives.NewTimestampFromMilliseconds(1500000000000)
+
+	var msgs []interfaces.IMsg
+
+	eom := new(messages.EOM)
+	eom.Timestamp = ts
+	eom.Minute = 3
+	eom.ChainID = NewRepeatingHash(0xee)
+	eom.DBHeight = 2
+	msgs = append(msgs, sign(eom))
+
+	ack := new(messages.Ack)
+	ack.Timestamp = ts
+	ack.MessageHash = eom.GetMsgHash()
+	ack.DBHeight = 2
+	ack.Height = 5
+	ack.SerialHash = NewRepeatingHash(0xaa)
+	ack.LeaderChainID = eom.ChainID
+	msgs = append(msgs, sign(ack))
+
+	cc := new(messages.CommitChainMsg)
+	cc.CommitChain = NewCommitChain(set.EBlock)
+	msgs = append(msgs, sign(cc))
+
+	ce := messages.NewCommitEntryMsg()
+	ce.CommitEntry = NewCommitEntry(set.EBlock)
+	msgs = append(msgs, sign(ce))
+
+	reveal := messages.NewRevealEntryMsg()
+	reveal.Timestamp = ts
+	reveal.Entry = set.Entries[0]
+	msgs = append(msgs, reveal)
+
+	for _, tx := range set.FBlock.GetTransactions() {
+		ft := new(messages.FactoidTransaction)
+		ft.Transaction = tx
+		msgs = append(msgs, ft)
+	}
+
+	dbsig := new(messages.DirectoryBlockSignature)
+	dbsig.Timestamp = ts
+	dbsig.DBHeight = uint32(set.Height)
+	dbsig.ServerIdentityChainID = eom.ChainID
+	dbsig.DirectoryBlockHeader = set.DBlock.GetHeader()
+	msgs = append(msgs, sign(dbsig))
+
+	hb := new(messages.Heartbeat)
+	hb.Timestamp = ts
+	hb.SecretNumber = 42
+	hb.DBHeight = uint32(set.Height)
+	hb.DBlockHash = set.DBlock.GetKeyMR()
+	hb.IdentityChainID = eom.ChainID
+	msgs = append(msgs, sign(hb))
+
+	msgs = append(msgs, messages.NewMissingMsg(s, 1, 2, 3))
+	msgs = append(msgs, messages.NewMissingMsgResponse(s, eom, ack))
+	msgs = append(msgs, messages.NewMissingData(ts, set.Entries[0].GetHash()))
+	msgs = append(msgs, messages.NewDataResponse(s, set.Entries[0], 0, set.Entries[0].GetHash()))
+	keyMR, _ := set.EBlock.KeyMR()
+	msgs = append(msgs, messages.NewDataResponse(s, set.EBlock, 1, keyMR))
+
+	rb := new(messages.RequestBlock)
+	rb.Timestamp = ts
+	msgs = append(msgs, rb)
+
+	msgs = append(msgs, messages.NewDBStateMissing(s, 1, 2))
+	eblocks := []interfaces.IEntryBlock{set.EBlock, set.AnchorEBlock}
+	var entries []interfaces.IEBEntry
+	for _, e := range set.Entries {
+		entries = append(entries, e)
+	}
+	msgs = append(msgs, messages.NewDBStateMsg(ts, set.DBlock, set.ABlock, set.FBlock, set.ECBlock, eblocks, entries, nil))
+
+	msgs = append(msgs, sign(messages.NewAddServerByHashMsg(s, 0, eom.ChainID)))
+	msgs = append(msgs, sign(messages.NewChangeServerKeyMsg(s, eom.ChainID, constants.TYPE_ADD_FED_SERVER_KEY, 0, 0, NewRepeatingHash(0xcc))))
+	msgs = append(msgs, sign(messages.NewRemoveServerMsg(s, eom.ChainID, 1)))
+
+	bounce := new(messages.Bounce)
+	bounce.Name = "fuzz"
+	bounce.Number = 7
+	bounce.Timestamp = ts
+	bounce.Stamps = []interfaces.Timestamp{ts}
+	bounce.Data = []byte{1, 2, 3}
+	msgs = append(msgs, bounce)
+
+	reply := new(messages.BounceReply)
+	reply.Name = "fuzz"
+	reply.Number = 7
+	reply.Timestamp = ts
+	reply.Stamps = []interfaces.Timestamp{ts}
+	msgs = append(msgs, reply)
+
+	vol := new(electionMsgs.FedVoteVolunteerMsg)
+	vol.Minute = 3
+	vol.Name = "fuzz"
+	vol.DBHeight = 2
+	vol.ServerID = eom.ChainID
+	vol.Weight = NewRepeatingHash(0xdd)
+	vol.ServerIdx = 1
+	vol.Missing = eom
+	vol.Ack = ack
+	vol.TS = ts
+	vol.FedID = NewRepeatingHash(0xfe)
+	msgs = append(msgs, sign(vol))
+
+	proposal := electionMsgs.NewFedProposalMsg(vol.FedID, *vol)
+	msgs = append(msgs, sign(proposal))
+
+	level := electionMsgs.NewFedVoteLevelMessage(vol.FedID, *vol)
+	level.TS = ts
+	level.Committed = true
+	level.Justification = []interfaces.IMsg{}
+	msgs = append(msgs, sign(level))
+
+	return msgs
+}
+
+var (
+	fuzzOnce     sync.Once
+	fuzzMessages []interfaces.IMsg
+	fuzzState    *state.State
+)
+
+func fuzzSetup() {
+	fuzzOnce.Do(func() {
+		fuzzMessages = CreateTestMessages()
+		fuzzState = CreateEmptyTestState()
+	})
+}
+
+// FuzzMessage fuzzes the decoder of a message type, seeded with the test messages of the type, or
+// of every type if msgType is 0xff.  Whatever a peer sends must be refused with an error rather
+// than a panic, and what is taken must be a message the node can work with.
+func FuzzMessage(f *testing.F, msgType byte) {
+	fuzzSetup()
+	for _, m := range fuzzMessages {
+		if msgType != 0xff && m.Type() != msgType {
+			continue
+		}
+		data, err := m.MarshalBinary()
+		if err != nil {
+			f.Fatalf("can't marshal a %s: %v", constants.MessageName(m.Type()), err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var m interfaces.IMsg
+		if msgType == 0xff {
+			var err error
+			if m, err = msgsupport.UnmarshalMessage(data); err != nil {
+				return
+			}
+		} else {
+			m = msgsupport.CreateMsg(msgType)
+			if _, err := m.UnmarshalBinaryData(data); err != nil {
+				return
+			}
+		}
+		CheckFuzzedMessage(t, fuzzState, m)
+	})
+}
+
+// CheckFuzzedMessage checks a message that unmarshalled from fuzzed data marshals back to data
+// that unmarshals to the same message, and that none of what the node does with a message it
+// has been sent panics
+func CheckFuzzedMessage(t *testing.T, s interfaces.IState, m interfaces.IMsg) {
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return
+	}
+	m2 := msgsupport.CreateMsg(m.Type())
+	if _, err := m2.UnmarshalBinaryData(data); err != nil {
+		t.Fatalf("a %s doesn't unmarshal from what it marshalled to: %v", constants.MessageName(m.Type()), err)
+	}
+	data2, err := m2.MarshalBinary()
+	if err != nil {
+		t.Fatalf("a %s doesn't marshal again: %v", constants.MessageName(m.Type()), err)
+	}
+	if !bytes.Equal(data, data2) {
+		t.Fatalf("a %s marshals to\n%x\nthen to\n%x", constants.MessageName(m.Type()), data, data2)
+	}
+
+	m.GetHash()
+	m.GetMsgHash()
+	m.GetRepeatHash()
+	m.GetTimestamp()
+	_ = m.String()
+	m.Validate(s)
+}
+
+// FuzzBinary fuzzes the decoder of a block or other binary type, seeded with the values given.
+// Unmarshalling what a peer sends must return an error rather than panic, and a value that does
+// unmarshal must marshal back to data that unmarshals to the same value.
+func FuzzBinary(f *testing.F, create func() interfaces.BinaryMarshallable, seeds ...interfaces.BinaryMarshallable) {
+	for _, seed := range seeds {
+		data, err := seed.MarshalBinary()
+		if err != nil {
+			f.Fatalf("can't marshal a %T: %v", seed, err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := create()
+		if _, err := v.UnmarshalBinaryData(data); err != nil {
+			return
+		}
+		data, err := v.MarshalBinary()
+		if err != nil {
+			return
+		}
+		v2 := create()
+		if _, err := v2.UnmarshalBinaryData(data); err != nil {
+			t.Fatalf("a %T doesn't unmarshal from what it marshalled to: %v", v, err)
+		}
+		data2, err := v2.MarshalBinary()
+		if err != nil {
+			t.Fatalf("a %T doesn't marshal again: %v", v, err)
+		}
+		if !bytes.Equal(data, data2) {
+			t.Fatalf("a %T marshals to\n%x\nthen to\n%x", v, data, data2)
+		}
+
+		if p, ok := v.(interfaces.Printable); ok {
+			p.JSONString()
+			_ = p.String()
+		}
+	})
+}
@@ -15,6 +15,17 @@ import (
 
 var _ = fmt.Print
 
+// The keys the anchor records of the mainnet anchor chains are signed with
+var (
+	DefaultBitcoinAnchorRecordPublicKeys = []string{
+		"0426a802617848d4d16d87830fc521f4d136bb2d0c352850919c2679f189613a", // m1 key
+		"d569419348ed7056ec2ba54f0ecd9eea02648b260b26e0474f8c07fe9ac6bf83", // m2 key
+	}
+	DefaultEthereumAnchorRecordPublicKeys = []string{
+		"a4a7905ab2226f267c6b44e1d5db2c97638b7bbba72fd1823d053ccff2892455",
+	}
+)
+
 type FactomdConfig struct {
 	App struct {
 		PortNumber                             int
@@ -29,6 +40,10 @@ type FactomdConfig struct {
 		DirectoryBlockInSeconds                int
 		ExportData                             bool
 		ExportDataSubpath                      string
+		ExportFormats                          string
+		ExportParquetRange                     uint32
+		ExportSQLDriver                        string
+		ExportSQLSource                        string
 		FastBoot                               bool
 		FastBootLocation                       string
 		NodeMode                               string
@@ -61,6 +76,7 @@ type FactomdConfig struct {
 		CustomSpecialPeers      string
 		CustomBootstrapIdentity string
 		CustomBootstrapKey      string
+		CustomGenesis           string // Genesis blocks written by factomd genesis, instead of the default custom genesis
 		P2PIncoming             int
 		P2POutgoing             int
 		FactomdTlsEnabled       bool
@@ -89,6 +105,9 @@ type FactomdConfig struct {
 		LogPath         string
 		LogLevel        string
 		ConsoleLogLevel string
+		Journaling      bool // Journal every message entering the node
+		JournalMaxSize  int  // MB, the journal is rotated past it
+		JournalMaxFiles int  // Rotated journals kept
 	}
 	Wallet struct {
 		Address          string
@@ -142,6 +161,13 @@ DataStorePath                         = "data/export"
 DirectoryBlockInSeconds               = 6
 ExportData                            = false
 ExportDataSubpath                     = "database/export/"
+; --------------- ExportFormats: comma separated list of files | ndjson | parquet | sql
+ExportFormats                         = "files"
+; Number of heights in each Parquet file
+ExportParquetRange                    = 1000
+; The sql format writes to a postgres or sqlite3 database, ExportSQLSource is its data source
+ExportSQLDriver                       = ""
+ExportSQLSource                       = ""
 FastBoot                              = true
 FastBootLocation                      = ""
 ; --------------- Network: MAIN | TEST | LOCAL
@@ -161,6 +187,8 @@ CustomSeedURL        = ""
 CustomSpecialPeers   = ""
 CustomBootstrapIdentity     = 38bab1455b7bd7e5efd15c53c777c79d0c988e9210f1da49a99d95b3a6417be9
 CustomBootstrapKey          = cc1985cdfae4e32b5a454dfda8ce5e1361558482684f3367649c3ad852c8e31a
+; Genesis blocks written by factomd genesis from a genesis spec, empty for the default custom genesis
+CustomGenesis               = ""
 ; The maximum number of other peers dialing into this node that will be accepted
 P2PIncoming	= 200
 ; The maximum number of peers this node will attempt to dial into
@@ -211,6 +239,11 @@ ChangeAcksHeight                      = 0
 logLevel                              = error
 LogPath                               = "database/Log"
 ConsoleLogLevel                       = standard
+; Journaling records every message entering the node in LogPath/journal0.journal, for factomd replay.
+; The journal is rotated when it grows past JournalMaxSize MB, keeping JournalMaxFiles old journals
+Journaling                            = false
+JournalMaxSize                        = 100
+JournalMaxFiles                       = 5
 
 ; ------------------------------------------------------------------------------
 ; Configurations for factom-walletd
@@ -315,6 +348,10 @@ func (s *FactomdConfig) String() string {
 	if err12 != nil {
 		return ""
 	}
+	_, err72 := out.WriteString(fmt.Sprintf("\n    ExportFormats           %v", s.App.ExportFormats))
+	if err72 != nil {
+		return ""
+	}
 	_, err11 := out.WriteString(fmt.Sprintf("\n    Network                 %v", s.App.Network))
 	if err11 != nil {
 		return ""
@@ -379,6 +416,10 @@ func (s *FactomdConfig) String() string {
 	if err25 != nil {
 		return ""
 	}
+	_, err73 := out.WriteString(fmt.Sprintf("\n    CustomGenesis           %v", s.App.CustomGenesis))
+	if err73 != nil {
+		return ""
+	}
 	_, err31 := out.WriteString(fmt.Sprintf("\n    P2PIncoming             %v", s.App.P2PIncoming))
 	if err31 != nil {
 		return ""
@@ -464,6 +505,18 @@ func (s *FactomdConfig) String() string {
 	if err51 != nil {
 		return ""
 	}
+	_, err74 := out.WriteString(fmt.Sprintf("\n    Journaling              %v", s.Log.Journaling))
+	if err74 != nil {
+		return ""
+	}
+	_, err75 := out.WriteString(fmt.Sprintf("\n    JournalMaxSize          %v", s.Log.JournalMaxSize))
+	if err75 != nil {
+		return ""
+	}
+	_, err76 := out.WriteString(fmt.Sprintf("\n    JournalMaxFiles         %v", s.Log.JournalMaxFiles))
+	if err76 != nil {
+		return ""
+	}
 
 	_, err52 := out.WriteString(fmt.Sprintf("\n  Walletd"))
 	if err52 != nil {
@@ -650,15 +703,10 @@ func ReadConfig(filename string) *FactomdConfig {
 	}
 
 	if len(cfg.App.BitcoinAnchorRecordPublicKeys) == 0 {
-		cfg.App.BitcoinAnchorRecordPublicKeys = []string{
-			"0426a802617848d4d16d87830fc521f4d136bb2d0c352850919c2679f189613a", // m1 key
-			"d569419348ed7056ec2ba54f0ecd9eea02648b260b26e0474f8c07fe9ac6bf83", // m2 key
-		}
+		cfg.App.BitcoinAnchorRecordPublicKeys = append([]string{}, DefaultBitcoinAnchorRecordPublicKeys...)
 	}
 	if len(cfg.App.EthereumAnchorRecordPublicKeys) == 0 {
-		cfg.App.EthereumAnchorRecordPublicKeys = []string{
-			"a4a7905ab2226f267c6b44e1d5db2c97638b7bbba72fd1823d053ccff2892455",
-		}
+		cfg.App.EthereumAnchorRecordPublicKeys = append([]string{}, DefaultEthereumAnchorRecordPublicKeys...)
 	}
 
 	return cfg
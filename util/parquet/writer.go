@@ -0,0 +1,321 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package parquet writes flat tables as Apache Parquet files.
+//
+// Only what the block exporter needs is supported: every column is REQUIRED, values are
+// PLAIN encoded and uncompressed, and a file holds a single row group with one data page
+// per column.  That is enough for any Parquet reader (Spark, pandas, DuckDB, ...) to load
+// the files.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// ColumnType is the type of the values in a column
+type ColumnType int
+
+const (
+	Int64     ColumnType = iota // int64 (or any of the Go integer types), stored as INT64
+	ByteArray                   // []byte, stored as BYTE_ARRAY
+	String                      // string, stored as BYTE_ARRAY annotated as UTF8
+)
+
+// Column describes a column of a table
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Parquet thrift enum values
+const (
+	typeInt64     = 2
+	typeByteArray = 6
+
+	convertedUTF8 = 0
+
+	repetitionRequired = 0
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecUncompressed = 0
+
+	pageTypeData = 0
+)
+
+var magic = []byte("PAR1")
+
+// Writer collects the rows of a table in memory, and writes them as a Parquet file
+type Writer struct {
+	columns []Column
+	values  []bytes.Buffer // PLAIN encoded values, one buffer per column
+	rows    int
+}
+
+// NewWriter returns a Writer for a table with the given columns
+func NewWriter(columns []Column) *Writer {
+	w := new(Writer)
+	w.columns = columns
+	w.values = make([]bytes.Buffer, len(columns))
+	return w
+}
+
+// Rows is the number of rows appended so far
+func (w *Writer) Rows() int {
+	return w.rows
+}
+
+// Append adds a row to the table.  There must be a value for every column, of the column's type.
+func (w *Writer) Append(row ...interface{}) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, table has %d columns", len(row), len(w.columns))
+	}
+
+	// Check everything first so a bad row doesn't leave the columns with different lengths
+	for i, c := range w.columns {
+		if _, err := plain(c, row[i]); err != nil {
+			return err
+		}
+	}
+	for i, c := range w.columns {
+		data, _ := plain(c, row[i])
+		w.values[i].Write(data)
+	}
+	w.rows++
+	return nil
+}
+
+// plain returns the PLAIN encoding of a single value
+func plain(c Column, v interface{}) ([]byte, error) {
+	switch c.Type {
+	case Int64:
+		var n int64
+		switch x := v.(type) {
+		case int:
+			n = int64(x)
+		case int32:
+			n = int64(x)
+		case int64:
+			n = x
+		case uint8:
+			n = int64(x)
+		case uint32:
+			n = int64(x)
+		case uint64:
+			n = int64(x)
+		default:
+			return nil, fmt.Errorf("parquet: column %s expects an integer, got %T", c.Name, v)
+		}
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, uint64(n))
+		return data, nil
+	case ByteArray, String:
+		var b []byte
+		switch x := v.(type) {
+		case []byte:
+			b = x
+		case string:
+			b = []byte(x)
+		default:
+			return nil, fmt.Errorf("parquet: column %s expects a string or []byte, got %T", c.Name, v)
+		}
+		data := make([]byte, 4+len(b))
+		binary.LittleEndian.PutUint32(data, uint32(len(b)))
+		copy(data[4:], b)
+		return data, nil
+	}
+	return nil, fmt.Errorf("parquet: column %s has unknown type %d", c.Name, c.Type)
+}
+
+func (c Column) physicalType() int32 {
+	if c.Type == Int64 {
+		return typeInt64
+	}
+	return typeByteArray
+}
+
+// WriteTo writes the table as a complete Parquet file
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	var file bytes.Buffer
+	file.Write(magic)
+
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunk, len(w.columns))
+	var total int64
+
+	if w.rows > 0 {
+		for i := range w.columns {
+			data := w.values[i].Bytes()
+
+			ph := new(compactWriter)
+			ph.i32(1, pageTypeData)
+			ph.i32(2, int32(len(data)))
+			ph.i32(3, int32(len(data)))
+			ph.beginStruct(5) // DataPageHeader
+			ph.i32(1, int32(w.rows))
+			ph.i32(2, encodingPlain)
+			ph.i32(3, encodingRLE)
+			ph.i32(4, encodingRLE)
+			ph.endStruct()
+			ph.stop()
+
+			chunks[i].offset = int64(file.Len())
+			file.Write(ph.Bytes())
+			file.Write(data)
+			chunks[i].size = int64(file.Len()) - chunks[i].offset
+			total += chunks[i].size
+		}
+	}
+
+	md := new(compactWriter)
+	md.i32(1, 1) // version
+	md.list(2, ctStruct, len(w.columns)+1)
+	md.beginElem() // the root of the schema
+	md.binary(4, []byte("schema"))
+	md.i32(5, int32(len(w.columns)))
+	md.endStruct()
+	for _, c := range w.columns {
+		md.beginElem()
+		md.i32(1, c.physicalType())
+		md.i32(3, repetitionRequired)
+		md.binary(4, []byte(c.Name))
+		if c.Type == String {
+			md.i32(6, convertedUTF8)
+		}
+		md.endStruct()
+	}
+	md.i64(3, int64(w.rows))
+	if w.rows > 0 {
+		md.list(4, ctStruct, 1)
+		md.beginElem() // RowGroup
+		md.list(1, ctStruct, len(w.columns))
+		for i, c := range w.columns {
+			md.beginElem() // ColumnChunk
+			md.i64(2, chunks[i].offset)
+			md.beginStruct(3) // ColumnMetaData
+			md.i32(1, c.physicalType())
+			md.list(2, ctI32, 2)
+			md.varint(zigzag(encodingPlain))
+			md.varint(zigzag(encodingRLE))
+			md.list(3, ctBinary, 1)
+			md.varint(uint64(len(c.Name)))
+			md.Write([]byte(c.Name))
+			md.i32(4, codecUncompressed)
+			md.i64(5, int64(w.rows))
+			md.i64(6, chunks[i].size)
+			md.i64(7, chunks[i].size)
+			md.i64(9, chunks[i].offset)
+			md.endStruct()
+			md.endStruct()
+		}
+		md.i64(2, total)
+		md.i64(3, int64(w.rows))
+		md.endStruct()
+	} else {
+		md.list(4, ctStruct, 0)
+	}
+	md.binary(6, []byte("factomd"))
+	md.stop()
+
+	file.Write(md.Bytes())
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(md.Len()))
+	file.Write(length)
+	file.Write(magic)
+
+	return file.WriteTo(out)
+}
+
+// Thrift compact protocol types
+const (
+	ctI32    = 5
+	ctI64    = 6
+	ctBinary = 8
+	ctList   = 9
+	ctStruct = 12
+)
+
+// compactWriter is just enough of the thrift compact protocol to write the Parquet metadata
+type compactWriter struct {
+	bytes.Buffer
+	last  int16   // last field id written in the current struct
+	stack []int16 // last field ids of the enclosing structs
+}
+
+func zigzag(n int64) uint64 {
+	return uint64((n << 1) ^ (n >> 63))
+}
+
+func (w *compactWriter) varint(v uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	w.Write(buf[:binary.PutUvarint(buf, v)])
+}
+
+func (w *compactWriter) field(id int16, typ byte) {
+	if delta := id - w.last; delta > 0 && delta <= 15 {
+		w.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.WriteByte(typ)
+		w.varint(zigzag(int64(id)))
+	}
+	w.last = id
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, ctI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, ctI64)
+	w.varint(zigzag(v))
+}
+
+func (w *compactWriter) binary(id int16, b []byte) {
+	w.field(id, ctBinary)
+	w.varint(uint64(len(b)))
+	w.Write(b)
+}
+
+// list writes the header of a list, the caller writes the n elements
+func (w *compactWriter) list(id int16, elem byte, n int) {
+	w.field(id, ctList)
+	if n < 15 {
+		w.WriteByte(byte(n)<<4 | elem)
+	} else {
+		w.WriteByte(0xf0 | elem)
+		w.varint(uint64(n))
+	}
+}
+
+// beginStruct starts a struct field, which must be closed with endStruct
+func (w *compactWriter) beginStruct(id int16) {
+	w.field(id, ctStruct)
+	w.beginElem()
+}
+
+// beginElem starts a struct that is an element of a list, which must be closed with endStruct
+func (w *compactWriter) beginElem() {
+	w.stack = append(w.stack, w.last)
+	w.last = 0
+}
+
+func (w *compactWriter) endStruct() {
+	w.stop()
+	w.last = w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+}
+
+// stop ends the fields of a struct
+func (w *compactWriter) stop() {
+	w.WriteByte(0)
+}
@@ -0,0 +1,171 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/FactomProject/factomd/util/parquet"
+)
+
+// thrift is a minimal thrift compact protocol reader, used to check the metadata we write
+type thrift struct {
+	data []byte
+	pos  int
+}
+
+func (t *thrift) uvarint() uint64 {
+	v, n := binary.Uvarint(t.data[t.pos:])
+	t.pos += n
+	return v
+}
+
+func (t *thrift) varint() int64 {
+	v := t.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// value reads a value of the given type, returning ints, []byte, []interface{} or map[int16]interface{}
+func (t *thrift) value(typ byte) interface{} {
+	switch typ {
+	case 5, 6:
+		return t.varint()
+	case 8:
+		n := int(t.uvarint())
+		t.pos += n
+		return t.data[t.pos-n : t.pos]
+	case 9:
+		h := t.data[t.pos]
+		t.pos++
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(t.uvarint())
+		}
+		list := []interface{}{}
+		for i := 0; i < n; i++ {
+			list = append(list, t.value(h&0x0f))
+		}
+		return list
+	case 12:
+		fields := map[int16]interface{}{}
+		var last int16
+		for {
+			h := t.data[t.pos]
+			t.pos++
+			if h == 0 {
+				return fields
+			}
+			id := last + int16(h>>4)
+			if h>>4 == 0 {
+				id = int16(t.varint())
+			}
+			fields[id] = t.value(h & 0x0f)
+			last = id
+		}
+	}
+	panic("unexpected thrift type")
+}
+
+func TestWriter(t *testing.T) {
+	w := NewWriter([]Column{{"height", Int64}, {"hash", String}, {"content", ByteArray}})
+	if err := w.Append(uint32(1), "aa", []byte{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Append(2, "bb", []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Append("bad", "cc", []byte{}); err == nil {
+		t.Error("a string in an integer column was accepted")
+	}
+	if err := w.Append(3, "cc"); err == nil {
+		t.Error("a short row was accepted")
+	}
+	if w.Rows() != 2 {
+		t.Errorf("expected 2 rows, got %d", w.Rows())
+	}
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	file := buf.Bytes()
+	if !bytes.HasPrefix(file, []byte("PAR1")) || !bytes.HasSuffix(file, []byte("PAR1")) {
+		t.Fatal("missing magic")
+	}
+	length := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := &thrift{data: file[len(file)-8-length : len(file)-8]}
+	md := footer.value(12).(map[int16]interface{})
+	if footer.pos != length {
+		t.Errorf("footer is %d bytes, read %d", length, footer.pos)
+	}
+
+	if md[3].(int64) != 2 {
+		t.Errorf("expected 2 rows in the metadata, got %v", md[3])
+	}
+	schema := md[2].([]interface{})
+	if len(schema) != 4 {
+		t.Fatalf("expected 4 schema elements, got %d", len(schema))
+	}
+	for i, name := range []string{"schema", "height", "hash", "content"} {
+		if got := string(schema[i].(map[int16]interface{})[4].([]byte)); got != name {
+			t.Errorf("schema element %d is %s, expected %s", i, got, name)
+		}
+	}
+
+	// Check the data page of the height column holds the two values
+	rg := md[4].([]interface{})[0].(map[int16]interface{})
+	cc := rg[1].([]interface{})[0].(map[int16]interface{})
+	offset := int(cc[3].(map[int16]interface{})[9].(int64))
+	page := &thrift{data: file, pos: offset}
+	ph := page.value(12).(map[int16]interface{})
+	if ph[2].(int64) != 16 {
+		t.Errorf("expected 16 bytes of heights, got %v", ph[2])
+	}
+	if v := binary.LittleEndian.Uint64(file[page.pos+8:]); v != 2 {
+		t.Errorf("expected second height to be 2, got %d", v)
+	}
+}
+
+func TestWriterEmpty(t *testing.T) {
+	w := NewWriter([]Column{{"height", Int64}})
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	file := buf.Bytes()
+	length := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	md := (&thrift{data: file[len(file)-8-length : len(file)-8]}).value(12).(map[int16]interface{})
+	if md[3].(int64) != 0 || len(md[4].([]interface{})) != 0 {
+		t.Errorf("expected an empty file, got %v", md)
+	}
+}
+
+// TestWriterFixture checks the writer against testdata/table.parquet.  The fixture was read with
+// an independent reader, github.com/xitongsys/parquet-go, which returned the schema and the rows
+// written here, so any change to the bytes has to be checked with a reference reader again.
+func TestWriterFixture(t *testing.T) {
+	w := NewWriter([]Column{{"height", Int64}, {"hash", String}, {"content", ByteArray}})
+	rows := [][]interface{}{
+		{1, "aa", []byte{1, 2}},
+		{2, "bb", []byte{}},
+		{-3, "", []byte("xyz")},
+	}
+	for _, row := range rows {
+		if err := w.Append(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	fixture, err := ioutil.ReadFile("testdata/table.parquet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), fixture) {
+		t.Errorf("the file written differs from testdata/table.parquet")
+	}
+}
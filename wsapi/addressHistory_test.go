@@ -0,0 +1,39 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+)
+
+func TestAddressHistoryLimit(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	s.AddressIndex = state.NewAddressIndex(s)
+	if err := s.AddressIndex.IndexTo(uint32(testHelper.BlockCount - 1)); err != nil {
+		t.Fatal(err)
+	}
+	address := primitives.ConvertFctAddressToUserStr(testHelper.NewFactoidAddress(0))
+
+	for _, c := range []struct{ limit, used uint32 }{
+		{0, AddressHistoryDefaultLimit},
+		{2, 2},
+		{AddressHistoryMaxLimit, AddressHistoryMaxLimit},
+		{AddressHistoryMaxLimit + 1, AddressHistoryMaxLimit},
+		{1 << 31, AddressHistoryMaxLimit},
+	} {
+		resp, jErr := HandleV2AddressHistory(s, AddressHistoryRequest{Address: address, Limit: c.limit})
+		if jErr != nil {
+			t.Fatal(jErr)
+		}
+		r := resp.(*AddressHistoryResponse)
+		if r.Limit != c.used {
+			t.Errorf("limit %d: expected a page of %d, got %d", c.limit, c.used, r.Limit)
+		}
+		if uint32(len(r.Transactions)) > c.used {
+			t.Errorf("limit %d: expected at most %d transactions, got %d", c.limit, c.used, len(r.Transactions))
+		}
+	}
+}
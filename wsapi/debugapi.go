@@ -74,9 +74,18 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	case "authorities":
 		resp, jsonError = HandleAuthorities(state, params)
 		break
+	case "authorities-at-height":
+		resp, jsonError = HandleAuthoritiesAtHeight(state, params)
+		break
+	case "authority-changes":
+		resp, jsonError = HandleAuthorityChanges(state, params)
+		break
 	case "configuration":
 		resp, jsonError = HandleConfig(state, params)
 		break
+	case "election-log":
+		resp, jsonError = HandleElectionLog(state, params)
+		break
 	case "current-minute":
 		resp, jsonError = HandleCurrentMinute(state, params)
 		break
@@ -92,12 +101,36 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	case "set-drop-rate":
 		resp, jsonError = HandleSetDropRate(state, params)
 		break
+	case "load-stats":
+		resp, jsonError = HandleLoadStats(state, params)
+		break
+	case "set-load-profile":
+		resp, jsonError = HandleSetLoadProfile(state, params)
+		break
+	case "sim-topology":
+		resp, jsonError = HandleSimTopology(state, params)
+		break
+	case "set-sim-link":
+		resp, jsonError = HandleSetSimLink(state, params)
+		break
+	case "clear-sim-link":
+		resp, jsonError = HandleClearSimLink(state, params)
+		break
+	case "sim-partition":
+		resp, jsonError = HandleSimPartition(state, params)
+		break
+	case "sim-heal":
+		resp, jsonError = HandleSimHeal(state, params)
+		break
 	case "federated-servers":
 		resp, jsonError = HandleFedServers(state, params)
 		break
 	case "holding-queue":
 		resp, jsonError = HandleHoldingQueue(state, params)
 		break
+	case "integrity-check":
+		resp, jsonError = HandleIntegrityCheck(state, params)
+		break
 	case "messages":
 		resp, jsonError = HandleMessages(state, params)
 		break
@@ -174,6 +207,113 @@ func HandleAuthorities(state interfaces.IState, params interface{}) (interface{}
 	return r, nil
 }
 
+// HandleAuthoritiesAtHeight returns the federated and audit servers that built a block, by
+// default the one being built now
+func HandleAuthoritiesAtHeight(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(AuthoritiesAtHeightRequest)
+	if params != nil {
+		if err := MapToObject(params, req); err != nil {
+			return nil, NewInvalidParamsError()
+		}
+	}
+
+	next := state.GetHighestSavedBlk() + 1
+	height := next
+	if req.Height != nil {
+		if *req.Height < 0 {
+			return nil, NewInvalidParamsError()
+		}
+		if *req.Height > int64(next) {
+			return nil, NewBlockNotFoundError()
+		}
+		height = uint32(*req.Height)
+	}
+
+	set, err := state.GetAuthoritiesAtHeight(height)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	return set, nil
+}
+
+// AuthorityChangesDefaultLimit is the page size of authority-changes when the request has no limit
+const AuthorityChangesDefaultLimit = 50
+
+// HandleAuthorityChanges returns a page of the admin block entries that changed the authority set
+func HandleAuthorityChanges(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(AuthorityChangesRequest)
+	if params != nil {
+		if err := MapToObject(params, req); err != nil {
+			return nil, NewInvalidParamsError()
+		}
+	}
+	if req.EndHeight != 0 && req.EndHeight < req.StartHeight {
+		return nil, NewCustomInvalidParamsError("endheight is below startheight")
+	}
+
+	var chainID interfaces.IHash
+	if req.ChainID != "" {
+		h, err := primitives.HexToHash(req.ChainID)
+		if err != nil {
+			return nil, NewInvalidHashError()
+		}
+		chainID = h
+	}
+	if req.Limit == 0 {
+		req.Limit = AuthorityChangesDefaultLimit
+	}
+
+	changes, err := state.GetAuthorityChanges(chainID, req.StartHeight, req.EndHeight, req.Offset, req.Limit)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	resp := new(AuthorityChangesResponse)
+	resp.Offset = req.Offset
+	resp.AuthorityChanges = changes
+	return resp, nil
+}
+
+// ElectionLogDefaultLimit is the number of elections returned by election-log when the request has no limit
+const ElectionLogDefaultLimit = 50
+
+// HandleElectionLog returns the timelines of the last elections, newest first
+func HandleElectionLog(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(ElectionLogRequest)
+	if params != nil {
+		if err := MapToObject(params, req); err != nil {
+			return nil, NewInvalidParamsError()
+		}
+	}
+	switch req.Outcome {
+	case "", interfaces.ElectionRunning, interfaces.ElectionElected, interfaces.ElectionAbandoned:
+	default:
+		return nil, NewCustomInvalidParamsError("outcome must be running, elected or abandoned")
+	}
+	if req.Limit < 0 {
+		return nil, NewInvalidParamsError()
+	}
+	if req.Limit == 0 {
+		req.Limit = ElectionLogDefaultLimit
+	}
+
+	records, err := state.GetElectionLog()
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	resp := new(ElectionLogResponse)
+	resp.Elections = make([]*interfaces.ElectionRecord, 0)
+	for i := len(records) - 1; i >= 0; i-- {
+		if req.Outcome != "" && records[i].Outcome != req.Outcome {
+			continue
+		}
+		resp.Total++
+		if len(resp.Elections) < req.Limit {
+			resp.Elections = append(resp.Elections, records[i])
+		}
+	}
+	return resp, nil
+}
+
 func HandleConfig(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	return state.GetCfg(), nil
 }
@@ -243,6 +383,123 @@ func HandleSetDropRate(state interfaces.IState, params interface{}) (interface{}
 	return r, nil
 }
 
+func loadGenerator(state interfaces.IState) (interfaces.ILoadGenerator, *primitives.JSONError) {
+	lg := state.GetLoadGenerator()
+	if lg == nil {
+		return nil, NewCustomInternalError("There is no load generator on this node")
+	}
+	return lg, nil
+}
+
+func HandleLoadStats(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	lg, jsonError := loadGenerator(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return lg.Stats(), nil
+}
+
+func HandleSetLoadProfile(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	lg, jsonError := loadGenerator(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	profile := new(interfaces.LoadProfile)
+	err := MapToObject(params, profile)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if err := lg.SetProfile(profile); err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return lg.Stats(), nil
+}
+
+func simTopology(state interfaces.IState) (interfaces.ISimTopology, *primitives.JSONError) {
+	t := state.GetSimTopology()
+	if t == nil {
+		return nil, NewCustomInternalError("This node is not running in a simulation")
+	}
+	return t, nil
+}
+
+func HandleSimTopology(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	t, jsonError := simTopology(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	return t.Topology(), nil
+}
+
+func HandleSetSimLink(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	t, jsonError := simTopology(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	link := new(SetSimLinkRequest)
+	err := MapToObject(params, link)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if err := t.SetLink(link.From, link.To, &link.SimLink); err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return t.Topology(), nil
+}
+
+func HandleClearSimLink(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	t, jsonError := simTopology(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	link := new(ClearSimLinkRequest)
+	err := MapToObject(params, link)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if !t.ClearLink(link.From, link.To) {
+		return nil, NewCustomInvalidParamsError(fmt.Sprintf("There is no link from %d to %d", link.From, link.To))
+	}
+	return t.Topology(), nil
+}
+
+func HandleSimPartition(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	t, jsonError := simTopology(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	partition := new(SimPartitionRequest)
+	err := MapToObject(params, partition)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if err := t.Partition(partition.Name, partition.Groups); err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return t.Topology(), nil
+}
+
+func HandleSimHeal(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	t, jsonError := simTopology(state)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+
+	heal := new(SimHealRequest)
+	err := MapToObject(params, heal)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if !t.Heal(heal.Name) {
+		return nil, NewCustomInvalidParamsError(fmt.Sprintf("There is no partition %q", heal.Name))
+	}
+	return t.Topology(), nil
+}
+
 func HandleFedServers(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	type ret struct {
 		FederatedServers []interfaces.IServer
@@ -265,6 +522,10 @@ func HandleHoldingQueue(state interfaces.IState, params interface{}) (interface{
 	return r, nil
 }
 
+func HandleIntegrityCheck(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	return state.GetIntegrityReport(), nil
+}
+
 func HandleMessages(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	type ret struct {
 		Messages []json.RawMessage
@@ -376,6 +637,26 @@ type SetDropRateRequest struct {
 	DropRate int `json:"droprate"`
 }
 
+type SetSimLinkRequest struct {
+	From int `json:"from"` // -1 for any node
+	To   int `json:"to"`   // -1 for any node
+	interfaces.SimLink
+}
+
+type ClearSimLinkRequest struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+type SimPartitionRequest struct {
+	Name   string  `json:"name"`
+	Groups [][]int `json:"groups"`
+}
+
+type SimHealRequest struct {
+	Name string `json:"name"` // Empty to heal all the partitions
+}
+
 type GetCommands struct {
 	Commands []string `json:"commands"`
 }
@@ -0,0 +1,260 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The problems fee-estimate reports.  Each one would get the transaction, commit or
+// entry rejected if it were submitted as it is.
+const (
+	FeeProblemInvalidTransaction  = "invalid-transaction"
+	FeeProblemMissingSignatures   = "missing-signatures"
+	FeeProblemInsufficientFee     = "insufficient-fee"
+	FeeProblemInsufficientBalance = "insufficient-balance"
+	FeeProblemInvalidEntry        = "invalid-entry"
+	FeeProblemOversizeEntry       = "oversize-entry"
+	FeeProblemBadExtIDs           = "bad-extids"
+	FeeProblemMissingChain        = "missing-chain"
+	FeeProblemChainExists         = "chain-exists"
+	FeeProblemInvalidCommit       = "invalid-commit"
+	FeeProblemCommitMismatch      = "commit-mismatch"
+	FeeProblemUnderpaidCommit     = "underpaid-commit"
+)
+
+// The kinds of thing fee-estimate prices
+const (
+	FeeKindTransaction = "transaction"
+	FeeKindEntry       = "entry"
+	FeeKindChain       = "chain"
+)
+
+// HandleV2FeeEstimate returns the fee of a factoid transaction, a commit and reveal
+// pair or just an entry, and what is wrong with it, without submitting anything.
+// The transaction may be unsigned, either with empty signature blocks or as just
+// the part that gets signed.
+func HandleV2FeeEstimate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(FeeEstimateRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	resp := new(FeeEstimateResponse)
+	resp.Rate = state.GetFactoshisPerEC()
+	resp.PredictedRate = state.GetPredictiveFER()
+
+	switch {
+	case req.Transaction != "" && req.Commit == "" && req.Entry == "":
+		p, err := hex.DecodeString(req.Transaction)
+		if err != nil {
+			return nil, NewUnableToDecodeTransactionError()
+		}
+		tx := new(factoid.Transaction)
+		if err := tx.UnmarshalBinary(p); err != nil {
+			// Not signed yet, so only what gets signed
+			if rest, err := tx.UnmarshalBinarySigData(p); err != nil || len(rest) != 0 {
+				return nil, NewUnableToDecodeTransactionError()
+			}
+		}
+		if jErr := estimateTransactionFee(state, tx, resp); jErr != nil {
+			return nil, jErr
+		}
+	case req.Transaction == "" && req.Entry != "":
+		p, err := hex.DecodeString(req.Entry)
+		if err != nil {
+			return nil, NewInvalidEntryError()
+		}
+		entry := entryBlock.NewEntry()
+		if err := entry.UnmarshalBinary(p); err != nil {
+			return nil, NewInvalidEntryError()
+		}
+		var commit interfaces.IECBlockEntry
+		if req.Commit != "" {
+			if commit, err = decodeCommit(req.Commit); err != nil {
+				return nil, NewCustomInvalidParamsError(err.Error())
+			}
+		}
+		estimateEntryFee(state, entry, commit, resp)
+	default:
+		return nil, NewCustomInvalidParamsError("Expected a transaction, an entry, or a commit and an entry")
+	}
+
+	resp.FCTFee = resp.ECFee * resp.Rate
+	resp.PredictedFCTFee = resp.ECFee * resp.PredictedRate
+	resp.Valid = len(resp.Problems) == 0
+	return resp, nil
+}
+
+func (r *FeeEstimateResponse) addProblem(problem string, format string, args ...interface{}) {
+	r.Problems = append(r.Problems, FeeProblem{Problem: problem, Message: fmt.Sprintf(format, args...)})
+}
+
+// estimateTransactionFee prices a factoid transaction in EC.  Inputs without an RCD
+// yet are priced as the RCD_1 and signature they will most likely get.
+func estimateTransactionFee(state interfaces.IState, tx *factoid.Transaction, resp *FeeEstimateResponse) *primitives.JSONError {
+	resp.Kind = FeeKindTransaction
+
+	if missing := len(tx.Inputs) - len(tx.RCDs); missing > 0 {
+		resp.addProblem(FeeProblemMissingSignatures, "%d of %d inputs have no RCD", missing, len(tx.Inputs))
+		for i := len(tx.RCDs); i < len(tx.Inputs); i++ {
+			tx.AddAuthorization(new(factoid.RCD_1))
+			sig := new(factoid.SignatureBlock)
+			sig.AddSignature(new(factoid.FactoidSignature))
+			tx.SetSignatureBlock(i, sig)
+		}
+	} else if err := tx.Validate(1); err != nil {
+		resp.addProblem(FeeProblemInvalidTransaction, "%v", err)
+	} else if err := tx.ValidateSignatures(); err != nil {
+		resp.addProblem(FeeProblemMissingSignatures, "%v", err)
+	}
+
+	fee, err := tx.CalculateFee(1)
+	if err != nil {
+		return NewCustomInvalidParamsError(err.Error())
+	}
+	resp.ECFee = fee
+
+	tin, err := tx.TotalInputs()
+	if err != nil {
+		return NewCustomInvalidParamsError(err.Error())
+	}
+	tout, err := tx.TotalOutputs()
+	if err != nil {
+		return NewCustomInvalidParamsError(err.Error())
+	}
+	tec, err := tx.TotalECs()
+	if err != nil {
+		return NewCustomInvalidParamsError(err.Error())
+	}
+	if due := tout + tec + fee*state.GetFactoshisPerEC(); tin < due {
+		resp.addProblem(FeeProblemInsufficientFee, "The inputs %s do not cover the outputs, the Entry Credit outputs and the fee, %s in all",
+			primitives.ConvertDecimalToString(tin), primitives.ConvertDecimalToString(due))
+	}
+
+	// Read the balances rather than validate the transaction against the factoid state, which
+	// belongs to the consensus goroutine
+	spent := make(map[[32]byte]uint64)
+	for _, in := range tx.Inputs {
+		address := in.GetAddress().Fixed()
+		spent[address] += in.GetAmount()
+		if balance := state.GetFactoidState().GetFactoidBalance(address); balance < 0 || uint64(balance) < spent[address] {
+			resp.addProblem(FeeProblemInsufficientBalance, "%s does not have the funds for its input",
+				primitives.ConvertFctAddressToUserStr(in.GetAddress()))
+			break
+		}
+	}
+	return nil
+}
+
+// decodeCommit unmarshals a commit chain or a commit entry, told apart by their size
+func decodeCommit(commit string) (interfaces.IECBlockEntry, error) {
+	p, err := hex.DecodeString(commit)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Commit")
+	}
+	switch len(p) {
+	case entryCreditBlock.CommitChainSize:
+		c := entryCreditBlock.NewCommitChain()
+		if err := c.UnmarshalBinary(p); err != nil {
+			return nil, fmt.Errorf("Invalid Commit Chain")
+		}
+		return c, nil
+	case entryCreditBlock.CommitEntrySize:
+		c := entryCreditBlock.NewCommitEntry()
+		if err := c.UnmarshalBinary(p); err != nil {
+			return nil, fmt.Errorf("Invalid Commit Entry")
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("Invalid Commit")
+}
+
+// estimateEntryFee prices an entry in EC.  It is priced as the first entry of a new
+// chain if it comes with a commit chain, or if it has no commit and its chain does
+// not exist yet.
+func estimateEntryFee(state interfaces.IState, entry *entryBlock.Entry, commit interfaces.IECBlockEntry, resp *FeeEstimateResponse) {
+	exists := chainExists(state, entry.GetChainID())
+	commitChain, isChain := commit.(*entryCreditBlock.CommitChain)
+	if commit == nil {
+		isChain = !exists
+	}
+
+	if entry.Version != 0 {
+		resp.addProblem(FeeProblemInvalidEntry, "Entry version %d is not 0", entry.Version)
+	}
+	size := entry.KSize()
+	if size > 10 {
+		resp.addProblem(FeeProblemOversizeEntry, "Entry is %dK, over the 10K limit", size)
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	resp.Kind = FeeKindEntry
+	resp.ECFee = uint64(size)
+	if isChain {
+		resp.Kind = FeeKindChain
+		resp.ECFee += 10
+		if !entry.GetChainID().IsSameAs(entryBlock.NewChainID(entry)) {
+			resp.addProblem(FeeProblemBadExtIDs, "The ExtIDs produce the chain id %s, not %s",
+				entryBlock.NewChainID(entry).String(), entry.GetChainID().String())
+		}
+		if exists && commit != nil {
+			resp.addProblem(FeeProblemChainExists, "Chain %s already exists", entry.GetChainID().String())
+		}
+	} else if !exists {
+		resp.addProblem(FeeProblemMissingChain, "Chain %s does not exist", entry.GetChainID().String())
+	}
+
+	if commit == nil {
+		return
+	}
+
+	var valid bool
+	var credits uint8
+	var ecPubKey *primitives.ByteSlice32
+	if isChain {
+		valid, credits, ecPubKey = commitChain.IsValid(), commitChain.Credits, commitChain.ECPubKey
+		if !commitChain.ChainIDHash.IsSameAs(primitives.Shad(entry.GetChainID().Bytes())) {
+			resp.addProblem(FeeProblemCommitMismatch, "The commit is for another chain")
+		}
+	} else {
+		commitEntry := commit.(*entryCreditBlock.CommitEntry)
+		valid, credits, ecPubKey = commitEntry.IsValid(), commitEntry.Credits, commitEntry.ECPubKey
+	}
+	if !valid {
+		resp.addProblem(FeeProblemInvalidCommit, "The commit has a bad version, credits or signature")
+	}
+	if !commit.GetEntryHash().IsSameAs(entry.GetHash()) {
+		resp.addProblem(FeeProblemCommitMismatch, "The commit is for entry %s, not %s", commit.GetEntryHash().String(), entry.GetHash().String())
+	}
+	if uint64(credits) < resp.ECFee {
+		resp.addProblem(FeeProblemUnderpaidCommit, "The commit pays %d EC of the %d due", credits, resp.ECFee)
+	}
+	if balance := state.GetFactoidState().GetECBalance(ecPubKey.Fixed()); balance < int64(credits) {
+		resp.addProblem(FeeProblemInsufficientBalance, "%s has %d EC, and the commit pays %d",
+			primitives.ConvertECAddressToUserStr(factoid.NewAddress(ecPubKey[:])), balance, credits)
+	}
+}
+
+// chainExists is true if the chain is in the database, or made in a process list
+// not yet saved
+func chainExists(state interfaces.IState, chainID interfaces.IHash) bool {
+	lh := state.GetLeaderHeight()
+	if state.IsNewOrPendingEBlocks(lh, chainID) || state.IsNewOrPendingEBlocks(lh-1, chainID) {
+		return true
+	}
+	head, err := state.GetDB().FetchHeadIndexByChainID(chainID)
+	return err == nil && head != nil
+}
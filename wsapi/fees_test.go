@@ -0,0 +1,165 @@
+package wsapi_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func feeEstimate(t *testing.T, req FeeEstimateRequest) *FeeEstimateResponse {
+	state := testHelper.CreateAndPopulateTestState()
+	resp, jErr := HandleV2FeeEstimate(state, req)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	return resp.(*FeeEstimateResponse)
+}
+
+func problems(resp *FeeEstimateResponse) (rval []string) {
+	for _, p := range resp.Problems {
+		rval = append(rval, p.Problem)
+	}
+	return rval
+}
+
+func TestFeeEstimateTransaction(t *testing.T) {
+	newTx := func() *factoid.Transaction {
+		tx := new(factoid.Transaction)
+		tx.AddInput(testHelper.NewFactoidAddress(0), 1000+12) // 1 EC for the size, 10 for the output, 1 for the signature
+		tx.AddOutput(testHelper.NewFactoidAddress(1), 1000)
+		return tx
+	}
+	encode := func(tx *factoid.Transaction) string {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hex.EncodeToString(data)
+	}
+
+	signed := newTx()
+	testHelper.SignFactoidTransaction(0, signed)
+	resp := feeEstimate(t, FeeEstimateRequest{Transaction: encode(signed)})
+	assert.Equal(t, FeeKindTransaction, resp.Kind)
+	assert.Equal(t, uint64(12), resp.ECFee)
+	assert.Equal(t, resp.ECFee*resp.Rate, resp.FCTFee)
+	assert.Equal(t, resp.ECFee*resp.PredictedRate, resp.PredictedFCTFee)
+	assert.True(t, resp.Valid, "%v", resp.Problems)
+
+	// Unsigned, the fee is the same
+	unsigned, err := newTx().MarshalBinarySig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = feeEstimate(t, FeeEstimateRequest{Transaction: hex.EncodeToString(unsigned)})
+	assert.Equal(t, uint64(12), resp.ECFee)
+	assert.False(t, resp.Valid)
+	assert.Equal(t, []string{FeeProblemMissingSignatures}, problems(resp))
+
+	withRCD := newTx()
+	withRCD.AddAuthorization(testHelper.NewFactoidRCDAddress(0))
+	resp = feeEstimate(t, FeeEstimateRequest{Transaction: encode(withRCD)})
+	assert.Equal(t, uint64(12), resp.ECFee)
+	assert.Equal(t, []string{FeeProblemMissingSignatures}, problems(resp))
+
+	// Unsigned transactions are priced as signed ones, across the 1 KiB steps of the fee
+	for outputs := 1; outputs <= 30; outputs++ {
+		build := func() *factoid.Transaction {
+			tx := new(factoid.Transaction)
+			tx.AddInput(testHelper.NewFactoidAddress(0), 100000)
+			for i := 0; i < outputs; i++ {
+				tx.AddOutput(testHelper.NewFactoidAddress(1), 1)
+			}
+			return tx
+		}
+		full := build()
+		testHelper.SignFactoidTransaction(0, full)
+		expected, err := full.CalculateFee(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unsigned, err := build().MarshalBinarySig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp = feeEstimate(t, FeeEstimateRequest{Transaction: hex.EncodeToString(unsigned)})
+		assert.Equal(t, expected, resp.ECFee, "%d outputs", outputs)
+	}
+
+	// Signed, then changed
+	signed.Inputs[0].SetAmount(1000 + 11)
+	resp = feeEstimate(t, FeeEstimateRequest{Transaction: encode(signed)})
+	assert.Equal(t, []string{FeeProblemMissingSignatures, FeeProblemInsufficientFee}, problems(resp))
+
+	// Spending more than the address has
+	poor := new(factoid.Transaction)
+	poor.AddInput(testHelper.NewFactoidAddress(5), 1000+12)
+	poor.AddOutput(testHelper.NewFactoidAddress(1), 1000)
+	testHelper.SignFactoidTransaction(5, poor)
+	resp = feeEstimate(t, FeeEstimateRequest{Transaction: encode(poor)})
+	assert.Equal(t, []string{FeeProblemInsufficientBalance}, problems(resp))
+}
+
+func TestFeeEstimateEntry(t *testing.T) {
+	entry := entryBlock.NewEntry()
+	entry.ExtIDs = []primitives.ByteSlice{{Bytes: []byte("fee")}, {Bytes: []byte("estimate")}}
+	entry.Content = primitives.ByteSlice{Bytes: make([]byte, 1500)}
+	entry.ChainID = entryBlock.NewChainID(entry)
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No commit, and no chain, so it's the first entry of a new chain
+	resp := feeEstimate(t, FeeEstimateRequest{Entry: hex.EncodeToString(data)})
+	assert.Equal(t, FeeKindChain, resp.Kind)
+	assert.Equal(t, uint64(2+10), resp.ECFee)
+	assert.True(t, resp.Valid, "%v", resp.Problems)
+
+	commit := entryCreditBlock.NewCommitChain()
+	commit.ChainIDHash = primitives.Shad(entry.ChainID.Bytes())
+	commit.EntryHash = entry.GetHash()
+	commit.Credits = 12
+	testHelper.SignCommit(0, commit)
+	bin, err := commit.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = feeEstimate(t, FeeEstimateRequest{Commit: hex.EncodeToString(bin), Entry: hex.EncodeToString(data)})
+	assert.Equal(t, FeeKindChain, resp.Kind)
+	assert.True(t, resp.Valid, "%v", resp.Problems)
+
+	commit.Credits = 11
+	testHelper.SignCommit(0, commit)
+	bin, _ = commit.MarshalBinary()
+	resp = feeEstimate(t, FeeEstimateRequest{Commit: hex.EncodeToString(bin), Entry: hex.EncodeToString(data)})
+	assert.Equal(t, []string{FeeProblemUnderpaidCommit}, problems(resp))
+
+	// The ExtIDs no longer make the chain id, and it is too big
+	entry.ExtIDs = entry.ExtIDs[:1]
+	entry.Content = primitives.ByteSlice{Bytes: make([]byte, 11*1024)}
+	data, _ = entry.MarshalBinary()
+	resp = feeEstimate(t, FeeEstimateRequest{Entry: hex.EncodeToString(data)})
+	assert.Equal(t, []string{FeeProblemOversizeEntry, FeeProblemBadExtIDs}, problems(resp))
+}
+
+func TestFeeEstimateBadParams(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	for _, req := range []FeeEstimateRequest{
+		{},
+		{Transaction: "00", Entry: "00"},
+		{Commit: "00"},
+		{Transaction: "zz"},
+		{Entry: "00", Commit: "0011"},
+	} {
+		_, jErr := HandleV2FeeEstimate(state, req)
+		assert.NotNil(t, jErr, "%v", req)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// How many messages a page of the mempool holds, unless asked for fewer
+const (
+	MempoolDefaultLimit = 100
+	MempoolMaxLimit     = 1000
+)
+
+// The orders the mempool can be listed in, by the age of the messages
+const (
+	MempoolOrderOldest = "oldest"
+	MempoolOrderNewest = "newest"
+)
+
+// HandleV2Mempool lists the commits, reveals and factoid transactions the node has
+// not yet put in a saved block: where each one is, how long it has waited, and
+// what it is held for.  The list can be filtered by message type, status, chain
+// and address, and is returned a page at a time.  Each page ends with a cursor
+// that starts the next.
+func HandleV2Mempool(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(MempoolRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	switch req.Type {
+	case "", "commit-chain", "commit-entry", "reveal-entry", "factoid-transaction":
+	default:
+		return nil, NewCustomInvalidParamsError("Unknown message type " + req.Type)
+	}
+	var chainID interfaces.IHash
+	if req.ChainID != "" {
+		if chainID, err = primitives.HexToHash(req.ChainID); err != nil {
+			return nil, NewInvalidHashError()
+		}
+	}
+	if req.Address != "" && !primitives.ValidateFUserStr(req.Address) && !primitives.ValidateECUserStr(req.Address) {
+		return nil, NewInvalidAddressError()
+	}
+	switch req.Status {
+	case "", constants.MempoolStatusAcked, constants.MempoolStatusHeldForDependency, constants.MempoolStatusHeldForHeight, constants.MempoolStatusHolding:
+	default:
+		return nil, NewCustomInvalidParamsError("Unknown status " + req.Status)
+	}
+	switch req.Order {
+	case "", MempoolOrderOldest, MempoolOrderNewest:
+	default:
+		return nil, NewCustomInvalidParamsError("Order must be oldest or newest")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = MempoolDefaultLimit
+	}
+	if limit > MempoolMaxLimit {
+		limit = MempoolMaxLimit
+	}
+	var after *mempoolKey
+	if req.Cursor != "" {
+		if after, err = parseMempoolCursor(req.Cursor); err != nil {
+			return nil, NewCustomInvalidParamsError(err.Error())
+		}
+	}
+
+	pool := state.GetMempool()
+
+	// A commit entry doesn't name its chain, so it is on the chain of its reveal
+	revealChains := make(map[[32]byte]interfaces.IHash)
+	for _, m := range pool {
+		if reveal, ok := m.Msg.(*messages.RevealEntryMsg); ok {
+			revealChains[reveal.Entry.GetHash().Fixed()] = reveal.Entry.GetChainID()
+		}
+	}
+
+	var matches []interfaces.IMempoolMessage
+	for _, m := range pool {
+		if req.Type != "" && messageTypeName(m.Msg) != req.Type {
+			continue
+		}
+		if req.Status != "" && m.Status != req.Status {
+			continue
+		}
+		if chainID != nil && !onChain(m.Msg, chainID, revealChains) {
+			continue
+		}
+		if req.Address != "" && !hasAddress(m.Msg, req.Address) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+
+	newest := req.Order == MempoolOrderNewest
+	sort.Slice(matches, func(i, j int) bool {
+		return newMempoolKey(matches[i]).before(newMempoolKey(matches[j])) != newest
+	})
+
+	resp := new(MempoolResponse)
+	resp.Total = len(matches)
+	resp.Messages = make([]MempoolMessage, 0)
+	now := state.GetTimestamp().GetTimeMilli()
+	for _, m := range matches {
+		key := newMempoolKey(m)
+		if after != nil && (key == *after || key.before(*after) != newest) {
+			continue
+		}
+		if len(resp.Messages) == limit {
+			last := resp.Messages[limit-1]
+			resp.Cursor = mempoolKey{last.Timestamp, last.Hash}.String()
+			break
+		}
+		resp.Messages = append(resp.Messages, newMempoolMessage(m, now))
+	}
+	return resp, nil
+}
+
+// onChain is true for a reveal or commit of an entry in the chain
+func onChain(msg interfaces.IMsg, chainID interfaces.IHash, revealChains map[[32]byte]interfaces.IHash) bool {
+	switch m := msg.(type) {
+	case *messages.RevealEntryMsg:
+		return m.Entry.GetChainID().IsSameAs(chainID)
+	case *messages.CommitChainMsg:
+		return m.CommitChain.ChainIDHash.IsSameAs(primitives.Shad(chainID.Bytes()))
+	case *messages.CommitEntryMsg:
+		c, ok := revealChains[m.CommitEntry.EntryHash.Fixed()]
+		return ok && c.IsSameAs(chainID)
+	}
+	return false
+}
+
+// hasAddress is true for a transaction with the address as an input or output,
+// and for a commit paid by the address
+func hasAddress(msg interfaces.IMsg, address string) bool {
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		return m.Transaction.HasUserAddress(address)
+	case *messages.CommitChainMsg:
+		return address == primitives.ConvertECAddressToUserStr(factoid.NewAddress(m.CommitChain.ECPubKey[:]))
+	case *messages.CommitEntryMsg:
+		return address == primitives.ConvertECAddressToUserStr(factoid.NewAddress(m.CommitEntry.ECPubKey[:]))
+	}
+	return false
+}
+
+func newMempoolMessage(m interfaces.IMempoolMessage, now int64) MempoolMessage {
+	rval := MempoolMessage{
+		Type:      messageTypeName(m.Msg),
+		Hash:      m.Msg.GetMsgHash().String(),
+		Status:    m.Status,
+		Reason:    m.Reason,
+		DBHeight:  m.DBHeight,
+		Timestamp: m.Msg.GetTimestamp().GetTimeMilli(),
+	}
+	rval.Age = (now - rval.Timestamp) / 1000
+	if m.DependsOn != [32]byte{} {
+		rval.DependsOn = hex.EncodeToString(m.DependsOn[:])
+	}
+
+	switch msg := m.Msg.(type) {
+	case *messages.FactoidTransaction:
+		rval.TxID = msg.Transaction.GetSigHash().String()
+	case *messages.CommitChainMsg:
+		rval.TxID = msg.CommitChain.GetSigHash().String()
+		rval.EntryHash = msg.CommitChain.EntryHash.String()
+	case *messages.CommitEntryMsg:
+		rval.TxID = msg.CommitEntry.GetSigHash().String()
+		rval.EntryHash = msg.CommitEntry.EntryHash.String()
+	case *messages.RevealEntryMsg:
+		rval.EntryHash = msg.Entry.GetHash().String()
+		rval.ChainID = msg.Entry.GetChainID().String()
+	}
+	return rval
+}
+
+// A mempoolKey orders the mempool by age, and the messages of the same age by
+// their hash.  It is what the cursor holds.
+type mempoolKey struct {
+	timestamp int64
+	hash      string
+}
+
+func newMempoolKey(m interfaces.IMempoolMessage) mempoolKey {
+	return mempoolKey{m.Msg.GetTimestamp().GetTimeMilli(), m.Msg.GetMsgHash().String()}
+}
+
+func (k mempoolKey) before(o mempoolKey) bool {
+	if k.timestamp != o.timestamp {
+		return k.timestamp < o.timestamp
+	}
+	return k.hash < o.hash
+}
+
+func (k mempoolKey) String() string {
+	return fmt.Sprintf("%d-%s", k.timestamp, k.hash)
+}
+
+func parseMempoolCursor(cursor string) (*mempoolKey, error) {
+	parts := strings.SplitN(cursor, "-", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid cursor")
+	}
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid cursor")
+	}
+	if _, err := primitives.HexToHash(parts[1]); err != nil {
+		return nil, fmt.Errorf("Invalid cursor")
+	}
+	return &mempoolKey{ts, parts[1]}, nil
+}
@@ -0,0 +1,123 @@
+package wsapi_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// heldMempool holds three reveals of a minute apart and a commit for the first
+func heldMempool() (*state.State, []*messages.RevealEntryMsg, *messages.CommitEntryMsg) {
+	s := testHelper.CreateAndPopulateTestState()
+	var reveals []*messages.RevealEntryMsg
+	for i := 0; i < 3; i++ {
+		reveal := new(messages.RevealEntryMsg)
+		reveal.Entry = testHelper.CreateTestEntry(uint32(200 + i))
+		reveal.Timestamp = primitives.NewTimestampFromMilliseconds(uint64(1e12 + i*60000))
+		s.Add(reveal.Entry.GetHash().Fixed(), reveal)
+		reveals = append(reveals, reveal)
+	}
+
+	commit := entryCreditBlock.NewCommitEntry()
+	commit.EntryHash = reveals[0].Entry.GetHash()
+	commit.Credits = 1
+	testHelper.SignCommit(5, commit)
+	commitMsg := &messages.CommitEntryMsg{CommitEntry: commit}
+	s.Add(commit.ECPubKey.Fixed(), commitMsg)
+
+	s.HoldingLast = 0
+	s.UpdateState()
+	return s, reveals, commitMsg
+}
+
+func mempool(t *testing.T, s *state.State, req MempoolRequest) *MempoolResponse {
+	resp, jErr := HandleV2Mempool(s, req)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	return resp.(*MempoolResponse)
+}
+
+func hashes(resp *MempoolResponse) (rval []string) {
+	for _, m := range resp.Messages {
+		rval = append(rval, m.Hash)
+	}
+	return rval
+}
+
+func TestMempoolFilterAndOrder(t *testing.T) {
+	s, reveals, commit := heldMempool()
+
+	resp := mempool(t, s, MempoolRequest{Type: "reveal-entry", Status: constants.MempoolStatusHeldForDependency})
+	assert.Equal(t, 3, resp.Total)
+	assert.Equal(t, []string{reveals[0].GetMsgHash().String(), reveals[1].GetMsgHash().String(), reveals[2].GetMsgHash().String()}, hashes(resp))
+	assert.Equal(t, constants.MempoolReasonMissingCommit, resp.Messages[0].Reason)
+	assert.Equal(t, reveals[0].Entry.GetHash().String(), resp.Messages[0].EntryHash)
+	assert.Equal(t, reveals[0].Entry.GetHash().String(), resp.Messages[0].DependsOn)
+	assert.Empty(t, resp.Cursor)
+
+	resp = mempool(t, s, MempoolRequest{Type: "reveal-entry", Status: constants.MempoolStatusHeldForDependency, Order: MempoolOrderNewest})
+	assert.Equal(t, []string{reveals[2].GetMsgHash().String(), reveals[1].GetMsgHash().String(), reveals[0].GetMsgHash().String()}, hashes(resp))
+	assert.True(t, resp.Messages[0].Age < resp.Messages[1].Age)
+
+	// The commit entry is on the chain of its reveal
+	resp = mempool(t, s, MempoolRequest{ChainID: reveals[0].Entry.GetChainID().String(), Status: constants.MempoolStatusHeldForDependency})
+	assert.Contains(t, hashes(resp), commit.GetMsgHash().String())
+	assert.Contains(t, hashes(resp), reveals[0].GetMsgHash().String())
+
+	address := primitives.ConvertECAddressToUserStr(testHelper.NewECAddress(5))
+	resp = mempool(t, s, MempoolRequest{Address: address})
+	assert.Equal(t, []string{commit.GetMsgHash().String()}, hashes(resp))
+	assert.Equal(t, constants.MempoolReasonInsufficientEC, resp.Messages[0].Reason)
+	assert.Equal(t, commit.CommitEntry.GetSigHash().String(), resp.Messages[0].TxID)
+}
+
+func TestMempoolPages(t *testing.T) {
+	s, reveals, _ := heldMempool()
+
+	req := MempoolRequest{Type: "reveal-entry", Status: constants.MempoolStatusHeldForDependency, Limit: 2}
+	resp := mempool(t, s, req)
+	assert.Equal(t, 3, resp.Total)
+	assert.Equal(t, []string{reveals[0].GetMsgHash().String(), reveals[1].GetMsgHash().String()}, hashes(resp))
+	assert.NotEmpty(t, resp.Cursor)
+
+	req.Cursor = resp.Cursor
+	resp = mempool(t, s, req)
+	assert.Equal(t, []string{reveals[2].GetMsgHash().String()}, hashes(resp))
+	assert.Empty(t, resp.Cursor)
+
+	req = MempoolRequest{Type: "reveal-entry", Status: constants.MempoolStatusHeldForDependency, Order: MempoolOrderNewest, Limit: 1}
+	var pages []string
+	for {
+		resp = mempool(t, s, req)
+		pages = append(pages, hashes(resp)...)
+		if resp.Cursor == "" {
+			break
+		}
+		req.Cursor = resp.Cursor
+	}
+	assert.Equal(t, []string{reveals[2].GetMsgHash().String(), reveals[1].GetMsgHash().String(), reveals[0].GetMsgHash().String()}, pages)
+}
+
+func TestMempoolBadParams(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	for _, req := range []MempoolRequest{
+		{Type: "ack"},
+		{Status: "lost"},
+		{ChainID: "zz"},
+		{Address: "FA1234"},
+		{Order: "sideways"},
+		{Cursor: "12"},
+		{Cursor: "x-00"},
+	} {
+		_, jErr := HandleV2Mempool(s, req)
+		assert.NotNil(t, jErr, "%v", req)
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// What the node would do with a simulated message
+const (
+	SimulateResultAccepted = "accepted"
+	SimulateResultHeld     = "held"
+	SimulateResultRejected = "rejected"
+)
+
+// Why a simulated message was held or rejected
+const (
+	SimulateReasonMalformedTransaction = "malformed-transaction"
+	SimulateReasonBadSignatures        = "bad-signatures"
+	SimulateReasonInvalidTransaction   = "invalid-transaction"
+	SimulateReasonInsufficientFunds    = "insufficient-funds"
+	SimulateReasonInvalidCommit        = "invalid-commit"
+	SimulateReasonRepeatCommit         = "repeat-commit"
+	SimulateReasonInsufficientCredits  = "insufficient-credits"
+	SimulateReasonInvalidEntry         = "invalid-entry"
+	SimulateReasonMissingCommit        = "missing-commit"
+	SimulateReasonNotACommit           = "not-a-commit"
+	SimulateReasonOversizeEntry        = "oversize-entry"
+	SimulateReasonUnderpaidCommit      = "underpaid-commit"
+	SimulateReasonMissingChain         = "missing-chain"
+	SimulateReasonBadExtIDs            = "bad-extids"
+)
+
+// simulateReasons maps the reason the Validate() of each message holds or drops
+// it for to the reason simulate reports
+var simulateReasons = map[messages.ValidateReason]string{
+	messages.ReasonMalformedTransaction: SimulateReasonMalformedTransaction,
+	messages.ReasonBadSignatures:        SimulateReasonBadSignatures,
+	messages.ReasonInvalidTransaction:   SimulateReasonInvalidTransaction,
+	messages.ReasonShortFunds:           SimulateReasonInsufficientFunds,
+	messages.ReasonBadCommitSignature:   SimulateReasonInvalidCommit,
+	messages.ReasonShortCredits:         SimulateReasonInsufficientCredits,
+	messages.ReasonNoCommit:             SimulateReasonMissingCommit,
+	messages.ReasonNotACommit:           SimulateReasonNotACommit,
+	messages.ReasonOversizedEntry:       SimulateReasonOversizeEntry,
+	messages.ReasonUnderpaid:            SimulateReasonUnderpaidCommit,
+	messages.ReasonNoChain:              SimulateReasonMissingChain,
+	messages.ReasonBadChainID:           SimulateReasonBadExtIDs,
+}
+
+// reasonValidator is a message that says why Validate() holds or drops it
+type reasonValidator interface {
+	ValidateWithReason(state interfaces.IState) (int, messages.ValidateReason, error)
+}
+
+// HandleV2Simulate runs a factoid transaction, a commit, a reveal or a commit and
+// its reveal through the same Validate() the node runs on them, against a copy of
+// the balances.  Nothing is queued, held or broadcast.  Accepted messages are
+// applied to the copy, so a reveal sees its commit, and the balances they change
+// are returned.
+func HandleV2Simulate(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(SimulateRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	sim := newSimState(state)
+	resp := new(SimulateResponse)
+
+	switch {
+	case req.Transaction != "" && req.Commit == "" && req.Entry == "":
+		p, err := hex.DecodeString(req.Transaction)
+		if err != nil {
+			return nil, NewUnableToDecodeTransactionError()
+		}
+		msg := new(messages.FactoidTransaction)
+		if _, err := msg.UnmarshalTransData(p); err != nil {
+			return nil, NewUnableToDecodeTransactionError()
+		}
+		resp.Messages = append(resp.Messages, sim.run(msg))
+	case req.Transaction == "" && (req.Commit != "" || req.Entry != ""):
+		if req.Commit != "" {
+			commit, err := decodeCommit(req.Commit)
+			if err != nil {
+				return nil, NewCustomInvalidParamsError(err.Error())
+			}
+			var msg interfaces.IMsg
+			switch c := commit.(type) {
+			case *entryCreditBlock.CommitChain:
+				msg = &messages.CommitChainMsg{CommitChain: c}
+			case *entryCreditBlock.CommitEntry:
+				msg = &messages.CommitEntryMsg{CommitEntry: c}
+			}
+			if !state.IsHighestCommit(commit.GetEntryHash(), msg) {
+				resp.Messages = append(resp.Messages, SimulatedMessage{
+					Type:    messageTypeName(msg),
+					Hash:    commit.GetSigHash().String(),
+					Result:  SimulateResultRejected,
+					Reason:  SimulateReasonRepeatCommit,
+					Message: "A commit with equal or greater payment already exists",
+				})
+			} else {
+				resp.Messages = append(resp.Messages, sim.run(msg))
+			}
+		}
+		if req.Entry != "" {
+			p, err := hex.DecodeString(req.Entry)
+			if err != nil {
+				return nil, NewInvalidEntryError()
+			}
+			entry := entryBlock.NewEntry()
+			if err := entry.UnmarshalBinary(p); err != nil {
+				return nil, NewInvalidEntryError()
+			}
+			msg := new(messages.RevealEntryMsg)
+			msg.Entry = entry
+			msg.Timestamp = state.GetTimestamp()
+			if !entry.IsValid() {
+				resp.Messages = append(resp.Messages, SimulatedMessage{
+					Type:    messageTypeName(msg),
+					Hash:    entry.GetHash().String(),
+					Result:  SimulateResultRejected,
+					Reason:  SimulateReasonInvalidEntry,
+					Message: fmt.Sprintf("Entry version %d is not 0", entry.Version),
+				})
+			} else {
+				resp.Messages = append(resp.Messages, sim.run(msg))
+			}
+		}
+	default:
+		return nil, NewCustomInvalidParamsError("Expected a transaction, a commit, an entry, or a commit and an entry")
+	}
+
+	if sim.err != nil {
+		return nil, NewCustomInternalError(sim.err.Error())
+	}
+
+	resp.Accepted = true
+	for _, m := range resp.Messages {
+		resp.Accepted = resp.Accepted && m.Result == SimulateResultAccepted
+	}
+	resp.Balances = sim.fs.impacts()
+	return resp, nil
+}
+
+// messageTypeName names a message type the way the api methods that take it do
+func messageTypeName(msg interfaces.IMsg) string {
+	switch msg.(type) {
+	case *messages.FactoidTransaction:
+		return "factoid-transaction"
+	case *messages.CommitChainMsg:
+		return "commit-chain"
+	case *messages.CommitEntryMsg:
+		return "commit-entry"
+	case *messages.RevealEntryMsg:
+		return "reveal-entry"
+	}
+	return "unknown"
+}
+
+// simState is the node's state as the Validate() of a message sees it, with
+// everything Validate() would change caught instead.  Holding holds on nothing,
+// and commits are found among those already simulated before those the node has.
+type simState struct {
+	interfaces.IState
+	fs      *simFactoidState
+	commits map[[32]byte]interfaces.IMsg
+	err     error
+}
+
+func newSimState(state interfaces.IState) *simState {
+	s := new(simState)
+	s.IState = state
+	s.fs = newSimFactoidState(state)
+	s.commits = make(map[[32]byte]interfaces.IMsg)
+	return s
+}
+
+func (s *simState) GetFactoidState() interfaces.IFactoidState {
+	return s.fs
+}
+
+func (s *simState) Add(h [32]byte, msg interfaces.IMsg) int {
+	return -2
+}
+
+func (s *simState) NextCommit(hash interfaces.IHash) interfaces.IMsg {
+	if c, ok := s.commits[hash.Fixed()]; ok {
+		return c
+	}
+	return s.IState.NextCommit(hash)
+}
+
+// run validates one message, and if it would be accepted applies it to the
+// copy of the balances
+func (s *simState) run(msg interfaces.IMsg) (rval SimulatedMessage) {
+	rval.Type = messageTypeName(msg)
+
+	v, reason, err := msg.(reasonValidator).ValidateWithReason(s)
+	switch {
+	case v == 1 && reason == messages.ReasonNone:
+		rval.Result = SimulateResultAccepted
+	case v < 0 && v != -2:
+		rval.Result = SimulateResultRejected
+	default:
+		rval.Result = SimulateResultHeld
+	}
+	if rval.Result != SimulateResultAccepted {
+		rval.Reason = simulateReasons[reason]
+		if err != nil {
+			rval.Message = err.Error()
+		}
+	}
+
+	err = nil
+	switch m := msg.(type) {
+	case *messages.FactoidTransaction:
+		rval.Hash = m.Transaction.GetSigHash().String()
+		if rval.Result == SimulateResultAccepted {
+			err = s.fs.UpdateTransaction(true, m.Transaction)
+		}
+	case *messages.CommitChainMsg:
+		rval.Hash = m.CommitChain.GetSigHash().String()
+		if rval.Result == SimulateResultAccepted {
+			err = s.fs.UpdateECTransaction(true, m.CommitChain)
+			s.commits[m.CommitChain.EntryHash.Fixed()] = m
+		}
+	case *messages.CommitEntryMsg:
+		rval.Hash = m.CommitEntry.GetSigHash().String()
+		if rval.Result == SimulateResultAccepted {
+			err = s.fs.UpdateECTransaction(true, m.CommitEntry)
+			s.commits[m.CommitEntry.EntryHash.Fixed()] = m
+		}
+	case *messages.RevealEntryMsg:
+		rval.Hash = m.Entry.GetHash().String()
+	}
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	return rval
+}
+
+// simFactoidState is a copy of the factoid state.  Balances are read from the node
+// until a simulated message changes them, and from the copy after that.  Nothing
+// that would change the node's own balances or blocks reaches it.  Validate() is
+// the node's own; a simulation holds at most one transaction, so it is validated
+// before the copy differs from the node.
+type simFactoidState struct {
+	interfaces.IFactoidState
+	rate     int64
+	balances map[simAddress]*simBalance
+	order    []*simBalance
+}
+
+type simAddress struct {
+	ec      bool
+	address [32]byte
+}
+
+type simBalance struct {
+	simAddress
+	before int64
+	after  int64
+}
+
+func newSimFactoidState(state interfaces.IState) *simFactoidState {
+	fs := new(simFactoidState)
+	fs.IFactoidState = state.GetFactoidState()
+	fs.rate = int64(state.GetFactoshisPerEC())
+	fs.balances = make(map[simAddress]*simBalance)
+	return fs
+}
+
+func (fs *simFactoidState) get(ec bool, address [32]byte) int64 {
+	if b, ok := fs.balances[simAddress{ec, address}]; ok {
+		return b.after
+	}
+	if ec {
+		return fs.IFactoidState.GetECBalance(address)
+	}
+	return fs.IFactoidState.GetFactoidBalance(address)
+}
+
+func (fs *simFactoidState) put(ec bool, address [32]byte, v int64) {
+	b, ok := fs.balances[simAddress{ec, address}]
+	if !ok {
+		b = &simBalance{simAddress: simAddress{ec, address}, before: fs.get(ec, address)}
+		fs.balances[b.simAddress] = b
+		fs.order = append(fs.order, b)
+	}
+	b.after = v
+}
+
+func (fs *simFactoidState) GetFactoidBalance(address [32]byte) int64 {
+	return fs.get(false, address)
+}
+
+func (fs *simFactoidState) GetECBalance(address [32]byte) int64 {
+	return fs.get(true, address)
+}
+
+// UpdateTransaction moves the factoids of a transaction the way the node does,
+// in the copy
+func (fs *simFactoidState) UpdateTransaction(rt bool, trans interfaces.ITransaction) error {
+	for _, input := range trans.GetInputs() {
+		if v := fs.get(false, input.GetAddress().Fixed()) - int64(input.GetAmount()); v < 0 {
+			return fmt.Errorf("Not enough factoids (%d) to cover a transaction (%d)", v+int64(input.GetAmount()), input.GetAmount())
+		}
+	}
+	for _, input := range trans.GetInputs() {
+		adr := input.GetAddress().Fixed()
+		fs.put(false, adr, fs.get(false, adr)-int64(input.GetAmount()))
+	}
+	for _, output := range trans.GetOutputs() {
+		adr := output.GetAddress().Fixed()
+		fs.put(false, adr, fs.get(false, adr)+int64(output.GetAmount()))
+	}
+	for _, ecOut := range trans.GetECOutputs() {
+		adr := ecOut.GetAddress().Fixed()
+		fs.put(true, adr, fs.get(true, adr)+int64(ecOut.GetAmount())/fs.rate)
+	}
+	return nil
+}
+
+// UpdateECTransaction deducts the credits of a commit the way the node does, in
+// the copy
+func (fs *simFactoidState) UpdateECTransaction(rt bool, trans interfaces.IECBlockEntry) error {
+	var adr [32]byte
+	var credits int64
+	switch trans.ECID() {
+	case constants.ECIDChainCommit:
+		t := trans.(*entryCreditBlock.CommitChain)
+		adr, credits = t.ECPubKey.Fixed(), int64(t.Credits)
+	case constants.ECIDEntryCommit:
+		t := trans.(*entryCreditBlock.CommitEntry)
+		adr, credits = t.ECPubKey.Fixed(), int64(t.Credits)
+	default:
+		return nil
+	}
+	v := fs.get(true, adr) - credits
+	if v < 0 {
+		return fmt.Errorf("Not enough ECs (%d) to cover a commit (%d)", v+credits, credits)
+	}
+	fs.put(true, adr, v)
+	return nil
+}
+
+func (fs *simFactoidState) AddTransaction(int, interfaces.ITransaction) error {
+	return fmt.Errorf("Cannot add a transaction to a simulation")
+}
+
+func (fs *simFactoidState) AddTransactionBlock(interfaces.IFBlock) error {
+	return fmt.Errorf("Cannot add a block to a simulation")
+}
+
+func (fs *simFactoidState) AddECBlock(interfaces.IEntryCreditBlock) error {
+	return fmt.Errorf("Cannot add a block to a simulation")
+}
+
+func (fs *simFactoidState) ProcessEndOfBlock(interfaces.IState) {}
+
+func (fs *simFactoidState) EndOfPeriod(int) {}
+
+// impacts lists the balances the simulation changed, in the order it changed them
+func (fs *simFactoidState) impacts() []BalanceImpact {
+	rval := make([]BalanceImpact, 0, len(fs.order))
+	for _, b := range fs.order {
+		impact := BalanceImpact{Before: b.before, After: b.after}
+		if b.ec {
+			impact.Address = primitives.ConvertECAddressToUserStr(factoid.NewAddress(b.address[:]))
+		} else {
+			impact.Address = primitives.ConvertFctAddressToUserStr(factoid.NewAddress(b.address[:]))
+		}
+		rval = append(rval, impact)
+	}
+	return rval
+}
@@ -0,0 +1,151 @@
+package wsapi_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func simulate(t *testing.T, req SimulateRequest) *SimulateResponse {
+	state := testHelper.CreateAndPopulateTestState()
+	resp, jErr := HandleV2Simulate(state, req)
+	if jErr != nil {
+		t.Fatal(jErr)
+	}
+	return resp.(*SimulateResponse)
+}
+
+func TestSimulateTransaction(t *testing.T) {
+	encode := func(tx *factoid.Transaction) string {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hex.EncodeToString(data)
+	}
+
+	tx := new(factoid.Transaction)
+	tx.AddInput(testHelper.NewFactoidAddress(0), 1000+12)
+	tx.AddOutput(testHelper.NewFactoidAddress(1), 1000)
+	testHelper.SignFactoidTransaction(0, tx)
+
+	state := testHelper.CreateAndPopulateTestState()
+	from := state.GetFactoidState().GetFactoidBalance(testHelper.NewFactoidAddress(0).Fixed())
+	to := state.GetFactoidState().GetFactoidBalance(testHelper.NewFactoidAddress(1).Fixed())
+
+	resp := simulate(t, SimulateRequest{Transaction: encode(tx)})
+	assert.True(t, resp.Accepted, "%v", resp.Messages)
+	assert.Equal(t, []SimulatedMessage{{Type: "factoid-transaction", Hash: tx.GetSigHash().String(), Result: SimulateResultAccepted}}, resp.Messages)
+	assert.Equal(t, []BalanceImpact{
+		{Address: primitives.ConvertFctAddressToUserStr(testHelper.NewFactoidAddress(0)), Before: from, After: from - 1012},
+		{Address: primitives.ConvertFctAddressToUserStr(testHelper.NewFactoidAddress(1)), Before: to, After: to + 1000},
+	}, resp.Balances)
+
+	// Nothing reached the node
+	assert.Equal(t, from, state.GetFactoidState().GetFactoidBalance(testHelper.NewFactoidAddress(0).Fixed()))
+
+	tx.Inputs[0].SetAmount(1000 + 13)
+	resp = simulate(t, SimulateRequest{Transaction: encode(tx)})
+	assert.False(t, resp.Accepted)
+	assert.Equal(t, SimulateResultRejected, resp.Messages[0].Result)
+	assert.Equal(t, SimulateReasonBadSignatures, resp.Messages[0].Reason)
+	assert.Empty(t, resp.Balances)
+
+	poor := new(factoid.Transaction)
+	poor.AddInput(testHelper.NewFactoidAddress(5), 1000+12)
+	poor.AddOutput(testHelper.NewFactoidAddress(1), 1000)
+	testHelper.SignFactoidTransaction(5, poor)
+	resp = simulate(t, SimulateRequest{Transaction: encode(poor)})
+	assert.Equal(t, SimulateResultHeld, resp.Messages[0].Result)
+	assert.Equal(t, SimulateReasonInsufficientFunds, resp.Messages[0].Reason)
+	assert.NotEmpty(t, resp.Messages[0].Message)
+	assert.Empty(t, resp.Balances)
+}
+
+func TestSimulateEntry(t *testing.T) {
+	entry := entryBlock.NewEntry()
+	entry.ExtIDs = []primitives.ByteSlice{{Bytes: []byte("simulate")}}
+	entry.Content = primitives.ByteSlice{Bytes: make([]byte, 1500)}
+	entry.ChainID = entryBlock.NewChainID(entry)
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := entryCreditBlock.NewCommitChain()
+	commit.ChainIDHash = primitives.Shad(entry.ChainID.Bytes())
+	commit.EntryHash = entry.GetHash()
+	commit.Credits = 12
+	testHelper.SignCommit(0, commit)
+	bin, err := commit.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := testHelper.CreateAndPopulateTestState()
+	balance := state.GetFactoidState().GetECBalance(commit.ECPubKey.Fixed())
+
+	resp := simulate(t, SimulateRequest{Commit: hex.EncodeToString(bin), Entry: hex.EncodeToString(data)})
+	assert.True(t, resp.Accepted, "%v", resp.Messages)
+	assert.Equal(t, []SimulatedMessage{
+		{Type: "commit-chain", Hash: commit.GetSigHash().String(), Result: SimulateResultAccepted},
+		{Type: "reveal-entry", Hash: entry.GetHash().String(), Result: SimulateResultAccepted},
+	}, resp.Messages)
+	assert.Equal(t, []BalanceImpact{
+		{Address: primitives.ConvertECAddressToUserStr(factoid.NewAddress(commit.ECPubKey[:])), Before: balance, After: balance - 12},
+	}, resp.Balances)
+
+	// The reveal alone has no commit to go with it
+	resp = simulate(t, SimulateRequest{Entry: hex.EncodeToString(data)})
+	assert.Equal(t, SimulateResultHeld, resp.Messages[0].Result)
+	assert.Equal(t, SimulateReasonMissingCommit, resp.Messages[0].Reason)
+
+	commit.Credits = 11
+	testHelper.SignCommit(0, commit)
+	bin, _ = commit.MarshalBinary()
+	resp = simulate(t, SimulateRequest{Commit: hex.EncodeToString(bin), Entry: hex.EncodeToString(data)})
+	assert.Equal(t, SimulateResultAccepted, resp.Messages[0].Result)
+	assert.Equal(t, SimulateResultHeld, resp.Messages[1].Result)
+	assert.Equal(t, SimulateReasonUnderpaidCommit, resp.Messages[1].Reason)
+
+	// Paid from an address with no credits
+	commit.Credits = 12
+	testHelper.SignCommit(5, commit)
+	bin, _ = commit.MarshalBinary()
+	resp = simulate(t, SimulateRequest{Commit: hex.EncodeToString(bin)})
+	assert.Equal(t, SimulateResultHeld, resp.Messages[0].Result)
+	assert.Equal(t, SimulateReasonInsufficientCredits, resp.Messages[0].Reason)
+
+	// The ExtIDs no longer make the chain id
+	other := entryBlock.NewEntry()
+	other.ExtIDs = append(entry.ExtIDs, primitives.ByteSlice{Bytes: []byte("more")})
+	other.ChainID = entry.ChainID
+	data, _ = other.MarshalBinary()
+	commit.EntryHash = other.GetHash()
+	testHelper.SignCommit(0, commit)
+	bin, _ = commit.MarshalBinary()
+	resp = simulate(t, SimulateRequest{Commit: hex.EncodeToString(bin), Entry: hex.EncodeToString(data)})
+	assert.Equal(t, SimulateResultRejected, resp.Messages[1].Result)
+	assert.Equal(t, SimulateReasonBadExtIDs, resp.Messages[1].Reason)
+}
+
+func TestSimulateBadParams(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	for _, req := range []SimulateRequest{
+		{},
+		{Transaction: "00", Entry: "00"},
+		{Transaction: "zz"},
+		{Commit: "0011"},
+		{Entry: "zz"},
+	} {
+		_, jErr := HandleV2Simulate(state, req)
+		assert.NotNil(t, jErr, "%v", req)
+	}
+}
@@ -91,6 +91,17 @@ type AnchorsResponse struct {
 	Ethereum interface{} `json:"ethereum"`
 }
 
+type AnchorStatusResponse struct {
+	Anchored bool `json:"anchored"` // Anchored on at least one chain
+	*interfaces.AnchorStatus
+}
+
+type LatestAnchorsResponse struct {
+	BitcoinHeight  *uint32 `json:"bitcoinheight"` // Highest directory block anchored, null if none is
+	EthereumHeight *uint32 `json:"ethereumheight"`
+	*interfaces.LatestAnchors
+}
+
 type BitcoinAnchorResponse struct {
 	TransactionHash string `json:"transactionhash"`
 	BlockHash       string `json:"blockhash"`
@@ -153,6 +164,85 @@ type FactoidBalanceResponse struct {
 	Balance int64 `json:"balance"`
 }
 
+type AddressHistoryResponse struct {
+	Address string `json:"address"`
+	Offset  uint32 `json:"offset"`
+	Limit   uint32 `json:"limit"` // The page size used, which is capped at AddressHistoryMaxLimit
+	*interfaces.AddressHistory
+}
+
+type AuthorityChangesResponse struct {
+	Offset uint32 `json:"offset"`
+	*interfaces.AuthorityChanges
+}
+
+type ElectionLogResponse struct {
+	Total     int                          `json:"total"` // Elections in the log matching the request
+	Elections []*interfaces.ElectionRecord `json:"elections"`
+}
+
+type FeeEstimateResponse struct {
+	Kind            string       `json:"kind"`
+	ECFee           uint64       `json:"ecfee"`
+	FCTFee          uint64       `json:"fctfee"`
+	Rate            uint64       `json:"rate"`
+	PredictedRate   uint64       `json:"predictedrate"`
+	PredictedFCTFee uint64       `json:"predictedfctfee"`
+	Valid           bool         `json:"valid"`
+	Problems        []FeeProblem `json:"problems,omitempty"`
+}
+
+type FeeProblem struct {
+	Problem string `json:"problem"`
+	Message string `json:"message"`
+}
+
+type MempoolResponse struct {
+	Messages []MempoolMessage `json:"messages"`
+	Total    int              `json:"total"`
+	Cursor   string           `json:"cursor,omitempty"`
+}
+
+type MempoolMessage struct {
+	Type      string `json:"type"`
+	Hash      string `json:"hash"`
+	TxID      string `json:"txid,omitempty"`
+	EntryHash string `json:"entryhash,omitempty"`
+	ChainID   string `json:"chainid,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	DependsOn string `json:"dependson,omitempty"`
+	DBHeight  uint32 `json:"dbheight,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+	Age       int64  `json:"age"`
+}
+
+type SimulateResponse struct {
+	Accepted bool               `json:"accepted"`
+	Messages []SimulatedMessage `json:"messages"`
+	Balances []BalanceImpact    `json:"balances"`
+}
+
+type SimulatedMessage struct {
+	Type    string `json:"type"`
+	Hash    string `json:"hash"`
+	Result  string `json:"result"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type BalanceImpact struct {
+	Address string `json:"address"`
+	Before  int64  `json:"before"`
+	After   int64  `json:"after"`
+}
+
+type BalanceAtHeightResponse struct {
+	Address string `json:"address"`
+	Height  uint32 `json:"height"`
+	Balance int64  `json:"balance"`
+}
+
 type EntryCreditRateResponse struct {
 	Rate int64 `json:"rate"`
 }
@@ -271,11 +361,74 @@ type AddressRequest struct {
 	Address string `json:"address"`
 }
 
+type BalanceRequest struct {
+	Address string `json:"address"`
+	Height  *int64 `json:"height,omitempty"` // Balance at the end of this block, rather than the current one
+}
+
+type AddressHistoryRequest struct {
+	Address string `json:"address"`
+	Offset  uint32 `json:"offset,omitempty"`
+	Limit   uint32 `json:"limit,omitempty"`
+}
+
+type FeeEstimateRequest struct {
+	Transaction string `json:"transaction,omitempty"`
+	Commit      string `json:"commit,omitempty"`
+	Entry       string `json:"entry,omitempty"`
+}
+
+type MempoolRequest struct {
+	Type    string `json:"type,omitempty"`
+	Status  string `json:"status,omitempty"`
+	ChainID string `json:"chainid,omitempty"`
+	Address string `json:"address,omitempty"`
+	Order   string `json:"order,omitempty"`
+	Cursor  string `json:"cursor,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+type SimulateRequest struct {
+	Transaction string `json:"transaction,omitempty"`
+	Commit      string `json:"commit,omitempty"`
+	Entry       string `json:"entry,omitempty"`
+}
+
+type BalanceAtHeightRequest struct {
+	Address string `json:"address"`
+	Height  uint32 `json:"height"`
+}
+
 type ReplayRequest struct {
 	StartHeight uint32 `json:"startheight"`
 	EndHeight   uint32 `json:"endheight,omitempty"`
 }
 
+type AnchorStatusRequest struct {
+	Height *int64 `json:"height"`
+}
+
+type CoinbasePayoutRequest struct {
+	Height *int64 `json:"height"` // A block holding a coinbase payout
+}
+
+type AuthoritiesAtHeightRequest struct {
+	Height *int64 `json:"height,omitempty"` // The block being built now if not set
+}
+
+type AuthorityChangesRequest struct {
+	ChainID     string `json:"chainid,omitempty"`     // Only the changes of this authority
+	StartHeight uint32 `json:"startheight,omitempty"` // Only the changes in admin blocks from this height
+	EndHeight   uint32 `json:"endheight,omitempty"`   // to this one
+	Offset      uint32 `json:"offset,omitempty"`
+	Limit       uint32 `json:"limit,omitempty"`
+}
+
+type ElectionLogRequest struct {
+	Outcome string `json:"outcome,omitempty"` // Only the elections with this outcome
+	Limit   int    `json:"limit,omitempty"`
+}
+
 type HeightRequest struct {
 	Height int64 `json:"height"`
 	NoRaw  bool  `json:"noraw,omitempty"`
@@ -343,6 +496,7 @@ type SendRawMessageRequest struct {
 type ReceiptRequest struct {
 	EntryHash       string `json:"hash"`
 	IncludeRawEntry bool   `json:"includerawentry"`
+	IncludeAnchors  bool   `json:"includeanchors"`
 }
 
 type FactiodAccounts struct {
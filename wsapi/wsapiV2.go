@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"os"
 	"reflect"
@@ -25,6 +26,7 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/receipts"
 )
 
@@ -95,8 +97,14 @@ func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*
 		resp, jsonError = HandleV2ReplayDBFromHeight(state, params)
 	case "anchors":
 		resp, jsonError = HandleV2Anchors(state, params)
+	case "anchor-status":
+		resp, jsonError = HandleV2AnchorStatus(state, params)
+	case "latest-anchors":
+		resp, jsonError = HandleV2LatestAnchors(state, params)
 	case "chain-head":
 		resp, jsonError = HandleV2ChainHead(state, params)
+	case "coinbase-payout":
+		resp, jsonError = HandleV2CoinbasePayout(state, params)
 	case "commit-chain":
 		resp, jsonError = HandleV2CommitChain(state, params)
 	case "commit-entry":
@@ -169,6 +177,16 @@ func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*
 		resp, jsonError = HandleV2MultipleECBalances(state, params)
 	case "diagnostics":
 		resp, jsonError = HandleV2Diagnostics(state, params)
+	case "address-history":
+		resp, jsonError = HandleV2AddressHistory(state, params)
+	case "balance-at-height":
+		resp, jsonError = HandleV2BalanceAtHeight(state, params)
+	case "fee-estimate":
+		resp, jsonError = HandleV2FeeEstimate(state, params)
+	case "mempool":
+		resp, jsonError = HandleV2Mempool(state, params)
+	case "simulate":
+		resp, jsonError = HandleV2Simulate(state, params)
 		//case "factoid-accounts":
 		// resp, jsonError = HandleV2Accounts(state, params)
 	default:
@@ -897,6 +915,62 @@ func HandleV2Anchors(state interfaces.IState, params interface{}) (interface{},
 	return response, nil
 }
 
+// HandleV2AnchorStatus returns the anchor records of a directory block found so far
+func HandleV2AnchorStatus(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	request := new(AnchorStatusRequest)
+	err := MapToObject(params, request)
+	if err != nil || request.Height == nil || *request.Height < 0 {
+		return nil, NewInvalidParamsError()
+	}
+
+	status, err := state.GetAnchorStatus(uint32(*request.Height))
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	} else if status == nil {
+		return nil, NewBlockNotFoundError()
+	}
+	resp := new(AnchorStatusResponse)
+	resp.AnchorStatus = status
+	resp.Anchored = status.Bitcoin != nil || status.Ethereum != nil
+	return resp, nil
+}
+
+// HandleV2LatestAnchors returns the highest directory blocks anchored in Bitcoin and Ethereum
+func HandleV2LatestAnchors(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	latest, err := state.GetLatestAnchors()
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	resp := new(LatestAnchorsResponse)
+	resp.LatestAnchors = latest
+	if latest.Bitcoin != nil {
+		resp.BitcoinHeight = &latest.Bitcoin.DBHeight
+	}
+	if latest.Ethereum != nil {
+		resp.EthereumHeight = &latest.Ethereum.DBHeight
+	}
+	return resp, nil
+}
+
+// HandleV2CoinbasePayout returns the outputs a coinbase pays, as paid for a saved block and as
+// projected for a future one
+func HandleV2CoinbasePayout(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	request := new(CoinbasePayoutRequest)
+	err := MapToObject(params, request)
+	if err != nil || request.Height == nil || *request.Height < 0 {
+		return nil, NewInvalidParamsError()
+	}
+	if !constants.IsCoinbasePayoutHeight(uint32(*request.Height)) {
+		return nil, NewCustomInvalidParamsError(fmt.Sprintf("block %d doesn't pay a coinbase", *request.Height))
+	}
+
+	payout, err := state.GetCoinbasePayout(uint32(*request.Height))
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	return payout, nil
+}
+
 func HandleV2Receipt(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallReceipt.Observe(float64(time.Since(n).Nanoseconds()))
@@ -915,7 +989,22 @@ func HandleV2Receipt(state interfaces.IState, params interface{}) (interface{},
 	dbo := state.GetDB()
 	receipt, err := receipts.CreateFullReceipt(dbo, h, request.IncludeRawEntry)
 	if err != nil {
-		return nil, NewReceiptError()
+		// Not an entry, so a factoid transaction or an entry credit commit
+		receipt, err = receipts.CreateTransactionReceipt(dbo, h, request.IncludeRawEntry)
+		if err != nil {
+			return nil, NewReceiptError()
+		}
+	}
+	if request.IncludeAnchors {
+		var bitcoinKeys, ethereumKeys []interfaces.Verifier
+		if overlay, ok := dbo.(*databaseOverlay.Overlay); ok {
+			bitcoinKeys = overlay.BitcoinAnchorRecordPublicKeys
+			ethereumKeys = overlay.EthereumAnchorRecordPublicKeys
+		}
+		err = receipts.AddAnchors(dbo, receipt, bitcoinKeys, ethereumKeys)
+		if err != nil {
+			return nil, NewCustomInternalError(err.Error())
+		}
 	}
 	resp := new(ReceiptResponse)
 	resp.Receipt = receipt
@@ -1156,7 +1245,7 @@ func HandleV2EntryCreditBalance(state interfaces.IState, params interface{}) (in
 	n := time.Now()
 	defer HandleV2APICallECBal.Observe(float64(time.Since(n).Nanoseconds()))
 
-	ecadr := new(AddressRequest)
+	ecadr := new(BalanceRequest)
 	err := MapToObject(params, ecadr)
 	if err != nil {
 		return nil, NewInvalidParamsError()
@@ -1185,6 +1274,14 @@ func HandleV2EntryCreditBalance(state interfaces.IState, params interface{}) (in
 		return nil, NewInvalidAddressError()
 	}
 	resp := new(EntryCreditBalanceResponse)
+	if ecadr.Height != nil {
+		var jsonError *primitives.JSONError
+		resp.Balance, _, jsonError = balanceAt(state, true, address.Fixed(), *ecadr.Height)
+		if jsonError != nil {
+			return nil, jsonError
+		}
+		return resp, nil
+	}
 	resp.Balance = state.GetFactoidState().GetECBalance(address.Fixed())
 	return resp, nil
 }
@@ -1236,7 +1333,7 @@ func HandleV2FactoidBalance(state interfaces.IState, params interface{}) (interf
 	n := time.Now()
 	defer HandleV2APICallFABal.Observe(float64(time.Since(n).Nanoseconds()))
 
-	fadr := new(AddressRequest)
+	fadr := new(BalanceRequest)
 	err := MapToObject(params, fadr)
 	if err != nil {
 		return nil, NewInvalidParamsError()
@@ -1261,6 +1358,14 @@ func HandleV2FactoidBalance(state interfaces.IState, params interface{}) (interf
 	}
 
 	resp := new(FactoidBalanceResponse)
+	if fadr.Height != nil {
+		var jsonError *primitives.JSONError
+		resp.Balance, _, jsonError = balanceAt(state, false, factoid.NewAddress(adr).Fixed(), *fadr.Height)
+		if jsonError != nil {
+			return nil, jsonError
+		}
+		return resp, nil
+	}
 	resp.Balance = state.GetFactoidState().GetFactoidBalance(factoid.NewAddress(adr).Fixed())
 	return resp, nil
 }
@@ -1472,6 +1577,15 @@ func HandleV2MultipleECBalances(state interfaces.IState, params interface{}) (in
 	var currentHeight uint32
 	var savedHeight uint32
 
+	height, jsonError := balanceHeightParam(x)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	if height != nil {
+		currentHeight = uint32(*height)
+		savedHeight = uint32(*height)
+	}
+
 	// Converts readable accounts
 	for i, a := range listofadd {
 		if a.(string) == "" {
@@ -1489,6 +1603,20 @@ func HandleV2MultipleECBalances(state interfaces.IState, params interface{}) (in
 		} else {
 			covertedAdd := [32]byte{}
 			copy(covertedAdd[:], primitives.ConvertUserStrToAddress(a.(string)))
+			if height != nil {
+				balance, found, jsonError := balanceAt(state, true, covertedAdd, *height)
+				if jsonError != nil {
+					return nil, jsonError
+				}
+				valueStruct := new(interfaces.StructToReturnValues)
+				valueStruct.TempBal = balance
+				valueStruct.PermBal = balance
+				if !found {
+					valueStruct.Error = "Address has not had a transaction"
+				}
+				totalBalances[i] = valueStruct
+				continue
+			}
 			cHeight, sHeight, temp, perm, error := state.GetFactoidState().GetMultipleECBalances(covertedAdd)
 			currentHeight = cHeight
 			savedHeight = sHeight
@@ -1524,6 +1652,15 @@ func HandleV2MultipleFCTBalances(state interfaces.IState, params interface{}) (i
 	var currentHeight uint32
 	var savedHeight uint32
 
+	height, jsonError := balanceHeightParam(x)
+	if jsonError != nil {
+		return nil, jsonError
+	}
+	if height != nil {
+		currentHeight = uint32(*height)
+		savedHeight = uint32(*height)
+	}
+
 	// Converts readable accounts
 	for i, a := range listofadd {
 		if a.(string) == "" {
@@ -1541,6 +1678,20 @@ func HandleV2MultipleFCTBalances(state interfaces.IState, params interface{}) (i
 		} else {
 			covertedAdd := [32]byte{}
 			copy(covertedAdd[:], primitives.ConvertUserStrToAddress(a.(string)))
+			if height != nil {
+				balance, found, jsonError := balanceAt(state, false, covertedAdd, *height)
+				if jsonError != nil {
+					return nil, jsonError
+				}
+				valueStruct := new(interfaces.StructToReturnValues)
+				valueStruct.TempBal = balance
+				valueStruct.PermBal = balance
+				if !found {
+					valueStruct.Error = "Address has not had a transaction"
+				}
+				totalBalances[i] = valueStruct
+				continue
+			}
 			cHeight, sHeight, temp, perm, error := state.GetFactoidState().GetMultipleFactoidBalances(covertedAdd)
 			currentHeight = cHeight
 			savedHeight = sHeight
@@ -1562,6 +1713,37 @@ func HandleV2MultipleFCTBalances(state interfaces.IState, params interface{}) (i
 	return h, nil
 }
 
+// balanceHeightParam reads the optional height of the multiple balance calls
+func balanceHeightParam(params map[string]interface{}) (*int64, *primitives.JSONError) {
+	if params["height"] == nil {
+		return nil, nil
+	}
+	h, ok := params["height"].(float64)
+	if !ok || h < 0 || h > math.MaxUint32 || h != math.Floor(h) {
+		return nil, NewInvalidHeightError()
+	}
+	height := int64(h)
+	return &height, nil
+}
+
+// balanceAt looks up the balance of an address at the end of a past block, for the balance
+// calls given a height.  found is false if the address had no transactions by then.
+func balanceAt(state interfaces.IState, ec bool, address [32]byte, height int64) (balance int64, found bool, jsonError *primitives.JSONError) {
+	if height < 0 || height > math.MaxUint32 {
+		return 0, false, NewInvalidHeightError()
+	}
+	fct, ecs, err := state.GetFactoidState().GetBalancesAt(uint32(height))
+	if err != nil {
+		return 0, false, NewCustomInternalError(err.Error())
+	}
+	if ec {
+		balance, found = ecs[address]
+	} else {
+		balance, found = fct[address]
+	}
+	return balance, found, nil
+}
+
 func HandleV2Diagnostics(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	// General state information
 	resp := new(DiagnosticsResponse)
@@ -1686,3 +1868,78 @@ func HandleV2Diagnostics(state interfaces.IState, params interface{}) (interface
 //
 // return h, nil
 //}
+
+// How many transactions a page of address-history holds, unless asked for fewer
+const (
+	AddressHistoryDefaultLimit = 50
+	AddressHistoryMaxLimit     = 1000
+)
+
+// historyAddress parses a human readable factoid (FA) or entry credit (EC) address for the
+// address history calls.  The prefix decides which history is looked up.
+func historyAddress(user string) (ec bool, address [32]byte, ok bool) {
+	switch {
+	case primitives.ValidateFUserStr(user):
+	case primitives.ValidateECUserStr(user):
+		ec = true
+	default:
+		return false, address, false
+	}
+	copy(address[:], primitives.ConvertUserStrToAddress(user))
+	return ec, address, true
+}
+
+func HandleV2AddressHistory(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(AddressHistoryRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	ec, address, ok := historyAddress(req.Address)
+	if !ok {
+		return nil, NewInvalidAddressError()
+	}
+	if req.Limit == 0 {
+		req.Limit = AddressHistoryDefaultLimit
+	}
+	if req.Limit > AddressHistoryMaxLimit {
+		req.Limit = AddressHistoryMaxLimit
+	}
+
+	history, err := state.GetAddressHistory(ec, address, req.Offset, req.Limit)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+
+	resp := new(AddressHistoryResponse)
+	resp.Address = req.Address
+	resp.Offset = req.Offset
+	resp.Limit = req.Limit
+	resp.AddressHistory = history
+	return resp, nil
+}
+
+func HandleV2BalanceAtHeight(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(BalanceAtHeightRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	ec, address, ok := historyAddress(req.Address)
+	if !ok {
+		return nil, NewInvalidAddressError()
+	}
+
+	balance, err := state.GetBalanceAtHeight(ec, address, req.Height)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+
+	resp := new(BalanceAtHeightResponse)
+	resp.Address = req.Address
+	resp.Height = req.Height
+	resp.Balance = balance
+	return resp, nil
+}
@@ -13,8 +13,10 @@ import (
 
 	"time"
 
+	"github.com/FactomProject/factomd/common/constants"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/receipts"
 	"github.com/FactomProject/factomd/testHelper"
 	. "github.com/FactomProject/factomd/wsapi"
@@ -357,6 +359,110 @@ func TestHandleV2GetReceipt(t *testing.T) {
 	assert.Nil(t, err, "receipt - %s", marshalled)
 }
 
+func TestHandleV2GetTransactionReceipt(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	keys := []interfaces.Verifier{testHelper.NewPrimitivesPrivateKey(0).Pub}
+	state.DB.(*databaseOverlay.Overlay).BitcoinAnchorRecordPublicKeys = keys
+	blocks := testHelper.CreateFullTestBlockSet()
+
+	tx := blocks[2].FBlock.GetTransactions()[1]
+	req := ReceiptRequest{EntryHash: tx.GetSigHash().String(), IncludeRawEntry: true, IncludeAnchors: true}
+	resp, jErr := HandleV2Receipt(state, req)
+	assert.Nil(t, jErr)
+
+	receipt := resp.(*ReceiptResponse).Receipt
+	assert.Equal(t, tx.GetHash().String(), receipt.Transaction.Hash)
+	assert.Equal(t, blocks[2].FBlock.GetKeyMR().String(), receipt.FactoidBlockKeyMR.String())
+	assert.Nil(t, receipt.Validate())
+	assert.Len(t, receipt.Anchors, 1)
+	assert.Nil(t, receipt.ValidateAnchors(keys, nil))
+
+	req.EntryHash = primitives.NewZeroHash().String()
+	_, jErr = HandleV2Receipt(state, req)
+	assert.NotNil(t, jErr)
+}
+
+func TestHandleV2AnchorStatus(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	blocks := testHelper.CreateFullTestBlockSet()
+
+	// Nothing is anchored until the anchor chains are parsed with the keys
+	resp, jErr := HandleV2LatestAnchors(state, nil)
+	assert.Nil(t, jErr)
+	assert.Nil(t, resp.(*LatestAnchorsResponse).BitcoinHeight)
+
+	dbo := state.DB.(*databaseOverlay.Overlay)
+	dbo.BitcoinAnchorRecordPublicKeys = []interfaces.Verifier{testHelper.NewPrimitivesPrivateKey(0).Pub}
+	assert.Nil(t, dbo.ReparseAnchorChains())
+
+	resp, jErr = HandleV2LatestAnchors(state, nil)
+	assert.Nil(t, jErr)
+	latest := resp.(*LatestAnchorsResponse)
+	last := uint32(len(blocks) - 2) // The last block is not anchored yet
+	if assert.NotNil(t, latest.BitcoinHeight) {
+		assert.Equal(t, last, *latest.BitcoinHeight)
+		assert.Equal(t, interfaces.AnchorChainBitcoin, latest.Bitcoin.Chain)
+	}
+	assert.Nil(t, latest.EthereumHeight)
+	assert.Nil(t, latest.Ethereum)
+
+	height := int64(2)
+	resp, jErr = HandleV2AnchorStatus(state, AnchorStatusRequest{Height: &height})
+	assert.Nil(t, jErr)
+	status := resp.(*AnchorStatusResponse)
+	assert.True(t, status.Anchored)
+	assert.Equal(t, blocks[2].DBlock.DatabasePrimaryIndex().String(), status.KeyMR)
+	if assert.NotNil(t, status.Bitcoin) {
+		assert.EqualValues(t, 2, status.Bitcoin.DBHeight)
+		assert.Equal(t, status.KeyMR, status.Bitcoin.KeyMR)
+		assert.NotEmpty(t, status.Bitcoin.TxID)
+		assert.NotEmpty(t, status.Bitcoin.EntryHash)
+	}
+	assert.Nil(t, status.Ethereum)
+
+	height = int64(last + 1)
+	resp, jErr = HandleV2AnchorStatus(state, AnchorStatusRequest{Height: &height})
+	assert.Nil(t, jErr)
+	assert.False(t, resp.(*AnchorStatusResponse).Anchored)
+
+	height = int64(len(blocks) + 10)
+	_, jErr = HandleV2AnchorStatus(state, AnchorStatusRequest{Height: &height})
+	assert.NotNil(t, jErr)
+	_, jErr = HandleV2AnchorStatus(state, AnchorStatusRequest{})
+	assert.NotNil(t, jErr)
+}
+
+func TestHandleV2CoinbasePayout(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	declaration, frequency, activation := constants.COINBASE_DECLARATION, constants.COINBASE_PAYOUT_FREQUENCY, constants.COINBASE_ACTIVATION
+	defer func() {
+		constants.COINBASE_DECLARATION, constants.COINBASE_PAYOUT_FREQUENCY, constants.COINBASE_ACTIVATION = declaration, frequency, activation
+	}()
+	constants.SetLocalCoinBaseConstants()
+
+	payout := int64(constants.COINBASE_DECLARATION + 2*constants.COINBASE_PAYOUT_FREQUENCY)
+	resp, jErr := HandleV2CoinbasePayout(state, CoinbasePayoutRequest{Height: &payout})
+	assert.Nil(t, jErr)
+	p := resp.(*interfaces.CoinbasePayout)
+	assert.EqualValues(t, payout, p.PayoutHeight)
+	assert.EqualValues(t, constants.COINBASE_PAYOUT_FREQUENCY*2, p.DescriptorHeight)
+	assert.Equal(t, interfaces.CoinbaseAuthorities, p.Kind)
+	assert.NotNil(t, p.Outputs)
+
+	payout++
+	resp, jErr = HandleV2CoinbasePayout(state, CoinbasePayoutRequest{Height: &payout})
+	assert.Nil(t, jErr)
+	assert.Equal(t, interfaces.CoinbaseGrants, resp.(*interfaces.CoinbasePayout).Kind)
+
+	payout++
+	_, jErr = HandleV2CoinbasePayout(state, CoinbasePayoutRequest{Height: &payout})
+	if assert.NotNil(t, jErr) {
+		assert.Equal(t, -32602, jErr.Code)
+	}
+	_, jErr = HandleV2CoinbasePayout(state, CoinbasePayoutRequest{})
+	assert.NotNil(t, jErr)
+}
+
 func TestHandleV2GetTransaction(t *testing.T) {
 	state := testHelper.CreateAndPopulateTestStateAndStartValidator()
 	blocks := testHelper.CreateFullTestBlockSet()